@@ -20,11 +20,13 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	api "github.com/dell/csi-baremetal/api/generated/v1"
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
 )
 
 // +kubebuilder:object:root=true
 
 // +kubebuilder:resource:scope=Cluster,shortName={acr,acrs}
+// +kubebuilder:storageversion
 // +kubebuilder:printcolumn:name="NAMESPACE",type="string",JSONPath=".spec.Namespace",description="Pod namespace"
 // +kubebuilder:printcolumn:name="STATUS",type="string",JSONPath=".spec.Status",description="Status of AvailableCapacityReservation"
 // AvailableCapacityReservation is the Schema for the availablecapacitiereservations API
@@ -32,6 +34,7 @@ type AvailableCapacityReservation struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 	Spec              api.AvailableCapacityReservation `json:"spec,omitempty"`
+	Status            apiV1.CRStatus                   `json:"status,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -53,4 +56,15 @@ func (in *AvailableCapacityReservation) DeepCopyInto(out *AvailableCapacityReser
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// GetStatus returns status of the AvailableCapacityReservation
+func (in *AvailableCapacityReservation) GetStatus() *apiV1.CRStatus {
+	return &in.Status
+}
+
+// SyncConditions sets Reserved condition from Status of the AvailableCapacityReservation
+func (in *AvailableCapacityReservation) SyncConditions() {
+	apiV1.SyncStateCondition(&in.Status.Conditions, apiV1.ConditionReserved, in.Spec.Status, apiV1.ReservationConfirmed)
 }
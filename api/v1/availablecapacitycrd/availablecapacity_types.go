@@ -20,10 +20,12 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	api "github.com/dell/csi-baremetal/api/generated/v1"
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
 )
 
 // +kubebuilder:object:root=true
 // +kubebuilder:resource:scope=Cluster,shortName={ac,acs}
+// +kubebuilder:storageversion
 // +kubebuilder:printcolumn:name="LOCATION",type="string",JSONPath=".spec.Location",description="Drive/LVG UUID used by AvailableCapacity"
 // +kubebuilder:printcolumn:name="NODE",type="string",JSONPath=".spec.NodeId",description="Node id of Available Capacity"
 // +kubebuilder:printcolumn:name="STORAGE CLASS",type="string",JSONPath=".spec.storageClass",description="StorageClass of AvailableCapacity"
@@ -33,6 +35,7 @@ type AvailableCapacity struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 	Spec              api.AvailableCapacity `json:"spec,omitempty"`
+	Status            apiV1.CRStatus        `json:"status,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -54,4 +57,13 @@ func (in *AvailableCapacity) DeepCopyInto(out *AvailableCapacity) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
 }
+
+// GetStatus returns status of the AvailableCapacity
+func (in *AvailableCapacity) GetStatus() *apiV1.CRStatus {
+	return &in.Status
+}
+
+// SyncConditions does nothing because AvailableCapacity has no runtime state
+func (in *AvailableCapacity) SyncConditions() {}
@@ -0,0 +1,162 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	// ConditionReady shows whether Volume or LogicalVolumeGroup is created and can be used
+	ConditionReady = "Ready"
+	// ConditionHealthy shows whether health of Drive, Volume or LogicalVolumeGroup is GOOD
+	ConditionHealthy = "Healthy"
+	// ConditionOnline shows whether Drive is ONLINE
+	ConditionOnline = "Online"
+	// ConditionReserved shows whether AvailableCapacityReservation is RESERVED
+	ConditionReserved = "Reserved"
+	// ConditionFirmwareCompliant shows whether firmware of Drive complies with FirmwarePolicy
+	ConditionFirmwareCompliant = "FirmwareCompliant"
+)
+
+// Condition contains details for one aspect of the current state of the custom resource.
+// It has the same schema as metav1.Condition which isn't available in the used apimachinery version
+type Condition struct {
+	// Type of condition in CamelCase
+	Type string `json:"type"`
+	// Status of the condition, one of True, False, Unknown
+	Status metav1.ConditionStatus `json:"status"`
+	// ObservedGeneration is the generation of the custom resource which condition was set upon
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// LastTransitionTime is the last time the condition transitioned from one status to another
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+	// Reason is a programmatic identifier of the condition's last transition
+	Reason string `json:"reason,omitempty"`
+	// Message is a human readable message with details about the transition
+	Message string `json:"message,omitempty"`
+}
+
+// CRStatus is a status of the custom resources which runtime state is kept in the protobuf Spec.
+// It is served through the status subresource
+type CRStatus struct {
+	// ObservedGeneration is the generation of the custom resource which status was written upon
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Conditions of the custom resource
+	Conditions []Condition `json:"conditions,omitempty"`
+}
+
+// DeepCopyInto copies the receiver into out
+func (in *CRStatus) DeepCopyInto(out *CRStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		out.Conditions = make([]Condition, len(in.Conditions))
+		for i := range in.Conditions {
+			in.Conditions[i].DeepCopyInto(&out.Conditions[i])
+		}
+	}
+}
+
+// DeepCopyInto copies the receiver into out
+func (in *Condition) DeepCopyInto(out *Condition) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+}
+
+// StatusObject is a custom resource with conditions in status.
+// v1 version has no status subresource, status is written by Create and Update calls along with spec
+type StatusObject interface {
+	runtime.Object
+	metav1.Object
+	// GetStatus returns status of the custom resource
+	GetStatus() *CRStatus
+	// SyncConditions sets conditions derived from the runtime state of the custom resource
+	SyncConditions()
+}
+
+// FindCondition returns condition with provided type or nil
+func FindCondition(conditions []Condition, conditionType string) *Condition {
+	for i := range conditions {
+		if conditions[i].Type == conditionType {
+			return &conditions[i]
+		}
+	}
+	return nil
+}
+
+// SetCondition adds condition or replaces condition with the same type,
+// LastTransitionTime is changed only if status of the condition is changed
+func SetCondition(conditions *[]Condition, newCondition Condition) {
+	existing := FindCondition(*conditions, newCondition.Type)
+	if existing == nil {
+		if newCondition.LastTransitionTime.IsZero() {
+			newCondition.LastTransitionTime = metav1.Now().Rfc3339Copy()
+		}
+		*conditions = append(*conditions, newCondition)
+		return
+	}
+	if existing.Status != newCondition.Status {
+		existing.Status = newCondition.Status
+		existing.LastTransitionTime = newCondition.LastTransitionTime
+		if existing.LastTransitionTime.IsZero() {
+			existing.LastTransitionTime = metav1.Now().Rfc3339Copy()
+		}
+	}
+	existing.ObservedGeneration = newCondition.ObservedGeneration
+	existing.Reason = newCondition.Reason
+	existing.Message = newCondition.Message
+}
+
+// RemoveCondition removes condition with provided type if it exists
+func RemoveCondition(conditions *[]Condition, conditionType string) {
+	for i := range *conditions {
+		if (*conditions)[i].Type == conditionType {
+			*conditions = append((*conditions)[:i], (*conditions)[i+1:]...)
+			return
+		}
+	}
+}
+
+// SyncStateCondition sets condition which is True if state is one of trueStates, state is used as a reason.
+// Condition isn't changed if it already has the same status and reason, so message set along with the state is kept
+func SyncStateCondition(conditions *[]Condition, conditionType, state string, trueStates ...string) {
+	status := metav1.ConditionFalse
+	if state == "" {
+		status = metav1.ConditionUnknown
+	}
+	for _, s := range trueStates {
+		if s == state {
+			status = metav1.ConditionTrue
+		}
+	}
+	if existing := FindCondition(*conditions, conditionType); existing != nil &&
+		existing.Status == status && existing.Reason == state {
+		return
+	}
+	SetCondition(conditions, Condition{Type: conditionType, Status: status, Reason: state})
+}
+
+// SetFailedCondition sets condition to False with FAILED reason and the error as a message,
+// it is called along with setting of FAILED state to explain the failure
+func SetFailedCondition(conditions *[]Condition, conditionType string, err error) {
+	SetCondition(conditions, Condition{
+		Type:    conditionType,
+		Status:  metav1.ConditionFalse,
+		Reason:  Failed,
+		Message: err.Error(),
+	})
+}
@@ -23,10 +23,15 @@ const (
 	LVGKind                          = "LogicalVolumeGroup"
 	DriveKind                        = "Drive"
 	CSIBMNodeKind                    = "Node"
+	FirmwarePolicyKind               = "FirmwarePolicy"
+	StorageQuotaKind                 = "StorageQuota"
 
 	Version            = "v1"
 	CSICRsGroupVersion = "csi-baremetal.dell.com"
 	APIV1Version       = "csi-baremetal.dell.com/v1"
+	// VersionV1alpha2 is a version of Volume, Drive, LogicalVolumeGroup, AvailableCapacity and
+	// AvailableCapacityReservation with runtime state in the status subresource, v1 is used as the storage version
+	VersionV1alpha2 = "v1alpha2"
 
 	// CSI statuses
 	Creating    = "CREATING"
@@ -69,6 +74,44 @@ const (
 	DriveAnnotationReplacementReady   = "ready"
 	DriveAnnotationVolumeStatusPrefix = "status"
 
+	// Drive replacement annotations, set by drive controller when new drive is inserted into the slot of removed drive
+	DriveAnnotationReplacedBy       = "replacement/replaced-by"
+	DriveAnnotationReplaces         = "replacement/replaces"
+	DriveAnnotationReplacementTime  = "replacement/time"
+	DriveAnnotationReplacementLVGSC = "replacement/lvg-storage-class"
+	DriveAnnotationReplacementLVG   = "replacement/lvg"
+	DriveAnnotationRemovalTime      = "replacement/removal-time"
+	// set on the new drive while LVG of the removed drive is restored on it, AC of such drive isn't offered
+	DriveAnnotationReplacementRestoring = "replacement/restoring-lvg"
+
+	// Drive evacuation annotations, set by drive controller when data of SUSPECT drive is moved to another drive of LVG
+	DriveAnnotationEvacuation         = "evacuation/status"
+	DriveAnnotationEvacuationProgress = "evacuation/progress"
+	DriveAnnotationEvacuationTarget   = "evacuation/target"
+	DriveAnnotationEvacuationAttempts = "evacuation/attempts"
+	EvacuationInProgress              = "in-progress"
+	EvacuationDone                    = "done"
+	EvacuationFailed                  = "failed"
+
+	// Drive firmware policy annotation, set by firmware policy controller on the drives which are checked by the policy.
+	// Result of the check is reported by FirmwareCompliant condition of the drive with one of the reasons below
+	DriveAnnotationFirmwarePolicy = "firmware/policy"
+	FirmwareCompliant             = "Compliant"
+	FirmwareNonCompliant          = "NonCompliant"
+	FirmwareExcluded              = "NonCompliantExcluded"
+	FirmwareUnknown               = "FirmwareUnknown"
+
+	// Drive maintenance annotation, set by user to remove the drive from scheduling without release of its volumes
+	DriveAnnotationMaintenance = "maintenance"
+	DriveMaintenanceOn         = "true"
+
+	// Force delete annotation, set by user to remove finalizer of Drive or AvailableCapacity CR which is still in use
+	AnnotationForceDelete = "force-delete"
+	ForceDeleteOn         = "true"
+
+	// AvailableCapacity finalizer, it is released by the controller when AC is consumed or isn't reserved anymore
+	ACFinalizer = "dell.emc.csi/ac-cleanup"
+
 	// Volume operational status
 	OperationalStatusOperative   = "OPERATIVE"
 	OperationalStatusInoperative = "INOPERATIVE"
@@ -89,6 +132,13 @@ const (
 	VolumeAnnotationReleaseFailed = "failed"
 	VolumeAnnotationReleaseStatus = "status"
 
+	// Volume annotation with name of PVC (or inline volume) from VolumeInfo, namespace of PVC is namespace of Volume CR
+	VolumeAnnotationPVCName = "pvc/name"
+
+	// Prefix of annotations with W3C trace context (trace/traceparent, trace/tracestate) of the last operation
+	// requested for the custom resource, node continues the trace of the controller from them
+	TraceAnnotationPrefix = "trace/"
+
 	//Volume expansion annotations
 	VolumePreviousStatus   = "expansion/previous-status"
 	VolumePreviousCapacity = "expansion/previous-capacity"
@@ -100,17 +150,46 @@ const (
 	//LVG annotations
 	LVGFreeSpaceAnnotation = "lvg/free-space"
 
+	// Node decommission annotations, policy is set by user on Node CR, status and progress are set by operator
+	NodeAnnotationDecommission         = "decommission/policy"
+	NodeAnnotationDecommissionStatus   = "decommission/status"
+	NodeAnnotationDecommissionProgress = "decommission/progress"
+	DecommissionPolicyWait             = "wait"
+	DecommissionPolicyForce            = "force"
+	DecommissionInProgress             = "in-progress"
+	DecommissionWaitingForPVCs         = "waiting-for-pvcs"
+	DecommissionWaitingForPVs          = "waiting-for-pvs"
+	DecommissionDone                   = "done"
+	DecommissionFailed                 = "failed"
+
 	// Volume location type
 	LocationTypeDrive = "DRIVE"
 	LocationTypeLVM   = "LVM"
 	LocationTypeNVMe  = "NVME"
 
+	// Available Capacity annotations, attributes of the underlying drive set by capacity controller
+	ACAnnotationDriveVID  = "drive/vid"
+	ACAnnotationDrivePID  = "drive/pid"
+	ACAnnotationDriveType = "drive/type"
+	ACAnnotationDriveSize = "drive/size"
+	// size of LVG AC which is suspended while drive of LVG is in maintenance
+	ACAnnotationMaintenanceSize = "maintenance/size"
+
 	// Available Capacity Reservation statuses
 	ReservationRequested = "REQUESTED"
 	ReservationConfirmed = "RESERVED"
 	ReservationRejected  = "REJECTED"
 	ReservationCancelled = "CANCELLED"
 
+	// Available Capacity Reservation annotations, link reservation to the pod
+	ReservationAnnotationPodName      = "reservation/pod-name"
+	ReservationAnnotationPodNamespace = "reservation/pod-namespace"
+	ReservationAnnotationPodUID       = "reservation/pod-uid"
+
+	// VolumeGroupKey is a PVC annotation, pod label and Volume CR label which tags volumes of the same workload,
+	// volumes with the same tag are spread across failure domains
+	VolumeGroupKey = "csi-baremetal.dell.com/volume-group"
+
 	// CSI StorageClass
 	// For volumes with storage class 'ANY' CSI will pick any AC except LVG AC
 	StorageClassAny       = "ANY"
@@ -22,6 +22,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	api "github.com/dell/csi-baremetal/api/generated/v1"
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
 )
 
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
@@ -32,6 +33,7 @@ import (
 // Drive is the Schema for the drives API
 //kubebuilder:object:generate=false
 // +kubebuilder:resource:scope=Cluster,shortName={ac,acs}
+// +kubebuilder:storageversion
 // +kubebuilder:printcolumn:name="SERIAL NUMBER",type="string",JSONPath=".spec.SerialNumber",description="Drive serial number"
 // +kubebuilder:printcolumn:name="HEALTH",type="string",JSONPath=".spec.Health",description="Drive health status"
 // +kubebuilder:printcolumn:name="TYPE",type="string",JSONPath=".spec.Type",description="Drive type (HDD/LVG/NVME)"
@@ -43,7 +45,8 @@ type Drive struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 
-	Spec api.Drive `json:"spec,omitempty"`
+	Spec   api.Drive      `json:"spec,omitempty"`
+	Status apiV1.CRStatus `json:"status,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -62,12 +65,24 @@ func (in *Drive) DeepCopyInto(out *Drive) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 func init() {
 	SchemeBuilderDrive.Register(&Drive{}, &DriveList{})
 }
 
+// GetStatus returns status of the Drive
+func (in *Drive) GetStatus() *apiV1.CRStatus {
+	return &in.Status
+}
+
+// SyncConditions sets Online and Healthy conditions from Status and Health of the Drive
+func (in *Drive) SyncConditions() {
+	apiV1.SyncStateCondition(&in.Status.Conditions, apiV1.ConditionOnline, in.Spec.Status, apiV1.DriveStatusOnline)
+	apiV1.SyncStateCondition(&in.Status.Conditions, apiV1.ConditionHealthy, in.Spec.Health, apiV1.HealthGood)
+}
+
 func (in *Drive) Equals(drive *api.Drive) bool {
 	return in.Spec.SerialNumber == drive.SerialNumber &&
 		in.Spec.NodeId == drive.NodeId &&
@@ -0,0 +1,81 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package firmwarepolicycrd
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +kubebuilder:object:root=true
+
+// FirmwarePolicy is the Schema for the firmwarepolicies API
+// +kubebuilder:resource:scope=Cluster,shortName={fwp,fwps}
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="COMPLIANT",type="integer",JSONPath=".status.compliantDrives",description="Amount of compliant drives"
+// +kubebuilder:printcolumn:name="NON-COMPLIANT",type="integer",JSONPath=".status.nonCompliantDrives",description="Amount of non-compliant drives"
+type FirmwarePolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   FirmwarePolicySpec   `json:"spec,omitempty"`
+	Status FirmwarePolicyStatus `json:"status,omitempty"`
+}
+
+// FirmwarePolicySpec defines firmware which is allowed for drives of particular models
+type FirmwarePolicySpec struct {
+	// Rules is a list of firmware requirements per drive model
+	Rules []FirmwareRule `json:"rules"`
+	// ExcludeNonCompliant defines whether clean drives with non-compliant firmware should be hidden from scheduling
+	ExcludeNonCompliant bool `json:"excludeNonCompliant,omitempty"`
+}
+
+// FirmwareRule defines firmware requirements for drives with particular VID/PID
+type FirmwareRule struct {
+	// VID is a drive vendor, case insensitive
+	VID string `json:"vid"`
+	// PID is a drive product, case insensitive. Rule matches all products of the vendor if PID is empty
+	PID string `json:"pid,omitempty"`
+	// MinVersion is the lowest compliant firmware, it is used if Blessed list is empty
+	MinVersion string `json:"minVersion,omitempty"`
+	// Blessed is a list of compliant firmware, all other firmware is treated as non-compliant
+	Blessed []string `json:"blessed,omitempty"`
+}
+
+// FirmwarePolicyStatus holds results of the last policy evaluation
+type FirmwarePolicyStatus struct {
+	// CompliantDrives is an amount of drives with compliant firmware
+	CompliantDrives int `json:"compliantDrives"`
+	// NonCompliantDrives is an amount of drives with non-compliant firmware
+	NonCompliantDrives int `json:"nonCompliantDrives"`
+	// NonCompliant is a list of Drive CR names with non-compliant firmware
+	NonCompliant []string `json:"nonCompliant,omitempty"`
+	// LastEvaluationTime is the time when policy was evaluated last time
+	LastEvaluationTime metav1.Time `json:"lastEvaluationTime,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// FirmwarePolicyList contains a list of FirmwarePolicy
+type FirmwarePolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []FirmwarePolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilderFirmwarePolicy.Register(&FirmwarePolicy{}, &FirmwarePolicyList{})
+}
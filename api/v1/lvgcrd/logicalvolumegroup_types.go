@@ -20,12 +20,14 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	api "github.com/dell/csi-baremetal/api/generated/v1"
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
 )
 
 // +kubebuilder:object:root=true
 
 // +kubebuilder:resource:scope=Cluster
 // +kubebuilder:resource:scope=Cluster,shortName={lvg,lvgs}
+// +kubebuilder:storageversion
 // +kubebuilder:printcolumn:name="HEALTH",type="string",JSONPath=".spec.Health",description="LVG health status"
 // +kubebuilder:printcolumn:name="NODE",type="string",JSONPath=".spec.Node",description="LVG node location"
 // +kubebuilder:printcolumn:name="SIZE",type="string",JSONPath=".spec.Size",description="Size of Logical volume group"
@@ -35,6 +37,7 @@ type LogicalVolumeGroup struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 	Spec              api.LogicalVolumeGroup `json:"spec,omitempty"`
+	Status            apiV1.CRStatus         `json:"status,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -57,4 +60,16 @@ func (in *LogicalVolumeGroup) DeepCopyInto(out *LogicalVolumeGroup) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// GetStatus returns status of the LogicalVolumeGroup
+func (in *LogicalVolumeGroup) GetStatus() *apiV1.CRStatus {
+	return &in.Status
+}
+
+// SyncConditions sets Ready and Healthy conditions from Status and Health of the LogicalVolumeGroup
+func (in *LogicalVolumeGroup) SyncConditions() {
+	apiV1.SyncStateCondition(&in.Status.Conditions, apiV1.ConditionReady, in.Spec.Status, apiV1.Created)
+	apiV1.SyncStateCondition(&in.Status.Conditions, apiV1.ConditionHealthy, in.Spec.Health, apiV1.HealthGood)
 }
@@ -0,0 +1,78 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storagequotacrd
+
+import (
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +kubebuilder:object:root=true
+
+// StorageQuota is the Schema for the storagequotas API
+// +kubebuilder:resource:scope=Cluster,shortName={sq,sqs}
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="NAMESPACE",type="string",JSONPath=".spec.namespace",description="Limited namespace"
+// +kubebuilder:printcolumn:name="STORAGE CLASS",type="string",JSONPath=".spec.storageClass",description="Limited storage class"
+// +kubebuilder:printcolumn:name="USED BYTES",type="integer",JSONPath=".status.usedBytes",description="Bytes used by volumes"
+// +kubebuilder:printcolumn:name="USED VOLUMES",type="integer",JSONPath=".status.usedVolumes",description="Amount of volumes"
+// +kubebuilder:printcolumn:name="USED DRIVES",type="integer",JSONPath=".status.usedDrives",description="Amount of drives which hold volumes"
+type StorageQuota struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   StorageQuotaSpec   `json:"spec,omitempty"`
+	Status StorageQuotaStatus `json:"status,omitempty"`
+}
+
+// StorageQuotaSpec defines limits for volumes of the namespace
+type StorageQuotaSpec struct {
+	// Namespace is a namespace which volumes are limited
+	Namespace string `json:"namespace"`
+	// StorageClass is a CSI storage class (HDD, SSD, NVME, HDDLVG, SSDLVG, NVMELVG, SYSLVG),
+	// quota is applied to all storage classes if it is empty
+	StorageClass string `json:"storageClass,omitempty"`
+	// MaxBytes is a limit for total size of the volumes, volume on the whole drive consumes the size of the drive
+	MaxBytes *resource.Quantity `json:"maxBytes,omitempty"`
+	// MaxVolumes is a limit for amount of the volumes
+	MaxVolumes *int64 `json:"maxVolumes,omitempty"`
+}
+
+// StorageQuotaStatus holds usage of the quota
+type StorageQuotaStatus struct {
+	// UsedBytes is a total size of the volumes
+	UsedBytes int64 `json:"usedBytes"`
+	// UsedVolumes is an amount of the volumes
+	UsedVolumes int64 `json:"usedVolumes"`
+	// UsedDrives is an amount of drives which hold the volumes, drives of LogicalVolumeGroup are counted as well
+	UsedDrives int64 `json:"usedDrives"`
+	// LastUpdateTime is the time when usage was calculated last time
+	LastUpdateTime metav1.Time `json:"lastUpdateTime,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// StorageQuotaList contains a list of StorageQuota
+type StorageQuotaList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []StorageQuota `json:"items"`
+}
+
+func init() {
+	SchemeBuilderStorageQuota.Register(&StorageQuota{}, &StorageQuotaList{})
+}
@@ -20,12 +20,14 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	api "github.com/dell/csi-baremetal/api/generated/v1"
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
 )
 
 // +kubebuilder:object:root=true
 
 // Volume is the Schema for the volumes API
 // +kubebuilder:resource:scope=Namespaced
+// +kubebuilder:storageversion
 // +kubebuilder:printcolumn:name="HEALTH",type="string",JSONPath=".spec.Health",description="Volume health status"
 // +kubebuilder:printcolumn:name="NODE",type="string",JSONPath=".spec.NodeId",description="Volume node location"
 // +kubebuilder:printcolumn:name="SIZE",type="string",JSONPath=".spec.Size",description="Volume allocated size"
@@ -36,7 +38,8 @@ type Volume struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 
-	Spec api.Volume `json:"spec,omitempty"`
+	Spec   api.Volume     `json:"spec,omitempty"`
+	Status apiV1.CRStatus `json:"status,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -55,8 +58,21 @@ func (in *Volume) DeepCopyInto(out *Volume) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 func init() {
 	SchemeBuilder.Register(&Volume{}, &VolumeList{})
 }
+
+// GetStatus returns status of the Volume
+func (in *Volume) GetStatus() *apiV1.CRStatus {
+	return &in.Status
+}
+
+// SyncConditions sets Ready and Healthy conditions from CSIStatus and Health of the Volume
+func (in *Volume) SyncConditions() {
+	apiV1.SyncStateCondition(&in.Status.Conditions, apiV1.ConditionReady, in.Spec.CSIStatus,
+		apiV1.Created, apiV1.VolumeReady, apiV1.Published, apiV1.Resized)
+	apiV1.SyncStateCondition(&in.Status.Conditions, apiV1.ConditionHealthy, in.Spec.Health, apiV1.HealthGood)
+}
@@ -0,0 +1,84 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package acrcrd
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	acrcrdv1 "github.com/dell/csi-baremetal/api/v1/acreservationcrd"
+)
+
+// ConvertTo converts AvailableCapacityReservation to the hub version v1
+func (in *AvailableCapacityReservation) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*acrcrdv1.AvailableCapacityReservation)
+	in.ObjectMeta.DeepCopyInto(&dst.ObjectMeta)
+	dst.Spec = api.AvailableCapacityReservation{
+		Namespace: in.Spec.Namespace,
+		Status:    in.Status.Status,
+		NodeRequests: &api.NodeRequests{
+			Requested: in.Spec.NodeRequests.Requested,
+			Reserved:  in.Spec.NodeRequests.Reserved,
+		},
+	}
+	for _, request := range in.Spec.ReservationRequests {
+		dst.Spec.ReservationRequests = append(dst.Spec.ReservationRequests, &api.ReservationRequest{
+			CapacityRequest: &api.CapacityRequest{
+				Name:         request.CapacityRequest.Name,
+				StorageClass: request.CapacityRequest.StorageClass,
+				Size:         request.CapacityRequest.Size,
+			},
+			Reservations: request.Reservations,
+		})
+	}
+	dst.Status = apiV1.CRStatus{
+		ObservedGeneration: in.Status.ObservedGeneration,
+		Conditions:         in.Status.Conditions,
+	}
+	return nil
+}
+
+// ConvertFrom converts AvailableCapacityReservation from the hub version v1
+func (in *AvailableCapacityReservation) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*acrcrdv1.AvailableCapacityReservation)
+	src.ObjectMeta.DeepCopyInto(&in.ObjectMeta)
+	in.Spec = AvailableCapacityReservationSpec{Namespace: src.Spec.Namespace}
+	if src.Spec.NodeRequests != nil {
+		in.Spec.NodeRequests = NodeRequests{
+			Requested: src.Spec.NodeRequests.Requested,
+			Reserved:  src.Spec.NodeRequests.Reserved,
+		}
+	}
+	for _, request := range src.Spec.ReservationRequests {
+		converted := ReservationRequest{Reservations: request.Reservations}
+		if request.CapacityRequest != nil {
+			converted.CapacityRequest = CapacityRequest{
+				Name:         request.CapacityRequest.Name,
+				StorageClass: request.CapacityRequest.StorageClass,
+				Size:         request.CapacityRequest.Size,
+			}
+		}
+		in.Spec.ReservationRequests = append(in.Spec.ReservationRequests, converted)
+	}
+	in.Status = AvailableCapacityReservationStatus{
+		Status:             src.Spec.Status,
+		ObservedGeneration: src.Status.ObservedGeneration,
+		Conditions:         src.Status.Conditions,
+	}
+	return nil
+}
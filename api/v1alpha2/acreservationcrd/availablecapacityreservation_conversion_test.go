@@ -0,0 +1,64 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package acrcrd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	acrcrdv1 "github.com/dell/csi-baremetal/api/v1/acreservationcrd"
+)
+
+func TestAvailableCapacityReservation_Conversion(t *testing.T) {
+	hub := &acrcrdv1.AvailableCapacityReservation{
+		ObjectMeta: metav1.ObjectMeta{Name: "default-pod"},
+		Spec: api.AvailableCapacityReservation{
+			Namespace: "default",
+			Status:    apiV1.ReservationConfirmed,
+			NodeRequests: &api.NodeRequests{
+				Requested: []string{"node-1", "node-2"},
+				Reserved:  []string{"node-1"},
+			},
+			ReservationRequests: []*api.ReservationRequest{{
+				CapacityRequest: &api.CapacityRequest{Name: "pvc-1", StorageClass: apiV1.StorageClassHDD, Size: 1024},
+				Reservations:    []string{"ac-1"},
+			}},
+		},
+		Status: apiV1.CRStatus{
+			Conditions: []apiV1.Condition{{Type: apiV1.ConditionReserved, Status: metav1.ConditionTrue}},
+		},
+	}
+
+	acr := &AvailableCapacityReservation{}
+	assert.Nil(t, acr.ConvertFrom(hub))
+	assert.Equal(t, hub.Spec.Status, acr.Status.Status)
+	assert.Equal(t, hub.Spec.NodeRequests.Reserved, acr.Spec.NodeRequests.Reserved)
+	assert.Equal(t, "pvc-1", acr.Spec.ReservationRequests[0].CapacityRequest.Name)
+
+	converted := &acrcrdv1.AvailableCapacityReservation{}
+	assert.Nil(t, acr.ConvertTo(converted))
+	assert.Equal(t, hub, converted)
+
+	// v1 ACR without node requests
+	hub.Spec.NodeRequests = nil
+	acr = &AvailableCapacityReservation{}
+	assert.Nil(t, acr.ConvertFrom(hub))
+	assert.Empty(t, acr.Spec.NodeRequests.Requested)
+}
@@ -0,0 +1,90 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package acrcrd
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+)
+
+// +kubebuilder:object:root=true
+
+// +kubebuilder:resource:scope=Cluster,shortName={acr,acrs}
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="NAMESPACE",type="string",JSONPath=".spec.namespace",description="Pod namespace"
+// +kubebuilder:printcolumn:name="STATUS",type="string",JSONPath=".status.status",description="Status of AvailableCapacityReservation"
+// AvailableCapacityReservation is the Schema for the availablecapacitiereservations API
+type AvailableCapacityReservation struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AvailableCapacityReservationSpec   `json:"spec,omitempty"`
+	Status AvailableCapacityReservationStatus `json:"status,omitempty"`
+}
+
+// AvailableCapacityReservationSpec defines capacity requested for volumes of the pod
+type AvailableCapacityReservationSpec struct {
+	Namespace           string               `json:"namespace,omitempty"`
+	NodeRequests        NodeRequests         `json:"nodeRequests,omitempty"`
+	ReservationRequests []ReservationRequest `json:"reservationRequests,omitempty"`
+}
+
+// NodeRequests holds nodes which are requested by scheduler and nodes where capacity is reserved
+type NodeRequests struct {
+	// Requested is filled by scheduler/extender
+	Requested []string `json:"requested,omitempty"`
+	// Reserved is filled by csi driver controller
+	Reserved []string `json:"reserved,omitempty"`
+}
+
+// ReservationRequest holds capacity request of the volume and reserved AvailableCapacities
+type ReservationRequest struct {
+	// CapacityRequest is filled by scheduler/extender
+	CapacityRequest CapacityRequest `json:"capacityRequest,omitempty"`
+	// Reservations are filled by csi driver controller
+	Reservations []string `json:"reservations,omitempty"`
+}
+
+// CapacityRequest is a request of the volume
+type CapacityRequest struct {
+	Name         string `json:"name,omitempty"`
+	StorageClass string `json:"storageClass,omitempty"`
+	Size         int64  `json:"size,omitempty"`
+}
+
+// AvailableCapacityReservationStatus holds runtime state of the reservation which is set by CSI components
+type AvailableCapacityReservationStatus struct {
+	Status string `json:"status,omitempty"`
+	// ObservedGeneration is the generation of the AvailableCapacityReservation which status was written upon
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Conditions of the AvailableCapacityReservation
+	Conditions []apiV1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// AvailableCapacityReservationList contains a list of AvailableCapacityReservation
+type AvailableCapacityReservationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AvailableCapacityReservation `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AvailableCapacityReservation{}, &AvailableCapacityReservationList{})
+}
@@ -0,0 +1,59 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package accrd
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	accrdv1 "github.com/dell/csi-baremetal/api/v1/availablecapacitycrd"
+)
+
+// ConvertTo converts AvailableCapacity to the hub version v1
+func (in *AvailableCapacity) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*accrdv1.AvailableCapacity)
+	in.ObjectMeta.DeepCopyInto(&dst.ObjectMeta)
+	dst.Spec = api.AvailableCapacity{
+		Location:     in.Spec.Location,
+		NodeId:       in.Spec.NodeID,
+		StorageClass: in.Spec.StorageClass,
+		Size:         in.Spec.Size,
+	}
+	dst.Status = apiV1.CRStatus{
+		ObservedGeneration: in.Status.ObservedGeneration,
+		Conditions:         in.Status.Conditions,
+	}
+	return nil
+}
+
+// ConvertFrom converts AvailableCapacity from the hub version v1
+func (in *AvailableCapacity) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*accrdv1.AvailableCapacity)
+	src.ObjectMeta.DeepCopyInto(&in.ObjectMeta)
+	in.Spec = AvailableCapacitySpec{
+		Location:     src.Spec.Location,
+		NodeID:       src.Spec.NodeId,
+		StorageClass: src.Spec.StorageClass,
+		Size:         src.Spec.Size,
+	}
+	in.Status = AvailableCapacityStatus{
+		ObservedGeneration: src.Status.ObservedGeneration,
+		Conditions:         src.Status.Conditions,
+	}
+	return nil
+}
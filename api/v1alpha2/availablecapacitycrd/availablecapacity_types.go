@@ -0,0 +1,68 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package accrd
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster,shortName={ac,acs}
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="LOCATION",type="string",JSONPath=".spec.location",description="Drive/LVG UUID used by AvailableCapacity"
+// +kubebuilder:printcolumn:name="NODE",type="string",JSONPath=".spec.nodeId",description="Node id of Available Capacity"
+// +kubebuilder:printcolumn:name="STORAGE CLASS",type="string",JSONPath=".spec.storageClass",description="StorageClass of AvailableCapacity"
+// +kubebuilder:printcolumn:name="SIZE",type="string",JSONPath=".spec.size",description="Size of AvailableCapacity"
+// AvailableCapacity is the Schema for the availablecapacities API
+type AvailableCapacity struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AvailableCapacitySpec   `json:"spec,omitempty"`
+	Status AvailableCapacityStatus `json:"status,omitempty"`
+}
+
+// AvailableCapacitySpec defines capacity of the drive or LVG which can be used for volumes
+type AvailableCapacitySpec struct {
+	Location     string `json:"location,omitempty"`
+	NodeID       string `json:"nodeId,omitempty"`
+	StorageClass string `json:"storageClass,omitempty"`
+	Size         int64  `json:"size,omitempty"`
+}
+
+// AvailableCapacityStatus holds status of the available capacity
+type AvailableCapacityStatus struct {
+	// ObservedGeneration is the generation of the AvailableCapacity which status was written upon
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Conditions of the AvailableCapacity
+	Conditions []apiV1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// AvailableCapacityList contains a list of AvailableCapacity
+type AvailableCapacityList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AvailableCapacity `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AvailableCapacity{}, &AvailableCapacityList{})
+}
@@ -0,0 +1,89 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package drivecrd
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	drivecrdv1 "github.com/dell/csi-baremetal/api/v1/drivecrd"
+)
+
+// ConvertTo converts Drive to the hub version v1
+func (in *Drive) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*drivecrdv1.Drive)
+	in.ObjectMeta.DeepCopyInto(&dst.ObjectMeta)
+	dst.Spec = api.Drive{
+		UUID:         in.Spec.UUID,
+		VID:          in.Spec.VID,
+		PID:          in.Spec.PID,
+		SerialNumber: in.Spec.SerialNumber,
+		Type:         in.Spec.Type,
+		Size:         in.Spec.Size,
+		NodeId:       in.Spec.NodeID,
+		Path:         in.Spec.Path,
+		Enclosure:    in.Spec.Enclosure,
+		Slot:         in.Spec.Slot,
+		Bay:          in.Spec.Bay,
+		Firmware:     in.Spec.Firmware,
+		Endurance:    in.Spec.Endurance,
+		IsSystem:     in.Spec.IsSystem,
+		Health:       in.Status.Health,
+		Status:       in.Status.Status,
+		Usage:        in.Status.Usage,
+		LEDState:     in.Status.LEDState,
+		IsClean:      in.Status.IsClean,
+	}
+	dst.Status = apiV1.CRStatus{
+		ObservedGeneration: in.Status.ObservedGeneration,
+		Conditions:         in.Status.Conditions,
+	}
+	return nil
+}
+
+// ConvertFrom converts Drive from the hub version v1
+func (in *Drive) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*drivecrdv1.Drive)
+	src.ObjectMeta.DeepCopyInto(&in.ObjectMeta)
+	in.Spec = DriveSpec{
+		UUID:         src.Spec.UUID,
+		VID:          src.Spec.VID,
+		PID:          src.Spec.PID,
+		SerialNumber: src.Spec.SerialNumber,
+		Type:         src.Spec.Type,
+		Size:         src.Spec.Size,
+		NodeID:       src.Spec.NodeId,
+		Path:         src.Spec.Path,
+		Enclosure:    src.Spec.Enclosure,
+		Slot:         src.Spec.Slot,
+		Bay:          src.Spec.Bay,
+		Firmware:     src.Spec.Firmware,
+		Endurance:    src.Spec.Endurance,
+		IsSystem:     src.Spec.IsSystem,
+	}
+	in.Status = DriveStatus{
+		Health:             src.Spec.Health,
+		Status:             src.Spec.Status,
+		Usage:              src.Spec.Usage,
+		LEDState:           src.Spec.LEDState,
+		IsClean:            src.Spec.IsClean,
+		ObservedGeneration: src.Status.ObservedGeneration,
+		Conditions:         src.Status.Conditions,
+	}
+	return nil
+}
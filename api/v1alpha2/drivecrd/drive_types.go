@@ -0,0 +1,87 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package drivecrd
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+)
+
+// +kubebuilder:object:root=true
+
+// Drive is the Schema for the drives API
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="SERIAL NUMBER",type="string",JSONPath=".spec.serialNumber",description="Drive serial number"
+// +kubebuilder:printcolumn:name="HEALTH",type="string",JSONPath=".status.health",description="Drive health status"
+// +kubebuilder:printcolumn:name="TYPE",type="string",JSONPath=".spec.type",description="Drive type (HDD/LVG/NVME)"
+// +kubebuilder:printcolumn:name="NODE",type="string",JSONPath=".spec.nodeId",description="Drive node location"
+// +kubebuilder:printcolumn:name="SIZE",type="string",JSONPath=".spec.size",description="Drive capacity"
+// +kubebuilder:printcolumn:name="SLOT",type="string",JSONPath=".spec.slot",description="Drive slot"
+type Drive struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DriveSpec   `json:"spec,omitempty"`
+	Status DriveStatus `json:"status,omitempty"`
+}
+
+// DriveSpec describes the discovered drive
+type DriveSpec struct {
+	UUID         string `json:"uuid,omitempty"`
+	VID          string `json:"vid,omitempty"`
+	PID          string `json:"pid,omitempty"`
+	SerialNumber string `json:"serialNumber,omitempty"`
+	Type         string `json:"type,omitempty"`
+	// Size in bytes
+	Size      int64  `json:"size,omitempty"`
+	NodeID    string `json:"nodeId,omitempty"`
+	Path      string `json:"path,omitempty"`
+	Enclosure string `json:"enclosure,omitempty"`
+	Slot      string `json:"slot,omitempty"`
+	Bay       string `json:"bay,omitempty"`
+	Firmware  string `json:"firmware,omitempty"`
+	Endurance int64  `json:"endurance,omitempty"`
+	IsSystem  bool   `json:"isSystem,omitempty"`
+}
+
+// DriveStatus holds runtime state of the drive which is set by CSI components
+type DriveStatus struct {
+	Health   string `json:"health,omitempty"`
+	Status   string `json:"status,omitempty"`
+	Usage    string `json:"usage,omitempty"`
+	LEDState string `json:"ledState,omitempty"`
+	IsClean  bool   `json:"isClean,omitempty"`
+	// ObservedGeneration is the generation of the Drive which status was written upon
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Conditions of the Drive
+	Conditions []apiV1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// DriveList contains a list of Drive
+type DriveList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Drive `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Drive{}, &DriveList{})
+}
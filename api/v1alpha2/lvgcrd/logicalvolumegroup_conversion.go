@@ -0,0 +1,65 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lvgcrd
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	lvgcrdv1 "github.com/dell/csi-baremetal/api/v1/lvgcrd"
+)
+
+// ConvertTo converts LogicalVolumeGroup to the hub version v1
+func (in *LogicalVolumeGroup) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*lvgcrdv1.LogicalVolumeGroup)
+	in.ObjectMeta.DeepCopyInto(&dst.ObjectMeta)
+	dst.Spec = api.LogicalVolumeGroup{
+		Name:       in.Spec.Name,
+		Node:       in.Spec.Node,
+		Locations:  in.Spec.Locations,
+		Size:       in.Spec.Size,
+		VolumeRefs: in.Spec.VolumeRefs,
+		Status:     in.Status.Status,
+		Health:     in.Status.Health,
+	}
+	dst.Status = apiV1.CRStatus{
+		ObservedGeneration: in.Status.ObservedGeneration,
+		Conditions:         in.Status.Conditions,
+	}
+	return nil
+}
+
+// ConvertFrom converts LogicalVolumeGroup from the hub version v1
+func (in *LogicalVolumeGroup) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*lvgcrdv1.LogicalVolumeGroup)
+	src.ObjectMeta.DeepCopyInto(&in.ObjectMeta)
+	in.Spec = LogicalVolumeGroupSpec{
+		Name:       src.Spec.Name,
+		Node:       src.Spec.Node,
+		Locations:  src.Spec.Locations,
+		Size:       src.Spec.Size,
+		VolumeRefs: src.Spec.VolumeRefs,
+	}
+	in.Status = LogicalVolumeGroupStatus{
+		Status:             src.Spec.Status,
+		Health:             src.Spec.Health,
+		ObservedGeneration: src.Status.ObservedGeneration,
+		Conditions:         src.Status.Conditions,
+	}
+	return nil
+}
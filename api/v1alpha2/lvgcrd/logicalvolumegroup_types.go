@@ -0,0 +1,72 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lvgcrd
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+)
+
+// +kubebuilder:object:root=true
+
+// +kubebuilder:resource:scope=Cluster,shortName={lvg,lvgs}
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="HEALTH",type="string",JSONPath=".status.health",description="LVG health status"
+// +kubebuilder:printcolumn:name="NODE",type="string",JSONPath=".spec.node",description="LVG node location"
+// +kubebuilder:printcolumn:name="SIZE",type="string",JSONPath=".spec.size",description="Size of Logical volume group"
+// +kubebuilder:printcolumn:name="LOCATIONS",type="string",JSONPath=".spec.locations",description="LVG drives locations list"
+// LogicalVolumeGroup is the Schema for the LVGs API
+type LogicalVolumeGroup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   LogicalVolumeGroupSpec   `json:"spec,omitempty"`
+	Status LogicalVolumeGroupStatus `json:"status,omitempty"`
+}
+
+// LogicalVolumeGroupSpec defines the volume group on the drives of the node
+type LogicalVolumeGroupSpec struct {
+	Name       string   `json:"name,omitempty"`
+	Node       string   `json:"node,omitempty"`
+	Locations  []string `json:"locations,omitempty"`
+	Size       int64    `json:"size,omitempty"`
+	VolumeRefs []string `json:"volumeRefs,omitempty"`
+}
+
+// LogicalVolumeGroupStatus holds runtime state of the volume group which is set by CSI components
+type LogicalVolumeGroupStatus struct {
+	Status string `json:"status,omitempty"`
+	Health string `json:"health,omitempty"`
+	// ObservedGeneration is the generation of the LogicalVolumeGroup which status was written upon
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Conditions of the LogicalVolumeGroup
+	Conditions []apiV1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// LogicalVolumeGroupList contains a list of LogicalVolumeGroup
+type LogicalVolumeGroupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []LogicalVolumeGroup `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&LogicalVolumeGroup{}, &LogicalVolumeGroupList{})
+}
@@ -0,0 +1,79 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package volumecrd
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	volumecrdv1 "github.com/dell/csi-baremetal/api/v1/volumecrd"
+)
+
+// ConvertTo converts Volume to the hub version v1
+func (in *Volume) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*volumecrdv1.Volume)
+	in.ObjectMeta.DeepCopyInto(&dst.ObjectMeta)
+	dst.Spec = api.Volume{
+		Id:                in.Spec.ID,
+		Location:          in.Spec.Location,
+		LocationType:      in.Spec.LocationType,
+		StorageClass:      in.Spec.StorageClass,
+		NodeId:            in.Spec.NodeID,
+		Owners:            in.Spec.Owners,
+		Size:              in.Spec.Size,
+		Mode:              in.Spec.Mode,
+		Type:              in.Spec.Type,
+		Ephemeral:         in.Spec.Ephemeral,
+		CSIStatus:         in.Status.CSIStatus,
+		Health:            in.Status.Health,
+		OperationalStatus: in.Status.OperationalStatus,
+		Usage:             in.Status.Usage,
+	}
+	dst.Status = apiV1.CRStatus{
+		ObservedGeneration: in.Status.ObservedGeneration,
+		Conditions:         in.Status.Conditions,
+	}
+	return nil
+}
+
+// ConvertFrom converts Volume from the hub version v1
+func (in *Volume) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*volumecrdv1.Volume)
+	src.ObjectMeta.DeepCopyInto(&in.ObjectMeta)
+	in.Spec = VolumeSpec{
+		ID:           src.Spec.Id,
+		Location:     src.Spec.Location,
+		LocationType: src.Spec.LocationType,
+		StorageClass: src.Spec.StorageClass,
+		NodeID:       src.Spec.NodeId,
+		Owners:       src.Spec.Owners,
+		Size:         src.Spec.Size,
+		Mode:         src.Spec.Mode,
+		Type:         src.Spec.Type,
+		Ephemeral:    src.Spec.Ephemeral,
+	}
+	in.Status = VolumeStatus{
+		CSIStatus:          src.Spec.CSIStatus,
+		Health:             src.Spec.Health,
+		OperationalStatus:  src.Spec.OperationalStatus,
+		Usage:              src.Spec.Usage,
+		ObservedGeneration: src.Status.ObservedGeneration,
+		Conditions:         src.Status.Conditions,
+	}
+	return nil
+}
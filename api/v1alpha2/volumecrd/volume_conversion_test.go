@@ -0,0 +1,63 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package volumecrd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	volumecrdv1 "github.com/dell/csi-baremetal/api/v1/volumecrd"
+)
+
+func TestVolume_Conversion(t *testing.T) {
+	hub := &volumecrdv1.Volume{
+		ObjectMeta: metav1.ObjectMeta{Name: "pvc-1", Namespace: "default", Generation: 2},
+		Spec: api.Volume{
+			Id:                "pvc-1",
+			Location:          "drive-uuid",
+			LocationType:      apiV1.LocationTypeDrive,
+			StorageClass:      apiV1.StorageClassHDD,
+			NodeId:            "node-uuid",
+			Owners:            []string{"pod"},
+			Size:              1024,
+			Mode:              apiV1.ModeFS,
+			Type:              "xfs",
+			Health:            apiV1.HealthGood,
+			CSIStatus:         apiV1.Published,
+			OperationalStatus: apiV1.OperationalStatusOperative,
+			Usage:             apiV1.VolumeUsageInUse,
+		},
+		Status: apiV1.CRStatus{
+			ObservedGeneration: 2,
+			Conditions:         []apiV1.Condition{{Type: apiV1.ConditionReady, Status: metav1.ConditionTrue}},
+		},
+	}
+
+	volume := &Volume{}
+	assert.Nil(t, volume.ConvertFrom(hub))
+	assert.Equal(t, hub.Name, volume.Name)
+	assert.Equal(t, hub.Spec.NodeId, volume.Spec.NodeID)
+	assert.Equal(t, hub.Spec.CSIStatus, volume.Status.CSIStatus)
+	assert.Equal(t, hub.Status.Conditions, volume.Status.Conditions)
+
+	converted := &volumecrdv1.Volume{}
+	assert.Nil(t, volume.ConvertTo(converted))
+	assert.Equal(t, hub, converted)
+}
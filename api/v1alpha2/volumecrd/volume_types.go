@@ -0,0 +1,81 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package volumecrd
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+)
+
+// +kubebuilder:object:root=true
+
+// Volume is the Schema for the volumes API
+// +kubebuilder:resource:scope=Namespaced
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="HEALTH",type="string",JSONPath=".status.health",description="Volume health status"
+// +kubebuilder:printcolumn:name="NODE",type="string",JSONPath=".spec.nodeId",description="Volume node location"
+// +kubebuilder:printcolumn:name="SIZE",type="string",JSONPath=".spec.size",description="Volume allocated size"
+// +kubebuilder:printcolumn:name="LOCATION",type="string",JSONPath=".spec.location",description="Volume LVG or drive location"
+// +kubebuilder:printcolumn:name="STORAGE CLASS",type="string",JSONPath=".spec.storageClass",description="Volume storage class"
+// +kubebuilder:printcolumn:name="CSI STATUS",type="string",JSONPath=".status.csiStatus",description="Volume internal CSI status"
+type Volume struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   VolumeSpec   `json:"spec,omitempty"`
+	Status VolumeStatus `json:"status,omitempty"`
+}
+
+// VolumeSpec defines the requested volume
+type VolumeSpec struct {
+	ID           string   `json:"id,omitempty"`
+	Location     string   `json:"location,omitempty"`
+	LocationType string   `json:"locationType,omitempty"`
+	StorageClass string   `json:"storageClass,omitempty"`
+	NodeID       string   `json:"nodeId,omitempty"`
+	Owners       []string `json:"owners,omitempty"`
+	Size         int64    `json:"size,omitempty"`
+	Mode         string   `json:"mode,omitempty"`
+	Type         string   `json:"type,omitempty"`
+	Ephemeral    bool     `json:"ephemeral,omitempty"`
+}
+
+// VolumeStatus holds runtime state of the volume which is set by CSI components
+type VolumeStatus struct {
+	CSIStatus         string `json:"csiStatus,omitempty"`
+	Health            string `json:"health,omitempty"`
+	OperationalStatus string `json:"operationalStatus,omitempty"`
+	Usage             string `json:"usage,omitempty"`
+	// ObservedGeneration is the generation of the Volume which status was written upon
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Conditions of the Volume
+	Conditions []apiV1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// VolumeList contains a list of Volume
+type VolumeList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Volume `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Volume{}, &VolumeList{})
+}
@@ -18,11 +18,15 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
+	"io/ioutil"
 	"net"
 	"net/http"
+	"path/filepath"
 	"strconv"
+	"strings"
 
 	"github.com/container-storage-interface/spec/lib/go/csi"
 	grpc_prometheus "github.com/grpc-ecosystem/go-grpc-prometheus"
@@ -31,6 +35,7 @@ import (
 	"github.com/sirupsen/logrus"
 	"google.golang.org/grpc"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -38,17 +43,35 @@ import (
 	acrcrd "github.com/dell/csi-baremetal/api/v1/acreservationcrd"
 	accrd "github.com/dell/csi-baremetal/api/v1/availablecapacitycrd"
 	"github.com/dell/csi-baremetal/api/v1/drivecrd"
+	fwcrd "github.com/dell/csi-baremetal/api/v1/firmwarepolicycrd"
 	"github.com/dell/csi-baremetal/api/v1/lvgcrd"
+	sqcrd "github.com/dell/csi-baremetal/api/v1/storagequotacrd"
 	"github.com/dell/csi-baremetal/api/v1/volumecrd"
+	acrcrdv1alpha2 "github.com/dell/csi-baremetal/api/v1alpha2/acreservationcrd"
+	accrdv1alpha2 "github.com/dell/csi-baremetal/api/v1alpha2/availablecapacitycrd"
+	drivecrdv1alpha2 "github.com/dell/csi-baremetal/api/v1alpha2/drivecrd"
+	lvgcrdv1alpha2 "github.com/dell/csi-baremetal/api/v1alpha2/lvgcrd"
+	volumecrdv1alpha2 "github.com/dell/csi-baremetal/api/v1alpha2/volumecrd"
 	"github.com/dell/csi-baremetal/pkg/base"
+	"github.com/dell/csi-baremetal/pkg/base/capacityplanner"
 	"github.com/dell/csi-baremetal/pkg/base/featureconfig"
 	"github.com/dell/csi-baremetal/pkg/base/k8s"
 	"github.com/dell/csi-baremetal/pkg/base/rpc"
+	"github.com/dell/csi-baremetal/pkg/base/tracing"
 	"github.com/dell/csi-baremetal/pkg/base/util"
 	"github.com/dell/csi-baremetal/pkg/controller"
 	"github.com/dell/csi-baremetal/pkg/controller/capacitycontroller"
+	"github.com/dell/csi-baremetal/pkg/crcontrollers/firmware"
+	"github.com/dell/csi-baremetal/pkg/crcontrollers/quota"
 	"github.com/dell/csi-baremetal/pkg/crcontrollers/reservation"
+	"github.com/dell/csi-baremetal/pkg/events"
 	"github.com/dell/csi-baremetal/pkg/metrics"
+	"github.com/dell/csi-baremetal/pkg/metrics/inventory"
+	"github.com/dell/csi-baremetal/pkg/webhook"
+)
+
+const (
+	componentName = "csi-baremetal-controller"
 )
 
 var (
@@ -59,11 +82,32 @@ var (
 	logPath    = flag.String("logpath", "", "Log path for Controller service")
 	useACRs    = flag.Bool("extender", false,
 		"Whether controller should read AvailableCapacityReservation CR during CreateVolume request or not")
+	useNodeAnnotation = flag.Bool("usenodeannotation", false,
+		"Whether controller should read id from node annotation and use it as id for all CRs or not")
+	useExternalAnnotation = flag.Bool("useexternalannotation", false,
+		"Whether controller read id from external annotation. It should exist before deployment. Use if \"usenodeannotation\" is True")
+	nodeIDAnnotation = flag.String("nodeidannotation", "",
+		"Custom node annotation name. Use if \"useexternalannotation\" is True")
 	logLevel = flag.String("loglevel", base.InfoLevel,
 		fmt.Sprintf("Log level, support values are %s, %s, %s", base.InfoLevel, base.DebugLevel, base.TraceLevel))
 	metricsAddress = flag.String("metrics-address", "", "The TCP network address where the prometheus metrics endpoint will run"+
 		"(example: :8080 which corresponds to port 8080 on local host). The default is empty string, which means metrics endpoint is disabled.")
-	metricspath = flag.String("metrics-path", "/metrics", "The HTTP path where prometheus metrics will be exposed. Default is /metrics.")
+	metricspath    = flag.String("metrics-path", "/metrics", "The HTTP path where prometheus metrics will be exposed. Default is /metrics.")
+	reservationTTL = flag.Duration("reservation-ttl", reservation.DefaultReservationTTL,
+		"Time after which REQUESTED and REJECTED AvailableCapacityReservations are removed")
+	webhookPort = flag.Int("webhook-port", 0,
+		"Port of the conversion and validating webhooks of custom resources, 0 disables webhooks")
+	webhookCertDir = flag.String("webhook-cert-dir", "/certs",
+		"Directory with tls.crt, tls.key and ca.crt of the webhook server")
+	webhookService = flag.String("webhook-service", "csi-baremetal-controller-webhook",
+		"Name of the service of the webhook server in the controller namespace")
+	webhookExemptUsers = flag.String("webhook-exempt-users", "",
+		"Comma separated users (service accounts of CSI components) which requests aren't validated by the webhook")
+	tracingExporter = flag.String("tracing-exporter", tracing.ExporterNone,
+		fmt.Sprintf("Exporter of OpenTelemetry traces, supported values are %s and %s. Tracing is disabled if empty",
+			tracing.ExporterOTLP, tracing.ExporterStdout))
+	tracingEndpoint = flag.String("tracing-endpoint", "",
+		"Address of OpenTelemetry collector which receives traces through OTLP gRPC, localhost:55680 is used if empty")
 )
 
 func main() {
@@ -71,6 +115,8 @@ func main() {
 
 	featureConf := featureconfig.NewFeatureConfig()
 	featureConf.Update(featureconfig.FeatureACReservation, *useACRs)
+	featureConf.Update(featureconfig.FeatureNodeIDFromAnnotation, *useNodeAnnotation)
+	featureConf.Update(featureconfig.FeatureExternalAnnotationForNode, *useExternalAnnotation)
 
 	var enableMetrics bool
 	if *metricspath != "" {
@@ -84,6 +130,12 @@ func main() {
 
 	logger.Info("Starting controller ...")
 
+	stopTracing, err := tracing.Init(*tracingExporter, *tracingEndpoint, componentName)
+	if err != nil {
+		logger.Fatalf("fail to initialize tracing: %v", err)
+	}
+	defer stopTracing()
+
 	csiControllerServer := rpc.NewServerRunner(nil, *endpoint, enableMetrics, logger)
 
 	k8SClient, err := k8s.GetK8SClient()
@@ -113,7 +165,7 @@ func main() {
 	}
 	stopCH := ctrl.SetupSignalHandler()
 	// todo make ACR feature mandatory and get rid of feature flag https://github.com/dell/csi-baremetal/issues/366
-	mgr, err := createManager(kubeClient, logger, featureConf.IsEnabled(featureconfig.FeatureACReservation), stopCH)
+	mgr, err := createManager(kubeClient, logger, featureConf, stopCH)
 	if err != nil {
 		logger.Fatal(err)
 	}
@@ -140,7 +192,8 @@ func main() {
 	logger.Info("Got SIGTERM signal")
 }
 
-func createManager(client *k8s.KubeClient, log *logrus.Logger, featureEnabled bool, ch <-chan struct{}) (ctrl.Manager, error) {
+func createManager(client *k8s.KubeClient, log *logrus.Logger, featureConf featureconfig.FeatureChecker,
+	ch <-chan struct{}) (ctrl.Manager, error) {
 	// create scheme
 	scheme := runtime.NewScheme()
 	if err := clientgoscheme.AddToScheme(scheme); err != nil {
@@ -164,33 +217,131 @@ func createManager(client *k8s.KubeClient, log *logrus.Logger, featureEnabled bo
 		return nil, err
 	}
 
+	if err := fwcrd.AddToSchemeFirmwarePolicy(scheme); err != nil {
+		return nil, err
+	}
+
+	if err := sqcrd.AddToSchemeStorageQuota(scheme); err != nil {
+		return nil, err
+	}
+
+	// both versions of the convertible CRs are needed by conversion webhook
+	for _, addToScheme := range []func(*runtime.Scheme) error{
+		volumecrd.AddToScheme,
+		volumecrdv1alpha2.AddToScheme,
+		drivecrdv1alpha2.AddToScheme,
+		lvgcrdv1alpha2.AddToScheme,
+		accrdv1alpha2.AddToScheme,
+		acrcrdv1alpha2.AddToScheme,
+	} {
+		if err := addToScheme(scheme); err != nil {
+			return nil, err
+		}
+	}
+
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme:    scheme,
 		Namespace: *namespace,
+		Port:      *webhookPort,
+		CertDir:   *webhookCertDir,
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	if featureEnabled {
+	if *webhookPort != 0 {
+		if err = setupConversionWebhook(mgr, client, log); err != nil {
+			return nil, err
+		}
+		var exemptUsers []string
+		if *webhookExemptUsers != "" {
+			exemptUsers = strings.Split(*webhookExemptUsers, ",")
+		}
+		webhook.RegisterValidation(mgr, webhook.NewValidator(client, exemptUsers, log))
+	}
+
+	eventRecorder, err := prepareEventRecorder(log)
+	if err != nil {
+		return nil, err
+	}
+
+	wrappedK8SClient := k8s.NewKubeClient(client, log, *namespace)
+	if featureConf.IsEnabled(featureconfig.FeatureACReservation) {
+		// in-memory index of ACs and ACRs which is updated from manager informers
+		capacityIndex := capacityplanner.NewCapacityIndex(log.WithField("component", "CapacityIndex"))
+		if err = capacityIndex.SetupInformers(mgr.GetCache()); err != nil {
+			return nil, err
+		}
 		// controller
-		reservationController := reservation.NewController(client, log)
+		reservationController := reservation.NewController(client, capacityIndex, log)
 		if err = reservationController.SetupWithManager(mgr); err != nil {
 			return nil, err
 		}
+		// garbage collector for reservations of removed or already scheduled pods
+		gcController := reservation.NewGCController(wrappedK8SClient, eventRecorder, *reservationTTL,
+			featureConf, *nodeIDAnnotation, log)
+		if err = gcController.SetupWithManager(mgr); err != nil {
+			return nil, err
+		}
 	}
-	wrappedK8SClient := k8s.NewKubeClient(client, log, *namespace)
 
 	kubeCache, err := k8s.InitKubeCache(log, ch,
-		&drivecrd.Drive{}, &accrd.AvailableCapacity{}, &volumecrd.Volume{})
+		&drivecrd.Drive{}, &accrd.AvailableCapacity{}, &volumecrd.Volume{}, &lvgcrd.LogicalVolumeGroup{})
 	if err != nil {
 		return nil, err
 	}
+	// collector is registered only when metrics endpoint is served, default metrics path is never empty
+	if *metricsAddress != "" {
+		// inventory metrics are read from the cache on each scrape
+		prometheus.MustRegister(inventory.NewCollector(kubeCache, log))
+	}
 
-	capacityController := capacitycontroller.NewCapacityController(wrappedK8SClient, kubeCache, log)
+	capacityController := capacitycontroller.NewCapacityController(wrappedK8SClient, kubeCache, eventRecorder, log)
 	// bind CSINodeService's VolumeManager to K8s Controller Manager as a driveLvgController for Volume CR
 	if err = capacityController.SetupWithManager(mgr); err != nil {
 		return nil, err
 	}
+
+	firmwareController := firmware.NewController(wrappedK8SClient, eventRecorder, log)
+	if err = firmwareController.SetupWithManager(mgr); err != nil {
+		return nil, err
+	}
+
+	quotaController := quota.NewController(wrappedK8SClient, log)
+	if err = quotaController.SetupWithManager(mgr); err != nil {
+		return nil, err
+	}
 	return mgr, nil
 }
+
+// setupConversionWebhook registers conversion webhook and points convertible CRDs to it
+func setupConversionWebhook(mgr ctrl.Manager, client *k8s.KubeClient, log *logrus.Logger) error {
+	caBundle, err := ioutil.ReadFile(filepath.Join(*webhookCertDir, "ca.crt"))
+	if err != nil {
+		return fmt.Errorf("unable to read CA of the webhook: %v", err)
+	}
+	webhook.RegisterConversion(mgr)
+	service := types.NamespacedName{Namespace: *namespace, Name: *webhookService}
+	return webhook.InjectConversionConfig(context.Background(), client, service, caBundle, log)
+}
+
+// prepareEventRecorder helper which makes all the work to get EventRecorder
+func prepareEventRecorder(logger *logrus.Logger) (*events.Recorder, error) {
+	k8SClientset, err := k8s.GetK8SClientset()
+	if err != nil {
+		return nil, fmt.Errorf("fail to create kubernetes client, error: %s", err)
+	}
+	eventInter := k8SClientset.CoreV1().Events("")
+
+	scheme, err := k8s.PrepareScheme()
+	if err != nil {
+		return nil, fmt.Errorf("fail to prepare kubernetes scheme, error: %s", err)
+	}
+
+	opt := events.Options{Logger: logger.WithField("componentName", "Events")}
+	eventRecorder, err := events.New(componentName, "", eventInter, scheme, opt)
+	if err != nil {
+		return nil, fmt.Errorf("fail to create events recorder, error: %s", err)
+	}
+	return eventRecorder, nil
+}
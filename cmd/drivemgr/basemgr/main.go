@@ -30,6 +30,9 @@ import (
 var (
 	endpoint = flag.String("drivemgrendpoint", base.DefaultDriveMgrEndpoint, "DriveManager Endpoint")
 	logPath  = flag.String("logpath", "", "log path for DriveManager")
+	tlsCert  = flag.String("tls-cert-file", "", "Path to the server certificate for mutual TLS")
+	tlsKey   = flag.String("tls-key-file", "", "Path to the server certificate key for mutual TLS")
+	tlsCA    = flag.String("tls-ca-file", "", "Path to the CA bundle for verification of client certificates")
 	logLevel = flag.String("loglevel", base.InfoLevel,
 		fmt.Sprintf("Log level, support values are %s, %s, %s", base.InfoLevel, base.DebugLevel, base.TraceLevel))
 )
@@ -42,8 +45,8 @@ func main() {
 		logger.Warnf("Can't set logger's output to %s. Using stdout instead.\n", *logPath)
 	}
 
-	// Server is insecure for now because credentials are nil
-	serverRunner := rpc.NewServerRunner(nil, *endpoint, false, logger)
+	serverRunner := dmsetup.NewServerRunner(*endpoint,
+		rpc.TLSFiles{CertFile: *tlsCert, KeyFile: *tlsKey, CAFile: *tlsCA}, logger)
 
 	e := command.NewExecutor(logger)
 
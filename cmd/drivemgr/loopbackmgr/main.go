@@ -36,6 +36,9 @@ import (
 var (
 	endpoint = flag.String("drivemgrendpoint", base.DefaultDriveMgrEndpoint, "DriveManager Endpoint")
 	logPath  = flag.String("logpath", "", "log path for DriveManager")
+	tlsCert  = flag.String("tls-cert-file", "", "Path to the server certificate for mutual TLS")
+	tlsKey   = flag.String("tls-key-file", "", "Path to the server certificate key for mutual TLS")
+	tlsCA    = flag.String("tls-ca-file", "", "Path to the CA bundle for verification of client certificates")
 	logLevel = flag.String("loglevel", base.InfoLevel,
 		fmt.Sprintf("Log level, support values are %s, %s, %s", base.InfoLevel, base.DebugLevel, base.TraceLevel))
 	useNodeAnnotation = flag.Bool("usenodeannotation", false,
@@ -70,8 +73,8 @@ func main() {
 		logger.Fatalf("fail to get nodeID, error: %v", err)
 	}
 
-	// Server is insecure for now because credentials are nil
-	serverRunner := rpc.NewServerRunner(nil, *endpoint, false, logger)
+	serverRunner := dmsetup.NewServerRunner(*endpoint,
+		rpc.TLSFiles{CertFile: *tlsCert, KeyFile: *tlsKey, CAFile: *tlsCA}, logger)
 
 	e := command.NewExecutor(logger)
 
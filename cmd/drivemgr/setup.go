@@ -18,8 +18,11 @@ limitations under the License.
 package dmsetup
 
 import (
+	"strings"
+
 	"github.com/sirupsen/logrus"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 
 	api "github.com/dell/csi-baremetal/api/generated/v1"
 	"github.com/dell/csi-baremetal/pkg/base/rpc"
@@ -45,3 +48,24 @@ func SetupAndRunDriveMgr(d drivemgr.DriveManager, sr *rpc.ServerRunner, cleanupF
 		logger.Fatalf("Failed to serve on %s. Error: %v", sr.Endpoint, err)
 	}
 }
+
+// NewServerRunner creates ServerRunner for DriveService with credentials which depend on endpoint and provided TLS files:
+// mutual TLS is used if TLS files are set, peer credentials check is used for unix socket
+// and insecure server is created otherwise
+func NewServerRunner(endpoint string, tlsFiles rpc.TLSFiles, logger *logrus.Logger) *rpc.ServerRunner {
+	var (
+		creds credentials.TransportCredentials
+		err   error
+	)
+	switch {
+	case tlsFiles.IsSet():
+		if creds, err = rpc.NewServerTLSCredentials(tlsFiles, logger); err != nil {
+			logger.Fatalf("Unable to load TLS credentials: %v", err)
+		}
+	case strings.HasPrefix(endpoint, "unix://"):
+		creds = rpc.NewPeerCredentials(nil, logger)
+	default:
+		logger.Warnf("TLS files weren't provided, DriveService on %s is insecure", endpoint)
+	}
+	return rpc.NewServerRunner(creds, endpoint, false, logger)
+}
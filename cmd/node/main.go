@@ -32,6 +32,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/sirupsen/logrus"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"gopkg.in/yaml.v2"
 	"k8s.io/apimachinery/pkg/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
@@ -45,9 +46,11 @@ import (
 	"github.com/dell/csi-baremetal/api/v1/lvgcrd"
 	"github.com/dell/csi-baremetal/api/v1/volumecrd"
 	"github.com/dell/csi-baremetal/pkg/base"
+	"github.com/dell/csi-baremetal/pkg/base/audit"
 	"github.com/dell/csi-baremetal/pkg/base/featureconfig"
 	"github.com/dell/csi-baremetal/pkg/base/k8s"
 	"github.com/dell/csi-baremetal/pkg/base/rpc"
+	"github.com/dell/csi-baremetal/pkg/base/tracing"
 	"github.com/dell/csi-baremetal/pkg/base/util"
 	"github.com/dell/csi-baremetal/pkg/crcontrollers/drive"
 	"github.com/dell/csi-baremetal/pkg/crcontrollers/lvg"
@@ -64,6 +67,9 @@ const (
 var (
 	namespace        = flag.String("namespace", "", "Namespace in which Node Service service run")
 	driveMgrEndpoint = flag.String("drivemgrendpoint", base.DefaultDriveMgrEndpoint, "Hardware Manager endpoint")
+	driveMgrTLSCert  = flag.String("drivemgr-tls-cert-file", "", "Path to the client certificate for mutual TLS with Hardware Manager")
+	driveMgrTLSKey   = flag.String("drivemgr-tls-key-file", "", "Path to the client certificate key for mutual TLS with Hardware Manager")
+	driveMgrTLSCA    = flag.String("drivemgr-tls-ca-file", "", "Path to the CA bundle for verification of Hardware Manager certificate")
 	healthIP         = flag.String("healthip", base.DefaultHealthIP, "Node health server ip")
 	csiEndpoint      = flag.String("csiendpoint", "unix:///tmp/csi.sock", "CSI endpoint")
 	nodeName         = flag.String("nodename", "", "node identification by k8s")
@@ -75,13 +81,31 @@ var (
 		"Whether node svc should read id from node annotation and use it as id for all CRs or not")
 	useExternalAnnotation = flag.Bool("useexternalannotation", false,
 		"Whether node svc should read id from external annotation. It should exist before deployment. Use if \"usenodeannotation\" is True")
+	lvgEvacuation = flag.Bool("lvg-evacuation", false,
+		"Whether node svc should move data of SUSPECT drive to another drive of LVG with pvmove instead of releasing volumes")
+	nodeReplacement = flag.Bool("node-replacement", false,
+		"Whether node svc should adopt drives and volumes of the OFFLINE node when drives are discovered on the current node")
 	nodeIDAnnotation = flag.String("nodeidannotation", "",
 		"Custom node annotation name. Use if \"useexternalannotation\" is True")
 	logLevel = flag.String("loglevel", base.InfoLevel,
 		fmt.Sprintf("Log level, support values are %s, %s, %s", base.InfoLevel, base.DebugLevel, base.TraceLevel))
 	metricsAddress = flag.String("metrics-address", "", "The TCP network address where the prometheus metrics endpoint will run"+
 		"(example: :8080 which corresponds to port 8080 on local host). The default is empty string, which means metrics endpoint is disabled.")
-	metricspath = flag.String("metrics-path", "/metrics", "The HTTP path where prometheus metrics will be exposed. Default is /metrics.")
+	metricspath   = flag.String("metrics-path", "/metrics", "The HTTP path where prometheus metrics will be exposed. Default is /metrics.")
+	auditInterval = flag.Duration("audit-interval", node.DefaultAuditInterval,
+		"Period of comparison of partitions, LVs and VGs with custom resources. Audit is disabled if 0")
+	auditPolicy = flag.String("audit-policy", node.AuditPolicyDryRun,
+		fmt.Sprintf("Storage audit policy, supported values are %s (report only) and %s (remove orphan LVs and empty VGs)",
+			node.AuditPolicyDryRun, node.AuditPolicyDelete))
+	tracingExporter = flag.String("tracing-exporter", tracing.ExporterNone,
+		fmt.Sprintf("Exporter of OpenTelemetry traces, supported values are %s and %s. Tracing is disabled if empty",
+			tracing.ExporterOTLP, tracing.ExporterStdout))
+	tracingEndpoint = flag.String("tracing-endpoint", "",
+		"Address of OpenTelemetry collector which receives traces through OTLP gRPC, localhost:55680 is used if empty")
+	diskAuditLog = flag.String("disk-audit-log", "",
+		"Path of the JSON audit log of destructive disk operations (wipefs, mkfs, parted, LVM removal). Audit is disabled if empty")
+	diskAuditEvents = flag.Bool("disk-audit-events", false,
+		"Whether to mirror entries of the disk audit log as Kubernetes Events of the affected Drive CRs")
 )
 
 func main() {
@@ -91,6 +115,8 @@ func main() {
 	featureConf.Update(featureconfig.FeatureACReservation, *useACRs)
 	featureConf.Update(featureconfig.FeatureNodeIDFromAnnotation, *useNodeAnnotation)
 	featureConf.Update(featureconfig.FeatureExternalAnnotationForNode, *useExternalAnnotation)
+	featureConf.Update(featureconfig.FeatureLVGEvacuation, *lvgEvacuation)
+	featureConf.Update(featureconfig.FeatureNodeReplacement, *nodeReplacement)
 
 	var enableMetrics bool
 	if *metricspath != "" {
@@ -104,10 +130,23 @@ func main() {
 
 	logger.Info("Starting Node Service")
 
+	stopTracing, err := tracing.Init(*tracingExporter, *tracingEndpoint, componentName)
+	if err != nil {
+		logger.Fatalf("fail to initialize tracing: %v", err)
+	}
+	defer stopTracing()
+
 	stopCH := ctrl.SetupSignalHandler()
 
-	// gRPC client for communication with DriveMgr via TCP socket
-	gRPCClient, err := rpc.NewClient(nil, *driveMgrEndpoint, enableMetrics, logger)
+	// gRPC client for communication with DriveMgr via TCP socket, mutual TLS is used if certificates are provided
+	var driveMgrCreds credentials.TransportCredentials
+	driveMgrTLSFiles := rpc.TLSFiles{CertFile: *driveMgrTLSCert, KeyFile: *driveMgrTLSKey, CAFile: *driveMgrTLSCA}
+	if driveMgrTLSFiles.IsSet() {
+		if driveMgrCreds, err = rpc.NewClientTLSCredentials(driveMgrTLSFiles, "", logger); err != nil {
+			logger.Fatalf("fail to load TLS credentials for DriveMgr client: %v", err)
+		}
+	}
+	gRPCClient, err := rpc.NewClient(driveMgrCreds, *driveMgrEndpoint, enableMetrics, logger)
 	if err != nil {
 		logger.Fatalf("fail to create grpc client for endpoint %s, error: %v", *driveMgrEndpoint, err)
 	}
@@ -132,6 +171,12 @@ func main() {
 	if err != nil {
 		logger.Fatalf("fail to get id of k8s Node object: %v", err)
 	}
+
+	stopAudit, err := audit.Init(*diskAuditLog, nodeID, logger)
+	if err != nil {
+		logger.Fatalf("fail to initialize disk audit log: %v", err)
+	}
+	defer stopAudit()
 	eventRecorder, err := prepareEventRecorder(*eventConfigPath, nodeID, logger)
 	if err != nil {
 		logger.Fatalf("fail to prepare event recorder: %v", err)
@@ -142,11 +187,14 @@ func main() {
 
 	csiNodeService := node.NewCSINodeService(
 		clientToDriveMgr, nodeID, logger, wrappedK8SClient, kubeCache, eventRecorder, featureConf)
+	if *diskAuditEvents {
+		audit.AddSink(csiNodeService.VolumeManager.SendAuditEvent)
+	}
 
 	mgr := prepareCRDControllerManagers(
 		csiNodeService,
 		lvg.NewController(wrappedK8SClient, nodeID, logger),
-		drive.NewController(wrappedK8SClient, nodeID, clientToDriveMgr, eventRecorder, logger),
+		drive.NewController(wrappedK8SClient, nodeID, clientToDriveMgr, eventRecorder, logger, featureConf),
 		logger)
 
 	// register CSI calls handler
@@ -160,6 +208,7 @@ func main() {
 		grpc_prometheus.EnableHandlingTimeHistogram()
 		grpc_prometheus.EnableClientHandlingTimeHistogram()
 		prometheus.MustRegister(metrics.BuildInfo)
+		prometheus.MustRegister(node.NewVolumeIOCollector(&csiNodeService.VolumeManager, node.DefaultSysfsPath, logger))
 
 		go func() {
 			http.Handle(*metricspath, promhttp.Handler())
@@ -183,6 +232,10 @@ func main() {
 		}
 	}()
 	go Discovering(csiNodeService, logger)
+	if *auditInterval > 0 {
+		auditor := node.NewStorageAuditor(&csiNodeService.VolumeManager, *auditPolicy, logger)
+		go auditor.Run(*auditInterval, stopCH)
+	}
 
 	logger.Info("Starting handle CSI calls ...")
 	if err := csiUDSServer.RunServer(); err != nil && err != grpc.ErrServerStopped {
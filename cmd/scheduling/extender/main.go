@@ -26,6 +26,8 @@ import (
 	storageV1 "k8s.io/api/storage/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 
+	"github.com/dell/csi-baremetal/api/v1/drivecrd"
+	sqcrd "github.com/dell/csi-baremetal/api/v1/storagequotacrd"
 	"github.com/dell/csi-baremetal/api/v1/volumecrd"
 	"github.com/dell/csi-baremetal/pkg/base"
 	"github.com/dell/csi-baremetal/pkg/base/featureconfig"
@@ -49,7 +51,9 @@ var (
 		"Custom node annotation name. Use if \"useexternalannotation\" is True")
 	metricsAddress = flag.String("metrics-address", "", "The TCP network address where the prometheus metrics endpoint will run"+
 		"(example: :8080 which corresponds to port 8080 on local host). The default is empty string, which means metrics endpoint is disabled.")
-	metricspath = flag.String("metrics-path", "/metrics", "The HTTP path where prometheus metrics will be exposed. Default is /metrics.")
+	metricspath     = flag.String("metrics-path", "/metrics", "The HTTP path where prometheus metrics will be exposed. Default is /metrics.")
+	scoringStrategy = flag.String("scoring-strategy", string(extender.VolumeCountStrategy),
+		"Strategy for nodes ranking on prioritize stage: volume-count, least-allocated, most-allocated or best-fit")
 )
 
 // TODO should be passed as parameters https://github.com/dell/csi-baremetal/issues/78
@@ -57,6 +61,7 @@ const (
 	FilterPattern     string = "/filter"
 	PrioritizePattern string = "/prioritize"
 	BindPattern       string = "/bind"
+	PreemptPattern    string = "/preempt"
 )
 
 func main() {
@@ -75,6 +80,11 @@ func main() {
 		}()
 	}
 
+	strategy, err := extender.ParseScoringStrategy(*scoringStrategy)
+	if err != nil {
+		logger.Fatal(err)
+	}
+
 	featureConf := featureconfig.NewFeatureConfig()
 	featureConf.Update(featureconfig.FeatureNodeIDFromAnnotation, *useNodeAnnotation)
 	featureConf.Update(featureconfig.FeatureExternalAnnotationForNode, *useExternalAnnotation)
@@ -88,7 +98,9 @@ func main() {
 	kubeCache, err := k8s.InitKubeCache(logger, stopCH,
 		&coreV1.PersistentVolumeClaim{},
 		&storageV1.StorageClass{},
-		&volumecrd.Volume{})
+		&volumecrd.Volume{},
+		&drivecrd.Drive{},
+		&sqcrd.StorageQuota{})
 
 	if err != nil {
 		logger.Fatalf("Fail to init kubeCache: %v", err)
@@ -98,6 +110,8 @@ func main() {
 	if err != nil {
 		logger.Fatalf("Fail to create extender: %v", err)
 	}
+	newExtender.SetScoringStrategy(strategy)
+	logger.Infof("Nodes are ranked with %s scoring strategy", strategy)
 
 	logger.Infof("Starting extender on port %d ...", *port)
 	// filter stage
@@ -108,6 +122,10 @@ func main() {
 	logger.Info("Registering for prioritize stage ... ")
 	http.HandleFunc(PrioritizePattern, newExtender.PrioritizeHandler)
 
+	// preempt stage
+	logger.Info("Registering for preempt stage ... ")
+	http.HandleFunc(PreemptPattern, newExtender.PreemptHandler)
+
 	// bind stage
 	logger.Infof("Registering for bind stage ... ")
 	http.HandleFunc(BindPattern, newExtender.BindHandler)
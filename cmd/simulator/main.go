@@ -0,0 +1,105 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package for main function of offline placement simulator
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/dell/csi-baremetal/pkg/base"
+	"github.com/dell/csi-baremetal/pkg/base/k8s"
+	"github.com/dell/csi-baremetal/pkg/simulator"
+)
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+)
+
+var (
+	scenarioFile = flag.String("scenario", "", "Path to YAML file with hypothetical volumes and nodes")
+	snapshot     = flag.String("snapshot", "",
+		"Comma separated paths to YAML dumps of AvailableCapacities and AvailableCapacityReservations "+
+			"(kubectl get ac,acr -o yaml). Capacity is read from the cluster if empty")
+	output   = flag.String("output", outputTable, fmt.Sprintf("Output format: %s or %s", outputTable, outputJSON))
+	logLevel = flag.String("loglevel", "",
+		fmt.Sprintf("Log level, support values are %s, %s, %s. Only warnings are logged by default",
+			base.InfoLevel, base.DebugLevel, base.TraceLevel))
+)
+
+func main() {
+	flag.Parse()
+
+	logger, _ := base.InitLogger("", *logLevel)
+	// report is written to stdout
+	logger.SetOutput(os.Stderr)
+	if *logLevel == "" {
+		logger.SetLevel(logrus.WarnLevel)
+	}
+
+	if *scenarioFile == "" || (*output != outputTable && *output != outputJSON) {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	scenario, err := simulator.LoadScenario(*scenarioFile)
+	if err != nil {
+		logger.Fatalf("Unable to load scenario: %v", err)
+	}
+
+	ctx := context.Background()
+	var capacity *simulator.Snapshot
+	if *snapshot != "" {
+		capacity, err = simulator.LoadSnapshot(strings.Split(*snapshot, ","))
+	} else {
+		capacity, err = readClusterSnapshot(ctx, logger)
+	}
+	if err != nil {
+		logger.Fatalf("Unable to load capacity: %v", err)
+	}
+
+	report, err := simulator.NewSimulator(logger.WithField("component", "Simulator")).Run(ctx, capacity, scenario)
+	if err != nil {
+		logger.Fatalf("Simulation failed: %v", err)
+	}
+
+	if *output == outputJSON {
+		err = report.WriteJSON(os.Stdout)
+	} else {
+		err = report.WriteTable(os.Stdout)
+	}
+	if err != nil {
+		logger.Fatalf("Unable to write report: %v", err)
+	}
+	if len(report.Unplaced) > 0 {
+		os.Exit(1)
+	}
+}
+
+func readClusterSnapshot(ctx context.Context, logger *logrus.Logger) (*simulator.Snapshot, error) {
+	k8sClient, err := k8s.GetK8SClient()
+	if err != nil {
+		return nil, err
+	}
+	return simulator.ReadSnapshot(ctx, k8s.NewKubeClient(k8sClient, logger, ""))
+}
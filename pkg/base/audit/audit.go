@@ -0,0 +1,211 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package audit contains append-only JSON log of destructive disk operations such as wipefs, parted rm, lvremove or mkfs.
+// Commands are recorded by executor if they are executed with command.Destructive option
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/dell/csi-baremetal/pkg/base"
+)
+
+const (
+	// ResultSuccess is a result of the command which was finished successfully
+	ResultSuccess = "success"
+	// ResultFailure is a result of the command which was finished with error
+	ResultFailure = "failure"
+	// SerialSeparator separates serial numbers of the drives in the target of commands which affect several drives
+	SerialSeparator = ","
+)
+
+// Entry is a record of the audit log, it is written as a single JSON line
+type Entry struct {
+	Time        time.Time `json:"time"`
+	Node        string    `json:"node"`
+	DriveSerial string    `json:"driveSerial,omitempty"`
+	VolumeID    string    `json:"volumeID,omitempty"`
+	RequestUUID string    `json:"requestUUID,omitempty"`
+	Command     string    `json:"command"`
+	Result      string    `json:"result"`
+	Error       string    `json:"error,omitempty"`
+}
+
+// Target holds drive and volume affected by destructive commands executed with the context,
+// DriveSerial contains serial numbers separated by SerialSeparator if command affects several drives, e.g. VG
+type Target struct {
+	DriveSerial string
+	VolumeID    string
+}
+
+// DrivesTarget returns target of the commands which affect all given drives, e.g. drives of VG
+func DrivesTarget(serials ...string) Target {
+	return Target{DriveSerial: strings.Join(serials, SerialSeparator)}
+}
+
+// DriveSerials returns serial numbers of the drives affected by the command of the entry
+func (e Entry) DriveSerials() []string {
+	if e.DriveSerial == "" {
+		return nil
+	}
+	return strings.Split(e.DriveSerial, SerialSeparator)
+}
+
+// Sink receives each entry after it is written to the audit log, e.g. to mirror it as Kubernetes Event
+type Sink func(entry Entry)
+
+type targetKey struct{}
+
+// WithTarget returns context with the target of destructive commands, fields which are empty in the given target
+// are kept from the parent context
+func WithTarget(ctx context.Context, target Target) context.Context {
+	parent := TargetFromContext(ctx)
+	if target.DriveSerial == "" {
+		target.DriveSerial = parent.DriveSerial
+	}
+	if target.VolumeID == "" {
+		target.VolumeID = parent.VolumeID
+	}
+	return context.WithValue(ctx, targetKey{}, target)
+}
+
+// TargetFromContext returns target stored by WithTarget or empty one
+func TargetFromContext(ctx context.Context) Target {
+	if ctx == nil {
+		return Target{}
+	}
+	target, _ := ctx.Value(targetKey{}).(Target)
+	return target
+}
+
+// fileLog writes audit entries to the file
+type fileLog struct {
+	mu    sync.Mutex
+	file  *os.File
+	node  string
+	sinks []Sink
+	log   *logrus.Entry
+}
+
+var (
+	globalMu     sync.RWMutex
+	globalLogger *fileLog
+)
+
+// Init opens audit log at the given path in append mode and sets it as a global audit logger of the node,
+// audit is disabled if path is empty.
+// Returns function which closes the audit log or error if the file can't be opened
+func Init(path, node string, logger *logrus.Logger) (func(), error) {
+	if path == "" {
+		return func() {}, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("unable to create directory of audit log %s: %v", path, err)
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open audit log %s: %v", path, err)
+	}
+
+	l := &fileLog{file: file, node: node, log: logger.WithField("component", "AuditLogger")}
+	globalMu.Lock()
+	globalLogger = l
+	globalMu.Unlock()
+
+	return func() {
+		globalMu.Lock()
+		if globalLogger == l {
+			globalLogger = nil
+		}
+		globalMu.Unlock()
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		_ = l.file.Close()
+	}, nil
+}
+
+// AddSink registers sink of the global audit logger, it is ignored if audit is disabled
+func AddSink(sink Sink) {
+	globalMu.RLock()
+	defer globalMu.RUnlock()
+	if globalLogger == nil {
+		return
+	}
+	globalLogger.mu.Lock()
+	defer globalLogger.mu.Unlock()
+	globalLogger.sinks = append(globalLogger.sinks, sink)
+}
+
+// Record writes entry about the command to the global audit log, it does nothing if audit is disabled.
+// Drive serial and volume ID are taken from the target of the context, request UUID from base.RequestUUID value
+func Record(ctx context.Context, cmd string, cmdErr error) {
+	globalMu.RLock()
+	l := globalLogger
+	globalMu.RUnlock()
+	if l == nil {
+		return
+	}
+	l.record(ctx, cmd, cmdErr)
+}
+
+func (l *fileLog) record(ctx context.Context, cmd string, cmdErr error) {
+	target := TargetFromContext(ctx)
+	entry := Entry{
+		Time:        time.Now().UTC(),
+		Node:        l.node,
+		DriveSerial: target.DriveSerial,
+		VolumeID:    target.VolumeID,
+		Command:     cmd,
+		Result:      ResultSuccess,
+	}
+	if ctx != nil {
+		if requestUUID, ok := ctx.Value(base.RequestUUID).(string); ok {
+			entry.RequestUUID = requestUUID
+		}
+	}
+	if cmdErr != nil {
+		entry.Result = ResultFailure
+		entry.Error = cmdErr.Error()
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		l.log.Errorf("Unable to marshal audit entry %v: %v", entry, err)
+		return
+	}
+
+	l.mu.Lock()
+	// entry is written by single call to be appended atomically
+	if _, err = l.file.Write(append(line, '\n')); err != nil {
+		l.log.Errorf("Unable to write audit entry %s: %v", line, err)
+	}
+	sinks := l.sinks
+	l.mu.Unlock()
+
+	for _, sink := range sinks {
+		sink(entry)
+	}
+}
@@ -0,0 +1,107 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dell/csi-baremetal/pkg/base"
+)
+
+var testCtx = context.Background()
+
+func readEntries(t *testing.T, path string) []Entry {
+	file, err := os.Open(path)
+	assert.Nil(t, err)
+	defer func() { _ = file.Close() }()
+
+	entries := make([]Entry, 0)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry Entry
+		assert.Nil(t, json.Unmarshal(scanner.Bytes(), &entry))
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestDrivesTarget(t *testing.T) {
+	target := DrivesTarget("SN1", "SN2")
+	assert.Equal(t, []string{"SN1", "SN2"}, Entry{DriveSerial: target.DriveSerial}.DriveSerials())
+	assert.Empty(t, DrivesTarget().DriveSerial)
+	assert.Nil(t, Entry{}.DriveSerials())
+}
+
+func TestRecord(t *testing.T) {
+	dir, err := ioutil.TempDir("", "audit")
+	assert.Nil(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+	path := filepath.Join(dir, "log", "audit.log")
+
+	// audit is disabled
+	Record(testCtx, "wipefs -af /dev/sda", nil)
+	stop, err := Init("", "node", logrus.New())
+	assert.Nil(t, err)
+	stop()
+
+	stop, err = Init(path, "node", logrus.New())
+	assert.Nil(t, err)
+	sunk := make([]Entry, 0)
+	AddSink(func(entry Entry) { sunk = append(sunk, entry) })
+
+	ctx := context.WithValue(testCtx, base.RequestUUID, "req-1")
+	ctx = WithTarget(ctx, Target{DriveSerial: "SN1"})
+	ctx = WithTarget(ctx, Target{VolumeID: "vol-1"})
+	Record(ctx, "wipefs -af /dev/sda", nil)
+	Record(testCtx, "lvremove -fy vg/lv", errors.New("error"))
+	stop()
+	// audit log is closed
+	Record(ctx, "mkfs.xfs /dev/sda", nil)
+
+	entries := readEntries(t, path)
+	assert.Equal(t, 2, len(entries))
+	assert.Equal(t, entries, sunk)
+
+	assert.Equal(t, "node", entries[0].Node)
+	assert.Equal(t, "SN1", entries[0].DriveSerial)
+	assert.Equal(t, "vol-1", entries[0].VolumeID)
+	assert.Equal(t, "req-1", entries[0].RequestUUID)
+	assert.Equal(t, "wipefs -af /dev/sda", entries[0].Command)
+	assert.Equal(t, ResultSuccess, entries[0].Result)
+	assert.Empty(t, entries[0].Error)
+
+	assert.Empty(t, entries[1].DriveSerial)
+	assert.Equal(t, ResultFailure, entries[1].Result)
+	assert.Equal(t, "error", entries[1].Error)
+
+	// log is appended
+	stop, err = Init(path, "node", logrus.New())
+	assert.Nil(t, err)
+	Record(ctx, "pvremove /dev/sda", nil)
+	stop()
+	assert.Equal(t, 3, len(readEntries(t, path)))
+}
@@ -0,0 +1,73 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package capacityplanner
+
+import (
+	"context"
+	"fmt"
+
+	coreV1 "k8s.io/api/core/v1"
+
+	genV1 "github.com/dell/csi-baremetal/api/generated/v1"
+	"github.com/dell/csi-baremetal/pkg/base/k8s"
+)
+
+// PlanningConstraints holds constraints which are applied by CapacityManager.PlanVolumesPlacing to volumes of the pod
+type PlanningConstraints struct {
+	// capacity request name to selector from StorageClass parameters
+	Selectors map[string]*ACSelector
+	// failure domain spreading constraint, nil when spreading isn't required
+	Spread *SpreadConstraint
+	// IDs of decommissioned nodes
+	Cordoned map[string]struct{}
+}
+
+// ResolvePlanningConstraints returns constraints of capacity planning for capacity requests of the pod,
+// spreading isn't resolved if pod is nil. Scheduler plugin and reservation controller must plan capacity
+// with the same constraints
+func ResolvePlanningConstraints(ctx context.Context, reader k8s.CRReader, namespace string, pod *coreV1.Pod,
+	requests []*genV1.CapacityRequest) (*PlanningConstraints, error) {
+	var (
+		constraints = &PlanningConstraints{}
+		err         error
+	)
+	// StorageClass of the volume might restrict drives and define fallback storage classes
+	if constraints.Selectors, err = ResolveACSelectors(ctx, reader, namespace, requests); err != nil {
+		return nil, fmt.Errorf("unable to resolve capacity selectors: %v", err)
+	}
+	// volumes of the same volume group are spread across failure domains
+	if pod != nil {
+		if constraints.Spread, err = ResolveSpreadConstraint(ctx, reader, pod); err != nil {
+			return nil, fmt.Errorf("unable to resolve spread constraint: %v", err)
+		}
+	}
+	// capacity of decommissioned nodes isn't reserved
+	if constraints.Cordoned, err = ResolveCordonedNodes(ctx, reader); err != nil {
+		return nil, fmt.Errorf("unable to resolve cordoned nodes: %v", err)
+	}
+	return constraints, nil
+}
+
+// WithPlanningConstraints returns context with all constraints of capacity planning
+func WithPlanningConstraints(ctx context.Context, constraints *PlanningConstraints) context.Context {
+	if constraints == nil {
+		return ctx
+	}
+	ctx = WithACSelectors(ctx, constraints.Selectors)
+	ctx = WithSpreadConstraint(ctx, constraints.Spread)
+	return WithCordonedNodes(ctx, constraints.Cordoned)
+}
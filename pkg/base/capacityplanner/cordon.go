@@ -0,0 +1,67 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package capacityplanner
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+
+	v1 "github.com/dell/csi-baremetal/api/v1"
+	"github.com/dell/csi-baremetal/api/v1/nodecrd"
+	"github.com/dell/csi-baremetal/pkg/base/k8s"
+)
+
+// cordonedNodesKey is a context key for IDs of cordoned nodes
+type cordonedNodesKey struct{}
+
+// WithCordonedNodes returns context with IDs of the nodes which capacity isn't used by CapacityManager.PlanVolumesPlacing
+func WithCordonedNodes(ctx context.Context, nodes map[string]struct{}) context.Context {
+	if len(nodes) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, cordonedNodesKey{}, nodes)
+}
+
+// isCordoned checks whether node is stored as cordoned by WithCordonedNodes
+func isCordoned(ctx context.Context, nodeID string) bool {
+	nodes, ok := ctx.Value(cordonedNodesKey{}).(map[string]struct{})
+	if !ok {
+		return false
+	}
+	_, cordoned := nodes[nodeID]
+	return cordoned
+}
+
+// ResolveCordonedNodes returns IDs of the nodes which are decommissioned, Node CR of such node has
+// v1.NodeAnnotationDecommission annotation. Returns empty result if Node CRD isn't installed
+func ResolveCordonedNodes(ctx context.Context, reader k8s.CRReader) (map[string]struct{}, error) {
+	nodes := map[string]struct{}{}
+	nodeList := &nodecrd.NodeList{}
+	if err := reader.ReadList(ctx, nodeList); err != nil {
+		if meta.IsNoMatchError(err) {
+			return nodes, nil
+		}
+		return nil, err
+	}
+	for i := range nodeList.Items {
+		if _, ok := nodeList.Items[i].Annotations[v1.NodeAnnotationDecommission]; ok {
+			nodes[nodeList.Items[i].Spec.UUID] = struct{}{}
+		}
+	}
+	return nodes, nil
+}
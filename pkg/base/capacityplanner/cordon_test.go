@@ -0,0 +1,61 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package capacityplanner
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	k8smetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	genV1 "github.com/dell/csi-baremetal/api/generated/v1"
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	"github.com/dell/csi-baremetal/api/v1/nodecrd"
+)
+
+func TestResolveCordonedNodes(t *testing.T) {
+	client := getKubeClient(t)
+	decommissioned := &nodecrd.Node{
+		ObjectMeta: k8smetav1.ObjectMeta{Name: "csibmnode-1",
+			Annotations: map[string]string{apiV1.NodeAnnotationDecommission: apiV1.DecommissionPolicyWait}},
+		Spec: genV1.Node{UUID: testNode1},
+	}
+	live := &nodecrd.Node{ObjectMeta: k8smetav1.ObjectMeta{Name: "csibmnode-2"}, Spec: genV1.Node{UUID: testNode2}}
+	assert.Nil(t, client.Create(context.Background(), decommissioned))
+	assert.Nil(t, client.Create(context.Background(), live))
+
+	nodes, err := ResolveCordonedNodes(context.Background(), client)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]struct{}{testNode1: {}}, nodes)
+}
+
+func TestCapacityManager_PlanVolumesPlacing_Cordoned(t *testing.T) {
+	logger := testLogger.WithField("component", "test")
+	index := NewCapacityIndex(logger)
+	nodes := []string{testNode1, testNode2}
+	for _, node := range nodes {
+		index.OnAdd(getTestAC(node, testLargeSize, apiV1.StorageClassHDD))
+	}
+	vol := getTestVol("", testSmallSize, apiV1.StorageClassHDD)
+
+	ctx := WithCordonedNodes(context.Background(), map[string]struct{}{testNode1: {}})
+	plan, err := NewCapacityManager(logger, index).PlanVolumesPlacing(ctx, []*genV1.Volume{vol}, nodes)
+	assert.Nil(t, err)
+	assert.Nil(t, plan.GetVolumesToACMapping(testNode1))
+	assert.NotNil(t, plan.GetVolumesToACMapping(testNode2))
+}
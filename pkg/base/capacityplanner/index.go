@@ -0,0 +1,313 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package capacityplanner
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/runtime"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+
+	acrcrd "github.com/dell/csi-baremetal/api/v1/acreservationcrd"
+	accrd "github.com/dell/csi-baremetal/api/v1/availablecapacitycrd"
+	"github.com/dell/csi-baremetal/pkg/base/util"
+)
+
+// NodeCapacityReader methods to read available capacity of the particular nodes
+type NodeCapacityReader interface {
+	// ReadCapacityForNodes read capacity located on the nodes
+	ReadCapacityForNodes(ctx context.Context, nodes []string) ([]accrd.AvailableCapacity, error)
+}
+
+// NewCapacityIndex returns empty instance of CapacityIndex
+func NewCapacityIndex(logger *logrus.Entry) *CapacityIndex {
+	return &CapacityIndex{
+		logger:   logger,
+		acs:      map[string]*accrd.AvailableCapacity{},
+		acrs:     map[string]*acrcrd.AvailableCapacityReservation{},
+		acrACs:   map[string][]string{},
+		reserved: map[string]int{},
+		free:     map[string]map[string][]*accrd.AvailableCapacity{},
+	}
+}
+
+// CapacityIndex keeps AC and ACR in memory and updates them incrementally from informer events
+// Unreserved ACs are indexed by node ID and storage class and sorted by size
+// CapacityIndex implements CapacityReader (returns unreserved ACs), NodeCapacityReader and ReservationReader
+type CapacityIndex struct {
+	sync.RWMutex
+	logger *logrus.Entry
+
+	acs  map[string]*accrd.AvailableCapacity
+	acrs map[string]*acrcrd.AvailableCapacityReservation
+	// ACR name to names of ACs included into ACR at the moment of indexing,
+	// ACR spec isn't deep copied so it might be modified by the owner of the object
+	acrACs map[string][]string
+	// AC name to count of ACRs which include it
+	reserved map[string]int
+	// node ID to storage class to unreserved ACs sorted by size
+	free map[string]map[string][]*accrd.AvailableCapacity
+}
+
+// SetupInformers subscribes CapacityIndex to AC and ACR informers
+func (ci *CapacityIndex) SetupInformers(informers cache.Informers) error {
+	for _, obj := range []runtime.Object{&accrd.AvailableCapacity{}, &acrcrd.AvailableCapacityReservation{}} {
+		informer, err := informers.GetInformer(obj)
+		if err != nil {
+			return err
+		}
+		informer.AddEventHandler(ci)
+	}
+	return nil
+}
+
+// OnAdd handles informer add event
+func (ci *CapacityIndex) OnAdd(obj interface{}) {
+	switch o := obj.(type) {
+	case *accrd.AvailableCapacity:
+		ci.UpdateAC(o)
+	case *acrcrd.AvailableCapacityReservation:
+		ci.UpdateACR(o)
+	}
+}
+
+// OnUpdate handles informer update event
+func (ci *CapacityIndex) OnUpdate(_, newObj interface{}) {
+	ci.OnAdd(newObj)
+}
+
+// OnDelete handles informer delete event
+func (ci *CapacityIndex) OnDelete(obj interface{}) {
+	if tombstone, ok := obj.(toolscache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	switch o := obj.(type) {
+	case *accrd.AvailableCapacity:
+		ci.DeleteAC(o.Name)
+	case *acrcrd.AvailableCapacityReservation:
+		ci.DeleteACR(o.Name)
+	}
+}
+
+// UpdateAC adds AC to the index or replaces existing one
+func (ci *CapacityIndex) UpdateAC(ac *accrd.AvailableCapacity) {
+	ci.Lock()
+	defer ci.Unlock()
+
+	if old, ok := ci.acs[ac.Name]; ok {
+		if !isNewerVersion(ac.ResourceVersion, old.ResourceVersion) {
+			return
+		}
+		ci.removeFree(old)
+	}
+	ac = ac.DeepCopy()
+	ci.acs[ac.Name] = ac
+	if ci.reserved[ac.Name] == 0 {
+		ci.addFree(ac)
+	}
+}
+
+// DeleteAC removes AC from the index
+func (ci *CapacityIndex) DeleteAC(name string) {
+	ci.Lock()
+	defer ci.Unlock()
+
+	if ac, ok := ci.acs[name]; ok {
+		ci.removeFree(ac)
+		delete(ci.acs, name)
+	}
+}
+
+// UpdateACR adds ACR to the index or replaces existing one, ACs from the ACR become reserved
+// Should be called right after ACR was updated through API to avoid planning on stale data
+func (ci *CapacityIndex) UpdateACR(acr *acrcrd.AvailableCapacityReservation) {
+	ci.Lock()
+	defer ci.Unlock()
+
+	if old, ok := ci.acrs[acr.Name]; ok {
+		if !isNewerVersion(acr.ResourceVersion, old.ResourceVersion) {
+			return
+		}
+		ci.releaseACs(old.Name)
+	}
+	acr = acr.DeepCopy()
+	ci.acrs[acr.Name] = acr
+	ci.acrACs[acr.Name] = acNamesOfACR(acr)
+	ci.reserveACs(acr.Name)
+}
+
+// DeleteACR removes ACR from the index, ACs from the ACR become free
+func (ci *CapacityIndex) DeleteACR(name string) {
+	ci.Lock()
+	defer ci.Unlock()
+
+	if _, ok := ci.acrs[name]; ok {
+		ci.releaseACs(name)
+		delete(ci.acrs, name)
+		delete(ci.acrACs, name)
+	}
+}
+
+// ReadCapacity returns unreserved ACs
+func (ci *CapacityIndex) ReadCapacity(ctx context.Context) ([]accrd.AvailableCapacity, error) {
+	ci.RLock()
+	defer ci.RUnlock()
+
+	var result []accrd.AvailableCapacity
+	for _, scToACs := range ci.free {
+		for _, acs := range scToACs {
+			result = appendACs(result, acs)
+		}
+	}
+	util.AddCommonFields(ctx, ci.logger, "CapacityIndex.ReadCapacity").
+		Tracef("Read %d unreserved AvailableCapacity", len(result))
+	return result, nil
+}
+
+// ReadCapacityForNodes returns unreserved ACs located on the nodes
+func (ci *CapacityIndex) ReadCapacityForNodes(ctx context.Context, nodes []string) ([]accrd.AvailableCapacity, error) {
+	ci.RLock()
+	defer ci.RUnlock()
+
+	var result []accrd.AvailableCapacity
+	for _, node := range nodes {
+		for _, acs := range ci.free[node] {
+			result = appendACs(result, acs)
+		}
+	}
+	util.AddCommonFields(ctx, ci.logger, "CapacityIndex.ReadCapacityForNodes").
+		Tracef("Read %d unreserved AvailableCapacity on %d nodes", len(result), len(nodes))
+	return result, nil
+}
+
+// ReadReservations returns all ACRs
+func (ci *CapacityIndex) ReadReservations(_ context.Context) ([]acrcrd.AvailableCapacityReservation, error) {
+	ci.RLock()
+	defer ci.RUnlock()
+
+	result := make([]acrcrd.AvailableCapacityReservation, 0, len(ci.acrs))
+	for _, acr := range ci.acrs {
+		result = append(result, *acr.DeepCopy())
+	}
+	return result, nil
+}
+
+// FreeCapacity returns unreserved ACs with storage class sc on the node sorted by size
+func (ci *CapacityIndex) FreeCapacity(node, sc string) []accrd.AvailableCapacity {
+	ci.RLock()
+	defer ci.RUnlock()
+
+	return appendACs(nil, ci.free[node][sc])
+}
+
+func (ci *CapacityIndex) reserveACs(acrName string) {
+	for _, name := range ci.acrACs[acrName] {
+		ci.reserved[name]++
+		if ci.reserved[name] == 1 {
+			if ac, ok := ci.acs[name]; ok {
+				ci.removeFree(ac)
+			}
+		}
+	}
+}
+
+func (ci *CapacityIndex) releaseACs(acrName string) {
+	for _, name := range ci.acrACs[acrName] {
+		ci.reserved[name]--
+		if ci.reserved[name] > 0 {
+			continue
+		}
+		delete(ci.reserved, name)
+		if ac, ok := ci.acs[name]; ok {
+			ci.addFree(ac)
+		}
+	}
+}
+
+// addFree adds AC to the free capacity of its node, ACs which are being deleted are never free
+func (ci *CapacityIndex) addFree(ac *accrd.AvailableCapacity) {
+	if !ac.DeletionTimestamp.IsZero() {
+		return
+	}
+	node, sc := ac.Spec.NodeId, ac.Spec.StorageClass
+	if _, ok := ci.free[node]; !ok {
+		ci.free[node] = map[string][]*accrd.AvailableCapacity{}
+	}
+	acs := ci.free[node][sc]
+	i := sort.Search(len(acs), func(i int) bool { return acs[i].Spec.Size >= ac.Spec.Size })
+	acs = append(acs, nil)
+	copy(acs[i+1:], acs[i:])
+	acs[i] = ac
+	ci.free[node][sc] = acs
+}
+
+func (ci *CapacityIndex) removeFree(ac *accrd.AvailableCapacity) {
+	node, sc := ac.Spec.NodeId, ac.Spec.StorageClass
+	acs := ci.free[node][sc]
+	i := sort.Search(len(acs), func(i int) bool { return acs[i].Spec.Size >= ac.Spec.Size })
+	for ; i < len(acs) && acs[i].Spec.Size == ac.Spec.Size; i++ {
+		if acs[i].Name != ac.Name {
+			continue
+		}
+		acs = append(acs[:i], acs[i+1:]...)
+		break
+	}
+	if len(acs) == 0 {
+		delete(ci.free[node], sc)
+		if len(ci.free[node]) == 0 {
+			delete(ci.free, node)
+		}
+		return
+	}
+	ci.free[node][sc] = acs
+}
+
+// acNamesOfACR returns names of ACs included into ACR
+func acNamesOfACR(acr *acrcrd.AvailableCapacityReservation) []string {
+	var names []string
+	for _, request := range acr.Spec.ReservationRequests {
+		names = append(names, request.Reservations...)
+	}
+	return names
+}
+
+// appendACs appends copies of ACs to the list
+func appendACs(list []accrd.AvailableCapacity, acs []*accrd.AvailableCapacity) []accrd.AvailableCapacity {
+	for _, ac := range acs {
+		list = append(list, *ac.DeepCopy())
+	}
+	return list
+}
+
+// isNewerVersion checks that object with resource version newRV isn't older than object with oldRV
+// informer event might come after the object was updated in the index by the API response
+func isNewerVersion(newRV, oldRV string) bool {
+	newV, err := strconv.ParseUint(newRV, 10, 64)
+	if err != nil {
+		return true
+	}
+	oldV, err := strconv.ParseUint(oldRV, 10, 64)
+	if err != nil {
+		return true
+	}
+	return newV >= oldV
+}
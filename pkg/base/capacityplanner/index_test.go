@@ -0,0 +1,156 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package capacityplanner
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	toolscache "k8s.io/client-go/tools/cache"
+
+	genV1 "github.com/dell/csi-baremetal/api/generated/v1"
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	accrd "github.com/dell/csi-baremetal/api/v1/availablecapacitycrd"
+)
+
+func TestCapacityIndex(t *testing.T) {
+	ctx := context.Background()
+	index := NewCapacityIndex(testLogger.WithField("component", "test"))
+
+	large := getTestAC(testNode1, testLargeSize, apiV1.StorageClassHDD)
+	small := getTestAC(testNode1, testSmallSize, apiV1.StorageClassHDD)
+	ssd := getTestAC(testNode1, testSmallSize, apiV1.StorageClassSSD)
+	another := getTestAC(testNode2, testSmallSize, apiV1.StorageClassHDD)
+	for _, ac := range []*accrd.AvailableCapacity{large, small, ssd, another} {
+		index.OnAdd(ac)
+	}
+
+	// sorted by size
+	free := index.FreeCapacity(testNode1, apiV1.StorageClassHDD)
+	assert.Equal(t, 2, len(free))
+	assert.Equal(t, small.Name, free[0].Name)
+	assert.Equal(t, large.Name, free[1].Name)
+
+	acs, err := index.ReadCapacity(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, 4, len(acs))
+	acs, err = index.ReadCapacityForNodes(ctx, []string{testNode2})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(acs))
+	assert.Equal(t, another.Name, acs[0].Name)
+
+	// reserved AC isn't free
+	acr := getTestACR(testSmallSize, apiV1.StorageClassHDD, []*accrd.AvailableCapacity{small})
+	acr.ResourceVersion = "2"
+	index.OnAdd(acr)
+	free = index.FreeCapacity(testNode1, apiV1.StorageClassHDD)
+	assert.Equal(t, 1, len(free))
+	assert.Equal(t, large.Name, free[0].Name)
+	acrs, err := index.ReadReservations(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(acrs))
+
+	// stale ACR version is ignored
+	staleACR := acr.DeepCopy()
+	staleACR.ResourceVersion = "1"
+	staleACR.Spec.ReservationRequests[0].Reservations = nil
+	index.OnUpdate(nil, staleACR)
+	assert.Equal(t, 1, len(index.FreeCapacity(testNode1, apiV1.StorageClassHDD)))
+
+	// AC size changed
+	updated := large.DeepCopy()
+	updated.Spec.Size = testSmallSize / 2
+	index.OnUpdate(large, updated)
+	free = index.FreeCapacity(testNode1, apiV1.StorageClassHDD)
+	assert.Equal(t, 1, len(free))
+	assert.Equal(t, testSmallSize/2, free[0].Spec.Size)
+
+	// reservation removed
+	index.OnDelete(toolscache.DeletedFinalStateUnknown{Obj: acr})
+	free = index.FreeCapacity(testNode1, apiV1.StorageClassHDD)
+	assert.Equal(t, 2, len(free))
+	assert.Equal(t, updated.Name, free[0].Name)
+
+	// AC is being deleted
+	deleting := ssd.DeepCopy()
+	now := metav1.Now()
+	deleting.DeletionTimestamp = &now
+	index.OnUpdate(ssd, deleting)
+	assert.Equal(t, 0, len(index.FreeCapacity(testNode1, apiV1.StorageClassSSD)))
+
+	// AC removed
+	index.OnDelete(another)
+	assert.Equal(t, 0, len(index.FreeCapacity(testNode2, apiV1.StorageClassHDD)))
+	acs, err = index.ReadCapacityForNodes(ctx, []string{testNode2})
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(acs))
+}
+
+func TestCapacityIndex_PlanVolumesPlacing(t *testing.T) {
+	ctx := context.Background()
+	logger := testLogger.WithField("component", "test")
+	index := NewCapacityIndex(logger)
+
+	ac1 := getTestAC(testNode1, testSmallSize, apiV1.StorageClassHDD)
+	ac2 := getTestAC(testNode2, testSmallSize, apiV1.StorageClassHDD)
+	index.OnAdd(ac1)
+	index.OnAdd(ac2)
+	index.OnAdd(getTestACR(testSmallSize, apiV1.StorageClassHDD, []*accrd.AvailableCapacity{ac1}))
+
+	vol := getTestVol("", testSmallSize, apiV1.StorageClassHDD)
+	plan, err := NewCapacityManager(logger, index).PlanVolumesPlacing(ctx, []*genV1.Volume{vol},
+		[]string{testNode1, testNode2})
+	assert.Nil(t, err)
+	assert.NotNil(t, plan)
+	assert.Nil(t, plan.GetVolumesToACMapping(testNode1))
+	assert.Equal(t, ac2.Name, plan.GetACForVolume(testNode2, vol).Name)
+}
+
+// BenchmarkCapacityIndex_PlanVolumesPlacing shows that planning time for the requested nodes
+// doesn't depend on the cluster size when capacity index is used
+func BenchmarkCapacityIndex_PlanVolumesPlacing(b *testing.B) {
+	const (
+		acPerNode        = 20
+		requestedNodeNum = 3
+	)
+	logger := testLogger.WithField("component", "benchmark")
+	ctx := context.Background()
+
+	for _, nodeNum := range []int{10, 100, 1000} {
+		index := NewCapacityIndex(logger)
+		nodes := make([]string, nodeNum)
+		for i := 0; i < nodeNum; i++ {
+			nodes[i] = fmt.Sprintf("node-%d", i)
+			for j := 0; j < acPerNode; j++ {
+				index.OnAdd(getTestAC(nodes[i], testSmallSize*int64(j+1), apiV1.StorageClassHDD))
+			}
+		}
+		volumes := []*genV1.Volume{getTestVol("", testLargeSize, apiV1.StorageClassHDD)}
+
+		b.Run(fmt.Sprintf("nodes-%d", nodeNum), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				capManager := NewCapacityManager(logger, index)
+				if _, err := capManager.PlanVolumesPlacing(ctx, volumes, nodes[:requestedNodeNum]); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -66,18 +66,19 @@ func (nc *nodeCapacity) removeAC(ac *accrd.AvailableCapacity) {
 	delete(nc.capacity, ac.Name)
 }
 
-// selectACForVolume select AC for volume
+// selectACForVolume select AC for volume, only ACs for which match returns true are considered (all if nil)
 // will modify nodeCapacity AC cache
-func (nc *nodeCapacity) selectACForVolume(vol *genV1.Volume) *accrd.AvailableCapacity {
+func (nc *nodeCapacity) selectACForVolume(vol *genV1.Volume, match func(*accrd.AvailableCapacity) bool) *accrd.AvailableCapacity {
 	if util.IsStorageClassLVG(vol.StorageClass) {
-		return nc.selectACForLVMVolume(vol)
+		return nc.selectACForLVMVolume(vol, match)
 	}
-	return nc.selectACForFullDriveVolume(vol)
+	return nc.selectACForFullDriveVolume(vol, match)
 }
 
 // selectACForFullDriveVolume selects AC for ANY SC or for other "full drive" storage classes.
-func (nc *nodeCapacity) selectACForFullDriveVolume(vol *genV1.Volume) *accrd.AvailableCapacity {
-	scToACMap := nc.getStorageClassToACMapping()
+func (nc *nodeCapacity) selectACForFullDriveVolume(vol *genV1.Volume,
+	match func(*accrd.AvailableCapacity) bool) *accrd.AvailableCapacity {
+	scToACMap := nc.getStorageClassToACMapping(match)
 
 	if len(scToACMap[vol.StorageClass]) == 0 &&
 		vol.StorageClass != v1.StorageClassAny {
@@ -118,11 +119,12 @@ func (nc *nodeCapacity) selectACForFullDriveVolume(vol *genV1.Volume) *accrd.Ava
 
 // selectACForLVMVolume selects AC for Volume with LVM SC
 // first we try to find AC with LVM AC, if not found full drive AC will be converted to LVM AC
-func (nc *nodeCapacity) selectACForLVMVolume(vol *genV1.Volume) *accrd.AvailableCapacity {
+func (nc *nodeCapacity) selectACForLVMVolume(vol *genV1.Volume,
+	match func(*accrd.AvailableCapacity) bool) *accrd.AvailableCapacity {
 	// extract drive technology - HDD,SSD, etc.
 	subSC := util.GetSubStorageClass(vol.StorageClass)
 
-	scToACMap := nc.getStorageClassToACMapping()
+	scToACMap := nc.getStorageClassToACMapping(match)
 	if len(scToACMap[vol.StorageClass]) == 0 &&
 		len(scToACMap[subSC]) == 0 {
 		return nil
@@ -157,9 +159,13 @@ func (nc *nodeCapacity) selectACForLVMVolume(vol *genV1.Volume) *accrd.Available
 	return nc.getOriginalAC(foundAC.Name)
 }
 
-func (nc *nodeCapacity) getStorageClassToACMapping() SCToACMap {
+// getStorageClassToACMapping groups ACs by storage class, ACs for which match returns false are skipped
+func (nc *nodeCapacity) getStorageClassToACMapping(match func(*accrd.AvailableCapacity) bool) SCToACMap {
 	result := SCToACMap{}
 	for _, ac := range nc.capacity {
+		if match != nil && !match(ac) {
+			continue
+		}
 		if _, ok := result[ac.Spec.StorageClass]; !ok {
 			result[ac.Spec.StorageClass] = map[string]*accrd.AvailableCapacity{}
 		}
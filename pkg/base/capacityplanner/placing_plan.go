@@ -60,8 +60,13 @@ func (vpp *VolumesPlacingPlan) SelectNode() string {
 	if len(suitableNodes) == 0 {
 		return ""
 	}
+	// nodes with the same amount of capacity are ordered by name to make selection deterministic
 	sort.Slice(suitableNodes, func(i, j int) bool {
-		return len(vpp.capacity[suitableNodes[i]]) > len(vpp.capacity[suitableNodes[j]])
+		iLen, jLen := len(vpp.capacity[suitableNodes[i]]), len(vpp.capacity[suitableNodes[j]])
+		if iLen != jLen {
+			return iLen > jLen
+		}
+		return suitableNodes[i] < suitableNodes[j]
 	})
 	return suitableNodes[0]
 }
@@ -114,19 +114,26 @@ type CapacityManager struct {
 // PlanVolumesPlacing build placing plan for volumes
 func (cm *CapacityManager) PlanVolumesPlacing(ctx context.Context, volumes []*genV1.Volume, nodes []string) (*VolumesPlacingPlan, error) {
 	logger := util.AddCommonFields(ctx, cm.logger, "CapacityManager.PlanVolumesPlacing")
-	err := cm.update(ctx)
+	err := cm.update(ctx, nodes)
 	if err != nil {
 		return nil, fmt.Errorf("failed to update capacity data: %s", err.Error())
 	}
 	plan := VolumesPlanMap{}
 
 	for _, node := range nodes {
+		// capacity of decommissioned node isn't used for new volumes
+		if isCordoned(ctx, node) {
+			logger.Debugf("Node %s is cordoned", node)
+			continue
+		}
 		volToACOnNode := cm.selectCapacityOnNode(ctx, node, volumes)
 		if volToACOnNode == nil {
 			continue
 		}
 		plan[node] = volToACOnNode
 	}
+	// volumes of the same volume group are spread across failure domains
+	plan = applySpreadConstraint(ctx, logger, plan)
 
 	if len(plan) == 0 {
 		logger.Warning("Required capacity for volumes not found")
@@ -148,7 +155,18 @@ func (cm *CapacityManager) selectCapacityOnNode(ctx context.Context, node string
 	result := VolToACMap{}
 
 	for _, vol := range volumes {
-		ac := nodeCap.selectACForVolume(vol)
+		var (
+			ac  *accrd.AvailableCapacity
+			sel = acSelectorFromContext(ctx, vol.Id)
+		)
+		// try requested storage class first and then fallback storage classes in order
+		for _, sc := range sel.StorageClasses(vol.StorageClass) {
+			candidate := *vol
+			candidate.StorageClass = sc
+			if ac = nodeCap.selectACForVolume(&candidate, sel.Match); ac != nil {
+				break
+			}
+		}
 		if ac == nil {
 			logger.Tracef("AC for vol: %s not found on node %s", vol.Id, node)
 			return nil
@@ -160,10 +178,19 @@ func (cm *CapacityManager) selectCapacityOnNode(ctx context.Context, node string
 	return result
 }
 
-func (cm *CapacityManager) update(ctx context.Context) error {
+func (cm *CapacityManager) update(ctx context.Context, nodes []string) error {
 	logger := util.AddCommonFields(ctx, cm.logger, "CapacityManager.update")
 	cm.nodesCapacity = map[string]*nodeCapacity{}
-	capacity, err := cm.capReader.ReadCapacity(ctx)
+	var (
+		capacity []accrd.AvailableCapacity
+		err      error
+	)
+	// read capacity of requested nodes only if reader supports it
+	if nodeCapReader, ok := cm.capReader.(NodeCapacityReader); ok {
+		capacity, err = nodeCapReader.ReadCapacityForNodes(ctx, nodes)
+	} else {
+		capacity, err = cm.capReader.ReadCapacity(ctx)
+	}
 	if err != nil {
 		logger.Errorf("Failed to read capacity: %s", err.Error())
 		return err
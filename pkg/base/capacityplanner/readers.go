@@ -57,11 +57,18 @@ func (acr *ACReader) ReadCapacity(ctx context.Context) ([]accrd.AvailableCapacit
 		logger.Errorf("failed to read AC list: %s", err.Error())
 		return nil, err
 	}
-	logger.Tracef("Read AvailableCapacity: %+v", acList.Items)
+	// AC which is being deleted isn't offered for new volumes, it is kept only until its reservations are removed
+	acs := make([]accrd.AvailableCapacity, 0, len(acList.Items))
+	for _, ac := range acList.Items {
+		if ac.DeletionTimestamp.IsZero() {
+			acs = append(acs, ac)
+		}
+	}
+	logger.Tracef("Read AvailableCapacity: %+v", acs)
 	if acr.cached {
-		acr.cache = acList.Items
+		acr.cache = acs
 	}
-	return acList.Items, nil
+	return acs, nil
 }
 
 // NewACRReader returns instance of ACReader
@@ -0,0 +1,234 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package capacityplanner
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	coreV1 "k8s.io/api/core/v1"
+	storageV1 "k8s.io/api/storage/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+
+	genV1 "github.com/dell/csi-baremetal/api/generated/v1"
+	v1 "github.com/dell/csi-baremetal/api/v1"
+	accrd "github.com/dell/csi-baremetal/api/v1/availablecapacitycrd"
+	"github.com/dell/csi-baremetal/api/v1/drivecrd"
+	"github.com/dell/csi-baremetal/pkg/base"
+	"github.com/dell/csi-baremetal/pkg/base/k8s"
+	"github.com/dell/csi-baremetal/pkg/base/util"
+)
+
+// acSelectorsKey is a context key for volume ID to ACSelector mapping
+type acSelectorsKey struct{}
+
+// ACSelector restricts ACs which might be used for volume, it's built from StorageClass parameters
+type ACSelector struct {
+	// FallbackStorageClasses are used in order when AC with requested storage class isn't found on node
+	FallbackStorageClasses []string
+	// MinDriveSize and MaxDriveSize limit size of the underlying drive, 0 means no limit
+	MinDriveSize int64
+	MaxDriveSize int64
+	VID          string
+	PID          string
+	// Rotational selects HDD if true and SSD or NVMe if false, nil means any
+	Rotational *bool
+	// DriveLabels is a selector for labels of Drive CR, they are copied to AC by capacity controller
+	DriveLabels labels.Selector
+}
+
+// NewACSelector builds ACSelector from StorageClass parameters
+// Returns nil if parameters don't contain any selector
+func NewACSelector(params map[string]string) (*ACSelector, error) {
+	var (
+		sel   = &ACSelector{}
+		empty = true
+		err   error
+	)
+	if fallback := params[base.FallbackStorageTypesKey]; fallback != "" {
+		requested := util.ConvertStorageClass(params[base.StorageTypeKey])
+		for _, t := range strings.Split(fallback, ",") {
+			sc := util.ConvertStorageClass(strings.TrimSpace(t))
+			if sc == v1.StorageClassAny || util.IsStorageClassLVG(sc) != util.IsStorageClassLVG(requested) {
+				return nil, fmt.Errorf("fallback storage type %s isn't compatible with storage type %s", t, requested)
+			}
+			sel.FallbackStorageClasses = append(sel.FallbackStorageClasses, sc)
+		}
+		empty = false
+	}
+	if size := params[base.MinDriveSizeKey]; size != "" {
+		if sel.MinDriveSize, err = util.StrToBytes(size); err != nil {
+			return nil, err
+		}
+		empty = false
+	}
+	if size := params[base.MaxDriveSizeKey]; size != "" {
+		if sel.MaxDriveSize, err = util.StrToBytes(size); err != nil {
+			return nil, err
+		}
+		empty = false
+	}
+	if rotational := params[base.DriveRotationalKey]; rotational != "" {
+		value, err := strconv.ParseBool(rotational)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse %s: %v", base.DriveRotationalKey, err)
+		}
+		sel.Rotational = &value
+		empty = false
+	}
+	if selector := params[base.DriveSelectorKey]; selector != "" {
+		if sel.DriveLabels, err = labels.Parse(selector); err != nil {
+			return nil, fmt.Errorf("unable to parse %s: %v", base.DriveSelectorKey, err)
+		}
+		empty = false
+	}
+	sel.VID, sel.PID = params[base.DriveVIDKey], params[base.DrivePIDKey]
+	if sel.VID != "" || sel.PID != "" {
+		empty = false
+	}
+	if empty {
+		return nil, nil
+	}
+	return sel, nil
+}
+
+// StorageClasses returns storage classes which might be used for volume with storage class sc in order of preference
+func (s *ACSelector) StorageClasses(sc string) []string {
+	if s == nil {
+		return []string{sc}
+	}
+	return append([]string{sc}, s.FallbackStorageClasses...)
+}
+
+// Match checks that AC is located on the drive which satisfies selector
+// Attributes of the drive are taken from AC annotations and labels, AC without them doesn't match
+func (s *ACSelector) Match(ac *accrd.AvailableCapacity) bool {
+	if s == nil {
+		return true
+	}
+	if s.VID != "" && ac.Annotations[v1.ACAnnotationDriveVID] != s.VID {
+		return false
+	}
+	if s.PID != "" && ac.Annotations[v1.ACAnnotationDrivePID] != s.PID {
+		return false
+	}
+	if s.Rotational != nil {
+		driveType := ac.Annotations[v1.ACAnnotationDriveType]
+		if driveType == "" {
+			return false
+		}
+		if (driveType == v1.DriveTypeHDD) != *s.Rotational {
+			return false
+		}
+	}
+	if s.MinDriveSize > 0 || s.MaxDriveSize > 0 {
+		size, err := strconv.ParseInt(ac.Annotations[v1.ACAnnotationDriveSize], 10, 64)
+		if err != nil {
+			return false
+		}
+		if size < s.MinDriveSize || (s.MaxDriveSize > 0 && size > s.MaxDriveSize) {
+			return false
+		}
+	}
+	if s.DriveLabels != nil && !s.DriveLabels.Matches(labels.Set(ac.Labels)) {
+		return false
+	}
+	return true
+}
+
+// WithACSelectors returns context which holds volume ID to ACSelector mapping for CapacityManager
+func WithACSelectors(ctx context.Context, selectors map[string]*ACSelector) context.Context {
+	if len(selectors) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, acSelectorsKey{}, selectors)
+}
+
+// acSelectorFromContext returns ACSelector for volume, nil if volume doesn't have it
+func acSelectorFromContext(ctx context.Context, volumeID string) *ACSelector {
+	selectors, ok := ctx.Value(acSelectorsKey{}).(map[string]*ACSelector)
+	if !ok {
+		return nil
+	}
+	return selectors[volumeID]
+}
+
+// ResolveACSelectors builds ACSelectors for capacity requests using parameters of StorageClass of the PVC,
+// capacity request name is the name of PVC in the namespace. Requests for inline volumes are skipped
+func ResolveACSelectors(ctx context.Context, reader k8s.CRReader, namespace string,
+	requests []*genV1.CapacityRequest) (map[string]*ACSelector, error) {
+	result := map[string]*ACSelector{}
+	for _, request := range requests {
+		pvc := &coreV1.PersistentVolumeClaim{}
+		if err := reader.ReadCR(ctx, request.Name, namespace, pvc); err != nil {
+			if k8serrors.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		if pvc.Spec.StorageClassName == nil {
+			continue
+		}
+		sc := &storageV1.StorageClass{}
+		if err := reader.ReadCR(ctx, *pvc.Spec.StorageClassName, "", sc); err != nil {
+			return nil, err
+		}
+		sel, err := NewACSelector(sc.Parameters)
+		if err != nil {
+			return nil, fmt.Errorf("storage class %s: %v", sc.Name, err)
+		}
+		if sel != nil {
+			result[request.Name] = sel
+		}
+	}
+	return result, nil
+}
+
+// SetDriveAttributes sets attributes of the drive to AC annotations and copies Drive CR labels to AC labels,
+// they are used by capacity planner to select drives requested by StorageClass parameters
+// Returns true if AC was changed
+func SetDriveAttributes(ac *accrd.AvailableCapacity, drive *drivecrd.Drive) bool {
+	annotations := map[string]string{
+		v1.ACAnnotationDriveVID:  drive.Spec.GetVID(),
+		v1.ACAnnotationDrivePID:  drive.Spec.GetPID(),
+		v1.ACAnnotationDriveType: drive.Spec.GetType(),
+		v1.ACAnnotationDriveSize: strconv.FormatInt(drive.Spec.GetSize(), 10),
+	}
+	changed := false
+	for key, value := range annotations {
+		if ac.Annotations[key] != value {
+			if ac.Annotations == nil {
+				ac.Annotations = map[string]string{}
+			}
+			ac.Annotations[key] = value
+			changed = true
+		}
+	}
+	for key, value := range drive.Labels {
+		if ac.Labels[key] != value {
+			if ac.Labels == nil {
+				ac.Labels = map[string]string{}
+			}
+			ac.Labels[key] = value
+			changed = true
+		}
+	}
+	return changed
+}
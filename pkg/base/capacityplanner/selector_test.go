@@ -0,0 +1,163 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package capacityplanner
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	coreV1 "k8s.io/api/core/v1"
+	storageV1 "k8s.io/api/storage/v1"
+	k8smetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	genV1 "github.com/dell/csi-baremetal/api/generated/v1"
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	accrd "github.com/dell/csi-baremetal/api/v1/availablecapacitycrd"
+	"github.com/dell/csi-baremetal/pkg/base"
+)
+
+func getTestDriveAC(nodeID string, size int64, sc, driveType, pid string) *accrd.AvailableCapacity {
+	ac := getTestAC(nodeID, size, sc)
+	ac.Annotations = map[string]string{
+		apiV1.ACAnnotationDriveVID:  "vendor",
+		apiV1.ACAnnotationDrivePID:  pid,
+		apiV1.ACAnnotationDriveType: driveType,
+		apiV1.ACAnnotationDriveSize: strconv.FormatInt(size, 10),
+	}
+	return ac
+}
+
+func TestNewACSelector(t *testing.T) {
+	sel, err := NewACSelector(map[string]string{base.StorageTypeKey: apiV1.StorageClassHDD})
+	assert.Nil(t, err)
+	assert.Nil(t, sel)
+
+	sel, err = NewACSelector(map[string]string{
+		base.StorageTypeKey:          apiV1.StorageClassNVMe,
+		base.FallbackStorageTypesKey: "ssd, HDD",
+		base.MinDriveSizeKey:         "10Gi",
+		base.DriveRotationalKey:      "false",
+		base.DriveSelectorKey:        "tier=gold",
+		base.DrivePIDKey:             "pid",
+	})
+	assert.Nil(t, err)
+	assert.NotNil(t, sel)
+	assert.Equal(t, []string{apiV1.StorageClassNVMe, apiV1.StorageClassSSD, apiV1.StorageClassHDD},
+		sel.StorageClasses(apiV1.StorageClassNVMe))
+	assert.Equal(t, testSmallSize, sel.MinDriveSize)
+	assert.False(t, *sel.Rotational)
+
+	// LVG and full drive storage classes can't be mixed
+	_, err = NewACSelector(map[string]string{base.StorageTypeKey: apiV1.StorageClassHDDLVG,
+		base.FallbackStorageTypesKey: apiV1.StorageClassSSD})
+	assert.NotNil(t, err)
+	_, err = NewACSelector(map[string]string{base.DriveRotationalKey: "sometimes"})
+	assert.NotNil(t, err)
+	_, err = NewACSelector(map[string]string{base.MaxDriveSizeKey: "huge"})
+	assert.NotNil(t, err)
+
+	// nil selector doesn't restrict anything
+	sel = nil
+	assert.Equal(t, []string{apiV1.StorageClassHDD}, sel.StorageClasses(apiV1.StorageClassHDD))
+	assert.True(t, sel.Match(getTestAC(testNode1, testSmallSize, apiV1.StorageClassHDD)))
+}
+
+func TestACSelector_Match(t *testing.T) {
+	rotational := true
+	sel := &ACSelector{MinDriveSize: testSmallSize, MaxDriveSize: testSmallSize, PID: "pid", Rotational: &rotational}
+
+	ac := getTestDriveAC(testNode1, testSmallSize, apiV1.StorageClassHDD, apiV1.DriveTypeHDD, "pid")
+	assert.True(t, sel.Match(ac))
+	// AC without drive attributes
+	assert.False(t, sel.Match(getTestAC(testNode1, testSmallSize, apiV1.StorageClassHDD)))
+	assert.False(t, sel.Match(getTestDriveAC(testNode1, testLargeSize, apiV1.StorageClassHDD, apiV1.DriveTypeHDD, "pid")))
+	assert.False(t, sel.Match(getTestDriveAC(testNode1, testSmallSize, apiV1.StorageClassSSD, apiV1.DriveTypeSSD, "pid")))
+	assert.False(t, sel.Match(getTestDriveAC(testNode1, testSmallSize, apiV1.StorageClassHDD, apiV1.DriveTypeHDD, "other")))
+
+	sel, err := NewACSelector(map[string]string{base.DriveSelectorKey: "tier in (gold,silver)"})
+	assert.Nil(t, err)
+	assert.False(t, sel.Match(ac))
+	ac.Labels = map[string]string{"tier": "gold"}
+	assert.True(t, sel.Match(ac))
+}
+
+func TestCapacityManager_PlanVolumesPlacing_Selector(t *testing.T) {
+	logger := testLogger.WithField("component", "test")
+	index := NewCapacityIndex(logger)
+	hdd := getTestDriveAC(testNode1, testLargeSize, apiV1.StorageClassHDD, apiV1.DriveTypeHDD, "pid-hdd")
+	ssd := getTestDriveAC(testNode1, testLargeSize, apiV1.StorageClassSSD, apiV1.DriveTypeSSD, "pid-ssd")
+	for _, ac := range []*accrd.AvailableCapacity{hdd, ssd} {
+		index.OnAdd(ac)
+	}
+
+	vol := getTestVol("", testSmallSize, apiV1.StorageClassNVMe)
+	// NVMe isn't available, no fallback
+	plan, err := NewCapacityManager(logger, index).PlanVolumesPlacing(context.Background(),
+		[]*genV1.Volume{vol}, []string{testNode1})
+	assert.Nil(t, err)
+	assert.Nil(t, plan)
+
+	// fallback to SSD
+	ctx := WithACSelectors(context.Background(), map[string]*ACSelector{
+		vol.Id: {FallbackStorageClasses: []string{apiV1.StorageClassSSD, apiV1.StorageClassHDD}}})
+	plan, err = NewCapacityManager(logger, index).PlanVolumesPlacing(ctx, []*genV1.Volume{vol}, []string{testNode1})
+	assert.Nil(t, err)
+	assert.NotNil(t, plan)
+	assert.Equal(t, ssd.Name, plan.GetACForVolume(testNode1, vol).Name)
+	// volume isn't modified by fallback
+	assert.Equal(t, apiV1.StorageClassNVMe, vol.StorageClass)
+
+	// fallback to HDD because of PID
+	ctx = WithACSelectors(context.Background(), map[string]*ACSelector{
+		vol.Id: {FallbackStorageClasses: []string{apiV1.StorageClassSSD, apiV1.StorageClassHDD}, PID: "pid-hdd"}})
+	plan, err = NewCapacityManager(logger, index).PlanVolumesPlacing(ctx, []*genV1.Volume{vol}, []string{testNode1})
+	assert.Nil(t, err)
+	assert.NotNil(t, plan)
+	assert.Equal(t, hdd.Name, plan.GetACForVolume(testNode1, vol).Name)
+
+	// LVG volume on the drive of fallback type
+	lvgVol := getTestVol("", testSmallSize, apiV1.StorageClassNVMeLVG)
+	ctx = WithACSelectors(context.Background(), map[string]*ACSelector{
+		lvgVol.Id: {FallbackStorageClasses: []string{apiV1.StorageClassHDDLVG}}})
+	plan, err = NewCapacityManager(logger, index).PlanVolumesPlacing(ctx, []*genV1.Volume{lvgVol}, []string{testNode1})
+	assert.Nil(t, err)
+	assert.NotNil(t, plan)
+	assert.Equal(t, hdd.Name, plan.GetACForVolume(testNode1, lvgVol).Name)
+}
+
+func TestResolveACSelectors(t *testing.T) {
+	client := getKubeClient(t)
+	scName := "csi-baremetal-sc-gold"
+	sc := &storageV1.StorageClass{
+		ObjectMeta: k8smetav1.ObjectMeta{Name: scName, Namespace: testNS},
+		Parameters: map[string]string{base.StorageTypeKey: apiV1.StorageClassSSD, base.DriveVIDKey: "vendor"},
+	}
+	pvc := &coreV1.PersistentVolumeClaim{
+		ObjectMeta: k8smetav1.ObjectMeta{Name: "pvc", Namespace: testNS},
+		Spec:       coreV1.PersistentVolumeClaimSpec{StorageClassName: &scName},
+	}
+	assert.Nil(t, client.Create(context.Background(), sc))
+	assert.Nil(t, client.Create(context.Background(), pvc))
+
+	selectors, err := ResolveACSelectors(context.Background(), client, testNS,
+		[]*genV1.CapacityRequest{{Name: "pvc"}, {Name: "pod-inline-volume"}})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(selectors))
+	assert.Equal(t, "vendor", selectors["pvc"].VID)
+}
@@ -0,0 +1,259 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package capacityplanner
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	coreV1 "k8s.io/api/core/v1"
+	storageV1 "k8s.io/api/storage/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+
+	v1 "github.com/dell/csi-baremetal/api/v1"
+	acrcrd "github.com/dell/csi-baremetal/api/v1/acreservationcrd"
+	volcrd "github.com/dell/csi-baremetal/api/v1/volumecrd"
+	"github.com/dell/csi-baremetal/pkg/base"
+	"github.com/dell/csi-baremetal/pkg/base/k8s"
+)
+
+// SpreadPolicy defines how volumes of the same volume group are spread across failure domains
+type SpreadPolicy string
+
+const (
+	// SpreadPolicyRequired forbids placing volumes of the same group in one failure domain
+	SpreadPolicyRequired SpreadPolicy = "required"
+	// SpreadPolicyPreferred places volumes of the same group in one failure domain only if there is no other choice
+	SpreadPolicyPreferred SpreadPolicy = "preferred"
+)
+
+// spreadConstraintKey is a context key for SpreadConstraint
+type spreadConstraintKey struct{}
+
+// SpreadConstraint describes failure domain spreading for volumes of the pod
+type SpreadConstraint struct {
+	// TopologyKey is a node label which defines failure domain
+	TopologyKey string
+	Policy      SpreadPolicy
+	// Group is a volume group tag of the pod volumes
+	Group string
+	// NodeDomains maps node ID to failure domain of the node
+	NodeDomains map[string]string
+	// OccupiedDomains are failure domains which already have volumes of the group
+	OccupiedDomains map[string]struct{}
+}
+
+// IsOccupied checks whether failure domain of the node with provided ID already has volumes of the group
+// Nodes without topology label aren't considered as a part of any failure domain
+func (sc *SpreadConstraint) IsOccupied(nodeID string) bool {
+	if sc == nil {
+		return false
+	}
+	domain := sc.NodeDomains[nodeID]
+	if domain == "" {
+		return false
+	}
+	_, occupied := sc.OccupiedDomains[domain]
+	return occupied
+}
+
+// WithSpreadConstraint returns context with SpreadConstraint which is applied by CapacityManager.PlanVolumesPlacing
+func WithSpreadConstraint(ctx context.Context, constraint *SpreadConstraint) context.Context {
+	if constraint == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, spreadConstraintKey{}, constraint)
+}
+
+// spreadConstraintFromContext returns SpreadConstraint stored by WithSpreadConstraint or nil
+func spreadConstraintFromContext(ctx context.Context) *SpreadConstraint {
+	constraint, _ := ctx.Value(spreadConstraintKey{}).(*SpreadConstraint)
+	return constraint
+}
+
+// applySpreadConstraint removes nodes from occupied failure domains from the plan. With SpreadPolicyPreferred
+// such nodes are kept if there are no other nodes in the plan
+func applySpreadConstraint(ctx context.Context, logger *logrus.Entry, plan VolumesPlanMap) VolumesPlanMap {
+	constraint := spreadConstraintFromContext(ctx)
+	if constraint == nil {
+		return plan
+	}
+	result := VolumesPlanMap{}
+	for node, volToAC := range plan {
+		if constraint.IsOccupied(node) {
+			logger.Debugf("Failure domain %s=%s of node %s already has volumes of group %s",
+				constraint.TopologyKey, constraint.NodeDomains[node], node, constraint.Group)
+			continue
+		}
+		result[node] = volToAC
+	}
+	if len(result) == 0 && constraint.Policy == SpreadPolicyPreferred {
+		logger.Infof("All failure domains have volumes of group %s, spreading is ignored", constraint.Group)
+		return plan
+	}
+	return result
+}
+
+// ResolveSpreadConstraint returns failure domain spreading constraint for the pod volumes
+// Volume group tag is taken from PVC annotation or pod label v1.VolumeGroupKey,
+// topology key and policy are taken from parameters of StorageClass of the PVC.
+// Failure domains are occupied by volumes of the group and by reservations of other pods of the group
+// Returns nil if pod volumes don't require spreading
+func ResolveSpreadConstraint(ctx context.Context, reader k8s.CRReader, pod *coreV1.Pod) (*SpreadConstraint, error) {
+	var (
+		constraint *SpreadConstraint
+		// volumes of the pod are ignored, they might be already created for restarted pod
+		podVolumes = map[string]struct{}{}
+	)
+	for _, v := range pod.Spec.Volumes {
+		if v.PersistentVolumeClaim == nil {
+			continue
+		}
+		pvc := &coreV1.PersistentVolumeClaim{}
+		if err := reader.ReadCR(ctx, v.PersistentVolumeClaim.ClaimName, pod.Namespace, pvc); err != nil {
+			if k8serrors.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		if pvc.Spec.VolumeName != "" {
+			podVolumes[pvc.Spec.VolumeName] = struct{}{}
+		}
+		if constraint != nil || pvc.Spec.StorageClassName == nil {
+			continue
+		}
+
+		group := pvc.Annotations[v1.VolumeGroupKey]
+		if group == "" {
+			group = pod.Labels[v1.VolumeGroupKey]
+		}
+		if group == "" {
+			continue
+		}
+
+		sc := &storageV1.StorageClass{}
+		if err := reader.ReadCR(ctx, *pvc.Spec.StorageClassName, "", sc); err != nil {
+			return nil, err
+		}
+		if sc.Parameters[base.SpreadTopologyKey] == "" {
+			continue
+		}
+		policy, err := parseSpreadPolicy(sc.Parameters[base.SpreadPolicyKey])
+		if err != nil {
+			return nil, fmt.Errorf("storage class %s: %v", sc.Name, err)
+		}
+		constraint = &SpreadConstraint{
+			TopologyKey:     sc.Parameters[base.SpreadTopologyKey],
+			Policy:          policy,
+			Group:           group,
+			NodeDomains:     map[string]string{},
+			OccupiedDomains: map[string]struct{}{},
+		}
+	}
+	if constraint == nil {
+		return nil, nil
+	}
+
+	nodeList := &coreV1.NodeList{}
+	if err := reader.ReadList(ctx, nodeList); err != nil {
+		return nil, err
+	}
+	for i := range nodeList.Items {
+		domain := nodeList.Items[i].Labels[constraint.TopologyKey]
+		if domain == "" {
+			continue
+		}
+		for _, id := range nodeIDCandidates(&nodeList.Items[i]) {
+			constraint.NodeDomains[id] = domain
+		}
+	}
+
+	volumeList := &volcrd.VolumeList{}
+	if err := reader.ReadList(ctx, volumeList); err != nil {
+		return nil, err
+	}
+	for _, volume := range volumeList.Items {
+		if _, ok := podVolumes[volume.Name]; ok {
+			continue
+		}
+		if volume.Namespace != pod.Namespace || volume.Labels[v1.VolumeGroupKey] != constraint.Group {
+			continue
+		}
+		if domain := constraint.NodeDomains[volume.Spec.NodeId]; domain != "" {
+			constraint.OccupiedDomains[domain] = struct{}{}
+		}
+	}
+
+	// volumes of the group which are reserved, but not created yet, occupy failure domains of reserved nodes
+	acrList := &acrcrd.AvailableCapacityReservationList{}
+	if err := reader.ReadList(ctx, acrList); err != nil {
+		return nil, err
+	}
+	for _, acr := range acrList.Items {
+		if !isReservationOfGroup(&acr, pod, constraint.Group) || acr.Spec.NodeRequests == nil {
+			continue
+		}
+		for _, nodeID := range acr.Spec.NodeRequests.Reserved {
+			if domain := constraint.NodeDomains[nodeID]; domain != "" {
+				constraint.OccupiedDomains[domain] = struct{}{}
+			}
+		}
+	}
+	return constraint, nil
+}
+
+// isReservationOfGroup checks whether ACR holds capacity for another pod of the volume group in the pod namespace
+func isReservationOfGroup(acr *acrcrd.AvailableCapacityReservation, pod *coreV1.Pod, group string) bool {
+	if acr.Spec.Status == v1.ReservationRejected || acr.Spec.Status == v1.ReservationCancelled {
+		return false
+	}
+	if acr.Spec.Namespace != pod.Namespace || acr.Annotations[v1.VolumeGroupKey] != group {
+		return false
+	}
+	// reservation of the pod itself
+	return acr.Annotations[v1.ReservationAnnotationPodName] != pod.Name ||
+		acr.Annotations[v1.ReservationAnnotationPodNamespace] != pod.Namespace
+}
+
+// nodeIDCandidates returns values which might be ID of the node: UID of the node and values of its annotations.
+// ID of the node is set by node service settings, which aren't known by planner, so all of them are used
+func nodeIDCandidates(node *coreV1.Node) []string {
+	result := make([]string, 0, len(node.Annotations)+1)
+	if node.UID != "" {
+		result = append(result, string(node.UID))
+	}
+	for _, value := range node.Annotations {
+		if value != "" {
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+// parseSpreadPolicy converts StorageClass parameter to SpreadPolicy, empty string means SpreadPolicyRequired
+func parseSpreadPolicy(policy string) (SpreadPolicy, error) {
+	switch p := SpreadPolicy(policy); p {
+	case "":
+		return SpreadPolicyRequired, nil
+	case SpreadPolicyRequired, SpreadPolicyPreferred:
+		return p, nil
+	default:
+		return "", fmt.Errorf("unknown spread policy %s, supported: %s, %s",
+			policy, SpreadPolicyRequired, SpreadPolicyPreferred)
+	}
+}
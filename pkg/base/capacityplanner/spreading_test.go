@@ -0,0 +1,184 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package capacityplanner
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	coreV1 "k8s.io/api/core/v1"
+	storageV1 "k8s.io/api/storage/v1"
+	k8smetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	genV1 "github.com/dell/csi-baremetal/api/generated/v1"
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	"github.com/dell/csi-baremetal/pkg/base"
+	"github.com/dell/csi-baremetal/pkg/base/k8s"
+)
+
+const (
+	testRackKey   = "rack"
+	testGroup     = "db"
+	testSpreadSC  = "csi-baremetal-sc-spread"
+	testSpreadPVC = "pvc-spread"
+)
+
+func newRackNode(name, rack string) *coreV1.Node {
+	return &coreV1.Node{ObjectMeta: k8smetav1.ObjectMeta{
+		Name: name, UID: types.UID(name + "-uid"), Labels: map[string]string{testRackKey: rack}}}
+}
+
+func setupSpreading(t *testing.T, policy SpreadPolicy) (*k8s.KubeClient, *coreV1.Pod) {
+	client := getKubeClient(t)
+	for _, node := range []*coreV1.Node{newRackNode("node-1", "rack-1"), newRackNode("node-2", "rack-1"),
+		newRackNode("node-3", "rack-2")} {
+		assert.Nil(t, client.Create(context.Background(), node))
+	}
+
+	scName := testSpreadSC
+	sc := &storageV1.StorageClass{
+		ObjectMeta: k8smetav1.ObjectMeta{Name: testSpreadSC, Namespace: testNS},
+		Parameters: map[string]string{base.StorageTypeKey: apiV1.StorageClassHDD,
+			base.SpreadTopologyKey: testRackKey, base.SpreadPolicyKey: string(policy)},
+	}
+	pvc := &coreV1.PersistentVolumeClaim{
+		ObjectMeta: k8smetav1.ObjectMeta{Name: testSpreadPVC, Namespace: testNS,
+			Annotations: map[string]string{apiV1.VolumeGroupKey: testGroup}},
+		Spec: coreV1.PersistentVolumeClaimSpec{StorageClassName: &scName},
+	}
+	assert.Nil(t, client.Create(context.Background(), sc))
+	assert.Nil(t, client.Create(context.Background(), pvc))
+
+	pod := &coreV1.Pod{ObjectMeta: k8smetav1.ObjectMeta{Name: "pod", Namespace: testNS}}
+	pod.Spec.Volumes = []coreV1.Volume{{VolumeSource: coreV1.VolumeSource{
+		PersistentVolumeClaim: &coreV1.PersistentVolumeClaimVolumeSource{ClaimName: testSpreadPVC}}}}
+
+	// replica of the same workload already has volume in rack-1
+	volume := client.ConstructVolumeCR("vol-replica", testNS,
+		genV1.Volume{Id: "vol-replica", NodeId: "node-1-uid", StorageClass: apiV1.StorageClassHDD})
+	volume.Labels = map[string]string{apiV1.VolumeGroupKey: testGroup}
+	assert.Nil(t, client.Create(context.Background(), volume))
+	return client, pod
+}
+
+func TestResolveSpreadConstraint(t *testing.T) {
+	client, pod := setupSpreading(t, "")
+
+	constraint, err := ResolveSpreadConstraint(context.Background(), client, pod)
+	assert.Nil(t, err)
+	assert.NotNil(t, constraint)
+	assert.Equal(t, SpreadPolicyRequired, constraint.Policy)
+	assert.Equal(t, testGroup, constraint.Group)
+	assert.True(t, constraint.IsOccupied("node-1-uid"))
+	assert.True(t, constraint.IsOccupied("node-2-uid"))
+	assert.False(t, constraint.IsOccupied("node-3-uid"))
+	// node without topology label
+	assert.False(t, constraint.IsOccupied("unknown"))
+
+	// pod without volume group
+	pvc := &coreV1.PersistentVolumeClaim{}
+	assert.Nil(t, client.ReadCR(context.Background(), testSpreadPVC, testNS, pvc))
+	pvc.Annotations = nil
+	assert.Nil(t, client.Update(context.Background(), pvc))
+	constraint, err = ResolveSpreadConstraint(context.Background(), client, pod)
+	assert.Nil(t, err)
+	assert.Nil(t, constraint)
+
+	// volume group from pod label
+	pod.Labels = map[string]string{apiV1.VolumeGroupKey: "another-group"}
+	constraint, err = ResolveSpreadConstraint(context.Background(), client, pod)
+	assert.Nil(t, err)
+	assert.Equal(t, "another-group", constraint.Group)
+	assert.Equal(t, 0, len(constraint.OccupiedDomains))
+}
+
+func TestResolveSpreadConstraint_Reservations(t *testing.T) {
+	client, pod := setupSpreading(t, "")
+	assert.Nil(t, client.Create(context.Background(), newRackNode("node-4", "rack-3")))
+	createACR := func(name, podName, status, nodeID string) {
+		acr := client.ConstructACRCR(name, genV1.AvailableCapacityReservation{
+			Namespace:    testNS,
+			Status:       status,
+			NodeRequests: &genV1.NodeRequests{Requested: []string{nodeID}, Reserved: []string{nodeID}},
+		})
+		acr.Annotations = map[string]string{apiV1.VolumeGroupKey: testGroup,
+			apiV1.ReservationAnnotationPodName: podName, apiV1.ReservationAnnotationPodNamespace: testNS}
+		assert.Nil(t, client.Create(context.Background(), acr))
+	}
+	// another replica has reserved capacity in rack-2, but its volume isn't created yet
+	createACR("acr-replica", "replica", apiV1.ReservationConfirmed, "node-3-uid")
+	// rejected reservation and reservation of the pod itself don't occupy rack-3
+	createACR("acr-rejected", "rejected", apiV1.ReservationRejected, "node-4-uid")
+	createACR("acr-pod", pod.Name, apiV1.ReservationConfirmed, "node-4-uid")
+
+	constraint, err := ResolveSpreadConstraint(context.Background(), client, pod)
+	assert.Nil(t, err)
+	assert.NotNil(t, constraint)
+	assert.True(t, constraint.IsOccupied("node-1-uid"))
+	assert.True(t, constraint.IsOccupied("node-3-uid"))
+	assert.False(t, constraint.IsOccupied("node-4-uid"))
+}
+
+func TestCapacityManager_PlanVolumesPlacing_Spreading(t *testing.T) {
+	logger := testLogger.WithField("component", "test")
+	index := NewCapacityIndex(logger)
+	nodes := []string{"node-1-uid", "node-3-uid"}
+	for _, node := range nodes {
+		index.OnAdd(getTestAC(node, testLargeSize, apiV1.StorageClassHDD))
+	}
+	vol := getTestVol("", testSmallSize, apiV1.StorageClassHDD)
+	constraint := &SpreadConstraint{
+		TopologyKey:     testRackKey,
+		Policy:          SpreadPolicyRequired,
+		Group:           testGroup,
+		NodeDomains:     map[string]string{"node-1-uid": "rack-1", "node-3-uid": "rack-2"},
+		OccupiedDomains: map[string]struct{}{"rack-1": {}},
+	}
+
+	ctx := WithSpreadConstraint(context.Background(), constraint)
+	plan, err := NewCapacityManager(logger, index).PlanVolumesPlacing(ctx, []*genV1.Volume{vol}, nodes)
+	assert.Nil(t, err)
+	assert.Nil(t, plan.GetVolumesToACMapping("node-1-uid"))
+	assert.NotNil(t, plan.GetVolumesToACMapping("node-3-uid"))
+
+	// all domains are occupied
+	constraint.OccupiedDomains["rack-2"] = struct{}{}
+	plan, err = NewCapacityManager(logger, index).PlanVolumesPlacing(ctx, []*genV1.Volume{vol}, nodes)
+	assert.Nil(t, err)
+	assert.Nil(t, plan)
+
+	constraint.Policy = SpreadPolicyPreferred
+	plan, err = NewCapacityManager(logger, index).PlanVolumesPlacing(ctx, []*genV1.Volume{vol}, nodes)
+	assert.Nil(t, err)
+	assert.NotNil(t, plan.GetVolumesToACMapping("node-1-uid"))
+	assert.NotNil(t, plan.GetVolumesToACMapping("node-3-uid"))
+}
+
+func TestParseSpreadPolicy(t *testing.T) {
+	p, err := parseSpreadPolicy("preferred")
+	assert.Nil(t, err)
+	assert.Equal(t, SpreadPolicyPreferred, p)
+
+	p, err = parseSpreadPolicy("")
+	assert.Nil(t, err)
+	assert.Equal(t, SpreadPolicyRequired, p)
+
+	_, err = parseSpreadPolicy("sometimes")
+	assert.NotNil(t, err)
+}
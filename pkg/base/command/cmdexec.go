@@ -18,17 +18,27 @@ package command
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"os/exec"
+	"path/filepath"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/api/kv"
 
+	"github.com/dell/csi-baremetal/pkg/base/audit"
+	"github.com/dell/csi-baremetal/pkg/base/tracing"
 	"github.com/dell/csi-baremetal/pkg/metrics/common"
 )
 
+// cmdKey is a span attribute with the executed command
+const cmdKey = kv.Key("cmd")
+
 // Options represents interface for applying options
 type Options interface {
 	Apply(*CmdOptions)
@@ -36,8 +46,11 @@ type Options interface {
 
 // CmdOptions encapsulates options for executing command
 type CmdOptions struct {
-	UseMetrics bool
-	CmdName    string
+	UseMetrics  bool
+	CmdName     string
+	TraceCtx    context.Context
+	Timeout     time.Duration
+	Destructive bool
 }
 
 // ApplyOptions applies given options for CmdOptions struct
@@ -66,11 +79,91 @@ func (c CmdName) Apply(opt *CmdOptions) {
 	opt.CmdName = string(c)
 }
 
+// TraceCtx represents options to trace command as a child span of the span from the given context
+// instead of the context of RunCmdContext, it is used by callers which have no context to cancel the command
+type TraceCtx struct {
+	Ctx context.Context
+}
+
+// Apply assigns TraceCtx to given CmdOptions
+// Receive CmdOptions
+func (t TraceCtx) Apply(opt *CmdOptions) {
+	opt.TraceCtx = t.Ctx
+}
+
+// Destructive represents options to record command which destroys data on the device, e.g. wipefs or lvremove,
+// in the audit log together with the drive and volume from audit.Target of the context
+type Destructive bool
+
+// Apply assigns Destructive to given CmdOptions
+// Receive CmdOptions
+func (d Destructive) Apply(opt *CmdOptions) {
+	opt.Destructive = bool(d)
+}
+
+// Timeout represents options to kill command if it doesn't finish in the given time, overrides default timeout of
+// the command from cmdTimeouts or DefaultCmdTimeout
+type Timeout time.Duration
+
+// Apply assigns Timeout to given CmdOptions
+// Receive CmdOptions
+func (t Timeout) Apply(opt *CmdOptions) {
+	opt.Timeout = time.Duration(t)
+}
+
+const (
+	// DefaultCmdTimeout is a timeout of commands which aren't present in cmdTimeouts and executed without Timeout option
+	DefaultCmdTimeout = 5 * time.Minute
+	// killWaitTimeout is a time to wait for exit of the killed command. Process in uninterruptible sleep,
+	// e.g. blocked on I/O to the failed drive, isn't terminated by SIGKILL until I/O is finished, so it's abandoned
+	killWaitTimeout = 10 * time.Second
+)
+
+// cmdTimeouts holds default timeouts of executables which differ from DefaultCmdTimeout.
+// Commands with timeout longer than DefaultCmdTimeout aren't cancelled by the context of the caller, killed mkfs or
+// lvextend leaves the device in inconsistent state, and deadline of the caller might be shorter than their timeout
+var cmdTimeouts = map[string]time.Duration{
+	// inventory commands are executed on each Discover and must not block it for long
+	"lsblk":   30 * time.Second,
+	"lsscsi":  30 * time.Second,
+	"findmnt": 30 * time.Second,
+	"df":      30 * time.Second,
+	// health and drive management commands query device firmware
+	"smartctl": time.Minute,
+	"nvme":     time.Minute,
+	"ipmitool": time.Minute,
+	// duration of file system creation and wiping depends on the size of the device
+	"mkfs.ext3": 30 * time.Minute,
+	"mkfs.ext4": 30 * time.Minute,
+	"mkfs.xfs":  30 * time.Minute,
+	"dd":        30 * time.Minute,
+}
+
+// TimeoutError is returned when command is killed because it didn't finish in time
+type TimeoutError struct {
+	Cmd     string
+	Timeout time.Duration
+}
+
+// Error returns description of the timeout error
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("command \"%s\" was killed after timeout %s", e.Cmd, e.Timeout)
+}
+
+// IsTimeout returns true if err is or wraps TimeoutError
+func IsTimeout(err error) bool {
+	var timeoutErr *TimeoutError
+	return errors.As(err, &timeoutErr)
+}
+
 // CmdExecutor is the interface for executor that runs linux commands with RunCmd
 type CmdExecutor interface {
 	RunCmd(cmd interface{}, opts ...Options) (string, string, error)
+	RunCmdContext(ctx context.Context, cmd interface{}, opts ...Options) (string, string, error)
 	SetLevel(level logrus.Level)
 	RunCmdWithAttempts(cmd interface{}, attempts int, timeout time.Duration, opts ...Options) (string, string, error)
+	RunCmdWithAttemptsContext(ctx context.Context, cmd interface{}, attempts int, timeout time.Duration,
+		opts ...Options) (string, string, error)
 }
 
 // Executor is the implementation of CmdExecutor based on os/exec package
@@ -95,65 +188,158 @@ func (e *Executor) SetLevel(level logrus.Level) {
 // Receives command as empty interface, It could be string or instance of exec.Cmd; number of attempts; timeout.
 // Returns stdout as string, stderr as string and golang error if something went wrong
 func (e *Executor) RunCmdWithAttempts(cmd interface{}, attempts int, timeout time.Duration, opts ...Options) (string, string, error) {
+	return e.RunCmdWithAttemptsContext(context.Background(), cmd, attempts, timeout, opts...)
+}
+
+// RunCmdWithAttemptsContext runs specified command on OS with given attempts and timeout between attempts,
+// attempts are stopped when context is done
+// Receives context; command as empty interface, It could be string or instance of exec.Cmd; number of attempts; timeout.
+// Returns stdout as string, stderr as string and error of the last attempt if all of them failed
+func (e *Executor) RunCmdWithAttemptsContext(ctx context.Context, cmd interface{}, attempts int, timeout time.Duration,
+	opts ...Options) (stdout string, stderr string, err error) {
 	options := &CmdOptions{}
 	options.ApplyOptions(opts)
 	if options.UseMetrics {
 		defer common.SystemCMDDuration.EvaluateDuration(prometheus.Labels{"name": options.CmdName})()
 	}
 	ll := e.log.WithFields(logrus.Fields{
-		"method": "RunCmdWithAttempts",
+		"method": "RunCmdWithAttemptsContext",
 	})
-	var (
-		stdout string
-		stderr string
-		err    error
-	)
+	// duration is evaluated for all attempts, not for each of them
+	attemptOpts := append(append([]Options{}, opts...), UseMetrics(false))
 	for i := 0; i < attempts; i++ {
-		if stdout, stderr, err := e.RunCmd(cmd); err == nil {
-			return stdout, stderr, err
+		if stdout, stderr, err = e.RunCmdContext(ctx, cmd, attemptOpts...); err == nil {
+			return stdout, stderr, nil
+		}
+		ll.Warnf("Unable to execute cmd: %v. Attempt %d out of %d.", err, i+1, attempts)
+		select {
+		case <-ctx.Done():
+			return stdout, stderr, fmt.Errorf("attempts to execute command were stopped: %w", ctx.Err())
+		case <-time.After(timeout):
 		}
-		ll.Warnf("Unable to execute cmd: %v. Attempt %d out of %d.", err, i, attempts)
-		<-time.After(timeout)
 	}
-	errMsg := fmt.Errorf("failed to execute command after %d attempt, error: %v", attempts, err)
-	return stdout, stderr, errMsg
+	return stdout, stderr, fmt.Errorf("failed to execute command after %d attempts, error: %w", attempts, err)
 }
 
 // RunCmd runs specified command on OS
 // Receives command as empty interface. It could be string or instance of exec.Cmd
+// Command is traced as a child span if TraceCtx option holds span of the traced operation
 // Returns stdout as string, stderr as string and golang error if something went wrong
 func (e *Executor) RunCmd(cmd interface{}, opts ...Options) (string, string, error) {
+	return e.RunCmdContext(context.Background(), cmd, opts...)
+}
+
+// RunCmdContext runs specified command on OS, command is killed together with its child processes when context is
+// done or its timeout is expired. Timeout is set with Timeout option or taken from cmdTimeouts by executable name
+// Receives context and command as empty interface. It could be string or instance of exec.Cmd
+// Command is traced as a child span of the span from the context or from TraceCtx option if it's set
+// Returns stdout as string, stderr as string and golang error if something went wrong, TimeoutError on timeout
+func (e *Executor) RunCmdContext(ctx context.Context, cmd interface{}, opts ...Options) (stdout string, stderr string, err error) {
 	options := &CmdOptions{}
 	options.ApplyOptions(opts)
 	if options.UseMetrics {
 		defer common.SystemCMDDuration.EvaluateDuration(prometheus.Labels{"name": options.CmdName})()
 	}
-	if cmdStr, ok := cmd.(string); ok {
-		return e.runCmdFromStr(cmdStr)
+	var cmdObj *exec.Cmd
+	switch c := cmd.(type) {
+	case string:
+		if cmdObj = cmdFromStr(c); cmdObj == nil {
+			return "", "", fmt.Errorf("could not interpret command from %v", cmd)
+		}
+	case *exec.Cmd:
+		cmdObj = c
+	default:
+		return "", "", fmt.Errorf("could not interpret command from %v", cmd)
+	}
+
+	traceCtx := ctx
+	if options.TraceCtx != nil {
+		traceCtx = options.TraceCtx
+	}
+	_, span := tracing.StartChildSpan(traceCtx, "RunCmd", cmdKey.String(strings.Join(cmdObj.Args, " ")))
+	defer func() { tracing.EndSpan(span, err) }()
+
+	timeout := options.Timeout
+	if timeout == 0 {
+		timeout = cmdTimeout(cmdObj)
+	}
+	runCtx := ctx
+	if timeout > DefaultCmdTimeout {
+		runCtx = Detach(ctx)
 	}
-	if cmdObj, ok := cmd.(*exec.Cmd); ok {
-		return e.runCmdFromCmdObj(cmdObj)
+	stdout, stderr, err = e.runCmdFromCmdObj(runCtx, cmdObj, timeout)
+	if options.Destructive {
+		audit.Record(ctx, strings.Join(cmdObj.Args, " "), err)
+	}
+	if err != nil {
+		name := options.CmdName
+		if name == "" {
+			name = filepath.Base(cmdObj.Args[0])
+		}
+		common.SystemCMDFailures.WithLabelValues(name).Inc()
+		if IsTimeout(err) {
+			common.SystemCMDTimeouts.WithLabelValues(name).Inc()
+		}
+	}
+	return stdout, stderr, err
+}
+
+// detachedContext holds values of the parent context but isn't cancelled with it and has no deadline
+type detachedContext struct {
+	parent context.Context
+}
+
+// Deadline returns no deadline
+func (detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+// Done returns nil channel, detached context is never done
+func (detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+// Err always returns nil
+func (detachedContext) Err() error {
+	return nil
+}
+
+// Value returns value of the parent context
+func (c detachedContext) Value(key interface{}) interface{} {
+	return c.parent.Value(key)
+}
+
+// Detach returns context which keeps values of ctx, e.g. request UUID and trace span, but isn't cancelled with it.
+// It is used for operations which must not be interrupted by the caller, they are bounded by their own timeout
+func Detach(ctx context.Context) context.Context {
+	return detachedContext{parent: ctx}
+}
+
+// cmdTimeout returns default timeout of the command by name of its executable
+func cmdTimeout(cmd *exec.Cmd) time.Duration {
+	if len(cmd.Args) > 0 {
+		if timeout, ok := cmdTimeouts[filepath.Base(cmd.Args[0])]; ok {
+			return timeout
+		}
 	}
-	return "", "", fmt.Errorf("could not interpret command from %v", cmd)
+	return DefaultCmdTimeout
 }
 
-// runCmdFromStr gets command as a string, like: "netstat -n -a -p" and transform it into exec.Command type
-// and runs runCmdFromCmdObj(cmd)
+// cmdFromStr gets command as a string, like: "netstat -n -a -p" and transform it into exec.Command type
 // Receives command as a string like: bash -c "something -param" are not supported
-// Returns stdout as string, stderr as string and golang error if something went wrong
-func (e *Executor) runCmdFromStr(cmd string) (string, string, error) {
+// Returns instance of exec.Cmd or nil if command is empty
+func cmdFromStr(cmd string) *exec.Cmd {
 	fields := strings.Fields(cmd)
-	name := fields[0]
-	if len(fields) > 1 {
-		return e.runCmdFromCmdObj(exec.Command(name, fields[1:]...))
+	if len(fields) == 0 {
+		return nil
 	}
-	return e.runCmdFromCmdObj(exec.Command(name))
+	return exec.Command(fields[0], fields[1:]...)
 }
 
 // runCmdFromCmdObj runs command based on exec.Cmd
-// Receives instance of exec.Cmd
+// Receives context, instance of exec.Cmd and timeout of the command
 // Returns stdout as string, stderr as string and golang error if something went wrong
-func (e *Executor) runCmdFromCmdObj(cmd *exec.Cmd) (outStr string, errStr string, err error) {
+func (e *Executor) runCmdFromCmdObj(ctx context.Context, cmd *exec.Cmd, timeout time.Duration) (outStr string, errStr string, err error) {
 	var (
 		level               = e.msgLevel
 		stdout, stderr      bytes.Buffer
@@ -164,12 +350,28 @@ func (e *Executor) runCmdFromCmdObj(cmd *exec.Cmd) (outStr string, errStr string
 	}
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
+	// command is started in its own process group to kill its child processes too
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Setpgid = true
 
 	cmdStartTime := time.Now()
-	err = cmd.Run()
+	ctx, cancelFn := context.WithTimeout(ctx, timeout)
+	defer cancelFn()
+	exited, err := runWithContext(ctx, cmd)
 	cmdDuration := time.Since(cmdStartTime)
+	if errors.Is(err, context.DeadlineExceeded) {
+		deadline, _ := ctx.Deadline()
+		err = &TimeoutError{Cmd: strings.Join(cmd.Args, " "), Timeout: deadline.Sub(cmdStartTime).Round(time.Millisecond)}
+	} else if errors.Is(err, context.Canceled) {
+		err = fmt.Errorf("command \"%s\" was killed: %w", strings.Join(cmd.Args, " "), err)
+	}
 
-	outStr, errStr = stdout.String(), stderr.String()
+	// output of abandoned command is still written, so it isn't read
+	if exited {
+		outStr, errStr = stdout.String(), stderr.String()
+	}
 	// construct log message based on output and error
 	if len(errStr) > 0 {
 		stdErrPart = fmt.Sprintf(", stderr: %s", errStr)
@@ -186,3 +388,30 @@ func (e *Executor) runCmdFromCmdObj(cmd *exec.Cmd) (outStr string, errStr string
 		Logf(level, "stdout: %s%s%s", outStr, stdErrPart, errPart)
 	return outStr, errStr, err
 }
+
+// runWithContext starts command and waits for it. Process group of the command is killed when context is done
+// Returns false if killed command didn't exit in killWaitTimeout and error of the command or the context
+func runWithContext(ctx context.Context, cmd *exec.Cmd) (bool, error) {
+	if err := cmd.Start(); err != nil {
+		return true, err
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait()
+	}()
+
+	select {
+	case err := <-done:
+		return true, err
+	case <-ctx.Done():
+	}
+
+	// negative pid kills the whole process group
+	_ = syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	select {
+	case <-done:
+		return true, ctx.Err()
+	case <-time.After(killWaitTimeout):
+		return false, ctx.Err()
+	}
+}
@@ -17,14 +17,22 @@ limitations under the License.
 package command
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"io/ioutil"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"runtime"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
+
+	"github.com/dell/csi-baremetal/pkg/base/audit"
 )
 
 type cmdAndResult struct {
@@ -77,3 +85,87 @@ func TestExecutorFromStrAndExpectError(t *testing.T) {
 		assert.Contains(t, err.Error(), test.err.Error())
 	}
 }
+
+func TestExecutorTimeout(t *testing.T) {
+	e := NewExecutor(logrus.New())
+
+	// child process holds stdout of the command, it must be killed together with the shell
+	start := time.Now()
+	_, _, err := e.RunCmd(exec.Command("sh", "-c", "sleep 30 & sleep 30"), Timeout(100*time.Millisecond))
+	assert.True(t, IsTimeout(err))
+	assert.Less(t, int64(time.Since(start)), int64(killWaitTimeout))
+
+	ctx, cancelFn := context.WithCancel(context.Background())
+	cancelFn()
+	_, _, err = e.RunCmdContext(ctx, "sleep 30")
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.False(t, IsTimeout(err))
+
+	assert.Equal(t, 30*time.Second, cmdTimeout(exec.Command("/usr/bin/lsblk")))
+	assert.Equal(t, DefaultCmdTimeout, cmdTimeout(exec.Command("/sbin/lvm")))
+}
+
+func TestExecutorDetach(t *testing.T) {
+	e := NewExecutor(logrus.New())
+	ctx, cancelFn := context.WithCancel(context.WithValue(context.Background(), ctxKey("key"), "value"))
+	cancelFn()
+
+	// long command isn't cancelled by context of the caller
+	_, _, err := e.RunCmdContext(ctx, "true", Timeout(DefaultCmdTimeout+time.Minute))
+	assert.Nil(t, err)
+
+	detached := Detach(ctx)
+	assert.Nil(t, detached.Err())
+	assert.Equal(t, "value", detached.Value(ctxKey("key")))
+	_, ok := detached.Deadline()
+	assert.False(t, ok)
+}
+
+type ctxKey string
+
+func TestExecutorWithAttempts(t *testing.T) {
+	e := NewExecutor(logrus.New())
+
+	// error of the last attempt is returned
+	_, _, err := e.RunCmdWithAttempts("false", 2, time.Millisecond)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "exit status 1")
+
+	_, _, err = e.RunCmdWithAttempts("sleep 30", 2, time.Millisecond, Timeout(50*time.Millisecond))
+	assert.True(t, IsTimeout(err))
+
+	// attempts are stopped when context is done
+	ctx, cancelFn := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancelFn()
+	start := time.Now()
+	_, _, err = e.RunCmdWithAttemptsContext(ctx, "false", 5, time.Minute)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.Less(t, int64(time.Since(start)), int64(time.Minute))
+}
+
+func TestExecutorDestructive(t *testing.T) {
+	e := NewExecutor(logrus.New())
+	dir, err := ioutil.TempDir("", "audit")
+	assert.Nil(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+	path := filepath.Join(dir, "audit.log")
+
+	stop, err := audit.Init(path, "node", logrus.New())
+	assert.Nil(t, err)
+	ctx := audit.WithTarget(context.Background(), audit.Target{DriveSerial: "SN1"})
+	_, _, err = e.RunCmdContext(ctx, "echo destructive", Destructive(true))
+	assert.Nil(t, err)
+	_, _, err = e.RunCmdContext(ctx, "false", Destructive(true))
+	assert.NotNil(t, err)
+	_, _, err = e.RunCmdContext(ctx, "echo safe")
+	assert.Nil(t, err)
+	stop()
+
+	data, err := ioutil.ReadFile(path)
+	assert.Nil(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	assert.Equal(t, 2, len(lines))
+	assert.Contains(t, lines[0], `"command":"echo destructive"`)
+	assert.Contains(t, lines[0], `"driveSerial":"SN1"`)
+	assert.Contains(t, lines[1], `"result":"failure"`)
+}
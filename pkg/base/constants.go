@@ -58,6 +58,24 @@ const (
 	StorageTypeKey = "storageType"
 	// SizeKey key from volume_context in CreateVolumeRequest of NodePublishVolumeRequest
 	SizeKey = "size"
+	// FallbackStorageTypesKey StorageClass parameter, comma separated storage types which are used in order
+	// when capacity with storageType isn't found
+	FallbackStorageTypesKey = "fallbackStorageTypes"
+	// MinDriveSizeKey and MaxDriveSizeKey StorageClass parameters, limit size of the underlying drive
+	MinDriveSizeKey = "minDriveSize"
+	MaxDriveSizeKey = "maxDriveSize"
+	// DriveVIDKey and DrivePIDKey StorageClass parameters, vendor and product ID of the underlying drive
+	DriveVIDKey = "driveVID"
+	DrivePIDKey = "drivePID"
+	// DriveRotationalKey StorageClass parameter, "true" selects HDD and "false" selects SSD and NVMe drives
+	DriveRotationalKey = "driveRotational"
+	// DriveSelectorKey StorageClass parameter, label selector for Drive CR labels
+	DriveSelectorKey = "driveSelector"
+	// SpreadTopologyKey StorageClass parameter, node label which defines failure domain (rack, zone, etc.)
+	SpreadTopologyKey = "spreadTopologyKey"
+	// SpreadPolicyKey StorageClass parameter, "required" forbids and "preferred" penalizes
+	// placing volumes of the same volume group in one failure domain
+	SpreadPolicyKey = "spreadPolicy"
 	// DefaultNamespace represents default namespace in Kubernetes
 	DefaultNamespace = "default"
 )
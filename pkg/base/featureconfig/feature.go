@@ -25,6 +25,10 @@ const (
 	FeatureNodeIDFromAnnotation = "NodeIDFromAnnotation"
 	// FeatureExternalAnnotationForNode store name for ExternalAnnotationForNodeID feature
 	FeatureExternalAnnotationForNode = "ExternalAnnotationForNode"
+	// FeatureLVGEvacuation store name for LVGEvacuation feature
+	FeatureLVGEvacuation = "LVGEvacuation"
+	// FeatureNodeReplacement store name for NodeReplacement feature
+	FeatureNodeReplacement = "NodeReplacement"
 )
 
 // FeatureChecker is a "read" interface for FeatureConfig
@@ -28,12 +28,14 @@ import (
 
 	api "github.com/dell/csi-baremetal/api/generated/v1"
 	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	acrcrd "github.com/dell/csi-baremetal/api/v1/acreservationcrd"
 	accrd "github.com/dell/csi-baremetal/api/v1/availablecapacitycrd"
 	"github.com/dell/csi-baremetal/api/v1/drivecrd"
 	"github.com/dell/csi-baremetal/api/v1/lvgcrd"
 	"github.com/dell/csi-baremetal/api/v1/volumecrd"
 	"github.com/dell/csi-baremetal/pkg/base"
 	errTypes "github.com/dell/csi-baremetal/pkg/base/error"
+	"github.com/dell/csi-baremetal/pkg/base/util"
 )
 
 // CRHelper is able to collect different CRs by different criteria
@@ -74,7 +76,8 @@ func (cs *CRHelper) GetACByLocation(location string) (*accrd.AvailableCapacity,
 	}
 
 	for _, ac := range acList.Items {
-		if strings.EqualFold(ac.Spec.Location, location) {
+		// AC which is being deleted is kept only until its reservations are removed
+		if strings.EqualFold(ac.Spec.Location, location) && ac.DeletionTimestamp.IsZero() {
 			return &ac, nil
 		}
 	}
@@ -102,7 +105,7 @@ func (cs *CRHelper) DeleteACsByNodeID(nodeID string) error {
 		if strings.EqualFold(ac.Spec.NodeId, nodeID) {
 			// todo fix linter issue - https://github.com/kyoh86/scopelint/issues/5
 			// nolint:scopelint
-			if err := cs.k8sClient.DeleteCR(context.Background(), &ac); err != nil {
+			if err := cs.DeleteConsumedAC(context.Background(), &ac); err != nil {
 				ll.Warningf("Unable to delete AC %s: %s", ac.Name, err)
 				isError = true
 			}
@@ -116,6 +119,22 @@ func (cs *CRHelper) DeleteACsByNodeID(nodeID string) error {
 	return nil
 }
 
+// DeleteConsumedAC deletes AC CR which capacity is consumed or doesn't exist anymore,
+// finalizer is removed first because reservations of such AC are stale and must not postpone its removal
+// Receives golang context and AC CR
+// Returns error or nil
+func (cs *CRHelper) DeleteConsumedAC(ctx context.Context, ac *accrd.AvailableCapacity) error {
+	if util.ContainsString(ac.Finalizers, apiV1.ACFinalizer) {
+		ac.Finalizers = util.RemoveString(ac.Finalizers, apiV1.ACFinalizer)
+		if err := cs.k8sClient.UpdateCR(ctx, ac); err != nil {
+			cs.log.WithField("method", "DeleteConsumedAC").
+				Errorf("Unable to remove finalizer of AC %s: %v", ac.Name, err)
+			return err
+		}
+	}
+	return cs.k8sClient.DeleteCR(ctx, ac)
+}
+
 // GetVolumesByLocation reads the whole list of Volume CRs from a cluster and searches the volume with provided location
 // Receives golang context and location name which should be equal to Volume.Spec.Location
 // Returns a list of a pointers to volumes which are belong to the location and error
@@ -161,18 +180,32 @@ func (cs *CRHelper) GetVolumesByLocation(ctx context.Context, location string) (
 // Receives golang context and drive uuid
 // Returns found lvg and error
 func (cs *CRHelper) GetLVGByDrive(ctx context.Context, driveUUID string) (*lvgcrd.LogicalVolumeGroup, error) {
-	ll := cs.log.WithFields(logrus.Fields{
-		"method":    "GetLVGByDrive",
-		"driveUUID": driveUUID,
+	return cs.getLVG(ctx, "GetLVGByDrive", func(lvg *lvgcrd.LogicalVolumeGroup) bool {
+		return len(lvg.Spec.Locations) > 0 && lvg.Spec.Locations[0] == driveUUID
+	})
+}
+
+// GetLVGContainingDrive reads list of LogicalVolumeGroup CRs from a cluster and searches the lvg which contains
+// provided drive in any location, e.g. drive which was added to LVG during evacuation
+// Receives golang context and drive uuid
+// Returns found lvg and error
+func (cs *CRHelper) GetLVGContainingDrive(ctx context.Context, driveUUID string) (*lvgcrd.LogicalVolumeGroup, error) {
+	return cs.getLVG(ctx, "GetLVGContainingDrive", func(lvg *lvgcrd.LogicalVolumeGroup) bool {
+		return util.ContainsString(lvg.Spec.Locations, driveUUID)
 	})
+}
+
+// getLVG returns the first lvg which matches provided function or nil
+func (cs *CRHelper) getLVG(ctx context.Context, method string,
+	match func(lvg *lvgcrd.LogicalVolumeGroup) bool) (*lvgcrd.LogicalVolumeGroup, error) {
 	lvgList := &lvgcrd.LogicalVolumeGroupList{}
 	if err := cs.reader.ReadList(ctx, lvgList); err != nil {
-		ll.Errorf("Failed to get LogicalVolumeGroup CR list, error %v", err)
+		cs.log.WithField("method", method).Errorf("Failed to get LogicalVolumeGroup CR list, error %v", err)
 		return nil, err
 	}
-	for _, lvg := range lvgList.Items {
-		if len(lvg.Spec.Locations) > 0 && lvg.Spec.Locations[0] == driveUUID {
-			return &lvg, nil
+	for i := range lvgList.Items {
+		if match(&lvgList.Items[i]) {
+			return &lvgList.Items[i], nil
 		}
 	}
 	return nil, nil
@@ -316,7 +349,7 @@ func (cs *CRHelper) GetDriveCRs(node ...string) ([]drivecrd.Drive, error) {
 }
 
 // GetACCRs collect ACs CR that locate on node, use just node[0] element
-// if node isn't provided - return all ACs CR
+// if node isn't provided - return all ACs CR, ACs which are being deleted are skipped
 // if error occurs - return nil and error
 func (cs *CRHelper) GetACCRs(node ...string) ([]accrd.AvailableCapacity, error) {
 	var (
@@ -328,20 +361,40 @@ func (cs *CRHelper) GetACCRs(node ...string) ([]accrd.AvailableCapacity, error)
 		return nil, err
 	}
 
-	if len(node) == 0 {
-		return acsList.Items, nil
-	}
-
-	// if node was provided, collect drives that are on that node
+	// if node was provided, collect ACs that are on that node, ACs which are being deleted are skipped
 	res := make([]accrd.AvailableCapacity, 0)
 	for _, ac := range acsList.Items {
-		if ac.Spec.NodeId == node[0] {
+		if !ac.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if len(node) == 0 || ac.Spec.NodeId == node[0] {
 			res = append(res, ac)
 		}
 	}
 	return res, nil
 }
 
+// GetACRsByAC reads AvailableCapacityReservation CRs and returns names of those which reserve AC with provided name
+// Returns nil and error if something went wrong
+func (cs *CRHelper) GetACRsByAC(ctx context.Context, acName string) ([]string, error) {
+	acrList := &acrcrd.AvailableCapacityReservationList{}
+	if err := cs.reader.ReadList(ctx, acrList); err != nil {
+		cs.log.WithField("method", "GetACRsByAC").Errorf("Failed to get ACR CR list, error %v", err)
+		return nil, err
+	}
+
+	res := make([]string, 0)
+	for _, acr := range acrList.Items {
+		for _, request := range acr.Spec.ReservationRequests {
+			if util.ContainsString(request.Reservations, acName) {
+				res = append(res, acr.Name)
+				break
+			}
+		}
+	}
+	return res, nil
+}
+
 // GetDriveCRByUUID reads drive CRs and returns drive CR with uuid dUUID
 func (cs *CRHelper) GetDriveCRByUUID(dUUID string) *drivecrd.Drive {
 	driveCRs, _ := cs.GetDriveCRs()
@@ -393,6 +446,29 @@ func (cs *CRHelper) GetVGNameByLVGCRName(lvgCRName string) (string, error) {
 	return lvgCR.Spec.Name, nil
 }
 
+// GetLVGDriveSerials returns serial numbers of the drives of LogicalVolumeGroup CR with provided name,
+// drives which CRs aren't found are skipped
+func (cs *CRHelper) GetLVGDriveSerials(ctx context.Context, lvgName string) ([]string, error) {
+	lvg := &lvgcrd.LogicalVolumeGroup{}
+	if err := cs.reader.ReadCR(ctx, lvgName, "", lvg); err != nil {
+		return nil, err
+	}
+	drives, err := cs.GetDriveCRs()
+	if err != nil {
+		return nil, err
+	}
+	serials := make([]string, 0, len(lvg.Spec.Locations))
+	for _, location := range lvg.Spec.Locations {
+		for _, drive := range drives {
+			if drive.Spec.UUID == location {
+				serials = append(serials, drive.Spec.SerialNumber)
+				break
+			}
+		}
+	}
+	return serials, nil
+}
+
 // GetLVGCRs collect LogicalVolumeGroup CRs that locate on node, use just node[0] element
 // if node isn't provided - return all volume CRs
 // if error occurs - return nil
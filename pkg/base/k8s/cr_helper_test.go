@@ -21,6 +21,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	k8sError "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	v1 "github.com/dell/csi-baremetal/api/v1"
 	accrd "github.com/dell/csi-baremetal/api/v1/availablecapacitycrd"
@@ -46,11 +48,33 @@ func TestCRHelper_GetACByLocation(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, expectedAC.Spec, currentAC.Spec)
 
+	// AC which is being deleted is skipped
+	now := metav1.Now()
+	currentAC.DeletionTimestamp = &now
+	assert.Nil(t, ch.k8sClient.UpdateCR(testCtx, currentAC))
+	_, err = ch.GetACByLocation(testACCR.Spec.Location)
+	assert.Equal(t, err, errTypes.ErrorNotFound)
+	acs, err := ch.GetACCRs()
+	assert.Nil(t, err)
+	assert.Empty(t, acs)
+
 	// expected nil because of empty string as a location
-	currentAC, err = ch.GetACByLocation("")
+	_, err = ch.GetACByLocation("")
 	assert.Equal(t, err, errTypes.ErrorNotFound)
 }
 
+func TestCRHelper_DeleteConsumedAC(t *testing.T) {
+	ch := setup()
+	ac := testACCR.DeepCopy()
+	ac.Finalizers = []string{v1.ACFinalizer}
+	assert.Nil(t, ch.k8sClient.CreateCR(testCtx, ac.Name, ac))
+
+	assert.Nil(t, ch.DeleteConsumedAC(testCtx, ac))
+	assert.Empty(t, ac.Finalizers)
+	err := ch.k8sClient.ReadCR(testCtx, ac.Name, "", &accrd.AvailableCapacity{})
+	assert.True(t, k8sError.IsNotFound(err))
+}
+
 func TestCRHelper_GetVolumeByLocation(t *testing.T) {
 	ch := setup()
 	expectedV := testVolumeCR.DeepCopy()
@@ -190,6 +214,44 @@ func TestCRHelper_GetVGNameByLVGCRName(t *testing.T) {
 	assert.Equal(t, "", currentVGName)
 }
 
+func TestCRHelper_GetLVGDriveSerials(t *testing.T) {
+	ch := setup()
+	lvgCR := testLVGCR.DeepCopy()
+	lvgCR.Spec.Locations = []string{testDriveCR.Spec.UUID, "unknown"}
+	assert.Nil(t, ch.k8sClient.CreateCR(testCtx, lvgCR.Name, lvgCR))
+	driveCR := testDriveCR.DeepCopy()
+	assert.Nil(t, ch.k8sClient.CreateCR(testCtx, driveCR.Name, driveCR))
+
+	serials, err := ch.GetLVGDriveSerials(testCtx, lvgCR.Name)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{driveCR.Spec.SerialNumber}, serials)
+
+	_, err = ch.GetLVGDriveSerials(testCtx, "randomName")
+	assert.NotNil(t, err)
+}
+
+func TestCRHelper_GetLVGByDrive(t *testing.T) {
+	ch := setup()
+	lvgCR := testLVGCR.DeepCopy()
+	lvgCR.Spec.Locations = []string{testDriveLocation1, "added-drive"}
+	assert.Nil(t, ch.k8sClient.CreateCR(testCtx, lvgCR.Name, lvgCR))
+
+	lvg, err := ch.GetLVGByDrive(testCtx, testDriveLocation1)
+	assert.Nil(t, err)
+	assert.Equal(t, lvgCR.Name, lvg.Name)
+	// only LVG created on the drive is returned
+	lvg, err = ch.GetLVGByDrive(testCtx, "added-drive")
+	assert.Nil(t, err)
+	assert.Nil(t, lvg)
+
+	lvg, err = ch.GetLVGContainingDrive(testCtx, "added-drive")
+	assert.Nil(t, err)
+	assert.Equal(t, lvgCR.Name, lvg.Name)
+	lvg, err = ch.GetLVGContainingDrive(testCtx, "unknown")
+	assert.Nil(t, err)
+	assert.Nil(t, lvg)
+}
+
 // test AC deletion
 func TestCRHelper_DeleteACsByNodeID(t *testing.T) {
 	mock := setup()
@@ -22,6 +22,7 @@ import (
 	"time"
 
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/api/kv"
 	v1 "k8s.io/api/apps/v1"
 	coreV1 "k8s.io/api/core/v1"
 	k8sError "k8s.io/apimachinery/pkg/api/errors"
@@ -36,10 +37,13 @@ import (
 	acrcrd "github.com/dell/csi-baremetal/api/v1/acreservationcrd"
 	accrd "github.com/dell/csi-baremetal/api/v1/availablecapacitycrd"
 	"github.com/dell/csi-baremetal/api/v1/drivecrd"
+	"github.com/dell/csi-baremetal/api/v1/firmwarepolicycrd"
 	"github.com/dell/csi-baremetal/api/v1/lvgcrd"
 	"github.com/dell/csi-baremetal/api/v1/nodecrd"
+	"github.com/dell/csi-baremetal/api/v1/storagequotacrd"
 	"github.com/dell/csi-baremetal/api/v1/volumecrd"
 	"github.com/dell/csi-baremetal/pkg/base"
+	"github.com/dell/csi-baremetal/pkg/base/tracing"
 	"github.com/dell/csi-baremetal/pkg/metrics"
 	"github.com/dell/csi-baremetal/pkg/metrics/common"
 )
@@ -86,8 +90,10 @@ func NewKubeClient(k8sclient k8sCl.Client, logger *logrus.Logger, namespace stri
 // CreateCR creates provided resource on k8s cluster with checking its existence before
 // Receives golang context, name of the created object, and object that implements k8s runtime.Object interface
 // Returns error if something went wrong
-func (k *KubeClient) CreateCR(ctx context.Context, name string, obj runtime.Object) error {
+func (k *KubeClient) CreateCR(ctx context.Context, name string, obj runtime.Object) (err error) {
 	defer k.metrics.EvaluateDurationForMethod("CreateCR")()
+	ctx, span := tracing.StartChildSpan(ctx, "CreateCR", crAttributes(obj)...)
+	defer func() { tracing.EndSpan(span, err) }()
 	requestUUID := ctx.Value(base.RequestUUID)
 	if requestUUID == nil {
 		requestUUID = DefaultVolumeID
@@ -98,7 +104,8 @@ func (k *KubeClient) CreateCR(ctx context.Context, name string, obj runtime.Obje
 	})
 	crKind := obj.GetObjectKind().GroupVersionKind().Kind
 	ll.Infof("Creating CR %s: %v", crKind, obj)
-	err := k.Create(ctx, obj)
+	syncConditions(obj)
+	err = k.Create(ctx, obj)
 	if err != nil {
 		if k8sError.IsAlreadyExists(err) {
 			ll.Infof("CR %s %s already exist", crKind, name)
@@ -133,8 +140,10 @@ func (k *KubeClient) ReadList(ctx context.Context, obj runtime.Object) error {
 // UpdateCR updates provided resource on k8s cluster
 // Receives golang context and updated object that implements k8s runtime.Object interface
 // Returns error if something went wrong
-func (k *KubeClient) UpdateCR(ctx context.Context, obj runtime.Object) error {
+func (k *KubeClient) UpdateCR(ctx context.Context, obj runtime.Object) (err error) {
 	defer k.metrics.EvaluateDurationForMethod("UpdateCR")()
+	ctx, span := tracing.StartChildSpan(ctx, "UpdateCR", crAttributes(obj)...)
+	defer func() { tracing.EndSpan(span, err) }()
 	requestUUID := ctx.Value(base.RequestUUID)
 	if requestUUID == nil {
 		requestUUID = DefaultVolumeID
@@ -145,14 +154,34 @@ func (k *KubeClient) UpdateCR(ctx context.Context, obj runtime.Object) error {
 		"requestUUID": requestUUID.(string),
 	}).Infof("Updating CR %s, %v", obj.GetObjectKind().GroupVersionKind().Kind, obj)
 
+	syncConditions(obj)
 	return k.Update(ctx, obj)
 }
 
+// crAttributes returns span attributes which identify the custom resource
+func crAttributes(obj runtime.Object) []kv.KeyValue {
+	attrs := []kv.KeyValue{kv.String("cr.kind", obj.GetObjectKind().GroupVersionKind().Kind)}
+	if meta, ok := obj.(apisV1.Object); ok {
+		attrs = append(attrs, kv.String("cr.name", meta.GetName()))
+	}
+	return attrs
+}
+
+// syncConditions sets conditions of the custom resource derived from its runtime state.
+// v1 version of such resources has no status subresource, so conditions are written along with spec in one call
+func syncConditions(obj runtime.Object) {
+	if statusObj, ok := obj.(crdV1.StatusObject); ok {
+		statusObj.SyncConditions()
+	}
+}
+
 // DeleteCR deletes provided resource from k8s cluster
 // Receives golang context and removable object that implements k8s runtime.Object interface
 // Returns error if something went wrong
-func (k *KubeClient) DeleteCR(ctx context.Context, obj runtime.Object) error {
+func (k *KubeClient) DeleteCR(ctx context.Context, obj runtime.Object) (err error) {
 	defer k.metrics.EvaluateDurationForMethod("DeleteCR")()
+	ctx, span := tracing.StartChildSpan(ctx, "DeleteCR", crAttributes(obj)...)
+	defer func() { tracing.EndSpan(span, err) }()
 	requestUUID := ctx.Value(base.RequestUUID)
 	if requestUUID == nil {
 		requestUUID = DefaultVolumeID
@@ -435,5 +464,15 @@ func PrepareScheme() (*runtime.Scheme, error) {
 		return nil, err
 	}
 
+	// register firmware policy crd
+	if err := firmwarepolicycrd.AddToSchemeFirmwarePolicy(scheme); err != nil {
+		return nil, err
+	}
+
+	// register storage quota crd
+	if err := storagequotacrd.AddToSchemeStorageQuota(scheme); err != nil {
+		return nil, err
+	}
+
 	return scheme, nil
 }
@@ -18,6 +18,7 @@ package k8s
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"testing"
@@ -373,6 +374,44 @@ var _ = Describe("Working with CRD", func() {
 		})
 	})
 
+	Context("Status of CR", func() {
+		It("Should set conditions of Drive on create and update", func() {
+			driveCR := testDriveCR
+			err := k8sclient.CreateCR(testCtx, testUUID, &driveCR)
+			Expect(err).To(BeNil())
+			rdrive := &drivecrd.Drive{}
+			err = k8sclient.ReadCR(testCtx, testUUID, "", rdrive)
+			Expect(err).To(BeNil())
+			online := apiV1.FindCondition(rdrive.Status.Conditions, apiV1.ConditionOnline)
+			Expect(online).ToNot(BeNil())
+			Expect(online.Status).To(Equal(k8smetav1.ConditionTrue))
+			Expect(online.Reason).To(Equal(apiV1.DriveStatusOnline))
+
+			rdrive.Spec.Health = apiV1.HealthBad
+			err = k8sclient.UpdateCR(testCtx, rdrive)
+			Expect(err).To(BeNil())
+			healthy := apiV1.FindCondition(rdrive.Status.Conditions, apiV1.ConditionHealthy)
+			Expect(healthy).ToNot(BeNil())
+			Expect(healthy.Status).To(Equal(k8smetav1.ConditionFalse))
+			Expect(healthy.Reason).To(Equal(apiV1.HealthBad))
+		})
+
+		It("Should keep message of failed condition", func() {
+			volumeCR := testVolumeCR
+			volumeCR.Spec.CSIStatus = apiV1.Failed
+			apiV1.SetFailedCondition(&volumeCR.Status.Conditions, apiV1.ConditionReady, errors.New("error"))
+			err := k8sclient.CreateCR(testCtx, testID, &volumeCR)
+			Expect(err).To(BeNil())
+			rVolume := &vcrd.Volume{}
+			err = k8sclient.ReadCR(testCtx, testID, testNs, rVolume)
+			Expect(err).To(BeNil())
+			ready := apiV1.FindCondition(rVolume.Status.Conditions, apiV1.ConditionReady)
+			Expect(ready).ToNot(BeNil())
+			Expect(ready.Status).To(Equal(k8smetav1.ConditionFalse))
+			Expect(ready.Message).To(Equal("error"))
+		})
+	})
+
 	Context("Delete CR", func() {
 		It("AC should be deleted", func() {
 			err := k8sclient.CreateCR(testCtx, testUUID, &testACCR)
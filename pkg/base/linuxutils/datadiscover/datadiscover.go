@@ -18,6 +18,7 @@ limitations under the License.
 package datadiscover
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/dell/csi-baremetal/pkg/base/linuxutils/datadiscover/types"
@@ -44,14 +45,14 @@ func NewDataDiscover(fs fs.WrapFS,
 // It executes lsblk to find file systems and partitions, parted for partition table
 // Receive device path and serial number
 // Return true if device has data, false in opposite, error if something went wrong
-func (w *WrapDataDiscoverImpl) DiscoverData(device, serialNumber string) (*types.DiscoverResult, error) {
+func (w *WrapDataDiscoverImpl) DiscoverData(ctx context.Context, device, serialNumber string) (*types.DiscoverResult, error) {
 	var (
 		fileSystem string
 		hasData    bool
 		err        error
 	)
 
-	if fileSystem, err = w.fsHelper.DeviceFs(device); err != nil {
+	if fileSystem, err = w.fsHelper.DeviceFs(ctx, device); err != nil {
 		return nil, err
 	}
 	if fileSystem != "" {
@@ -61,7 +62,7 @@ func (w *WrapDataDiscoverImpl) DiscoverData(device, serialNumber string) (*types
 		}, nil
 	}
 
-	if hasData, err = w.partHelper.DeviceHasPartitionTable(device); err != nil {
+	if hasData, err = w.partHelper.DeviceHasPartitionTable(ctx, device); err != nil {
 		return nil, err
 	}
 	if hasData {
@@ -71,7 +72,7 @@ func (w *WrapDataDiscoverImpl) DiscoverData(device, serialNumber string) (*types
 		}, nil
 	}
 
-	if hasData, err = w.partHelper.DeviceHasPartitions(device, serialNumber); err != nil {
+	if hasData, err = w.partHelper.DeviceHasPartitions(ctx, device, serialNumber); err != nil {
 		return nil, err
 	}
 	if hasData {
@@ -1,6 +1,7 @@
 package datadiscover
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"testing"
@@ -10,6 +11,8 @@ import (
 	mocklu "github.com/dell/csi-baremetal/pkg/mocks/linuxutils"
 )
 
+var testCtx = context.Background()
+
 func Test_DiscoverData(t *testing.T) {
 	var (
 		device       = "/dev/sda"
@@ -23,7 +26,7 @@ func Test_DiscoverData(t *testing.T) {
 			discoverData = NewDataDiscover(&fs, &part, &lvm)
 		)
 		fs.On("DeviceFs", device).Return("xfs", nil).Times(1)
-		discoverResult, err := discoverData.DiscoverData(device, serialNumber)
+		discoverResult, err := discoverData.DiscoverData(testCtx, device, serialNumber)
 		assert.Nil(t, err)
 		assert.True(t, discoverResult.HasData)
 	})
@@ -36,7 +39,7 @@ func Test_DiscoverData(t *testing.T) {
 		)
 		fs.On("DeviceFs", device).Return(" ", nil).Times(1)
 		part.On("DeviceHasPartitionTable", device).Return(true, nil).Times(1)
-		discoverResult, err := discoverData.DiscoverData(device, serialNumber)
+		discoverResult, err := discoverData.DiscoverData(testCtx, device, serialNumber)
 		assert.Nil(t, err)
 		assert.True(t, discoverResult.HasData)
 	})
@@ -50,7 +53,7 @@ func Test_DiscoverData(t *testing.T) {
 		fs.On("DeviceFs", device).Return("", nil).Times(1)
 		part.On("DeviceHasPartitionTable", device).Return(false, nil).Times(1)
 		part.On("DeviceHasPartitions", device, serialNumber).Return(true, nil).Times(1)
-		discoverResult, err := discoverData.DiscoverData(device, serialNumber)
+		discoverResult, err := discoverData.DiscoverData(testCtx, device, serialNumber)
 		assert.Nil(t, err)
 		assert.True(t, discoverResult.HasData)
 
@@ -65,7 +68,7 @@ func Test_DiscoverData(t *testing.T) {
 		fs.On("DeviceFs", device).Return("", nil).Times(1)
 		part.On("DeviceHasPartitionTable", device).Return(false, nil).Times(1)
 		part.On("DeviceHasPartitions", device, serialNumber).Return(false, nil).Times(1)
-		discoverResult, err := discoverData.DiscoverData(device, serialNumber)
+		discoverResult, err := discoverData.DiscoverData(testCtx, device, serialNumber)
 		assert.Nil(t, err)
 		fmt.Println(discoverResult.HasData)
 		assert.False(t, discoverResult.HasData)
@@ -78,7 +81,7 @@ func Test_DiscoverData(t *testing.T) {
 			discoverData = NewDataDiscover(&fs, &part, &lvm)
 		)
 		fs.On("DeviceFs", device).Return("", errors.New("error")).Times(1)
-		discoverResult, err := discoverData.DiscoverData(device, serialNumber)
+		discoverResult, err := discoverData.DiscoverData(testCtx, device, serialNumber)
 		assert.NotNil(t, err)
 		assert.Nil(t, discoverResult)
 	})
@@ -91,7 +94,7 @@ func Test_DiscoverData(t *testing.T) {
 		)
 		fs.On("DeviceFs", device).Return("", nil).Times(1)
 		part.On("DeviceHasPartitionTable", device).Return(false, errors.New("error")).Times(1)
-		discoverResult, err := discoverData.DiscoverData(device, serialNumber)
+		discoverResult, err := discoverData.DiscoverData(testCtx, device, serialNumber)
 		assert.NotNil(t, err)
 		assert.Nil(t, discoverResult)
 	})
@@ -105,7 +108,7 @@ func Test_DiscoverData(t *testing.T) {
 		fs.On("DeviceFs", device).Return("", nil).Times(1)
 		part.On("DeviceHasPartitionTable", device).Return(false, nil).Times(1)
 		part.On("DeviceHasPartitions", device, serialNumber).Return(false, errors.New("error")).Times(1)
-		discoverResult, err := discoverData.DiscoverData(device, serialNumber)
+		discoverResult, err := discoverData.DiscoverData(testCtx, device, serialNumber)
 		assert.NotNil(t, err)
 		assert.Nil(t, discoverResult)
 	})
@@ -17,9 +17,11 @@ limitations under the License.
 // Package types contains interface and structure for discovering of logic entries on drive
 package types
 
+import "context"
+
 // WrapDataDiscover is the interface which encapsulates method to discover data on drives
 type WrapDataDiscover interface {
-	DiscoverData(device, serialNumber string) (*DiscoverResult, error)
+	DiscoverData(ctx context.Context, device, serialNumber string) (*DiscoverResult, error)
 }
 
 // DiscoverResult encapsulates result of DiscoverData function
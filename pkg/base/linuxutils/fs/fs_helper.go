@@ -18,6 +18,7 @@ limitations under the License.
 package fs
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path"
@@ -72,19 +73,19 @@ const (
 
 // WrapFS is an interface that encapsulates operation with file systems
 type WrapFS interface {
-	GetFSSpace(src string) (int64, error)
-	MkDir(src string) error
-	MkFile(src string) error
-	RmDir(src string) error
-	CreateFS(fsType FileSystem, device string) error
-	WipeFS(device string) error
-	GetFSType(device string) (FileSystem, error)
+	GetFSSpace(ctx context.Context, src string) (int64, error)
+	MkDir(ctx context.Context, src string) error
+	MkFile(ctx context.Context, src string) error
+	RmDir(ctx context.Context, src string) error
+	CreateFS(ctx context.Context, fsType FileSystem, device string) error
+	WipeFS(ctx context.Context, device string) error
+	GetFSType(ctx context.Context, device string) (FileSystem, error)
 	// Mount operations
-	IsMounted(src string) (bool, error)
-	FindMountPoint(target string) (string, error)
-	Mount(src, dst string, opts ...string) error
-	Unmount(src string) error
-	DeviceFs(device string) (string, error)
+	IsMounted(ctx context.Context, src string) (bool, error)
+	FindMountPoint(ctx context.Context, target string) (string, error)
+	Mount(ctx context.Context, src, dst string, opts ...string) error
+	Unmount(ctx context.Context, src string) error
+	DeviceFs(ctx context.Context, device string) (string, error)
 }
 
 // WrapFSImpl is a WrapFS implementer
@@ -100,7 +101,7 @@ func NewFSImpl(e command.CmdExecutor) *WrapFSImpl {
 
 // GetFSSpace calls df command and return available space on the provided file system (src)
 // Returns free bytes as int64 or error if something went wrong
-func (h *WrapFSImpl) GetFSSpace(src string) (int64, error) {
+func (h *WrapFSImpl) GetFSSpace(ctx context.Context, src string) (int64, error) {
 	/*
 		Example of output:
 			~# df /dev --output=target,avail --block-size=M
@@ -108,7 +109,7 @@ func (h *WrapFSImpl) GetFSSpace(src string) (int64, error) {
 				/dev       7982M
 	*/
 
-	stdout, _, err := h.e.RunCmd(fmt.Sprintf(CheckSpaceCmdImpl, src),
+	stdout, _, err := h.e.RunCmdContext(ctx, fmt.Sprintf(CheckSpaceCmdImpl, src),
 		command.UseMetrics(true),
 		command.CmdName(strings.TrimSpace(fmt.Sprintf(CheckSpaceCmdImpl, ""))))
 	if err != nil {
@@ -136,10 +137,10 @@ func (h *WrapFSImpl) GetFSSpace(src string) (int64, error) {
 // MkDir creates specified path using mkdir if it doesn't exist
 // Receives directory path to create as a string
 // Returns error if something went wrong
-func (h *WrapFSImpl) MkDir(src string) error {
+func (h *WrapFSImpl) MkDir(ctx context.Context, src string) error {
 	cmd := fmt.Sprintf(MkDirCmdTmpl, src)
 
-	if _, _, err := h.e.RunCmd(cmd,
+	if _, _, err := h.e.RunCmdContext(ctx, cmd,
 		command.UseMetrics(true),
 		command.CmdName(strings.TrimSpace(fmt.Sprintf(MkDirCmdTmpl, "")))); err != nil {
 		return fmt.Errorf("failed to create dir %s: %v", src, err)
@@ -148,13 +149,13 @@ func (h *WrapFSImpl) MkDir(src string) error {
 }
 
 // MkFile create file with specified path
-func (h *WrapFSImpl) MkFile(src string) error {
+func (h *WrapFSImpl) MkFile(ctx context.Context, src string) error {
 	st, err := os.Stat(src)
 	if err != nil {
 		if !os.IsNotExist(err) {
 			return err
 		}
-		err = h.MkDir(path.Dir(src))
+		err = h.MkDir(ctx, path.Dir(src))
 		if err != nil {
 			return fmt.Errorf("failed to create parrent dir")
 		}
@@ -176,10 +177,10 @@ func (h *WrapFSImpl) MkFile(src string) error {
 // RmDir removes specified path using rm
 // Receives directory of file path to delete as a string
 // Returns error if something went wrong
-func (h *WrapFSImpl) RmDir(src string) error {
+func (h *WrapFSImpl) RmDir(ctx context.Context, src string) error {
 	cmd := fmt.Sprintf(RmDirCmdTmpl, src)
 
-	if _, _, err := h.e.RunCmd(cmd,
+	if _, _, err := h.e.RunCmdContext(ctx, cmd,
 		command.UseMetrics(true),
 		command.CmdName(strings.TrimSpace(fmt.Sprintf(RmDirCmdTmpl, "")))); err != nil {
 		return fmt.Errorf("failed to delete path %s: %v", src, err)
@@ -190,7 +191,7 @@ func (h *WrapFSImpl) RmDir(src string) error {
 // CreateFS creates specified file system on the provided device using mkfs
 // Receives file system as a var of FileSystem type and path of the device as a string
 // Returns error if something went wrong
-func (h *WrapFSImpl) CreateFS(fsType FileSystem, device string) error {
+func (h *WrapFSImpl) CreateFS(ctx context.Context, fsType FileSystem, device string) error {
 	var cmd string
 	switch fsType {
 	case XFS:
@@ -201,9 +202,10 @@ func (h *WrapFSImpl) CreateFS(fsType FileSystem, device string) error {
 		return fmt.Errorf("unsupported file system %v", fsType)
 	}
 
-	if _, _, err := h.e.RunCmd(cmd,
+	if _, _, err := h.e.RunCmdContext(ctx, cmd,
 		command.UseMetrics(true),
-		command.CmdName(strings.TrimSpace(fmt.Sprintf(MkFSCmdTmpl, "", "")))); err != nil {
+		command.CmdName(strings.TrimSpace(fmt.Sprintf(MkFSCmdTmpl, "", ""))),
+		command.Destructive(true)); err != nil {
 		return fmt.Errorf("failed to create file system on %s: %v", device, err)
 	}
 	return nil
@@ -212,19 +214,20 @@ func (h *WrapFSImpl) CreateFS(fsType FileSystem, device string) error {
 // WipeFS deletes file system from the provided device using wipefs
 // Receives file path of the device as a string
 // Returns error if something went wrong
-func (h *WrapFSImpl) WipeFS(device string) error {
+func (h *WrapFSImpl) WipeFS(ctx context.Context, device string) error {
 	cmd := fmt.Sprintf(WipeFSCmdTmpl, device)
 
-	if _, _, err := h.e.RunCmd(cmd,
+	if _, _, err := h.e.RunCmdContext(ctx, cmd,
 		command.UseMetrics(true),
-		command.CmdName(strings.TrimSpace(fmt.Sprintf(WipeFSCmdTmpl, "")))); err != nil {
+		command.CmdName(strings.TrimSpace(fmt.Sprintf(WipeFSCmdTmpl, ""))),
+		command.Destructive(true)); err != nil {
 		return fmt.Errorf("failed to wipe file system on %s: %v", device, err)
 	}
 	return nil
 }
 
 // GetFSType returns FS type on the device or error
-func (h *WrapFSImpl) GetFSType(device string) (FileSystem, error) {
+func (h *WrapFSImpl) GetFSType(ctx context.Context, device string) (FileSystem, error) {
 	/*
 		Example of output:
 			~# wipefs /dev/mvg/lv1 --output TYPE --noheadings
@@ -232,7 +235,7 @@ func (h *WrapFSImpl) GetFSType(device string) (FileSystem, error) {
 	*/
 	cmd := fmt.Sprintf(GetFSTypeCmdTmpl, device)
 
-	stdout, _, err := h.e.RunCmd(cmd,
+	stdout, _, err := h.e.RunCmdContext(ctx, cmd,
 		command.UseMetrics(true),
 		command.CmdName(strings.TrimSpace(fmt.Sprintf(GetFSTypeCmdTmpl, ""))))
 	if err != nil {
@@ -245,7 +248,7 @@ func (h *WrapFSImpl) GetFSType(device string) (FileSystem, error) {
 // IsMounted checks if the path is presented in /proc/self/mountinfo
 // Receives path as a string
 // Returns bool that represents mount status or error if something went wrong
-func (h *WrapFSImpl) IsMounted(path string) (bool, error) {
+func (h *WrapFSImpl) IsMounted(ctx context.Context, path string) (bool, error) {
 	h.opMutex.Lock()
 	defer h.opMutex.Unlock()
 
@@ -267,7 +270,7 @@ func (h *WrapFSImpl) IsMounted(path string) (bool, error) {
 // FindMountPoint returns source of mount point for target
 // Receives path of a mount point as target
 // Returns mount point or empty string and error
-func (h *WrapFSImpl) FindMountPoint(target string) (string, error) {
+func (h *WrapFSImpl) FindMountPoint(ctx context.Context, target string) (string, error) {
 	/*
 		Example of output:
 			~# findmnt --target / --output SOURCE --noheadings
@@ -278,7 +281,7 @@ func (h *WrapFSImpl) FindMountPoint(target string) (string, error) {
 	cmd := fmt.Sprintf(FindMntCmdTmpl, target)
 	h.opMutex.Unlock()
 
-	strOut, _, err := h.e.RunCmd(cmd,
+	strOut, _, err := h.e.RunCmdContext(ctx, cmd,
 		command.UseMetrics(true),
 		command.CmdName(strings.TrimSpace(fmt.Sprintf(FindMntCmdTmpl, ""))))
 	if err != nil {
@@ -290,10 +293,10 @@ func (h *WrapFSImpl) FindMountPoint(target string) (string, error) {
 // Mount mounts source path to the destination directory
 // Receives source path and destination dir and also opts parameters that are used for mount command for example --bind
 // Returns error if something went wrong
-func (h *WrapFSImpl) Mount(src, dir string, opts ...string) error {
+func (h *WrapFSImpl) Mount(ctx context.Context, src, dir string, opts ...string) error {
 	cmd := fmt.Sprintf(MountCmdTmpl, strings.Join(opts, " "), src, dir)
 	h.opMutex.Lock()
-	_, _, err := h.e.RunCmd(cmd,
+	_, _, err := h.e.RunCmdContext(ctx, cmd,
 		command.UseMetrics(true),
 		command.CmdName(strings.TrimSpace(fmt.Sprintf(MountCmdTmpl, "", "", ""))))
 	h.opMutex.Unlock()
@@ -304,11 +307,11 @@ func (h *WrapFSImpl) Mount(src, dir string, opts ...string) error {
 // Unmount unmounts device from the specified path
 // Receives path where the device is mounted
 // Returns error if something went wrong
-func (h *WrapFSImpl) Unmount(path string) error {
+func (h *WrapFSImpl) Unmount(ctx context.Context, path string) error {
 	cmd := fmt.Sprintf(UnmountCmdTmpl, path)
 
 	h.opMutex.Lock()
-	_, _, err := h.e.RunCmd(cmd,
+	_, _, err := h.e.RunCmdContext(ctx, cmd,
 		command.UseMetrics(true),
 		command.CmdName(strings.TrimSpace(fmt.Sprintf(UnmountCmdTmpl, ""))))
 	h.opMutex.Unlock()
@@ -319,13 +322,13 @@ func (h *WrapFSImpl) Unmount(path string) error {
 // DeviceFs detect FS from the provided device using lsblk --output FSTYPE
 // Receives file path of the device as a string
 // Returns error if something went wrong
-func (h *WrapFSImpl) DeviceFs(device string) (string, error) {
+func (h *WrapFSImpl) DeviceFs(ctx context.Context, device string) (string, error) {
 	var (
 		cmd    = fmt.Sprintf(DetectFSCmdTmpl, device)
 		stdout string
 		err    error
 	)
-	if stdout, _, err = h.e.RunCmd(cmd,
+	if stdout, _, err = h.e.RunCmdContext(ctx, cmd,
 		command.UseMetrics(true),
 		command.CmdName(fmt.Sprintf(DetectFSCmdTmpl, ""))); err != nil {
 		return "", fmt.Errorf("failed to detect file system on %s: %v", device, err)
@@ -17,6 +17,7 @@ limitations under the License.
 package fs
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"testing"
@@ -28,6 +29,7 @@ import (
 )
 
 var (
+	testCtx   = context.Background()
 	testError = errors.New("error")
 )
 
@@ -45,13 +47,13 @@ func TestFindMountPoint(t *testing.T) {
 
 	// success
 	e.OnCommand(cmd).Return(expectedRes, "", nil).Times(1)
-	currentRes, err = fh.FindMountPoint(target)
+	currentRes, err = fh.FindMountPoint(testCtx, target)
 	assert.Nil(t, err)
 	assert.Equal(t, expectedRes, currentRes)
 
 	// expect error
 	e.OnCommand(cmd).Return("", "", expectedErr).Times(1)
-	currentRes, err = fh.FindMountPoint(target)
+	currentRes, err = fh.FindMountPoint(testCtx, target)
 	assert.Equal(t, expectedErr, err)
 }
 
@@ -66,7 +68,7 @@ func TestGetFSSpace_Fail(t *testing.T) {
 	// wrong df output
 	mockexec.On("RunCmd", cmd).
 		Return("dadasda", "", nil).Times(1)
-	freeBytes, err := fh.GetFSSpace("/")
+	freeBytes, err := fh.GetFSSpace(testCtx, "/")
 	assert.NotNil(t, err)
 	assert.Contains(t, err.Error(), "wrong df output")
 	assert.Equal(t, freeBytes, int64(0))
@@ -74,14 +76,14 @@ func TestGetFSSpace_Fail(t *testing.T) {
 	// fail to parse output
 	mockexec.On("RunCmd", cmd).
 		Return("Mounted on Avail\n/   10MM", "", nil).Times(1)
-	freeBytes, err = fh.GetFSSpace(path)
+	freeBytes, err = fh.GetFSSpace(testCtx, path)
 	assert.NotNil(t, err)
 	assert.Equal(t, freeBytes, int64(0))
 
 	// command error
 	mockexec.On("RunCmd", cmd).
 		Return("/   10MM", "", fmt.Errorf("error")).Times(1)
-	freeBytes, err = fh.GetFSSpace("/")
+	freeBytes, err = fh.GetFSSpace(testCtx, "/")
 	assert.NotNil(t, err)
 	assert.Contains(t, err.Error(), "error")
 	assert.Equal(t, freeBytes, int64(0))
@@ -99,7 +101,7 @@ func TestGetFSSpace_Success(t *testing.T) {
 
 	mockexec.On("RunCmd", cmd).
 		Return(cmdResult, "", nil)
-	freeBytes, err := fh.GetFSSpace(path)
+	freeBytes, err := fh.GetFSSpace(testCtx, path)
 	assert.Nil(t, err)
 	expectedRes, err := util.StrToBytes(sizeStr)
 	assert.Nil(t, err)
@@ -116,12 +118,12 @@ func TestMkDir(t *testing.T) {
 	)
 
 	e.OnCommand(cmd).Return("", "", nil).Times(1)
-	err = fh.MkDir(src)
+	err = fh.MkDir(testCtx, src)
 	assert.Nil(t, err)
 
 	// cmd failed
 	e.OnCommand(cmd).Return("", "", testError).Times(1)
-	err = fh.MkDir(src)
+	err = fh.MkDir(testCtx, src)
 	assert.NotNil(t, err)
 }
 
@@ -135,12 +137,12 @@ func TestRmDir(t *testing.T) {
 	)
 
 	e.OnCommand(cmd).Return("", "", nil).Times(1)
-	err = fh.RmDir(src)
+	err = fh.RmDir(testCtx, src)
 	assert.Nil(t, err)
 
 	// cmd failed
 	e.OnCommand(cmd).Return("", "", testError).Times(1)
-	err = fh.RmDir(src)
+	err = fh.RmDir(testCtx, src)
 	assert.NotNil(t, err)
 }
 
@@ -155,16 +157,16 @@ func TestCreateFS(t *testing.T) {
 	)
 
 	e.OnCommand(cmd).Return("", "", nil).Times(1)
-	err = fh.CreateFS(fsType, device)
+	err = fh.CreateFS(testCtx, fsType, device)
 	assert.Nil(t, err)
 
 	// cmd failed
 	e.OnCommand(cmd).Return("", "", testError).Times(1)
-	err = fh.CreateFS(fsType, device)
+	err = fh.CreateFS(testCtx, fsType, device)
 	assert.NotNil(t, err)
 
 	// unsupported FS
-	err = fh.CreateFS("anotherFS", device)
+	err = fh.CreateFS(testCtx, "anotherFS", device)
 	assert.NotNil(t, err)
 	assert.Contains(t, err.Error(), "unsupported file system")
 }
@@ -179,12 +181,12 @@ func TestWipeFS(t *testing.T) {
 	)
 
 	e.OnCommand(cmd).Return("", "", nil).Times(1)
-	err = fh.WipeFS(device)
+	err = fh.WipeFS(testCtx, device)
 	assert.Nil(t, err)
 
 	// cmd failed
 	e.OnCommand(cmd).Return("", "", testError).Times(1)
-	err = fh.WipeFS(device)
+	err = fh.WipeFS(testCtx, device)
 	assert.NotNil(t, err)
 }
 
@@ -200,13 +202,13 @@ func TestGetFSType(t *testing.T) {
 	)
 
 	e.OnCommand(cmd).Return(expectedFS, "", nil).Times(1)
-	currentFS, err = fh.GetFSType(device)
+	currentFS, err = fh.GetFSType(testCtx, device)
 	assert.Nil(t, err)
 	assert.Equal(t, FileSystem(expectedFS), currentFS)
 
 	// cmd failed
 	e.OnCommand(cmd).Return("", "", testError).Times(1)
-	_, err = fh.GetFSType(device)
+	_, err = fh.GetFSType(testCtx, device)
 	assert.NotNil(t, err)
 }
 
@@ -221,12 +223,12 @@ func TestMount(t *testing.T) {
 	)
 
 	e.OnCommand(cmd).Return("", "", nil).Times(1)
-	err = fh.Mount(src, dst)
+	err = fh.Mount(testCtx, src, dst)
 	assert.Nil(t, err)
 
 	// cmd failed
 	e.OnCommand(cmd).Return("", "", testError).Times(1)
-	err = fh.Mount(src, dst)
+	err = fh.Mount(testCtx, src, dst)
 	assert.NotNil(t, err)
 }
 
@@ -240,12 +242,12 @@ func TestUnmount(t *testing.T) {
 	)
 
 	e.OnCommand(cmd).Return("", "", nil).Times(1)
-	err = fh.Unmount(path)
+	err = fh.Unmount(testCtx, path)
 	assert.Nil(t, err)
 
 	// cmd failed
 	e.OnCommand(cmd).Return("", "", testError).Times(1)
-	err = fh.Unmount(path)
+	err = fh.Unmount(testCtx, path)
 	assert.NotNil(t, err)
 }
 
@@ -258,17 +260,17 @@ func Test_DeviceHasFs(t *testing.T) {
 	)
 
 	e.OnCommand(cmd).Return("", "", nil).Times(1)
-	hasData, err := fh.DeviceFs(path)
+	hasData, err := fh.DeviceFs(testCtx, path)
 	assert.Nil(t, err)
 	assert.Equal(t, "", hasData)
 
 	e.OnCommand(cmd).Return("xfs", "", testError).Times(1)
-	hasData, err = fh.DeviceFs(path)
+	hasData, err = fh.DeviceFs(testCtx, path)
 	assert.NotNil(t, err)
 	assert.Equal(t, "", hasData)
 
 	e.OnCommand(cmd).Return("xfs", "", nil).Times(1)
-	hasData, err = fh.DeviceFs(path)
+	hasData, err = fh.DeviceFs(testCtx, path)
 	assert.Nil(t, err)
 	assert.Equal(t, "xfs", hasData)
 }
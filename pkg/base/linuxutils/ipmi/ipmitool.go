@@ -18,6 +18,7 @@ limitations under the License.
 package ipmi
 
 import (
+	"context"
 	"regexp"
 	"strings"
 
@@ -31,7 +32,7 @@ const (
 
 // WrapIpmi is an interface that encapsulates operation with system ipmi util
 type WrapIpmi interface {
-	GetBmcIP() string
+	GetBmcIP(ctx context.Context) string
 }
 
 // IPMI is implementation for WrapImpi interface
@@ -45,13 +46,13 @@ func NewIPMI(e command.CmdExecutor) *IPMI {
 }
 
 // GetBmcIP returns BMC IP using ipmitool
-func (i *IPMI) GetBmcIP() string {
+func (i *IPMI) GetBmcIP(ctx context.Context) string {
 	/* Sample output
 	IP Address Source       : DHCP Address
 	IP Address              : 10.245.137.136
 	*/
 
-	strOut, _, err := i.e.RunCmd(LanPrintCmd,
+	strOut, _, err := i.e.RunCmdContext(ctx, LanPrintCmd,
 		command.UseMetrics(true),
 		command.CmdName(LanPrintCmd))
 	if err != nil {
@@ -18,6 +18,7 @@ limitations under the License.
 package ipmi
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -26,22 +27,24 @@ import (
 	"github.com/dell/csi-baremetal/pkg/mocks"
 )
 
+var testCtx = context.Background()
+
 func TestIPMI_GetBmcIP(t *testing.T) {
 	e := &mocks.GoMockExecutor{}
 	l := NewIPMI(e)
 
 	strOut := "IP Address Source       : DHCP Address \n IP Address              : 10.245.137.136"
 	e.On(mocks.RunCmd, LanPrintCmd).Return(strOut, "", nil).Times(1)
-	ip := l.GetBmcIP()
+	ip := l.GetBmcIP(testCtx)
 	assert.Equal(t, "10.245.137.136", ip)
 
 	strOut = "IP Address Source       : DHCP Address \n"
 	e.On(mocks.RunCmd, LanPrintCmd).Return(strOut, "", nil).Times(1)
-	ip = l.GetBmcIP()
+	ip = l.GetBmcIP(testCtx)
 	assert.Equal(t, "", ip)
 
 	expectedError := errors.New("ipmitool failed")
 	e.On(mocks.RunCmd, LanPrintCmd).Return("", "", expectedError).Times(1)
-	ip = l.GetBmcIP()
+	ip = l.GetBmcIP(testCtx)
 	assert.Equal(t, "", ip)
 }
@@ -18,6 +18,7 @@ limitations under the License.
 package lsblk
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -41,8 +42,8 @@ const (
 
 // WrapLsblk is an interface that encapsulates operation with system lsblk util
 type WrapLsblk interface {
-	GetBlockDevices(device string) ([]BlockDevice, error)
-	SearchDrivePath(drive *drivecrd.Drive) (string, error)
+	GetBlockDevices(ctx context.Context, device string) ([]BlockDevice, error)
+	SearchDrivePath(ctx context.Context, drive *drivecrd.Drive) (string, error)
 }
 
 // LSBLK is a wrap for system lsblk util
@@ -128,9 +129,9 @@ type BlockDevice struct {
 // GetBlockDevices run os lsblk command for device and construct BlockDevice struct based on output
 // Receives device path. If device is empty string, info about all devices will be collected
 // Returns slice of BlockDevice structs or error if something went wrong
-func (l *LSBLK) GetBlockDevices(device string) ([]BlockDevice, error) {
+func (l *LSBLK) GetBlockDevices(ctx context.Context, device string) ([]BlockDevice, error) {
 	cmd := fmt.Sprintf(CmdTmpl, device)
-	strOut, _, err := l.e.RunCmd(cmd,
+	strOut, _, err := l.e.RunCmdContext(ctx, cmd,
 		command.UseMetrics(true),
 		command.CmdName(strings.TrimSpace(fmt.Sprintf(CmdTmpl, ""))))
 	if err != nil {
@@ -160,7 +161,7 @@ func (l *LSBLK) GetBlockDevices(device string) ([]BlockDevice, error) {
 // SearchDrivePath if not defined returns drive path based on drive S/N, VID and PID.
 // Receives an instance of drivecrd.Drive struct
 // Returns drive's path based on provided drivecrd.Drive or error if something went wrong
-func (l *LSBLK) SearchDrivePath(drive *drivecrd.Drive) (string, error) {
+func (l *LSBLK) SearchDrivePath(ctx context.Context, drive *drivecrd.Drive) (string, error) {
 	// device path might be already set by hwmgr
 	device := drive.Spec.Path
 	if device != "" {
@@ -168,7 +169,7 @@ func (l *LSBLK) SearchDrivePath(drive *drivecrd.Drive) (string, error) {
 	}
 
 	// try to find it with lsblk
-	lsblkOut, err := l.GetBlockDevices("")
+	lsblkOut, err := l.GetBlockDevices(ctx, "")
 	if err != nil {
 		return "", err
 	}
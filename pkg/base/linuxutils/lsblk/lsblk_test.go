@@ -17,6 +17,7 @@ limitations under the License.
 package lsblk
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"testing"
@@ -30,6 +31,7 @@ import (
 )
 
 var (
+	testCtx       = context.Background()
 	testLogger    = logrus.New()
 	allDevicesCmd = fmt.Sprintf(CmdTmpl, "")
 
@@ -49,7 +51,7 @@ func TestLSBLK_GetBlockDevices_Success(t *testing.T) {
 	l.e = e
 	e.On("RunCmd", allDevicesCmd).Return(mocks.LsblkTwoDevicesStr, "", nil)
 
-	out, err := l.GetBlockDevices("")
+	out, err := l.GetBlockDevices(testCtx, "")
 	assert.Nil(t, err)
 	assert.NotNil(t, out)
 	assert.Equal(t, 2, len(out))
@@ -61,20 +63,20 @@ func TestLSBLK_GetBlockDevices_Fail(t *testing.T) {
 	l := NewLSBLK(testLogger)
 	l.e = e
 	e.On(mocks.RunCmd, allDevicesCmd).Return("not a json", "", nil).Times(1)
-	out, err := l.GetBlockDevices("")
+	out, err := l.GetBlockDevices(testCtx, "")
 	assert.Nil(t, out)
 	assert.NotNil(t, err)
 	assert.Contains(t, err.Error(), "unable to unmarshal output to BlockDevice instance")
 
 	expectedError := errors.New("lsblk failed")
 	e.On(mocks.RunCmd, allDevicesCmd).Return("", "", expectedError).Times(1)
-	out, err = l.GetBlockDevices("")
+	out, err = l.GetBlockDevices(testCtx, "")
 	assert.Nil(t, out)
 	assert.NotNil(t, err)
 	assert.Equal(t, expectedError, err)
 
 	e.On(mocks.RunCmd, allDevicesCmd).Return(mocks.NoLsblkKeyStr, "", nil).Times(1)
-	out, err = l.GetBlockDevices("")
+	out, err = l.GetBlockDevices(testCtx, "")
 	assert.Nil(t, out)
 	assert.NotNil(t, err)
 	assert.Contains(t, err.Error(), "unexpected lsblk output format")
@@ -89,7 +91,7 @@ func TestLSBLK_SearchDrivePath_Success(t *testing.T) {
 	path := "/dev/sda"
 	dCR.Spec.Path = path
 
-	res, err := l.SearchDrivePath(&dCR)
+	res, err := l.SearchDrivePath(testCtx, &dCR)
 	assert.Nil(t, err)
 	assert.Equal(t, path, res)
 
@@ -100,7 +102,7 @@ func TestLSBLK_SearchDrivePath_Success(t *testing.T) {
 	d2CR := testDriveCR
 	d2CR.Spec.SerialNumber = sn
 
-	res, err = l.SearchDrivePath(&d2CR)
+	res, err = l.SearchDrivePath(testCtx, &d2CR)
 	assert.Nil(t, err)
 	assert.Equal(t, expectedDevice, res)
 }
@@ -112,7 +114,7 @@ func TestLSBLK_SearchDrivePath(t *testing.T) {
 	// lsblk fail
 	expectedErr := errors.New("lsblk error")
 	e.On("RunCmd", allDevicesCmd).Return("", "", expectedErr)
-	res, err := l.SearchDrivePath(&testDriveCR)
+	res, err := l.SearchDrivePath(testCtx, &testDriveCR)
 	assert.Equal(t, "", res)
 	assert.Equal(t, expectedErr, err)
 
@@ -122,7 +124,7 @@ func TestLSBLK_SearchDrivePath(t *testing.T) {
 	dCR := testDriveCR
 	dCR.Spec.SerialNumber = sn
 
-	res, err = l.SearchDrivePath(&dCR)
+	res, err = l.SearchDrivePath(testCtx, &dCR)
 	assert.Equal(t, "", res)
 	assert.NotNil(t, err)
 
@@ -133,7 +135,7 @@ func TestLSBLK_SearchDrivePath(t *testing.T) {
 	dCR.Spec.VID = "vendor"
 	dCR.Spec.PID = "pid"
 
-	res, err = l.SearchDrivePath(&dCR)
+	res, err = l.SearchDrivePath(testCtx, &dCR)
 	assert.NotNil(t, err)
 }
 
@@ -143,7 +145,7 @@ func TestLSBLK_GetBlockDevicesV2_Success(t *testing.T) {
 	e.On("RunCmd", allDevicesCmd).Return(mocks.LsblkDevV2, "", nil)
 	l.e = e
 
-	out, err := l.GetBlockDevices("")
+	out, err := l.GetBlockDevices(testCtx, "")
 	assert.Nil(t, err)
 	assert.NotNil(t, out)
 	assert.Equal(t, 1, len(out))
@@ -155,7 +157,7 @@ func TestLSBLK_GetAllV2_Success(t *testing.T) {
 	e.On("RunCmd", allDevicesCmd).Return(mocks.LsblkAllV2, "", nil)
 	l.e = e
 
-	out, err := l.GetBlockDevices("")
+	out, err := l.GetBlockDevices(testCtx, "")
 	assert.Nil(t, err)
 	assert.NotNil(t, out)
 	assert.Equal(t, 2, len(out))
@@ -17,6 +17,7 @@ limitations under the License.
 package lsscsi
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"regexp"
@@ -41,7 +42,7 @@ const (
 
 // WrapLsscsi is an interface that encapsulates operation with system lsscsi util
 type WrapLsscsi interface {
-	GetSCSIDevices() ([]*SCSIDevice, error)
+	GetSCSIDevices(ctx context.Context) ([]*SCSIDevice, error)
 }
 
 // LSSCSI is a wrap for system lsscsi util
@@ -66,17 +67,17 @@ func NewLSSCSI(e command.CmdExecutor, logger *logrus.Logger) *LSSCSI {
 }
 
 // GetSCSIDevices gets information about SCSIDevice using lsscsi util
-func (la *LSSCSI) GetSCSIDevices() ([]*SCSIDevice, error) {
+func (la *LSSCSI) GetSCSIDevices(ctx context.Context) ([]*SCSIDevice, error) {
 	ll := la.log.WithField("method", "GetSCSIDevices")
-	devices, err := la.getSCSIDevicesBasicInfo()
+	devices, err := la.getSCSIDevicesBasicInfo(ctx)
 	if err != nil {
 		return nil, err
 	}
 	for _, device := range devices {
-		if err := la.fillDeviceSize(device); err != nil {
+		if err := la.fillDeviceSize(ctx, device); err != nil {
 			ll.Errorf("lsscsi failed %v", err)
 		}
-		if err := la.fillDeviceInfo(device); err != nil {
+		if err := la.fillDeviceInfo(ctx, device); err != nil {
 			ll.Errorf("lsscsi failed %v", err)
 		}
 	}
@@ -88,7 +89,7 @@ func (la *LSSCSI) GetSCSIDevices() ([]*SCSIDevice, error) {
 // The output is easy to parse, because we know, that the Path and Id are on the last and the first positions in the output
 // This command doesn't provide information about size.
 // To facilitates the parsing of the output we use separate command lsscsi --no-nvme --brief --size to get information about size
-func (la *LSSCSI) getSCSIDevicesBasicInfo() ([]*SCSIDevice, error) {
+func (la *LSSCSI) getSCSIDevicesBasicInfo(ctx context.Context) ([]*SCSIDevice, error) {
 	//	/*Example output
 	//	[0:0:0:0]    disk    VMware   Virtual disk     2.0   /dev/sda
 	//	[0:0:1:0]    disk    VMware   Virtual disk     2.0   /dev/sdb
@@ -96,7 +97,7 @@ func (la *LSSCSI) getSCSIDevicesBasicInfo() ([]*SCSIDevice, error) {
 	//	*/
 	ll := la.log.WithField("method", "getSCSIDevicesBasicInfo")
 	var devices []*SCSIDevice
-	strOut, _, err := la.e.RunCmd(LsscsiCmdImpl)
+	strOut, _, err := la.e.RunCmdContext(ctx, LsscsiCmdImpl)
 	if err != nil {
 		return nil, errors.New("unable to get devices basic info")
 	}
@@ -116,11 +117,11 @@ func (la *LSSCSI) getSCSIDevicesBasicInfo() ([]*SCSIDevice, error) {
 
 // fillDeviceSize fill information about device size
 // lsscsi --no-nvme --brief --size is easy to parse because size on the last position.
-func (la *LSSCSI) fillDeviceSize(device *SCSIDevice) error {
+func (la *LSSCSI) fillDeviceSize(ctx context.Context, device *SCSIDevice) error {
 	/*
 	 [2:0:0:0]    /dev/sda   32.3GB
 	*/
-	strOut, _, err := la.e.RunCmd(fmt.Sprintf(SCSIDeviceSizeCmdImpl, device.ID),
+	strOut, _, err := la.e.RunCmdContext(ctx, fmt.Sprintf(SCSIDeviceSizeCmdImpl, device.ID),
 		command.UseMetrics(true),
 		command.CmdName(strings.TrimSpace(fmt.Sprintf(SCSIDeviceSizeCmdImpl, ""))))
 	if err != nil {
@@ -138,14 +139,14 @@ func (la *LSSCSI) fillDeviceSize(device *SCSIDevice) error {
 }
 
 // fillDeviceInfo returns information about device model, vendor and firmware
-func (la *LSSCSI) fillDeviceInfo(device *SCSIDevice) error {
+func (la *LSSCSI) fillDeviceInfo(ctx context.Context, device *SCSIDevice) error {
 	/*
 		Attached devices:
 		Host: scsi0 Channel: 00 Target: 00 Lun: 00
 		  Vendor: VMware   Model: Virtual disk     Rev: 2.0
 		  Type:   Direct-Access                    ANSI SCSI revision: 06
 	*/
-	strOut, _, err := la.e.RunCmd(fmt.Sprintf(SCSIDeviceCmdImpl, device.ID),
+	strOut, _, err := la.e.RunCmdContext(ctx, fmt.Sprintf(SCSIDeviceCmdImpl, device.ID),
 		command.UseMetrics(true),
 		command.CmdName(strings.TrimSpace(fmt.Sprintf(SCSIDeviceCmdImpl, ""))))
 	if err != nil {
@@ -17,6 +17,7 @@ limitations under the License.
 package lsscsi
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -25,6 +26,8 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
+var testCtx = context.Background()
+
 var testLogger = logrus.New()
 
 func TestLSSCSI_getSCSIDevicesBasicInfoSuccess(t *testing.T) {
@@ -36,7 +39,7 @@ func TestLSSCSI_getSCSIDevicesBasicInfoSuccess(t *testing.T) {
 		[0:0:2:0]    cd/dvd   VMware   Virtual disk     2.0   /dev/sdc`
 	e.On("RunCmd", LsscsiCmdImpl).Return(output, "", nil)
 
-	devs, err := l.getSCSIDevicesBasicInfo()
+	devs, err := l.getSCSIDevicesBasicInfo(testCtx)
 	assert.Nil(t, err)
 	assert.Equal(t, 2, len(devs))
 
@@ -53,7 +56,7 @@ func TestLSSCSI_getSCSIDevicesBasicInfoFail(t *testing.T) {
 
 	e.On("RunCmd", LsscsiCmdImpl).Return("", "", fmt.Errorf("error"))
 
-	_, err := l.getSCSIDevicesBasicInfo()
+	_, err := l.getSCSIDevicesBasicInfo(testCtx)
 	assert.NotNil(t, err)
 }
 
@@ -67,7 +70,7 @@ func TestLSSCSI_getSCSIDeviceSize(t *testing.T) {
 
 	devs := &SCSIDevice{ID: "[2:0:0:0]"}
 
-	err := l.fillDeviceSize(devs)
+	err := l.fillDeviceSize(testCtx, devs)
 	assert.Nil(t, err)
 	assert.Equal(t, int64(34681860915), devs.Size)
 }
@@ -82,7 +85,7 @@ func TestLSSCSI_getSCSIDeviceSizeWrongSizeFormat(t *testing.T) {
 
 	devs := &SCSIDevice{ID: "[2:0:0:0]"}
 
-	err := l.fillDeviceSize(devs)
+	err := l.fillDeviceSize(testCtx, devs)
 	assert.NotNil(t, err)
 }
 
@@ -95,7 +98,7 @@ func TestLSSCSI_getSCSIDeviceSizeFail(t *testing.T) {
 
 	devs := &SCSIDevice{ID: "[2:0:0:0]"}
 
-	err := l.fillDeviceSize(devs)
+	err := l.fillDeviceSize(testCtx, devs)
 	assert.NotNil(t, err)
 }
 
@@ -113,7 +116,7 @@ func TestLSSCSI_getSCSIDeviceInfo(t *testing.T) {
 
 	e.On("RunCmd", cmd).Return(output, "", nil)
 
-	err := l.fillDeviceInfo(devs)
+	err := l.fillDeviceInfo(testCtx, devs)
 
 	assert.Nil(t, err)
 	assert.Equal(t, "VMware vendor", devs.Vendor)
@@ -130,7 +133,7 @@ func TestLSSCSI_getSCSIDeviceInfoFail(t *testing.T) {
 
 	e.On("RunCmd", cmd).Return("", "", fmt.Errorf("error"))
 
-	err := l.fillDeviceInfo(devs)
+	err := l.fillDeviceInfo(testCtx, devs)
 
 	assert.NotNil(t, err)
 }
@@ -141,7 +144,7 @@ func TestLSSCSI_GetSCSIDevicesFail(t *testing.T) {
 
 	e.On("RunCmd", LsscsiCmdImpl).Return("", "", fmt.Errorf("error"))
 
-	_, err := l.GetSCSIDevices()
+	_, err := l.GetSCSIDevices(testCtx)
 	assert.NotNil(t, err)
 }
 
@@ -167,7 +170,7 @@ func TestLSSCSI_GetSCSIDevicesSizeCMDFail(t *testing.T) {
 	cmd = fmt.Sprintf(SCSIDeviceCmdImpl, "[0:0:1:0]")
 	e.On("RunCmd", cmd).Return("", "", fmt.Errorf("error"))
 
-	devs, err := l.GetSCSIDevices()
+	devs, err := l.GetSCSIDevices(testCtx)
 	assert.Nil(t, err)
 	assert.Equal(t, 2, len(devs))
 }
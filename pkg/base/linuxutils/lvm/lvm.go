@@ -19,6 +19,7 @@ limitations under the License.
 package lvm
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"strconv"
@@ -62,25 +63,47 @@ const (
 	PVInfoCmdTmpl = lvmPath + "pvdisplay %s --colon" // add PV name
 	// LVExpandCmdTmpl expand LV
 	LVExpandCmdTmpl = lvmPath + "lvextend --size %sb --resizefs %s" // add full LV name
+	// VGExtendCmdTmpl add PVs to VG cmd
+	VGExtendCmdTmpl = lvmPath + "vgextend --yes %s %s" // add VG name and PV names
+	// VGReduceCmdTmpl remove PVs from VG cmd
+	VGReduceCmdTmpl = lvmPath + "vgreduce --yes %s %s" // add VG name and PV names
+	// PVMoveCmdTmpl start moving of allocated extents from PV in background cmd
+	PVMoveCmdTmpl = lvmPath + "pvmove --background %s %s" // add source PV name and destination PV names (optional)
+	// PVMoveAbortCmd abort all pvmove operations, extents which were already moved stay on destination PVs
+	PVMoveAbortCmd = lvmPath + "pvmove --abort"
+	// PVMoveProgressCmdTmpl print LVs in VG including hidden pvmove LVs with copy progress
+	PVMoveProgressCmdTmpl = lvmPath + "lvs --all --options lv_name,copy_percent --noheadings %s" // add VG name
+	// PVUsageCmdTmpl print used and free space of PV cmd
+	PVUsageCmdTmpl = lvmPath + "pvs %s --options pv_used,pv_free --units b --noheadings" // add PV name
+	// pvMoveLVPrefix is a prefix of temporary LV which is created by pvmove
+	pvMoveLVPrefix = "[pvmove"
 	// timeoutBetweenAttempts used for RunCmdWithAttempts as a timeout between calling lvremove
 	timeoutBetweenAttempts = 500 * time.Millisecond
+	// lvExpandTimeout is a timeout of lvextend with resize of file system
+	lvExpandTimeout = 30 * time.Minute
 )
 
 // WrapLVM is an interface that encapsulates operation with system logical volume manager (/sbin/lvm)
 type WrapLVM interface {
-	PVCreate(dev string) error
-	PVRemove(name string) error
-	VGCreate(name string, pvs ...string) error
-	VGRemove(name string) error
-	LVCreate(name, size, vgName string) error
-	LVRemove(fullLVName string) error
-	IsVGContainsLVs(vgName string) bool
-	RemoveOrphanPVs() error
-	GetVgFreeSpace(vgName string) (int64, error)
-	GetAllPVs() ([]string, error)
-	GetLVsInVG(vgName string) ([]string, error)
-	GetVGNameByPVName(pvName string) (string, error)
-	ExpandLV(lvName string, requiredSize int64) error
+	PVCreate(ctx context.Context, dev string) error
+	PVRemove(ctx context.Context, name string) error
+	VGCreate(ctx context.Context, name string, pvs ...string) error
+	VGRemove(ctx context.Context, name string) error
+	LVCreate(ctx context.Context, name, size, vgName string) error
+	LVRemove(ctx context.Context, fullLVName string) error
+	IsVGContainsLVs(ctx context.Context, vgName string) bool
+	RemoveOrphanPVs(ctx context.Context) error
+	GetVgFreeSpace(ctx context.Context, vgName string) (int64, error)
+	GetAllPVs(ctx context.Context) ([]string, error)
+	GetLVsInVG(ctx context.Context, vgName string) ([]string, error)
+	GetVGNameByPVName(ctx context.Context, pvName string) (string, error)
+	ExpandLV(ctx context.Context, lvName string, requiredSize int64) error
+	VGExtend(ctx context.Context, name string, pvs ...string) error
+	VGReduce(ctx context.Context, name string, pvs ...string) error
+	PVMove(ctx context.Context, src string, dst ...string) error
+	PVMoveAbort(ctx context.Context) error
+	GetPVMoveProgress(ctx context.Context, vgName string) (float64, bool, error)
+	GetPVUsage(ctx context.Context, pvName string) (int64, int64, error)
 }
 
 // LVM is an implementation of WrapLVM interface and is a wrap for system /sbin/lvm util in
@@ -100,22 +123,24 @@ func NewLVM(e command.CmdExecutor, l *logrus.Logger) *LVM {
 // PVCreate creates physical volume based on provided device or partition
 // Receives device path
 // Returns error if something went wrong
-func (l *LVM) PVCreate(dev string) error {
+func (l *LVM) PVCreate(ctx context.Context, dev string) error {
 	cmd := fmt.Sprintf(PVCreateCmdTmpl, dev)
-	_, _, err := l.e.RunCmd(cmd,
+	_, _, err := l.e.RunCmdContext(ctx, cmd,
 		command.UseMetrics(true),
-		command.CmdName(strings.TrimSpace(fmt.Sprintf(PVCreateCmdTmpl, ""))))
+		command.CmdName(strings.TrimSpace(fmt.Sprintf(PVCreateCmdTmpl, ""))),
+		command.Destructive(true))
 	return err
 }
 
 // PVRemove removes physical volumes, ignore error if PV doesn't exist
 // Receives name of a physical volume to delete
 // Returns error if something went wrong
-func (l *LVM) PVRemove(name string) error {
+func (l *LVM) PVRemove(ctx context.Context, name string) error {
 	cmd := fmt.Sprintf(PVRemoveCmdTmpl, name)
-	_, stdErr, err := l.e.RunCmd(cmd,
+	_, stdErr, err := l.e.RunCmdContext(ctx, cmd,
 		command.UseMetrics(true),
-		command.CmdName(strings.TrimSpace(fmt.Sprintf(PVRemoveCmdTmpl, ""))))
+		command.CmdName(strings.TrimSpace(fmt.Sprintf(PVRemoveCmdTmpl, ""))),
+		command.Destructive(true))
 	if err != nil && strings.Contains(stdErr, "No PV label found") {
 		return nil
 	}
@@ -125,11 +150,13 @@ func (l *LVM) PVRemove(name string) error {
 // ExpandLV expand logical volume
 // Receives full name of a logical volume and requiredSize to resize
 // Returns error if something went wrong
-func (l *LVM) ExpandLV(lvName string, requiredSize int64) error {
+func (l *LVM) ExpandLV(ctx context.Context, lvName string, requiredSize int64) error {
 	cmd := fmt.Sprintf(LVExpandCmdTmpl, strconv.FormatInt(requiredSize, 10), lvName)
-	_, _, err := l.e.RunCmd(cmd,
+	// file system is resized too, it takes longer than other LVM commands
+	_, _, err := l.e.RunCmdContext(ctx, cmd,
 		command.UseMetrics(true),
-		command.CmdName(strings.TrimSpace(fmt.Sprintf(LVExpandCmdTmpl, "", ""))))
+		command.CmdName(strings.TrimSpace(fmt.Sprintf(LVExpandCmdTmpl, "", ""))),
+		command.Timeout(lvExpandTimeout))
 	if err != nil {
 		return err
 	}
@@ -139,9 +166,9 @@ func (l *LVM) ExpandLV(lvName string, requiredSize int64) error {
 // VGCreate creates volume group and based on provided physical volumes (pvs). Ignore error if VG already exists
 // Receives name of VG to create and names of physical volumes which VG should based on
 // Returns error if something went wrong
-func (l *LVM) VGCreate(name string, pvs ...string) error {
+func (l *LVM) VGCreate(ctx context.Context, name string, pvs ...string) error {
 	cmd := fmt.Sprintf(VGCreateCmdTmpl, name, strings.Join(pvs, " "))
-	_, stdErr, err := l.e.RunCmd(cmd,
+	_, stdErr, err := l.e.RunCmdContext(ctx, cmd,
 		command.UseMetrics(true),
 		command.CmdName(strings.TrimSpace(fmt.Sprintf(VGCreateCmdTmpl, "", ""))))
 	if err != nil && strings.Contains(stdErr, "already exists") {
@@ -153,11 +180,12 @@ func (l *LVM) VGCreate(name string, pvs ...string) error {
 // VGRemove removes volume group, ignore error if VG doesn't exist
 // Receives name of VG to remove
 // Returns error if something went wrong
-func (l *LVM) VGRemove(name string) error {
+func (l *LVM) VGRemove(ctx context.Context, name string) error {
 	cmd := fmt.Sprintf(VGRemoveCmdTmpl, name)
-	_, stdErr, err := l.e.RunCmd(cmd,
+	_, stdErr, err := l.e.RunCmdContext(ctx, cmd,
 		command.UseMetrics(true),
-		command.CmdName(strings.TrimSpace(fmt.Sprintf(VGRemoveCmdTmpl, ""))))
+		command.CmdName(strings.TrimSpace(fmt.Sprintf(VGRemoveCmdTmpl, ""))),
+		command.Destructive(true))
 	if strings.Contains(stdErr, "not found") {
 		return nil
 	}
@@ -167,9 +195,9 @@ func (l *LVM) VGRemove(name string) error {
 // LVCreate created logical volume in volume group, ignore error if LV already exists
 // Receives name of created LV, size which is a string like 1.2G, 100M and name of VG which LV should be based on
 // Returns error if something went wrong
-func (l *LVM) LVCreate(name, size, vgName string) error {
+func (l *LVM) LVCreate(ctx context.Context, name, size, vgName string) error {
 	cmd := fmt.Sprintf(LVCreateCmdTmpl, name, size, vgName)
-	_, stdErr, err := l.e.RunCmd(cmd,
+	_, stdErr, err := l.e.RunCmdContext(ctx, cmd,
 		command.UseMetrics(true),
 		command.CmdName(strings.TrimSpace(fmt.Sprintf(LVCreateCmdTmpl, "", "", ""))))
 	if err != nil && strings.Contains(stdErr, "already exists") {
@@ -181,10 +209,11 @@ func (l *LVM) LVCreate(name, size, vgName string) error {
 // LVRemove removes logical volume, ignore error if LV doesn't exist
 // Receives fullLVName that is a path to LV
 // Returns error if something went wrong
-func (l *LVM) LVRemove(fullLVName string) error {
+func (l *LVM) LVRemove(ctx context.Context, fullLVName string) error {
 	cmd := fmt.Sprintf(LVRemoveCmdTmpl, fullLVName)
-	_, stdErr, err := l.e.RunCmdWithAttempts(cmd, 5, timeoutBetweenAttempts, command.UseMetrics(true),
-		command.CmdName(strings.TrimSpace(fmt.Sprintf(LVRemoveCmdTmpl, ""))))
+	_, stdErr, err := l.e.RunCmdWithAttemptsContext(ctx, cmd, 5, timeoutBetweenAttempts, command.UseMetrics(true),
+		command.CmdName(strings.TrimSpace(fmt.Sprintf(LVRemoveCmdTmpl, ""))),
+		command.Destructive(true))
 	if err != nil && strings.Contains(stdErr, "Failed to find logical volume") {
 		return nil
 	}
@@ -194,9 +223,9 @@ func (l *LVM) LVRemove(fullLVName string) error {
 // IsVGContainsLVs checks whether VG vgName contains any LVs or no
 // Receives Volume Group name to check
 // Returns true in case of error to prevent mistaken VG remove
-func (l *LVM) IsVGContainsLVs(vgName string) bool {
+func (l *LVM) IsVGContainsLVs(ctx context.Context, vgName string) bool {
 	cmd := fmt.Sprintf(LVsInVGCmdTmpl, vgName)
-	stdout, _, err := l.e.RunCmd(cmd,
+	stdout, _, err := l.e.RunCmdContext(ctx, cmd,
 		command.UseMetrics(true),
 		command.CmdName(strings.TrimSpace(fmt.Sprintf(LVsInVGCmdTmpl, ""))))
 	if err != nil {
@@ -211,9 +240,9 @@ func (l *LVM) IsVGContainsLVs(vgName string) bool {
 // GetLVsInVG collects LVs for given volume group
 // Receives Volume Group name
 // Returns slice of found logical volumes
-func (l *LVM) GetLVsInVG(vgName string) ([]string, error) {
+func (l *LVM) GetLVsInVG(ctx context.Context, vgName string) ([]string, error) {
 	cmd := fmt.Sprintf(LVsInVGCmdTmpl, vgName)
-	stdout, _, err := l.e.RunCmd(cmd,
+	stdout, _, err := l.e.RunCmdContext(ctx, cmd,
 		command.UseMetrics(true),
 		command.CmdName(strings.TrimSpace(fmt.Sprintf(LVsInVGCmdTmpl, ""))))
 	if err != nil {
@@ -225,9 +254,9 @@ func (l *LVM) GetLVsInVG(vgName string) ([]string, error) {
 
 // RemoveOrphanPVs removes PVs that do not have VG
 // Returns error if something went wrong
-func (l *LVM) RemoveOrphanPVs() error {
+func (l *LVM) RemoveOrphanPVs(ctx context.Context) error {
 	pvsCmd := fmt.Sprintf(PVsInVGCmdTmpl, EmptyName)
-	stdout, _, err := l.e.RunCmd(pvsCmd,
+	stdout, _, err := l.e.RunCmdContext(ctx, pvsCmd,
 		command.UseMetrics(true),
 		command.CmdName(strings.TrimSpace(fmt.Sprintf(PVsInVGCmdTmpl, ""))))
 	if err != nil {
@@ -238,7 +267,7 @@ func (l *LVM) RemoveOrphanPVs() error {
 		if len(pv) == 0 {
 			continue
 		}
-		if err := l.PVRemove(pv); err != nil {
+		if err := l.PVRemove(ctx, pv); err != nil {
 			l.log.WithField("method", "RemoveOrphanPVs").Errorf("Unable to remove pv %s: %v", pv, err)
 			wasError = true
 		}
@@ -252,7 +281,7 @@ func (l *LVM) RemoveOrphanPVs() error {
 // GetVgFreeSpace returns VG free space in bytes
 // Receives VG name to count ints free space
 // Returns -1 in case of error and error
-func (l *LVM) GetVgFreeSpace(vgName string) (int64, error) {
+func (l *LVM) GetVgFreeSpace(ctx context.Context, vgName string) (int64, error) {
 	/*
 		Example of output:
 		root@provo-goop:~# vgs --options vg_free unassigned-hostname-vg --units b --nosuffix --noheadings
@@ -264,7 +293,7 @@ func (l *LVM) GetVgFreeSpace(vgName string) (int64, error) {
 	}
 
 	cmd := fmt.Sprintf(VGFreeSpaceCmdTmpl, vgName)
-	strOut, _, err := l.e.RunCmd(cmd,
+	strOut, _, err := l.e.RunCmdContext(ctx, cmd,
 		command.UseMetrics(true),
 		command.CmdName(strings.TrimSpace(fmt.Sprintf(VGFreeSpaceCmdTmpl, ""))))
 	if err != nil {
@@ -280,8 +309,8 @@ func (l *LVM) GetVgFreeSpace(vgName string) (int64, error) {
 }
 
 // GetAllPVs returns slice with names of all physical volumes in the system
-func (l *LVM) GetAllPVs() ([]string, error) {
-	stdOut, _, err := l.e.RunCmd(AllPVsCmd,
+func (l *LVM) GetAllPVs(ctx context.Context) ([]string, error) {
+	stdOut, _, err := l.e.RunCmdContext(ctx, AllPVsCmd,
 		command.UseMetrics(true),
 		command.CmdName(AllPVsCmd))
 	if err != nil {
@@ -292,10 +321,10 @@ func (l *LVM) GetAllPVs() ([]string, error) {
 }
 
 // GetVGNameByPVName finds out volume group name based on physical volume name
-func (l *LVM) GetVGNameByPVName(pvName string) (string, error) {
+func (l *LVM) GetVGNameByPVName(ctx context.Context, pvName string) (string, error) {
 	cmd := fmt.Sprintf(PVInfoCmdTmpl, pvName)
 
-	stdOut, _, err := l.e.RunCmd(cmd,
+	stdOut, _, err := l.e.RunCmdContext(ctx, cmd,
 		command.UseMetrics(true),
 		command.CmdName(strings.TrimSpace(fmt.Sprintf(PVInfoCmdTmpl, ""))))
 	if err != nil {
@@ -322,3 +351,117 @@ func (l *LVM) GetVGNameByPVName(pvName string) (string, error) {
 
 	return splitted[1], nil
 }
+
+// VGExtend adds physical volumes to volume group, ignore error if PVs are already in VG
+// Receives name of VG and names of physical volumes to add
+// Returns error if something went wrong
+func (l *LVM) VGExtend(ctx context.Context, name string, pvs ...string) error {
+	cmd := fmt.Sprintf(VGExtendCmdTmpl, name, strings.Join(pvs, " "))
+	_, stdErr, err := l.e.RunCmdContext(ctx, cmd,
+		command.UseMetrics(true),
+		command.CmdName(strings.TrimSpace(fmt.Sprintf(VGExtendCmdTmpl, "", ""))))
+	if err != nil && strings.Contains(stdErr, "is already in volume group") {
+		return nil
+	}
+	return err
+}
+
+// VGReduce removes physical volumes from volume group, ignore error if PVs aren't in VG
+// Receives name of VG and names of physical volumes to remove, PVs mustn't have allocated extents
+// Returns error if something went wrong
+func (l *LVM) VGReduce(ctx context.Context, name string, pvs ...string) error {
+	cmd := fmt.Sprintf(VGReduceCmdTmpl, name, strings.Join(pvs, " "))
+	_, stdErr, err := l.e.RunCmdContext(ctx, cmd,
+		command.UseMetrics(true),
+		command.CmdName(strings.TrimSpace(fmt.Sprintf(VGReduceCmdTmpl, "", ""))))
+	if err != nil && strings.Contains(stdErr, "not found in volume group") {
+		return nil
+	}
+	return err
+}
+
+// PVMove starts moving of allocated extents from src physical volume in background.
+// Extents are moved to dst physical volumes or to any free extents of VG if dst is empty.
+// Interrupted move is resumed if PVMove is called again for the same src
+// Returns error if something went wrong
+func (l *LVM) PVMove(ctx context.Context, src string, dst ...string) error {
+	cmd := fmt.Sprintf(PVMoveCmdTmpl, src, strings.Join(dst, " "))
+	_, _, err := l.e.RunCmdContext(ctx, cmd,
+		command.UseMetrics(true),
+		command.CmdName(strings.TrimSpace(fmt.Sprintf(PVMoveCmdTmpl, "", ""))))
+	return err
+}
+
+// PVMoveAbort aborts pvmove operations in progress
+// Returns error if something went wrong
+func (l *LVM) PVMoveAbort(ctx context.Context) error {
+	_, _, err := l.e.RunCmdContext(ctx, PVMoveAbortCmd, command.UseMetrics(true))
+	return err
+}
+
+// GetPVMoveProgress returns progress of pvmove in volume group
+// Receives VG name
+// Returns copy percent, whether pvmove is in progress and error
+func (l *LVM) GetPVMoveProgress(ctx context.Context, vgName string) (float64, bool, error) {
+	/*
+		Example of output:
+		root@provo-goop:~# lvs --all --options lv_name,copy_percent --noheadings lvg-1
+			  pvc-e9a58e24
+			  [pvmove0]     42.17
+	*/
+	cmd := fmt.Sprintf(PVMoveProgressCmdTmpl, vgName)
+	stdout, _, err := l.e.RunCmdContext(ctx, cmd,
+		command.UseMetrics(true),
+		command.CmdName(strings.TrimSpace(fmt.Sprintf(PVMoveProgressCmdTmpl, ""))))
+	if err != nil {
+		return 0, false, err
+	}
+
+	for _, line := range util.SplitAndTrimSpace(stdout, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || !strings.HasPrefix(fields[0], pvMoveLVPrefix) {
+			continue
+		}
+		if len(fields) < 2 {
+			return 0, true, nil
+		}
+		percent, err := strconv.ParseFloat(fields[1], 64)
+		if err != nil {
+			return 0, true, fmt.Errorf("unable to parse pvmove progress %s: %v", fields[1], err)
+		}
+		return percent, true, nil
+	}
+	return 0, false, nil
+}
+
+// GetPVUsage returns used and free space of physical volume in bytes
+// Receives PV name
+// Returns used space, free space and error
+func (l *LVM) GetPVUsage(ctx context.Context, pvName string) (int64, int64, error) {
+	/*
+		Example of output:
+		root@provo-goop:~# pvs /dev/sdb --options pv_used,pv_free --units b --noheadings
+			  107374182400B  892622487552B
+	*/
+	cmd := fmt.Sprintf(PVUsageCmdTmpl, pvName)
+	stdout, _, err := l.e.RunCmdContext(ctx, cmd,
+		command.UseMetrics(true),
+		command.CmdName(strings.TrimSpace(fmt.Sprintf(PVUsageCmdTmpl, ""))))
+	if err != nil {
+		return -1, -1, err
+	}
+
+	fields := strings.Fields(stdout)
+	if len(fields) != 2 {
+		return -1, -1, fmt.Errorf("unable to parse usage of PV %s from output %s", pvName, stdout)
+	}
+	used, err := util.StrToBytes(fields[0])
+	if err != nil {
+		return -1, -1, err
+	}
+	free, err := util.StrToBytes(fields[1])
+	if err != nil {
+		return -1, -1, err
+	}
+	return used, free, nil
+}
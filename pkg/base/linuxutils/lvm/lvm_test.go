@@ -17,6 +17,7 @@ limitations under the License.
 package lvm
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"strings"
@@ -29,6 +30,7 @@ import (
 )
 
 var (
+	testCtx    = context.Background()
 	testLogger = logrus.New()
 )
 
@@ -41,7 +43,7 @@ func TestLinuxUtils_PVCreate(t *testing.T) {
 		err error
 	)
 	e.OnCommand(cmd).Return("", "", nil)
-	err = l.PVCreate(dev)
+	err = l.PVCreate(testCtx, dev)
 	assert.Nil(t, err)
 }
 
@@ -56,15 +58,15 @@ func TestLinuxUtils_PVRemove(t *testing.T) {
 	)
 
 	e.OnCommand(cmd).Return("", "", nil).Times(1)
-	err = l.PVRemove(dev)
+	err = l.PVRemove(testCtx, dev)
 	assert.Nil(t, err)
 
 	e.OnCommand(cmd).Return("", "No PV label found on /dev/sda", expectedErr).Times(1)
-	err = l.PVRemove(dev)
+	err = l.PVRemove(testCtx, dev)
 	assert.Nil(t, err)
 
 	e.OnCommand(cmd).Return("", "some another error", expectedErr).Times(1)
-	err = l.PVRemove(dev)
+	err = l.PVRemove(testCtx, dev)
 	assert.NotNil(t, err)
 	assert.Equal(t, expectedErr, err)
 }
@@ -82,17 +84,17 @@ func TestLinuxUtils_VGCreate(t *testing.T) {
 	)
 
 	e.OnCommand(cmd).Return("", "", nil).Times(1)
-	err = l.VGCreate(vg, dev1, dev2)
+	err = l.VGCreate(testCtx, vg, dev1, dev2)
 	assert.Nil(t, err)
 
 	e.OnCommand(cmd).
 		Return("", "already exists", expectedErr).
 		Times(1)
-	err = l.VGCreate(vg, dev1, dev2)
+	err = l.VGCreate(testCtx, vg, dev1, dev2)
 	assert.Nil(t, err)
 
 	e.OnCommand(cmd).Return("", "", expectedErr).Times(1)
-	err = l.VGCreate(vg, dev1, dev2)
+	err = l.VGCreate(testCtx, vg, dev1, dev2)
 	assert.Equal(t, expectedErr, err)
 }
 
@@ -107,15 +109,15 @@ func TestLinuxUtils_VGRemove(t *testing.T) {
 	)
 
 	e.OnCommand(cmd).Return("", "", nil).Times(1)
-	err = l.VGRemove(vg)
+	err = l.VGRemove(testCtx, vg)
 	assert.Nil(t, err)
 
 	e.OnCommand(cmd).Return("", "not found", expectedErr).Times(1)
-	err = l.VGRemove(vg)
+	err = l.VGRemove(testCtx, vg)
 	assert.Nil(t, err)
 
 	e.OnCommand(cmd).Return("", "", expectedErr).Times(1)
-	err = l.VGRemove(vg)
+	err = l.VGRemove(testCtx, vg)
 	assert.Equal(t, expectedErr, err)
 }
 
@@ -132,15 +134,15 @@ func TestLinuxUtils_LVCreate(t *testing.T) {
 	)
 
 	e.OnCommand(cmd).Return("", "", nil).Times(1)
-	err = l.LVCreate(lv, size, vg)
+	err = l.LVCreate(testCtx, lv, size, vg)
 	assert.Nil(t, err)
 
 	e.OnCommand(cmd).Return("", "already exists", expectedErr).Times(1)
-	err = l.LVCreate(lv, size, vg)
+	err = l.LVCreate(testCtx, lv, size, vg)
 	assert.Nil(t, err)
 
 	e.OnCommand(cmd).Return("", "", expectedErr).Times(1)
-	err = l.LVCreate(lv, size, vg)
+	err = l.LVCreate(testCtx, lv, size, vg)
 	assert.Equal(t, expectedErr, err)
 }
 
@@ -157,14 +159,14 @@ func TestLinuxUtils_LVRemove(t *testing.T) {
 	)
 
 	e.OnCommandWithAttempts(cmd, 5, timeoutBetweenAttempts).Return("", "", nil).Times(1)
-	err = l.LVRemove(fullLVName)
+	err = l.LVRemove(testCtx, fullLVName)
 	assert.Nil(t, err)
 	e.OnCommandWithAttempts(cmd, 5, timeoutBetweenAttempts).Return("", "Failed to find logical volume", expectedErr).Times(1)
-	err = l.LVRemove(fullLVName)
+	err = l.LVRemove(testCtx, fullLVName)
 	assert.Nil(t, err)
 
 	e.OnCommandWithAttempts(cmd, 5, timeoutBetweenAttempts).Return("", "", expectedErr).Times(1)
-	err = l.LVRemove(fullLVName)
+	err = l.LVRemove(testCtx, fullLVName)
 	assert.Equal(t, expectedErr, err)
 }
 
@@ -179,15 +181,15 @@ func TestLinuxUtilsIs_VGContainsLVs(t *testing.T) {
 	)
 
 	e.OnCommand(cmd).Return("\n", "", nil).Times(1)
-	res = l.IsVGContainsLVs(vg)
+	res = l.IsVGContainsLVs(testCtx, vg)
 	assert.False(t, res)
 
 	e.OnCommand(cmd).Return("asdf\nadf", "", nil).Times(1)
-	res = l.IsVGContainsLVs(vg)
+	res = l.IsVGContainsLVs(testCtx, vg)
 	assert.True(t, res)
 
 	e.OnCommand(cmd).Return("", "", expectedErr).Times(1)
-	res = l.IsVGContainsLVs(vg)
+	res = l.IsVGContainsLVs(testCtx, vg)
 	assert.True(t, res)
 }
 
@@ -201,14 +203,14 @@ func TestLinuxUtils_GetLVsInVG(t *testing.T) {
 	)
 
 	e.OnCommand(cmd).Return("  asdf\n  adf", "", nil).Times(1)
-	res, err := l.GetLVsInVG(vg)
+	res, err := l.GetLVsInVG(testCtx, vg)
 	assert.Nil(t, err)
 	assert.Equal(t, len(res), 2)
 	assert.Equal(t, res[0], "asdf")
 	assert.Equal(t, res[1], "adf")
 
 	e.OnCommand(cmd).Return("", "", expectedErr).Times(1)
-	res, err = l.GetLVsInVG(vg)
+	res, err = l.GetLVsInVG(testCtx, vg)
 	assert.NotNil(t, err)
 	assert.Empty(t, res)
 }
@@ -224,23 +226,23 @@ func TestLinuxUtils_RemoveOrphanPVs(t *testing.T) {
 	)
 
 	e.OnCommand(cmd).Return("\n", "", nil).Times(1)
-	err = l.RemoveOrphanPVs()
+	err = l.RemoveOrphanPVs(testCtx)
 	assert.Nil(t, err)
 
 	e.OnCommand(cmd).Return(dev1, "", nil).Times(1)
 	e.OnCommand(fmt.Sprintf(PVRemoveCmdTmpl, dev1)).
 		Return("", "", nil).Times(1)
-	err = l.RemoveOrphanPVs()
+	err = l.RemoveOrphanPVs(testCtx)
 	assert.Nil(t, err)
 
 	e.OnCommand(cmd).Return(dev1, "", nil).Times(1)
 	e.OnCommand(fmt.Sprintf(PVRemoveCmdTmpl, dev1)).
 		Return("", "", expectedErr).Times(1)
-	err = l.RemoveOrphanPVs()
+	err = l.RemoveOrphanPVs(testCtx)
 	assert.Equal(t, errors.New("not all PVs were removed"), err)
 
 	e.OnCommand(cmd).Return(dev1, "", expectedErr).Times(1)
-	err = l.RemoveOrphanPVs()
+	err = l.RemoveOrphanPVs(testCtx)
 	assert.Equal(t, expectedErr, err)
 }
 
@@ -258,24 +260,24 @@ func TestLinuxUtils_GetVgFreeSpace(t *testing.T) {
 
 	// expected success (tabs and new line were trim)
 	e.OnCommand(cmd).Return(fmt.Sprintf("\t\t %dB \n", expectedSize), "", nil).Times(1)
-	currentSize, err = l.GetVgFreeSpace(vgName)
+	currentSize, err = l.GetVgFreeSpace(testCtx, vgName)
 	assert.Nil(t, err)
 	assert.Equal(t, expectedSize, currentSize)
 
 	// expected error in cmd
 	e.OnCommand(cmd).Return("", "", expectedErr).Times(1)
-	currentSize, err = l.GetVgFreeSpace(vgName)
+	currentSize, err = l.GetVgFreeSpace(testCtx, vgName)
 	assert.Equal(t, expectedErr, err)
 	assert.Equal(t, int64(-1), currentSize)
 
 	// empty string, expected err
-	currentSize, err = l.GetVgFreeSpace("")
+	currentSize, err = l.GetVgFreeSpace(testCtx, "")
 	assert.Equal(t, int64(-1), currentSize)
 	assert.Equal(t, errors.New("VG name shouldn't be an empty string"), err)
 
 	// empty string, unable to convert to int
 	e.OnCommand(cmd).Return(fmt.Sprintf("\t\t %d \n", expectedSize), "", nil).Times(1)
-	currentSize, err = l.GetVgFreeSpace(vgName)
+	currentSize, err = l.GetVgFreeSpace(testCtx, vgName)
 	assert.Equal(t, int64(-1), currentSize)
 	assert.Contains(t, err.Error(), "unknown size unit")
 }
@@ -291,7 +293,7 @@ func TestLinuxUtils_GetAllPVs(t *testing.T) {
 
 	t.Run("Happy pass", func(t *testing.T) {
 		e.OnCommand(AllPVsCmd).Return("  /dev/sda\n  ", "", nil).Once()
-		res, err = l.GetAllPVs()
+		res, err = l.GetAllPVs(testCtx)
 		assert.Nil(t, err)
 		assert.Equal(t, len(res), 1)
 		assert.Equal(t, res[0], "/dev/sda")
@@ -299,7 +301,7 @@ func TestLinuxUtils_GetAllPVs(t *testing.T) {
 
 	t.Run("Cmd finished with error", func(t *testing.T) {
 		e.OnCommand(AllPVsCmd).Return("", "", expectedErr).Once()
-		res, err = l.GetAllPVs()
+		res, err = l.GetAllPVs(testCtx)
 		assert.NotNil(t, err)
 		assert.Empty(t, res)
 	})
@@ -319,21 +321,21 @@ func TestLinuxUtils_GetVGNameByPVName(t *testing.T) {
 
 	t.Run("Happy pass", func(t *testing.T) {
 		e.OnCommand(cmd).Return(fmt.Sprintf("%s:%s:another:info", pvName, expectedVGName), "", nil).Once()
-		res, err = l.GetVGNameByPVName(pvName)
+		res, err = l.GetVGNameByPVName(testCtx, pvName)
 		assert.Nil(t, err)
 		assert.Equal(t, expectedVGName, res)
 	})
 
 	t.Run("Cmd finished with error", func(t *testing.T) {
 		e.OnCommand(cmd).Return("", "", expectedErr).Once()
-		res, err = l.GetVGNameByPVName(pvName)
+		res, err = l.GetVGNameByPVName(testCtx, pvName)
 		assert.Equal(t, "", res)
 		assert.Equal(t, expectedErr, err)
 	})
 
 	t.Run("PV isn't related to any VG", func(t *testing.T) {
 		e.OnCommand(cmd).Return("/dev/sda is a new physical volume\nsome::another:info", "", nil).Once()
-		res, err = l.GetVGNameByPVName(pvName)
+		res, err = l.GetVGNameByPVName(testCtx, pvName)
 		assert.Equal(t, "", res)
 		assert.NotNil(t, err)
 		assert.Contains(t, err.Error(), "isn't related to any VG")
@@ -341,9 +343,90 @@ func TestLinuxUtils_GetVGNameByPVName(t *testing.T) {
 
 	t.Run("Unable to parse output", func(t *testing.T) {
 		e.OnCommand(cmd).Return("/dev/sda", "", nil).Once()
-		res, err = l.GetVGNameByPVName(pvName)
+		res, err = l.GetVGNameByPVName(testCtx, pvName)
 		assert.Equal(t, "", res)
 		assert.NotNil(t, err)
 		assert.Contains(t, err.Error(), "unable to find VG name for PV")
 	})
 }
+
+func TestLVM_VGExtendVGReduce(t *testing.T) {
+	var (
+		e           = &mocks.GoMockExecutor{}
+		l           = NewLVM(e, testLogger)
+		vg          = "test-lvg"
+		dev         = "/dev/sdb"
+		extendCmd   = fmt.Sprintf(VGExtendCmdTmpl, vg, dev)
+		reduceCmd   = fmt.Sprintf(VGReduceCmdTmpl, vg, dev)
+		expectedErr = errors.New("error")
+	)
+
+	e.OnCommand(extendCmd).Return("", "", nil).Once()
+	assert.Nil(t, l.VGExtend(testCtx, vg, dev))
+	e.OnCommand(extendCmd).Return("", "Physical volume '/dev/sdb' is already in volume group 'test-lvg'", expectedErr).Once()
+	assert.Nil(t, l.VGExtend(testCtx, vg, dev))
+	e.OnCommand(extendCmd).Return("", "another error", expectedErr).Once()
+	assert.Equal(t, expectedErr, l.VGExtend(testCtx, vg, dev))
+
+	e.OnCommand(reduceCmd).Return("", "", nil).Once()
+	assert.Nil(t, l.VGReduce(testCtx, vg, dev))
+	e.OnCommand(reduceCmd).Return("", "Physical Volume /dev/sdb not found in volume group test-lvg", expectedErr).Once()
+	assert.Nil(t, l.VGReduce(testCtx, vg, dev))
+	e.OnCommand(reduceCmd).Return("", "Physical volume /dev/sdb still in use", expectedErr).Once()
+	assert.Equal(t, expectedErr, l.VGReduce(testCtx, vg, dev))
+}
+
+func TestLVM_PVMove(t *testing.T) {
+	var (
+		e           = &mocks.GoMockExecutor{}
+		l           = NewLVM(e, testLogger)
+		vg          = "test-lvg"
+		progressCmd = fmt.Sprintf(PVMoveProgressCmdTmpl, vg)
+		expectedErr = errors.New("error")
+	)
+
+	e.OnCommand(fmt.Sprintf(PVMoveCmdTmpl, "/dev/sda", "/dev/sdb")).Return("", "", nil).Once()
+	assert.Nil(t, l.PVMove(testCtx, "/dev/sda", "/dev/sdb"))
+	e.OnCommand(fmt.Sprintf(PVMoveCmdTmpl, "/dev/sda", "")).Return("", "", expectedErr).Once()
+	assert.Equal(t, expectedErr, l.PVMove(testCtx, "/dev/sda"))
+	e.OnCommand(PVMoveAbortCmd).Return("", "", nil).Once()
+	assert.Nil(t, l.PVMoveAbort(testCtx))
+
+	e.OnCommand(progressCmd).Return("  pvc-1\n  [pvmove0]  42.17\n", "", nil).Once()
+	percent, inProgress, err := l.GetPVMoveProgress(testCtx, vg)
+	assert.Nil(t, err)
+	assert.True(t, inProgress)
+	assert.Equal(t, 42.17, percent)
+
+	e.OnCommand(progressCmd).Return("  pvc-1\n", "", nil).Once()
+	_, inProgress, err = l.GetPVMoveProgress(testCtx, vg)
+	assert.Nil(t, err)
+	assert.False(t, inProgress)
+
+	e.OnCommand(progressCmd).Return("  [pvmove0]  abc\n", "", nil).Once()
+	_, _, err = l.GetPVMoveProgress(testCtx, vg)
+	assert.NotNil(t, err)
+
+	e.OnCommand(progressCmd).Return("", "", expectedErr).Once()
+	_, _, err = l.GetPVMoveProgress(testCtx, vg)
+	assert.Equal(t, expectedErr, err)
+}
+
+func TestLVM_GetPVUsage(t *testing.T) {
+	var (
+		e   = &mocks.GoMockExecutor{}
+		l   = NewLVM(e, testLogger)
+		dev = "/dev/sdb"
+		cmd = fmt.Sprintf(PVUsageCmdTmpl, dev)
+	)
+
+	e.OnCommand(cmd).Return("  1024B  2048B\n", "", nil).Once()
+	used, free, err := l.GetPVUsage(testCtx, dev)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1024), used)
+	assert.Equal(t, int64(2048), free)
+
+	e.OnCommand(cmd).Return("  1024B\n", "", nil).Once()
+	_, _, err = l.GetPVUsage(testCtx, dev)
+	assert.NotNil(t, err)
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package nvmecli
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"strings"
@@ -42,7 +43,7 @@ const (
 
 // WrapNvmecli is an interface that encapsulates operation with system nvme util
 type WrapNvmecli interface {
-	GetNVMDevices() ([]NVMDevice, error)
+	GetNVMDevices(ctx context.Context) ([]NVMDevice, error)
 }
 
 // NVMDevice represents devices from nvme list output
@@ -74,9 +75,9 @@ func NewNVMECLI(e command.CmdExecutor, logger *logrus.Logger) *NVMECLI {
 }
 
 // GetNVMDevices gets information about NVMDevice using nvme_cli util
-func (na *NVMECLI) GetNVMDevices() ([]NVMDevice, error) {
+func (na *NVMECLI) GetNVMDevices(ctx context.Context) ([]NVMDevice, error) {
 	ll := na.log.WithField("method", "GetNVMDevices")
-	strOut, _, err := na.e.RunCmd(NVMeDeviceCmdImpl,
+	strOut, _, err := na.e.RunCmdContext(ctx, NVMeDeviceCmdImpl,
 		command.UseMetrics(true),
 		command.CmdName(NVMeDeviceCmdImpl))
 	if err != nil {
@@ -96,17 +97,17 @@ func (na *NVMECLI) GetNVMDevices() ([]NVMDevice, error) {
 		return nil, fmt.Errorf("unexpected nvme list output format")
 	}
 	for i, d := range devs {
-		devs[i].Health = na.getNVMDeviceHealth(d.DevicePath)
-		na.fillNVMDeviceVendor(&devs[i])
+		devs[i].Health = na.getNVMDeviceHealth(ctx, d.DevicePath)
+		na.fillNVMDeviceVendor(ctx, &devs[i])
 	}
 	return devs, nil
 }
 
 // getNVMDeviceHealth gets information about device health based on critical_warning SMART attribute using nvme_cli smart-log util
-func (na *NVMECLI) getNVMDeviceHealth(path string) string {
+func (na *NVMECLI) getNVMDeviceHealth(ctx context.Context, path string) string {
 	ll := na.log.WithField("method", "getNVMDeviceHealth")
 	cmd := fmt.Sprintf(NVMeHealthCmdImpl, path)
-	strOut, _, err := na.e.RunCmd(cmd,
+	strOut, _, err := na.e.RunCmdContext(ctx, cmd,
 		command.UseMetrics(true),
 		command.CmdName(strings.TrimSpace(fmt.Sprintf(NVMeHealthCmdImpl, ""))))
 	if err != nil {
@@ -130,10 +131,10 @@ func (na *NVMECLI) getNVMDeviceHealth(path string) string {
 }
 
 // fillNVMDeviceVendor gets information about device vendor id
-func (na *NVMECLI) fillNVMDeviceVendor(device *NVMDevice) {
+func (na *NVMECLI) fillNVMDeviceVendor(ctx context.Context, device *NVMDevice) {
 	ll := na.log.WithField("method", "fillNVMDeviceVendor")
 	cmd := fmt.Sprintf(NVMeVendorCmdImpl, device.DevicePath)
-	strOut, _, err := na.e.RunCmd(cmd,
+	strOut, _, err := na.e.RunCmdContext(ctx, cmd,
 		command.UseMetrics(true),
 		command.CmdName(strings.TrimSpace(fmt.Sprintf(NVMeVendorCmdImpl, ""))))
 	if err != nil {
@@ -17,6 +17,7 @@ limitations under the License.
 package nvmecli
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -28,6 +29,7 @@ import (
 )
 
 var (
+	testCtx    = context.Background()
 	testLogger = logrus.New()
 	testPath   = "/dev/nvme9n1"
 )
@@ -77,7 +79,7 @@ func TestNVMECLI_GetNVMDevicesSuccess(t *testing.T) {
 	e.On("RunCmd", NVMeDeviceCmdImpl).Return(output, "", nil)
 	e.On("RunCmd", fmt.Sprintf(NVMeHealthCmdImpl, "/dev/nvme9n1")).Return(health, "", nil)
 	e.On("RunCmd", fmt.Sprintf(NVMeVendorCmdImpl, "/dev/nvme9n1")).Return(vendor, "", nil)
-	devices, err := l.GetNVMDevices()
+	devices, err := l.GetNVMDevices(testCtx)
 	assert.Nil(t, err)
 
 	assert.Equal(t, 1, len(devices))
@@ -96,7 +98,7 @@ func TestNVMECLI_GetNVMDevicesFails(t *testing.T) {
 
 	e.On("RunCmd", NVMeDeviceCmdImpl).Return("", "", fmt.Errorf("error"))
 
-	_, err := l.GetNVMDevices()
+	_, err := l.GetNVMDevices(testCtx)
 	assert.NotNil(t, err)
 }
 
@@ -122,7 +124,7 @@ func TestNVMECLI_GetNVMDevicesUnmarshallError(t *testing.T) {
 	l := NewNVMECLI(e, testLogger)
 
 	e.On("RunCmd", NVMeDeviceCmdImpl).Return(output, "", nil)
-	_, err := l.GetNVMDevices()
+	_, err := l.GetNVMDevices(testCtx)
 	assert.NotNil(t, err)
 }
 
@@ -148,7 +150,7 @@ func TestNVMECLI_GetNVMDevicesWrongKey(t *testing.T) {
 	l := NewNVMECLI(e, testLogger)
 
 	e.On("RunCmd", NVMeDeviceCmdImpl).Return(output, "", nil)
-	_, err := l.GetNVMDevices()
+	_, err := l.GetNVMDevices(testCtx)
 	assert.NotNil(t, err)
 }
 
@@ -161,7 +163,7 @@ func TestNVMECLI_getNVMDeviceHealthBad(t *testing.T) {
 	}
 	`
 	e.On("RunCmd", fmt.Sprintf(NVMeHealthCmdImpl, testPath)).Return(health, "", nil)
-	deviceHealth := l.getNVMDeviceHealth(testPath)
+	deviceHealth := l.getNVMDeviceHealth(testCtx, testPath)
 	assert.Equal(t, apiV1.HealthBad, deviceHealth)
 }
 func TestNVMECLI_getNVMDeviceHealthSuspect(t *testing.T) {
@@ -172,7 +174,7 @@ func TestNVMECLI_getNVMDeviceHealthSuspect(t *testing.T) {
 	}
 	`
 	e.On("RunCmd", fmt.Sprintf(NVMeHealthCmdImpl, testPath)).Return(health, "", nil)
-	deviceHealth := l.getNVMDeviceHealth(testPath)
+	deviceHealth := l.getNVMDeviceHealth(testCtx, testPath)
 	assert.Equal(t, apiV1.HealthSuspect, deviceHealth)
 }
 
@@ -184,7 +186,7 @@ func TestNVMECLI_getNVMDeviceHealthGood(t *testing.T) {
 	}
 	`
 	e.On("RunCmd", fmt.Sprintf(NVMeHealthCmdImpl, testPath)).Return(health, "", nil)
-	deviceHealth := l.getNVMDeviceHealth(testPath)
+	deviceHealth := l.getNVMDeviceHealth(testCtx, testPath)
 	assert.Equal(t, apiV1.HealthGood, deviceHealth)
 }
 
@@ -197,7 +199,7 @@ func TestNVMECLI_getNVMDeviceHealthUnmarshallError(t *testing.T) {
 	}
 	`
 	e.On("RunCmd", fmt.Sprintf(NVMeHealthCmdImpl, testPath)).Return(health, "", nil)
-	deviceHealth := l.getNVMDeviceHealth(testPath)
+	deviceHealth := l.getNVMDeviceHealth(testCtx, testPath)
 	assert.Equal(t, apiV1.HealthUnknown, deviceHealth)
 }
 
@@ -205,7 +207,7 @@ func TestNVMECLI_getNVMDeviceHealthCMDError(t *testing.T) {
 	e := &mocks.GoMockExecutor{}
 	l := NewNVMECLI(e, testLogger)
 	e.On("RunCmd", fmt.Sprintf(NVMeHealthCmdImpl, testPath)).Return("", "", fmt.Errorf("error"))
-	deviceHealth := l.getNVMDeviceHealth(testPath)
+	deviceHealth := l.getNVMDeviceHealth(testCtx, testPath)
 	assert.Equal(t, apiV1.HealthUnknown, deviceHealth)
 }
 
@@ -216,7 +218,7 @@ func TestNVMECLI_getNVMDeviceVendorFail(t *testing.T) {
 		DevicePath: "/dev/nvme9n1",
 	}
 	e.On("RunCmd", fmt.Sprintf(NVMeVendorCmdImpl, "/dev/nvme9n1")).Return("", "", fmt.Errorf("error"))
-	l.fillNVMDeviceVendor(&device)
+	l.fillNVMDeviceVendor(testCtx, &device)
 	assert.Equal(t, 0, device.Vendor)
 }
 
@@ -231,7 +233,7 @@ func TestNVMECLI_getNVMDeviceVendorUnmarshalError(t *testing.T) {
 		DevicePath: "/dev/nvme9n1",
 	}
 	e.On("RunCmd", fmt.Sprintf(NVMeVendorCmdImpl, "/dev/nvme9n1")).Return(vendor, "", nil)
-	l.fillNVMDeviceVendor(&device)
+	l.fillNVMDeviceVendor(testCtx, &device)
 	assert.Equal(t, 0, device.Vendor)
 }
 
@@ -19,6 +19,7 @@ limitations under the License.
 package partitionhelper
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"sync"
@@ -32,17 +33,17 @@ import (
 
 // WrapPartition is the interface which encapsulates methods to work with drives' partitions
 type WrapPartition interface {
-	IsPartitionExists(device, partNum string) (exists bool, err error)
-	GetPartitionTableType(device string) (ptType string, err error)
-	CreatePartitionTable(device, partTableType string) (err error)
-	CreatePartition(device, label string) (err error)
-	DeletePartition(device, partNum string) (err error)
-	SetPartitionUUID(device, partNum, partUUID string) error
-	GetPartitionUUID(device, partNum string) (string, error)
-	SyncPartitionTable(device string) error
-	GetPartitionNameByUUID(device, partUUID string) (string, error)
-	DeviceHasPartitionTable(device string) (bool, error)
-	DeviceHasPartitions(device, serialNumber string) (bool, error)
+	IsPartitionExists(ctx context.Context, device, partNum string) (exists bool, err error)
+	GetPartitionTableType(ctx context.Context, device string) (ptType string, err error)
+	CreatePartitionTable(ctx context.Context, device, partTableType string) (err error)
+	CreatePartition(ctx context.Context, device, label string) (err error)
+	DeletePartition(ctx context.Context, device, partNum string) (err error)
+	SetPartitionUUID(ctx context.Context, device, partNum, partUUID string) error
+	GetPartitionUUID(ctx context.Context, device, partNum string) (string, error)
+	SyncPartitionTable(ctx context.Context, device string) error
+	GetPartitionNameByUUID(ctx context.Context, device, partUUID string) (string, error)
+	DeviceHasPartitionTable(ctx context.Context, device string) (bool, error)
+	DeviceHasPartitions(ctx context.Context, device, serialNumber string) (bool, error)
 }
 
 const (
@@ -100,7 +101,7 @@ func NewWrapPartitionImpl(e command.CmdExecutor, log *logrus.Logger) *WrapPartit
 // IsPartitionExists checks if a partition exists in a provided device
 // Receives path to a device to check a partition existence
 // Returns partition existence status or error if something went wrong
-func (p *WrapPartitionImpl) IsPartitionExists(device, partNum string) (bool, error) {
+func (p *WrapPartitionImpl) IsPartitionExists(ctx context.Context, device, partNum string) (bool, error) {
 	cmd := fmt.Sprintf(PartprobeDeviceCmdTmpl, device)
 	/*
 		example of output:
@@ -109,7 +110,7 @@ func (p *WrapPartitionImpl) IsPartitionExists(device, partNum string) (bool, err
 	*/
 
 	p.opMutex.Lock()
-	stdout, _, err := p.e.RunCmd(cmd,
+	stdout, _, err := p.e.RunCmdContext(ctx, cmd,
 		command.UseMetrics(true),
 		command.CmdName(strings.TrimSpace(fmt.Sprintf(PartprobeDeviceCmdTmpl, ""))))
 	p.opMutex.Unlock()
@@ -132,16 +133,17 @@ func (p *WrapPartitionImpl) IsPartitionExists(device, partNum string) (bool, err
 // CreatePartitionTable created partition table on a provided device
 // Receives device path on which to create table
 // Returns error if something went wrong
-func (p *WrapPartitionImpl) CreatePartitionTable(device, partTableType string) error {
+func (p *WrapPartitionImpl) CreatePartitionTable(ctx context.Context, device, partTableType string) error {
 	if !util.ContainsString(supportedTypes, partTableType) {
 		return fmt.Errorf("unable to create partition table for device %s unsupported partition table type: %#v",
 			device, partTableType)
 	}
 
 	cmd := fmt.Sprintf(CreatePartitionTableCmdTmpl, device, partTableType)
-	_, _, err := p.e.RunCmd(cmd,
+	_, _, err := p.e.RunCmdContext(ctx, cmd,
 		command.UseMetrics(true),
-		command.CmdName(strings.TrimSpace(fmt.Sprintf(CreatePartitionTableCmdTmpl, "", ""))))
+		command.CmdName(strings.TrimSpace(fmt.Sprintf(CreatePartitionTableCmdTmpl, "", ""))),
+		command.Destructive(true))
 
 	if err != nil {
 		return fmt.Errorf("unable to create partition table for device %s", device)
@@ -153,10 +155,10 @@ func (p *WrapPartitionImpl) CreatePartitionTable(device, partTableType string) e
 // GetPartitionTableType returns string that represent partition table type
 // Receives device path from which partition table type should be got
 // Returns partition table type as a string or error if something went wrong
-func (p *WrapPartitionImpl) GetPartitionTableType(device string) (string, error) {
+func (p *WrapPartitionImpl) GetPartitionTableType(ctx context.Context, device string) (string, error) {
 	cmd := fmt.Sprintf(PartprobeDeviceCmdTmpl, device)
 
-	stdout, _, err := p.e.RunCmd(cmd,
+	stdout, _, err := p.e.RunCmdContext(ctx, cmd,
 		command.UseMetrics(true),
 		command.CmdName(strings.TrimSpace(fmt.Sprintf(PartprobeDeviceCmdTmpl, ""))))
 
@@ -175,11 +177,11 @@ func (p *WrapPartitionImpl) GetPartitionTableType(device string) (string, error)
 // CreatePartition creates partition with name partName on a device
 // Receives device path to create a partition
 // Returns error if something went wrong
-func (p *WrapPartitionImpl) CreatePartition(device, label string) error {
+func (p *WrapPartitionImpl) CreatePartition(ctx context.Context, device, label string) error {
 	cmd := fmt.Sprintf(CreatePartitionCmdTmpl, device, label)
 
 	p.opMutex.Lock()
-	_, _, err := p.e.RunCmd(cmd,
+	_, _, err := p.e.RunCmdContext(ctx, cmd,
 		command.UseMetrics(true),
 		command.CmdName(strings.TrimSpace(fmt.Sprintf(CreatePartitionCmdTmpl, "", ""))))
 	p.opMutex.Unlock()
@@ -194,13 +196,14 @@ func (p *WrapPartitionImpl) CreatePartition(device, label string) error {
 // DeletePartition removes partition partNum from a provided device
 // Receives device path and it's partition which should be deleted
 // Returns error if something went wrong
-func (p *WrapPartitionImpl) DeletePartition(device, partNum string) error {
+func (p *WrapPartitionImpl) DeletePartition(ctx context.Context, device, partNum string) error {
 	cmd := fmt.Sprintf(DeletePartitionCmdTmpl, device, partNum)
 
 	p.opMutex.Lock()
-	_, stderr, err := p.e.RunCmd(cmd,
+	_, stderr, err := p.e.RunCmdContext(ctx, cmd,
 		command.UseMetrics(true),
-		command.CmdName(strings.TrimSpace(fmt.Sprintf(DeletePartitionCmdTmpl, "", ""))))
+		command.CmdName(strings.TrimSpace(fmt.Sprintf(DeletePartitionCmdTmpl, "", ""))),
+		command.Destructive(true))
 	p.opMutex.Unlock()
 
 	if err != nil {
@@ -214,12 +217,13 @@ func (p *WrapPartitionImpl) DeletePartition(device, partNum string) error {
 // SetPartitionUUID writes partUUID as GUID for the partition partNum of a provided device
 // Receives device path and partUUID as strings
 // Returns error if something went wrong
-func (p *WrapPartitionImpl) SetPartitionUUID(device, partNum, partUUID string) error {
+func (p *WrapPartitionImpl) SetPartitionUUID(ctx context.Context, device, partNum, partUUID string) error {
 	cmd := fmt.Sprintf(SetPartitionUUIDCmdTmpl, device, partNum, partUUID)
 
-	if _, _, err := p.e.RunCmd(cmd,
+	if _, _, err := p.e.RunCmdContext(ctx, cmd,
 		command.UseMetrics(true),
-		command.CmdName(strings.TrimSpace(fmt.Sprintf(SetPartitionUUIDCmdTmpl, "", "", "")))); err != nil {
+		command.CmdName(strings.TrimSpace(fmt.Sprintf(SetPartitionUUIDCmdTmpl, "", "", ""))),
+		command.Destructive(true)); err != nil {
 		return err
 	}
 
@@ -229,7 +233,7 @@ func (p *WrapPartitionImpl) SetPartitionUUID(device, partNum, partUUID string) e
 // GetPartitionUUID reads partition unique GUID from the partition partNum of a provided device
 // Receives device path from which to read
 // Returns unique GUID as a string or error if something went wrong
-func (p *WrapPartitionImpl) GetPartitionUUID(device, partNum string) (string, error) {
+func (p *WrapPartitionImpl) GetPartitionUUID(ctx context.Context, device, partNum string) (string, error) {
 	/*
 		example of command output:
 		$ sgdisk /dev/sdy --info=1
@@ -244,7 +248,7 @@ func (p *WrapPartitionImpl) GetPartitionUUID(device, partNum string) (string, er
 	cmd := fmt.Sprintf(GetPartitionUUIDCmdTmpl, device, partNum)
 	partitionPresentation := "Partition unique GUID:"
 
-	stdout, _, err := p.e.RunCmd(cmd,
+	stdout, _, err := p.e.RunCmdContext(ctx, cmd,
 		command.UseMetrics(true),
 		command.CmdName(strings.TrimSpace(fmt.Sprintf(GetPartitionUUIDCmdTmpl, "", ""))))
 
@@ -267,11 +271,11 @@ func (p *WrapPartitionImpl) GetPartitionUUID(device, partNum string) (string, er
 // SyncPartitionTable syncs partition table for specific device
 // Receives device path to sync with partprobe, device could be an empty string (sync for all devices in the system)
 // Returns error if something went wrong
-func (p *WrapPartitionImpl) SyncPartitionTable(device string) error {
+func (p *WrapPartitionImpl) SyncPartitionTable(ctx context.Context, device string) error {
 	cmd := fmt.Sprintf(PartprobeCmdTmpl, device)
 
 	p.opMutex.Lock()
-	_, _, err := p.e.RunCmd(cmd,
+	_, _, err := p.e.RunCmdContext(ctx, cmd,
 		command.UseMetrics(true),
 		command.CmdName(strings.TrimSpace(fmt.Sprintf(PartprobeCmdTmpl, ""))))
 	p.opMutex.Unlock()
@@ -287,7 +291,7 @@ func (p *WrapPartitionImpl) SyncPartitionTable(device string) error {
 // for example "1" for /dev/sda1,  "1p2" for /dev/nvme1p2,  "0p3" for /dev/loopback0p3
 // Receives a device path and uuid of partition to find
 // Returns a partition number or error if something went wrong
-func (p *WrapPartitionImpl) GetPartitionNameByUUID(device, partUUID string) (string, error) {
+func (p *WrapPartitionImpl) GetPartitionNameByUUID(ctx context.Context, device, partUUID string) (string, error) {
 	if device == "" {
 		return "", fmt.Errorf("unable to find partition name by UUID %#v - device name is empty", partUUID)
 	}
@@ -297,7 +301,7 @@ func (p *WrapPartitionImpl) GetPartitionNameByUUID(device, partUUID string) (str
 	}
 
 	// list partitions
-	blockdevices, err := p.lsblkUtil.GetBlockDevices(device)
+	blockdevices, err := p.lsblkUtil.GetBlockDevices(ctx, device)
 	if err != nil {
 		return "", err
 	}
@@ -325,7 +329,7 @@ func (p *WrapPartitionImpl) GetPartitionNameByUUID(device, partUUID string) (str
 // DeviceHasPartitionTable calls parted  and determine if device has partition table from output
 // Receive device path
 // Return true if device has partition table, false in opposite, error if something went wrong
-func (p *WrapPartitionImpl) DeviceHasPartitionTable(device string) (bool, error) {
+func (p *WrapPartitionImpl) DeviceHasPartitionTable(ctx context.Context, device string) (bool, error) {
 	/*
 		Disk /dev/sda: 931.5 GiB, 1000204886016 bytes, 1953525168 sectors
 		Units: sectors of 1 * 512 = 512 bytes
@@ -339,7 +343,7 @@ func (p *WrapPartitionImpl) DeviceHasPartitionTable(device string) (bool, error)
 	cmd := fmt.Sprintf(DetectPartitionTableCmdTmpl, device)
 
 	p.opMutex.Lock()
-	stdout, _, err := p.e.RunCmd(cmd,
+	stdout, _, err := p.e.RunCmdContext(ctx, cmd,
 		command.UseMetrics(true),
 		command.CmdName(strings.TrimSpace(fmt.Sprintf(DetectPartitionTableCmdTmpl, ""))))
 	p.opMutex.Unlock()
@@ -357,8 +361,8 @@ func (p *WrapPartitionImpl) DeviceHasPartitionTable(device string) (bool, error)
 // DeviceHasPartitions calls lsblk and determine if device has partitions (children)
 // Receive device path and serial number
 // Return true if device has partitions, false in opposite, error if something went wrong
-func (p *WrapPartitionImpl) DeviceHasPartitions(device, serialNumber string) (bool, error) {
-	blockDevices, err := p.lsblkUtil.GetBlockDevices(device)
+func (p *WrapPartitionImpl) DeviceHasPartitions(ctx context.Context, device, serialNumber string) (bool, error) {
+	blockDevices, err := p.lsblkUtil.GetBlockDevices(ctx, device)
 	if len(blockDevices) != 1 {
 		return false, fmt.Errorf("wrong output of lsblk for %s, block devices: %v", device, blockDevices)
 	}
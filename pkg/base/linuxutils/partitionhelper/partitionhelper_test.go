@@ -17,6 +17,7 @@ limitations under the License.
 package partitionhelper
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"testing"
@@ -31,6 +32,7 @@ import (
 )
 
 var (
+	testCtx         = context.Background()
 	testLogger      = logrus.New()
 	testPartitioner = NewWrapPartitionImpl(mocks.NewMockExecutor(mocks.DiskCommands), testLogger)
 	testPartNum     = "1"
@@ -39,116 +41,116 @@ var (
 )
 
 func TestIsPartitionExists(t *testing.T) {
-	exists, _ := testPartitioner.IsPartitionExists("/dev/sda", testPartNum)
+	exists, _ := testPartitioner.IsPartitionExists(testCtx, "/dev/sda", testPartNum)
 	assert.Equal(t, false, exists)
 
-	exists, _ = testPartitioner.IsPartitionExists("/dev/sdb", testPartNum)
+	exists, _ = testPartitioner.IsPartitionExists(testCtx, "/dev/sdb", testPartNum)
 	assert.Equal(t, true, exists)
 
-	exists, _ = testPartitioner.IsPartitionExists("/dev/sdc", testPartNum)
+	exists, _ = testPartitioner.IsPartitionExists(testCtx, "/dev/sdc", testPartNum)
 	assert.Equal(t, false, exists)
 }
 
 func TestIsPartitionExistsFail(t *testing.T) {
-	exists, err := testPartitioner.IsPartitionExists("/dev/sdd", testPartNum)
+	exists, err := testPartitioner.IsPartitionExists(testCtx, "/dev/sdd", testPartNum)
 	assert.Equal(t, false, exists)
 	assert.NotNil(t, err)
 	assert.Contains(t, err.Error(), "unable to check partition")
 }
 
 func TestCreatePartitionTable(t *testing.T) {
-	err := testPartitioner.CreatePartitionTable("/dev/sda", PartitionGPT)
+	err := testPartitioner.CreatePartitionTable(testCtx, "/dev/sda", PartitionGPT)
 	assert.Nil(t, err)
 
-	err = testPartitioner.CreatePartitionTable("/dev/sdc", PartitionGPT)
+	err = testPartitioner.CreatePartitionTable(testCtx, "/dev/sdc", PartitionGPT)
 	assert.Nil(t, err)
 }
 
 func TestCreatePartitionTableFail(t *testing.T) {
-	err := testPartitioner.CreatePartitionTable("/dev/sdd", PartitionGPT)
+	err := testPartitioner.CreatePartitionTable(testCtx, "/dev/sdd", PartitionGPT)
 	assert.NotNil(t, err)
 	assert.Contains(t, err.Error(), "unable to create partition table for device")
 
 	// unsupported partition table type
-	err = testPartitioner.CreatePartitionTable("/dev/sdd", "qwerty")
+	err = testPartitioner.CreatePartitionTable(testCtx, "/dev/sdd", "qwerty")
 	assert.NotNil(t, err)
 	assert.Contains(t, err.Error(), "unsupported partition table type")
 }
 
 func TestCreatePartition(t *testing.T) {
-	err := testPartitioner.CreatePartition("/dev/sde", testCSILabel)
+	err := testPartitioner.CreatePartition(testCtx, "/dev/sde", testCSILabel)
 	assert.Nil(t, err)
 }
 
 func TestCreatePartitionFail(t *testing.T) {
-	err := testPartitioner.CreatePartition("/dev/sdf", testCSILabel)
+	err := testPartitioner.CreatePartition(testCtx, "/dev/sdf", testCSILabel)
 	assert.NotNil(t, err)
 
-	err = testPartitioner.CreatePartition("/dev/sdww", testCSILabel)
+	err = testPartitioner.CreatePartition(testCtx, "/dev/sdww", testCSILabel)
 	assert.NotNil(t, err)
 }
 
 func TestDeletePartition(t *testing.T) {
-	err := testPartitioner.DeletePartition("/dev/sda", testPartNum)
+	err := testPartitioner.DeletePartition(testCtx, "/dev/sda", testPartNum)
 	assert.Nil(t, err)
 }
 
 func TestDeletePartitionFail(t *testing.T) {
-	err := testPartitioner.DeletePartition("/dev/sdb", testPartNum)
+	err := testPartitioner.DeletePartition(testCtx, "/dev/sdb", testPartNum)
 	assert.NotNil(t, err)
 }
 
 func TestSetPartitionUUID(t *testing.T) {
-	err := testPartitioner.SetPartitionUUID("/dev/sda", testPartNum, testPartUUID)
+	err := testPartitioner.SetPartitionUUID(testCtx, "/dev/sda", testPartNum, testPartUUID)
 	assert.Nil(t, err)
 }
 
 func TestSetPartitionUUIDFail(t *testing.T) {
-	err := testPartitioner.SetPartitionUUID("/dev/sdb", testPartNum, testPartUUID)
+	err := testPartitioner.SetPartitionUUID(testCtx, "/dev/sdb", testPartNum, testPartUUID)
 	assert.NotNil(t, err)
 }
 
 func TestGetPartitionUUID(t *testing.T) {
-	uuid, err := testPartitioner.GetPartitionUUID("/dev/sda", testPartNum)
+	uuid, err := testPartitioner.GetPartitionUUID(testCtx, "/dev/sda", testPartNum)
 	assert.Equal(t, "64be631b-62a5-11e9-a756-00505680d67f", uuid)
 	assert.Nil(t, err)
 }
 
 func TestGetPartitionUUIDFail(t *testing.T) {
-	uuid, err := testPartitioner.GetPartitionUUID("/dev/sdb", testPartNum)
+	uuid, err := testPartitioner.GetPartitionUUID(testCtx, "/dev/sdb", testPartNum)
 	assert.Equal(t, "", uuid)
 	assert.Equal(t, errors.New("unable to get partition GUID for device /dev/sdb"), err)
 
-	uuid, err = testPartitioner.GetPartitionUUID("/dev/sdc", testPartNum)
+	uuid, err = testPartitioner.GetPartitionUUID(testCtx, "/dev/sdc", testPartNum)
 	assert.NotNil(t, err)
 	assert.Equal(t, "", uuid)
 	assert.Equal(t, errors.New("error"), err)
 }
 
 func TestSyncPartitionTable(t *testing.T) {
-	err := testPartitioner.SyncPartitionTable("/dev/sde")
+	err := testPartitioner.SyncPartitionTable(testCtx, "/dev/sde")
 	assert.Nil(t, err)
 }
 
 func TestSyncPartitionTableFail(t *testing.T) {
-	err := testPartitioner.SyncPartitionTable("/dev/sdXXXX")
+	err := testPartitioner.SyncPartitionTable(testCtx, "/dev/sdXXXX")
 	assert.NotNil(t, err)
 }
 
 func TestGetPartitionTableType(t *testing.T) {
-	ptType, _ := testPartitioner.GetPartitionTableType("/dev/sdb")
+	ptType, _ := testPartitioner.GetPartitionTableType(testCtx, "/dev/sdb")
 	assert.Equal(t, "msdos", ptType)
 
-	ptType, _ = testPartitioner.GetPartitionTableType("/dev/sdc")
+	ptType, _ = testPartitioner.GetPartitionTableType(testCtx, "/dev/sdc")
 	assert.Equal(t, "msdos", ptType)
 }
 
 func TestGetPartitionTableTypeFail(t *testing.T) {
-	ptType, err := testPartitioner.GetPartitionTableType("/dev/sdqwe")
+	ptType, err := testPartitioner.GetPartitionTableType(testCtx, "/dev/sdqwe")
 	assert.Equal(t, "", ptType)
 	assert.Equal(t, errors.New("unable to get partition table for device /dev/sdqwe"), err)
 
-	ptType, err = testPartitioner.GetPartitionTableType("/dev/sde")
+	ptType, err = testPartitioner.GetPartitionTableType(testCtx, "/dev/sde")
 	assert.NotNil(t, err)
 	assert.Contains(t, err.Error(), "unable to parse output")
 }
@@ -170,7 +172,7 @@ func TestGetPartitionNameByUUIDSuccess(t *testing.T) {
 	lsblkResults := []lsblk.BlockDevice{blkDev1}
 	mockLsblk.On("GetBlockDevices", device).Return(lsblkResults, nil)
 
-	res, err := p.GetPartitionNameByUUID(device, partUUID)
+	res, err := p.GetPartitionNameByUUID(testCtx, device, partUUID)
 	assert.Nil(t, err)
 	assert.Equal(t, partName, res)
 
@@ -187,12 +189,12 @@ func TestGetPartitionNameByUUIDFail(t *testing.T) {
 	)
 
 	// device wasn't provided
-	res, err = p.GetPartitionNameByUUID("", "bla")
+	res, err = p.GetPartitionNameByUUID(testCtx, "", "bla")
 	assert.Equal(t, "", res)
 	assert.NotNil(t, err)
 
 	// partition UUID wasn't provided
-	res, err = p.GetPartitionNameByUUID("bla", "")
+	res, err = p.GetPartitionNameByUUID(testCtx, "bla", "")
 	assert.Equal(t, "", res)
 	assert.NotNil(t, err)
 
@@ -205,7 +207,7 @@ func TestGetPartitionNameByUUIDFail(t *testing.T) {
 	expectedErr := errors.New("lsblk error")
 	mockLsblk.On("GetBlockDevices", device).
 		Return([]lsblk.BlockDevice{}, expectedErr).Times(1)
-	res, err = p.GetPartitionNameByUUID(device, partUUID)
+	res, err = p.GetPartitionNameByUUID(testCtx, device, partUUID)
 	assert.Equal(t, "", res)
 	assert.Equal(t, expectedErr, err)
 
@@ -216,14 +218,14 @@ func TestGetPartitionNameByUUIDFail(t *testing.T) {
 	lsblkResults := []lsblk.BlockDevice{blkDev1}
 	mockLsblk.On("GetBlockDevices", device).
 		Return(lsblkResults, nil).Times(1)
-	res, err = p.GetPartitionNameByUUID(device, partUUID)
+	res, err = p.GetPartitionNameByUUID(testCtx, device, partUUID)
 	assert.Equal(t, "", res)
 	assert.NotNil(t, err)
 
 	// partition with provided UUID wasn't found
 	mockLsblk.On("GetBlockDevices", device).
 		Return([]lsblk.BlockDevice{blkDev1}, nil).Times(1)
-	res, err = p.GetPartitionNameByUUID(device, "anotherUUID")
+	res, err = p.GetPartitionNameByUUID(testCtx, device, "anotherUUID")
 	assert.Equal(t, "", res)
 	assert.NotNil(t, err)
 
@@ -231,7 +233,7 @@ func TestGetPartitionNameByUUIDFail(t *testing.T) {
 	// partition with provided UUID wasn't found
 	mockLsblk.On("GetBlockDevices", device).
 		Return([]lsblk.BlockDevice{}, nil).Times(1)
-	res, err = p.GetPartitionNameByUUID(device, "anotherUUID")
+	res, err = p.GetPartitionNameByUUID(testCtx, device, "anotherUUID")
 	assert.Equal(t, "", res)
 	assert.NotNil(t, err)
 }
@@ -252,7 +254,7 @@ func TestLinuxUtils_DeviceHasPartitions(t *testing.T) {
 		}}
 		mockLsblk.On("GetBlockDevices", device).
 			Return([]lsblk.BlockDevice{blkDev1}, nil).Times(1)
-		hasPart, err := p.DeviceHasPartitions(device, serialNumber)
+		hasPart, err := p.DeviceHasPartitions(testCtx, device, serialNumber)
 		assert.Nil(t, err)
 		assert.True(t, hasPart)
 	})
@@ -261,7 +263,7 @@ func TestLinuxUtils_DeviceHasPartitions(t *testing.T) {
 		blkDev1 := lsblk.BlockDevice{Serial: serialNumber}
 		mockLsblk.On("GetBlockDevices", device).
 			Return([]lsblk.BlockDevice{blkDev1}, nil).Times(1)
-		hasPart, err := p.DeviceHasPartitions(device, serialNumber)
+		hasPart, err := p.DeviceHasPartitions(testCtx, device, serialNumber)
 		assert.Nil(t, err)
 		assert.False(t, hasPart)
 	})
@@ -269,14 +271,14 @@ func TestLinuxUtils_DeviceHasPartitions(t *testing.T) {
 	t.Run("Command failed", func(t *testing.T) {
 		mockLsblk.On("GetBlockDevices", device).
 			Return(nil, errors.New("error")).Times(1)
-		hasPart, err := p.DeviceHasPartitions(device, serialNumber)
+		hasPart, err := p.DeviceHasPartitions(testCtx, device, serialNumber)
 		assert.NotNil(t, err)
 		assert.False(t, hasPart)
 	})
 	t.Run("Bad output", func(t *testing.T) {
 		mockLsblk.On("GetBlockDevices", device).
 			Return(nil, nil).Times(1)
-		hasPart, err := p.DeviceHasPartitions(device, serialNumber)
+		hasPart, err := p.DeviceHasPartitions(testCtx, device, serialNumber)
 		assert.NotNil(t, err)
 		assert.False(t, hasPart)
 	})
@@ -284,7 +286,7 @@ func TestLinuxUtils_DeviceHasPartitions(t *testing.T) {
 		blkDev1 := lsblk.BlockDevice{Serial: serialNumber}
 		mockLsblk.On("GetBlockDevices", device).
 			Return([]lsblk.BlockDevice{blkDev1}, nil).Times(1)
-		hasPart, err := p.DeviceHasPartitions(device, "test2")
+		hasPart, err := p.DeviceHasPartitions(testCtx, device, "test2")
 		assert.NotNil(t, err)
 		assert.False(t, hasPart)
 	})
@@ -299,21 +301,21 @@ func TestLinuxUtils_DeviceHasPartitionTable(t *testing.T) {
 	t.Run("Device has partition table", func(t *testing.T) {
 		e.On("RunCmd", fmt.Sprintf(DetectPartitionTableCmdTmpl, device)).
 			Return("Disklabel type: gpt", "", nil).Times(1)
-		hasPart, err := p.DeviceHasPartitionTable(device)
+		hasPart, err := p.DeviceHasPartitionTable(testCtx, device)
 		assert.Nil(t, err)
 		assert.True(t, hasPart)
 	})
 	t.Run("Device doesn't have partition table", func(t *testing.T) {
 		e.On("RunCmd", fmt.Sprintf(DetectPartitionTableCmdTmpl, device)).
 			Return(" ", "", nil).Times(1)
-		hasPart, err := p.DeviceHasPartitionTable(device)
+		hasPart, err := p.DeviceHasPartitionTable(testCtx, device)
 		assert.Nil(t, err)
 		assert.False(t, hasPart)
 	})
 	t.Run("Command failed", func(t *testing.T) {
 		e.On("RunCmd", fmt.Sprintf(DetectPartitionTableCmdTmpl, device)).
 			Return("", "", errors.New("error")).Times(1)
-		hasPart, err := p.DeviceHasPartitionTable(device)
+		hasPart, err := p.DeviceHasPartitionTable(testCtx, device)
 		assert.NotNil(t, err)
 		assert.False(t, hasPart)
 	})
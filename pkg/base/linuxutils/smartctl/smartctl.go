@@ -17,6 +17,7 @@ limitations under the License.
 package smartctl
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"strings"
@@ -35,7 +36,7 @@ const (
 
 // WrapSmartctl is an interface that encapsulates operation with system smartctl util
 type WrapSmartctl interface {
-	GetDriveInfoByPath(path string) (*DeviceSMARTInfo, error)
+	GetDriveInfoByPath(ctx context.Context, path string) (*DeviceSMARTInfo, error)
 }
 
 // DeviceSMARTInfo represents SMART information about device
@@ -56,8 +57,8 @@ func NewSMARTCTL(e command.CmdExecutor) *SMARTCTL {
 }
 
 // GetDriveInfoByPath gets SMART information about device by its Path using smartctl util
-func (sa *SMARTCTL) GetDriveInfoByPath(path string) (*DeviceSMARTInfo, error) {
-	strOut, _, err := sa.e.RunCmd(fmt.Sprintf(SmartctlDeviceInfoCmdImpl, path),
+func (sa *SMARTCTL) GetDriveInfoByPath(ctx context.Context, path string) (*DeviceSMARTInfo, error) {
+	strOut, _, err := sa.e.RunCmdContext(ctx, fmt.Sprintf(SmartctlDeviceInfoCmdImpl, path),
 		command.UseMetrics(true),
 		command.CmdName(strings.TrimSpace(fmt.Sprintf(SmartctlDeviceInfoCmdImpl, ""))))
 	if err != nil {
@@ -69,7 +70,7 @@ func (sa *SMARTCTL) GetDriveInfoByPath(path string) (*DeviceSMARTInfo, error) {
 	if err != nil {
 		return nil, fmt.Errorf("unable to unmarshal output to []DeviceSMARTInfo instance, error: %v", err)
 	}
-	err = sa.fillSmartStatus(deviceInfo, path)
+	err = sa.fillSmartStatus(ctx, deviceInfo, path)
 	if err != nil {
 		return nil, fmt.Errorf("unable to get SMART status for device %s, error: %v", path, err)
 	}
@@ -77,8 +78,8 @@ func (sa *SMARTCTL) GetDriveInfoByPath(path string) (*DeviceSMARTInfo, error) {
 }
 
 // fillSmartStatus fill smart_status field in DeviceSMARTInfo using smartctl command
-func (sa *SMARTCTL) fillSmartStatus(dev *DeviceSMARTInfo, path string) error {
-	strOut, _, err := sa.e.RunCmd(fmt.Sprintf(SmartctlHealthCmdImpl, path),
+func (sa *SMARTCTL) fillSmartStatus(ctx context.Context, dev *DeviceSMARTInfo, path string) error {
+	strOut, _, err := sa.e.RunCmdContext(ctx, fmt.Sprintf(SmartctlHealthCmdImpl, path),
 		command.UseMetrics(true),
 		command.CmdName(strings.TrimSpace(fmt.Sprintf(SmartctlHealthCmdImpl, ""))))
 	if err != nil {
@@ -17,6 +17,7 @@ limitations under the License.
 package smartctl
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -25,6 +26,8 @@ import (
 	"github.com/dell/csi-baremetal/pkg/mocks"
 )
 
+var testCtx = context.Background()
+
 func TestSMARCTL_GetDriveInfoByPath(t *testing.T) {
 	output := `{ 
 				"serial_number": "29P4K65PF9NF", 
@@ -45,7 +48,7 @@ func TestSMARCTL_GetDriveInfoByPath(t *testing.T) {
 
 	e.On("RunCmd", cmd).Return(output, "", nil)
 	e.On("RunCmd", cmdHealth).Return(outputHealth, "", nil)
-	smartInfo, err := l.GetDriveInfoByPath("/dev/sdd")
+	smartInfo, err := l.GetDriveInfoByPath(testCtx, "/dev/sdd")
 	assert.Nil(t, err)
 
 	assert.Equal(t, smartInfo.SerialNumber, "29P4K65PF9NF")
@@ -60,7 +63,7 @@ func TestSMARCTL_GetDriveInfoByPathFails(t *testing.T) {
 
 	e.On("RunCmd", cmd).Return("", "", fmt.Errorf("error"))
 
-	_, err := l.GetDriveInfoByPath("/dev/sdd")
+	_, err := l.GetDriveInfoByPath(testCtx, "/dev/sdd")
 	assert.NotNil(t, err)
 }
 
@@ -82,7 +85,7 @@ func TestSMARCTL_GetDriveInfoByPathUnmarshallError(t *testing.T) {
 
 	e.On("RunCmd", cmd).Return(output, "", nil)
 
-	_, err := l.GetDriveInfoByPath("/dev/sdd")
+	_, err := l.GetDriveInfoByPath(testCtx, "/dev/sdd")
 	assert.NotNil(t, err)
 }
 
@@ -93,7 +96,7 @@ func TestSMARCTL_fillSmartStatus(t *testing.T) {
 
 	e.On("RunCmd", cmd).Return("", "", fmt.Errorf("error"))
 
-	err := l.fillSmartStatus(&DeviceSMARTInfo{}, "/dev/sdd")
+	err := l.fillSmartStatus(testCtx, &DeviceSMARTInfo{}, "/dev/sdd")
 	assert.NotNil(t, err)
 }
 
@@ -109,6 +112,6 @@ func TestSMARCTL_fillSmartStatusUnmarshallError(t *testing.T) {
 
 	e.On("RunCmd", cmd).Return(output, "", nil)
 
-	err := l.fillSmartStatus(&DeviceSMARTInfo{}, "/dev/sdd")
+	err := l.fillSmartStatus(testCtx, &DeviceSMARTInfo{}, "/dev/sdd")
 	assert.NotNil(t, err)
 }
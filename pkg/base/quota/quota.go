@@ -0,0 +1,170 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package quota contains helpers to calculate usage of StorageQuota custom resources and check requests against them
+package quota
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	"github.com/dell/csi-baremetal/api/v1/lvgcrd"
+	sqcrd "github.com/dell/csi-baremetal/api/v1/storagequotacrd"
+	"github.com/dell/csi-baremetal/api/v1/volumecrd"
+	"github.com/dell/csi-baremetal/pkg/base/k8s"
+)
+
+// Request is a storage request which is checked against quotas
+type Request struct {
+	// StorageClass is a CSI storage class of the request, only quotas for all storage classes match ANY
+	StorageClass string
+	Bytes        int64
+	Volumes      int64
+}
+
+// Usage is a storage usage of the namespace
+type Usage struct {
+	Bytes   int64
+	Volumes int64
+	Drives  int64
+}
+
+// ExceededError is returned when request exceeds the quota
+type ExceededError struct {
+	Quota     string
+	Namespace string
+	Resource  string
+	Used      int64
+	Requested int64
+	Limit     int64
+}
+
+// Error returns description of the exceeded quota
+func (e *ExceededError) Error() string {
+	return fmt.Sprintf("storage quota %s for namespace %s is exceeded: %s used %d, requested %d, limit %d",
+		e.Quota, e.Namespace, e.Resource, e.Used, e.Requested, e.Limit)
+}
+
+// Matches checks whether quota limits volumes with storage class sc in the namespace
+func Matches(quota *sqcrd.StorageQuota, namespace, sc string) bool {
+	return quota.Spec.Namespace == namespace && (quota.Spec.StorageClass == "" || quota.Spec.StorageClass == sc)
+}
+
+// CalculateUsage calculates usage of the quota by volumes
+// Volume on the whole drive consumes its drive, volumes on LogicalVolumeGroup consume all drives of the LVG
+func CalculateUsage(quota *sqcrd.StorageQuota, volumes []volumecrd.Volume, lvgs []lvgcrd.LogicalVolumeGroup) Usage {
+	lvgLocations := make(map[string][]string, len(lvgs))
+	for _, lvg := range lvgs {
+		lvgLocations[lvg.Name] = lvg.Spec.Locations
+	}
+
+	var (
+		usage  Usage
+		drives = map[string]struct{}{}
+	)
+	for _, volume := range volumes {
+		if volume.Spec.CSIStatus == apiV1.Removed ||
+			!Matches(quota, volume.Namespace, volume.Spec.StorageClass) {
+			continue
+		}
+		usage.Bytes += volume.Spec.Size
+		usage.Volumes++
+		if volume.Spec.LocationType == apiV1.LocationTypeLVM {
+			for _, drive := range lvgLocations[volume.Spec.Location] {
+				drives[drive] = struct{}{}
+			}
+			continue
+		}
+		drives[volume.Spec.Location] = struct{}{}
+	}
+	usage.Drives = int64(len(drives))
+	return usage
+}
+
+// NewChecker returns new instance of Checker
+func NewChecker(reader k8s.CRReader, logger *logrus.Entry) *Checker {
+	return &Checker{reader: reader, logger: logger}
+}
+
+// Checker checks storage requests against StorageQuota custom resources
+type Checker struct {
+	reader k8s.CRReader
+	logger *logrus.Entry
+}
+
+// Check returns *ExceededError if requests exceed any quota of the namespace
+// Usage is read on each call, so caller must serialize check and creation of volumes of the namespace
+func (c *Checker) Check(ctx context.Context, namespace string, requests []Request) error {
+	quotas := &sqcrd.StorageQuotaList{}
+	if err := c.reader.ReadList(ctx, quotas); err != nil {
+		return fmt.Errorf("unable to read storage quotas: %v", err)
+	}
+	var namespaceQuotas []*sqcrd.StorageQuota
+	for i := range quotas.Items {
+		if quotas.Items[i].Spec.Namespace == namespace {
+			namespaceQuotas = append(namespaceQuotas, &quotas.Items[i])
+		}
+	}
+	if len(namespaceQuotas) == 0 {
+		return nil
+	}
+
+	volumes := &volumecrd.VolumeList{}
+	if err := c.reader.ReadList(ctx, volumes); err != nil {
+		return fmt.Errorf("unable to read volumes: %v", err)
+	}
+	// volumes on LogicalVolumeGroup consume drives of the LVG
+	lvgs := &lvgcrd.LogicalVolumeGroupList{}
+	if err := c.reader.ReadList(ctx, lvgs); err != nil {
+		return fmt.Errorf("unable to read logical volume groups: %v", err)
+	}
+	for _, quota := range namespaceQuotas {
+		var requested Request
+		for _, request := range requests {
+			if Matches(quota, namespace, request.StorageClass) {
+				requested.Bytes += request.Bytes
+				requested.Volumes += request.Volumes
+			}
+		}
+		if requested.Bytes == 0 && requested.Volumes == 0 {
+			continue
+		}
+		usage := CalculateUsage(quota, volumes.Items, lvgs.Items)
+		if err := checkLimits(quota, usage, requested); err != nil {
+			c.logger.Infof("Request is rejected: %v", err)
+			return err
+		}
+	}
+	return nil
+}
+
+// checkLimits returns *ExceededError if usage with requested storage exceeds limits of the quota
+func checkLimits(quota *sqcrd.StorageQuota, usage Usage, requested Request) error {
+	if limit := quota.Spec.MaxVolumes; limit != nil && requested.Volumes > 0 && usage.Volumes+requested.Volumes > *limit {
+		return &ExceededError{Quota: quota.Name, Namespace: quota.Spec.Namespace, Resource: "volumes",
+			Used: usage.Volumes, Requested: requested.Volumes, Limit: *limit}
+	}
+	if quota.Spec.MaxBytes != nil && requested.Bytes > 0 {
+		if limit := quota.Spec.MaxBytes.Value(); usage.Bytes+requested.Bytes > limit {
+			return &ExceededError{Quota: quota.Name, Namespace: quota.Spec.Namespace, Resource: "bytes",
+				Used: usage.Bytes, Requested: requested.Bytes, Limit: limit}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quota
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	"github.com/dell/csi-baremetal/api/v1/lvgcrd"
+	sqcrd "github.com/dell/csi-baremetal/api/v1/storagequotacrd"
+	"github.com/dell/csi-baremetal/api/v1/volumecrd"
+	"github.com/dell/csi-baremetal/pkg/base/k8s"
+)
+
+var (
+	tCtx       = context.Background()
+	testLogger = logrus.New()
+	testNS     = "default"
+	otherNS    = "other"
+)
+
+func newQuota(name, namespace, sc string, maxBytes string, maxVolumes int64) *sqcrd.StorageQuota {
+	quota := &sqcrd.StorageQuota{
+		TypeMeta:   metav1.TypeMeta{Kind: apiV1.StorageQuotaKind, APIVersion: apiV1.APIV1Version},
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNS},
+		Spec:       sqcrd.StorageQuotaSpec{Namespace: namespace, StorageClass: sc},
+	}
+	if maxBytes != "" {
+		limit := resource.MustParse(maxBytes)
+		quota.Spec.MaxBytes = &limit
+	}
+	if maxVolumes > 0 {
+		quota.Spec.MaxVolumes = &maxVolumes
+	}
+	return quota
+}
+
+func newVolume(name, namespace, sc, location string, size int64) *volumecrd.Volume {
+	locationType := apiV1.LocationTypeDrive
+	if sc == apiV1.StorageClassHDDLVG {
+		locationType = apiV1.LocationTypeLVM
+	}
+	return &volumecrd.Volume{
+		TypeMeta:   metav1.TypeMeta{Kind: apiV1.VolumeKind, APIVersion: apiV1.APIV1Version},
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: api.Volume{Id: name, StorageClass: sc, Location: location, LocationType: locationType,
+			Size: size, CSIStatus: apiV1.Created},
+	}
+}
+
+func TestCalculateUsage(t *testing.T) {
+	lvgs := []lvgcrd.LogicalVolumeGroup{{
+		ObjectMeta: metav1.ObjectMeta{Name: "lvg"},
+		Spec:       api.LogicalVolumeGroup{Locations: []string{"drive-2", "drive-3"}},
+	}}
+	removed := newVolume("removed", testNS, apiV1.StorageClassHDD, "drive-4", 100)
+	removed.Spec.CSIStatus = apiV1.Removed
+	volumes := []volumecrd.Volume{
+		*newVolume("hdd", testNS, apiV1.StorageClassHDD, "drive-1", 100),
+		*newVolume("lvg-1", testNS, apiV1.StorageClassHDDLVG, "lvg", 10),
+		*newVolume("lvg-2", testNS, apiV1.StorageClassHDDLVG, "lvg", 20),
+		*newVolume("other", otherNS, apiV1.StorageClassHDD, "drive-5", 100),
+		*removed,
+	}
+
+	usage := CalculateUsage(newQuota("all", testNS, "", "", 0), volumes, lvgs)
+	assert.Equal(t, Usage{Bytes: 130, Volumes: 3, Drives: 3}, usage)
+
+	usage = CalculateUsage(newQuota("lvg", testNS, apiV1.StorageClassHDDLVG, "", 0), volumes, lvgs)
+	assert.Equal(t, Usage{Bytes: 30, Volumes: 2, Drives: 2}, usage)
+
+	usage = CalculateUsage(newQuota("ssd", testNS, apiV1.StorageClassSSD, "", 0), volumes, lvgs)
+	assert.Equal(t, Usage{}, usage)
+}
+
+func TestChecker_Check(t *testing.T) {
+	kubeClient, err := k8s.GetFakeKubeClient(testNS, testLogger)
+	assert.Nil(t, err)
+	checker := NewChecker(kubeClient, testLogger.WithField("component", "QuotaChecker"))
+
+	// no quotas
+	assert.Nil(t, checker.Check(tCtx, testNS, []Request{{StorageClass: apiV1.StorageClassHDD, Bytes: 1024, Volumes: 1}}))
+
+	assert.Nil(t, kubeClient.Create(tCtx, newQuota("hdd", testNS, apiV1.StorageClassHDD, "1Ki", 2)))
+	assert.Nil(t, kubeClient.Create(tCtx, newVolume("hdd", testNS, apiV1.StorageClassHDD, "drive-1", 512)))
+
+	assert.Nil(t, checker.Check(tCtx, testNS, []Request{{StorageClass: apiV1.StorageClassHDD, Bytes: 512, Volumes: 1}}))
+	// another storage class and namespace aren't limited
+	assert.Nil(t, checker.Check(tCtx, testNS, []Request{{StorageClass: apiV1.StorageClassSSD, Bytes: 2048, Volumes: 1}}))
+	assert.Nil(t, checker.Check(tCtx, otherNS, []Request{{StorageClass: apiV1.StorageClassHDD, Bytes: 2048, Volumes: 1}}))
+
+	err = checker.Check(tCtx, testNS, []Request{{StorageClass: apiV1.StorageClassHDD, Bytes: 1024, Volumes: 1}})
+	exceeded, ok := err.(*ExceededError)
+	assert.True(t, ok)
+	assert.Equal(t, "bytes", exceeded.Resource)
+	assert.Equal(t, int64(512), exceeded.Used)
+
+	err = checker.Check(tCtx, testNS, []Request{
+		{StorageClass: apiV1.StorageClassHDD, Bytes: 1, Volumes: 1},
+		{StorageClass: apiV1.StorageClassHDD, Bytes: 1, Volumes: 1},
+	})
+	exceeded, ok = err.(*ExceededError)
+	assert.True(t, ok)
+	assert.Equal(t, "volumes", exceeded.Resource)
+
+	// expansion doesn't consume volumes
+	assert.Nil(t, kubeClient.Create(tCtx, newVolume("hdd-2", testNS, apiV1.StorageClassHDD, "drive-2", 256)))
+	assert.Nil(t, checker.Check(tCtx, testNS, []Request{{StorageClass: apiV1.StorageClassHDD, Bytes: 256}}))
+}
@@ -0,0 +1,208 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rpc
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/credentials"
+)
+
+// TLSFiles holds paths to PEM encoded files which are used for mutual TLS
+type TLSFiles struct {
+	// CertFile is a certificate which is presented to the remote side
+	CertFile string
+	// KeyFile is a private key for CertFile
+	KeyFile string
+	// CAFile is a CA bundle which is used for verifying of the remote side certificate
+	CAFile string
+}
+
+// IsSet returns true if all files for mutual TLS were provided
+func (f TLSFiles) IsSet() bool {
+	return f.CertFile != "" && f.KeyFile != "" && f.CAFile != ""
+}
+
+// reloadableTLSCreds is an implementation of credentials.TransportCredentials which reads
+// certificates from the files and reloads them on handshake if one of the files was changed (for example on rotation)
+type reloadableTLSCreds struct {
+	files      TLSFiles
+	isServer   bool
+	serverName string
+
+	sync.Mutex
+	config  *tls.Config
+	modTime time.Time
+
+	log *logrus.Entry
+}
+
+// NewServerTLSCredentials creates TransportCredentials for gRPC server which require and verify client certificates
+// Receives paths to the certificate, key and CA bundle and logrus logger
+// Returns TransportCredentials or error if files could not be loaded
+func NewServerTLSCredentials(files TLSFiles, logger *logrus.Logger) (credentials.TransportCredentials, error) {
+	return newReloadableTLSCreds(files, true, "", logger)
+}
+
+// NewClientTLSCredentials creates TransportCredentials for gRPC client which present client certificate and verify
+// server certificate. If serverName is empty then host from the dial target is used for verification
+// Receives paths to the certificate, key and CA bundle, expected server name and logrus logger
+// Returns TransportCredentials or error if files could not be loaded
+func NewClientTLSCredentials(files TLSFiles, serverName string, logger *logrus.Logger) (credentials.TransportCredentials, error) {
+	return newReloadableTLSCreds(files, false, serverName, logger)
+}
+
+func newReloadableTLSCreds(files TLSFiles, isServer bool, serverName string,
+	logger *logrus.Logger) (*reloadableTLSCreds, error) {
+	if !files.IsSet() {
+		return nil, errors.New("certificate, key and CA files must be provided")
+	}
+	c := &reloadableTLSCreds{
+		files:      files,
+		isServer:   isServer,
+		serverName: serverName,
+		log:        logger.WithField("component", "TLSCredentials"),
+	}
+	modTime, err := c.lastModTime()
+	if err != nil {
+		return nil, err
+	}
+	if c.config, err = c.loadConfig(); err != nil {
+		return nil, err
+	}
+	c.modTime = modTime
+	return c, nil
+}
+
+// ClientHandshake does TLS handshake with the server using the latest loaded certificates
+func (c *reloadableTLSCreds) ClientHandshake(ctx context.Context, authority string,
+	rawConn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	return credentials.NewTLS(c.currentConfig()).ClientHandshake(ctx, authority, rawConn)
+}
+
+// ServerHandshake does TLS handshake with the client using the latest loaded certificates
+func (c *reloadableTLSCreds) ServerHandshake(rawConn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	return credentials.NewTLS(c.currentConfig()).ServerHandshake(rawConn)
+}
+
+// Info returns protocol info of TLS credentials
+func (c *reloadableTLSCreds) Info() credentials.ProtocolInfo {
+	return credentials.NewTLS(c.currentConfig()).Info()
+}
+
+// Clone returns copy of reloadableTLSCreds which shares nothing with the original one
+func (c *reloadableTLSCreds) Clone() credentials.TransportCredentials {
+	c.Lock()
+	defer c.Unlock()
+	return &reloadableTLSCreds{
+		files:      c.files,
+		isServer:   c.isServer,
+		serverName: c.serverName,
+		config:     c.config.Clone(),
+		modTime:    c.modTime,
+		log:        c.log,
+	}
+}
+
+// OverrideServerName overrides server name which is used for verification of the server certificate
+func (c *reloadableTLSCreds) OverrideServerName(serverName string) error {
+	c.Lock()
+	defer c.Unlock()
+	c.serverName = serverName
+	c.config.ServerName = serverName
+	return nil
+}
+
+// currentConfig returns tls config, reloads it if one of the files was modified since the last load
+// if reload fails previous config is used
+func (c *reloadableTLSCreds) currentConfig() *tls.Config {
+	c.Lock()
+	defer c.Unlock()
+
+	modTime, err := c.lastModTime()
+	if err != nil {
+		c.log.Warnf("Unable to check TLS files, continue with previously loaded certificates: %v", err)
+		return c.config
+	}
+	if !modTime.After(c.modTime) {
+		return c.config
+	}
+
+	config, err := c.loadConfig()
+	if err != nil {
+		c.log.Errorf("Unable to reload TLS files, continue with previously loaded certificates: %v", err)
+		return c.config
+	}
+	c.log.Infof("TLS certificates were reloaded from %s", c.files.CertFile)
+	c.config = config
+	c.modTime = modTime
+	return c.config
+}
+
+// loadConfig reads certificates from the files and builds tls config
+func (c *reloadableTLSCreds) loadConfig() (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(c.files.CertFile, c.files.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load key pair %s/%s: %v", c.files.CertFile, c.files.KeyFile, err)
+	}
+	caData, err := ioutil.ReadFile(c.files.CAFile)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read CA file %s: %v", c.files.CAFile, err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caData) {
+		return nil, fmt.Errorf("no certificates were found in CA file %s", c.files.CAFile)
+	}
+
+	config := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+	if c.isServer {
+		config.ClientCAs = pool
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	} else {
+		config.RootCAs = pool
+		config.ServerName = c.serverName
+	}
+	return config, nil
+}
+
+// lastModTime returns the latest modification time among the TLS files
+func (c *reloadableTLSCreds) lastModTime() (time.Time, error) {
+	var latest time.Time
+	for _, f := range []string{c.files.CertFile, c.files.KeyFile, c.files.CAFile} {
+		info, err := os.Stat(f)
+		if err != nil {
+			return latest, err
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest, nil
+}
@@ -0,0 +1,230 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rpc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T, cn string) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: cn},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	assert.Nil(t, err)
+	cert, err := x509.ParseCertificate(der)
+	assert.Nil(t, err)
+	return &testCA{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// writeLeaf issues certificate signed by ca and writes it together with ca bundle to the dir
+func (ca *testCA) writeLeaf(t *testing.T, dir, name string) TLSFiles {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	assert.Nil(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	assert.Nil(t, err)
+
+	files := TLSFiles{
+		CertFile: filepath.Join(dir, name+".crt"),
+		KeyFile:  filepath.Join(dir, name+".key"),
+		CAFile:   filepath.Join(dir, name+"-ca.crt"),
+	}
+	assert.Nil(t, ioutil.WriteFile(files.CertFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	assert.Nil(t, ioutil.WriteFile(files.KeyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600))
+	assert.Nil(t, ioutil.WriteFile(files.CAFile, ca.pem, 0600))
+	return files
+}
+
+func runHealthServer(t *testing.T, sr *ServerRunner) {
+	healthpb.RegisterHealthServer(sr.GRPCServer, health.NewServer())
+	go func() {
+		_ = sr.RunServer()
+	}()
+	// wait for listener
+	endpoint, socket := sr.GetEndpoint()
+	for i := 0; i < 50; i++ {
+		if conn, err := net.Dial(socket, endpoint); err == nil {
+			_ = conn.Close()
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("server on %s wasn't started", sr.Endpoint)
+}
+
+func checkHealth(c *Client) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_, err := healthpb.NewHealthClient(c.GRPCClient).Check(ctx, &healthpb.HealthCheckRequest{}, grpc.WaitForReady(false))
+	return err
+}
+
+func TestTLSFiles_IsSet(t *testing.T) {
+	assert.False(t, TLSFiles{}.IsSet())
+	assert.False(t, TLSFiles{CertFile: "a", KeyFile: "b"}.IsSet())
+	assert.True(t, TLSFiles{CertFile: "a", KeyFile: "b", CAFile: "c"}.IsSet())
+}
+
+func TestNewServerTLSCredentials_Fail(t *testing.T) {
+	_, err := NewServerTLSCredentials(TLSFiles{}, serverLogger)
+	assert.NotNil(t, err)
+
+	_, err = NewServerTLSCredentials(TLSFiles{CertFile: "/no/cert", KeyFile: "/no/key", CAFile: "/no/ca"}, serverLogger)
+	assert.NotNil(t, err)
+}
+
+func TestMutualTLS(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rpc-tls")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	ca := newTestCA(t, "test-ca")
+	serverFiles := ca.writeLeaf(t, dir, "server")
+	clientFiles := ca.writeLeaf(t, dir, "client")
+
+	serverCreds, err := NewServerTLSCredentials(serverFiles, serverLogger)
+	assert.Nil(t, err)
+	sr := NewServerRunner(serverCreds, "tcp://localhost:4244", false, serverLogger)
+	runHealthServer(t, sr)
+	defer sr.StopServer()
+
+	// client with certificate signed by the same CA
+	clientCreds, err := NewClientTLSCredentials(clientFiles, "", clientLogger)
+	assert.Nil(t, err)
+	client, err := NewClient(clientCreds, "tcp://localhost:4244", false, clientLogger)
+	assert.Nil(t, err)
+	assert.Nil(t, checkHealth(client))
+	assert.Nil(t, client.Close())
+
+	// insecure client
+	client, err = NewClient(nil, "tcp://localhost:4244", false, clientLogger)
+	assert.Nil(t, err)
+	assert.NotNil(t, checkHealth(client))
+	assert.Nil(t, client.Close())
+
+	// client with certificate signed by another CA
+	otherFiles := newTestCA(t, "other-ca").writeLeaf(t, dir, "other")
+	otherFiles.CAFile = clientFiles.CAFile
+	otherCreds, err := NewClientTLSCredentials(otherFiles, "", clientLogger)
+	assert.Nil(t, err)
+	client, err = NewClient(otherCreds, "tcp://localhost:4244", false, clientLogger)
+	assert.Nil(t, err)
+	assert.NotNil(t, checkHealth(client))
+	assert.Nil(t, client.Close())
+}
+
+func TestReloadableTLSCreds_Reload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rpc-tls-reload")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	files := newTestCA(t, "ca-1").writeLeaf(t, dir, "leaf")
+	creds, err := newReloadableTLSCreds(files, true, "", serverLogger)
+	assert.Nil(t, err)
+	initial := creds.currentConfig()
+	// nothing changed
+	assert.Equal(t, initial, creds.currentConfig())
+
+	// rotate certificates
+	newTestCA(t, "ca-2").writeLeaf(t, dir, "leaf")
+	future := time.Now().Add(time.Minute)
+	for _, f := range []string{files.CertFile, files.KeyFile, files.CAFile} {
+		assert.Nil(t, os.Chtimes(f, future, future))
+	}
+	reloaded := creds.currentConfig()
+	assert.NotEqual(t, initial, reloaded)
+	assert.NotEqual(t, initial.Certificates[0].Certificate[0], reloaded.Certificates[0].Certificate[0])
+
+	// broken files, previous config should be used
+	assert.Nil(t, ioutil.WriteFile(files.CertFile, []byte("broken"), 0600))
+	future = future.Add(time.Minute)
+	assert.Nil(t, os.Chtimes(files.CertFile, future, future))
+	assert.Equal(t, reloaded, creds.currentConfig())
+}
+
+func TestPeerCredentials(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rpc-peercred")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	// current UID is allowed by default
+	allowedEndpoint := "unix://" + filepath.Join(dir, "allowed.sock")
+	sr := NewServerRunner(NewPeerCredentials(nil, serverLogger), allowedEndpoint, false, serverLogger)
+	runHealthServer(t, sr)
+	defer sr.StopServer()
+
+	client, err := NewClient(nil, allowedEndpoint, false, clientLogger)
+	assert.Nil(t, err)
+	assert.Nil(t, checkHealth(client))
+	assert.Nil(t, client.Close())
+
+	// current UID isn't in the list
+	deniedEndpoint := "unix://" + filepath.Join(dir, "denied.sock")
+	deniedSR := NewServerRunner(NewPeerCredentials([]uint32{uint32(os.Getuid()) + 1}, serverLogger),
+		deniedEndpoint, false, serverLogger)
+	runHealthServer(t, deniedSR)
+	defer deniedSR.StopServer()
+
+	client, err = NewClient(nil, deniedEndpoint, false, clientLogger)
+	assert.Nil(t, err)
+	assert.NotNil(t, checkHealth(client))
+	assert.Nil(t, client.Close())
+}
@@ -17,7 +17,9 @@ limitations under the License.
 package rpc
 
 import (
+	"context"
 	"fmt"
+	"net"
 	"net/url"
 
 	grpc_prometheus "github.com/grpc-ecosystem/go-grpc-prometheus"
@@ -75,6 +77,12 @@ func (c *Client) initClient() error {
 		opts = append(opts, grpc.WithInsecure())
 	}
 
+	if c.isUnixEndpoint() {
+		opts = append(opts, grpc.WithContextDialer(func(ctx context.Context, addr string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, unix, addr)
+		}))
+	}
+
 	if c.metricsEnabled {
 		metricsOpts := []grpc.DialOption{grpc.WithUnaryInterceptor(grpc_prometheus.UnaryClientInterceptor), grpc.WithStreamInterceptor(grpc_prometheus.StreamClientInterceptor)}
 		opts = append(opts, metricsOpts...)
@@ -107,3 +115,9 @@ func (c *Client) GetEndpoint() (string, error) {
 
 	return u.Host, nil
 }
+
+// isUnixEndpoint returns true if client's endpoint has unix scheme
+func (c *Client) isUnixEndpoint() bool {
+	u, err := url.Parse(c.Endpoint)
+	return err == nil && u.Scheme == unix
+}
@@ -0,0 +1,132 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"syscall"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/credentials"
+)
+
+// peerCredAuthType is an auth type that is reported for connections authenticated by peerCreds
+const peerCredAuthType = "peercred"
+
+// PeerCredAuthInfo holds credentials of the process on the other side of unix socket
+type PeerCredAuthInfo struct {
+	credentials.CommonAuthInfo
+	Ucred syscall.Ucred
+}
+
+// AuthType returns type of PeerCredAuthInfo
+func (PeerCredAuthInfo) AuthType() string {
+	return peerCredAuthType
+}
+
+// peerCreds is an implementation of credentials.TransportCredentials for unix sockets
+// which accepts connections only from processes with allowed UIDs (SO_PEERCRED check)
+type peerCreds struct {
+	allowedUIDs map[uint32]bool
+	log         *logrus.Entry
+}
+
+// NewPeerCredentials creates TransportCredentials for gRPC server on unix socket which check UID of the peer process
+// Receives list of allowed UIDs (if empty - only UID of the current process is allowed) and logrus logger
+// Returns TransportCredentials
+func NewPeerCredentials(allowedUIDs []uint32, logger *logrus.Logger) credentials.TransportCredentials {
+	if len(allowedUIDs) == 0 {
+		allowedUIDs = []uint32{uint32(os.Getuid())}
+	}
+	allowed := make(map[uint32]bool, len(allowedUIDs))
+	for _, uid := range allowedUIDs {
+		allowed[uid] = true
+	}
+	return &peerCreds{
+		allowedUIDs: allowed,
+		log:         logger.WithField("component", "PeerCredentials"),
+	}
+}
+
+// ClientHandshake doesn't perform any checks on client side, connection is returned as is
+func (p *peerCreds) ClientHandshake(_ context.Context, _ string,
+	rawConn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	return rawConn, PeerCredAuthInfo{CommonAuthInfo: credentials.CommonAuthInfo{SecurityLevel: credentials.NoSecurity}}, nil
+}
+
+// ServerHandshake reads credentials of the peer process and rejects connection if its UID isn't allowed
+func (p *peerCreds) ServerHandshake(rawConn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	ucred, err := getPeerCred(rawConn)
+	if err != nil {
+		p.log.Errorf("Unable to read peer credentials: %v", err)
+		return nil, nil, err
+	}
+	if !p.allowedUIDs[ucred.Uid] {
+		p.log.Warnf("Connection from process with PID %d, UID %d was rejected", ucred.Pid, ucred.Uid)
+		return nil, nil, fmt.Errorf("peer with UID %d isn't allowed", ucred.Uid)
+	}
+	return rawConn, PeerCredAuthInfo{
+		CommonAuthInfo: credentials.CommonAuthInfo{SecurityLevel: credentials.NoSecurity},
+		Ucred:          *ucred,
+	}, nil
+}
+
+// Info returns protocol info of peerCreds
+func (p *peerCreds) Info() credentials.ProtocolInfo {
+	return credentials.ProtocolInfo{SecurityProtocol: peerCredAuthType}
+}
+
+// Clone returns copy of peerCreds
+func (p *peerCreds) Clone() credentials.TransportCredentials {
+	allowed := make(map[uint32]bool, len(p.allowedUIDs))
+	for uid := range p.allowedUIDs {
+		allowed[uid] = true
+	}
+	return &peerCreds{allowedUIDs: allowed, log: p.log}
+}
+
+// OverrideServerName isn't applicable for unix sockets
+func (p *peerCreds) OverrideServerName(string) error {
+	return nil
+}
+
+// getPeerCred reads SO_PEERCRED option of unix socket
+func getPeerCred(conn net.Conn) (*syscall.Ucred, error) {
+	unixConn, ok := conn.(*net.UnixConn)
+	if !ok {
+		return nil, errors.New("peer credentials check is supported only for unix sockets")
+	}
+	rawConn, err := unixConn.SyscallConn()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		ucred   *syscall.Ucred
+		credErr error
+	)
+	if err = rawConn.Control(func(fd uintptr) {
+		ucred, credErr = syscall.GetsockoptUcred(int(fd), syscall.SOL_SOCKET, syscall.SO_PEERCRED)
+	}); err != nil {
+		return nil, err
+	}
+	return ucred, credErr
+}
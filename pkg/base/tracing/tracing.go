@@ -0,0 +1,171 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package tracing contains helpers for OpenTelemetry tracing of volume operations.
+// Trace context is passed between controller and node through annotations of custom resources
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/api/global"
+	"go.opentelemetry.io/otel/api/kv"
+	"go.opentelemetry.io/otel/api/standard"
+	"go.opentelemetry.io/otel/api/trace"
+	"go.opentelemetry.io/otel/exporters/otlp"
+	"go.opentelemetry.io/otel/exporters/trace/stdout"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"google.golang.org/grpc/codes"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+)
+
+const (
+	// ExporterNone disables tracing
+	ExporterNone = ""
+	// ExporterOTLP exports spans to OpenTelemetry collector through OTLP gRPC protocol
+	ExporterOTLP = "otlp"
+	// ExporterStdout writes spans to stdout as JSON
+	ExporterStdout = "stdout"
+
+	tracerName = "csi-baremetal"
+)
+
+const (
+	// VolumeIDKey is a span attribute with ID of the volume
+	VolumeIDKey = kv.Key("volume.id")
+	// CSIStatusKey is a span attribute with CSI status of the volume
+	CSIStatusKey = kv.Key("volume.csi_status")
+)
+
+// Init configures global trace provider which exports spans of the service with the given exporter,
+// endpoint is an address of OpenTelemetry collector which is used by OTLP exporter, empty for the default one.
+// Returns function which flushes remaining spans and stops exporter or error if exporter can't be configured
+func Init(exporter, endpoint, serviceName string) (func(), error) {
+	var (
+		processor sdktrace.SpanProcessor
+		stopFn    = func() {}
+	)
+	switch exporter {
+	case ExporterNone:
+		return stopFn, nil
+	case ExporterStdout:
+		exp, err := stdout.NewExporter(stdout.Options{})
+		if err != nil {
+			return nil, err
+		}
+		processor = sdktrace.NewSimpleSpanProcessor(exp)
+	case ExporterOTLP:
+		opts := []otlp.ExporterOption{otlp.WithInsecure()}
+		if endpoint != "" {
+			opts = append(opts, otlp.WithAddress(endpoint))
+		}
+		exp, err := otlp.NewExporter(opts...)
+		if err != nil {
+			return nil, err
+		}
+		if processor, err = sdktrace.NewBatchSpanProcessor(exp); err != nil {
+			_ = exp.Stop()
+			return nil, err
+		}
+		stopFn = func() { _ = exp.Stop() }
+	default:
+		return nil, fmt.Errorf("unknown tracing exporter %q, supported are %q and %q", exporter, ExporterOTLP, ExporterStdout)
+	}
+
+	provider, err := sdktrace.NewProvider(
+		sdktrace.WithResource(resource.New(standard.ServiceNameKey.String(serviceName))))
+	if err != nil {
+		return nil, err
+	}
+	provider.RegisterSpanProcessor(processor)
+	global.SetTraceProvider(provider)
+
+	return func() {
+		// flushes queued spans before exporter is stopped
+		provider.UnregisterSpanProcessor(processor)
+		stopFn()
+	}, nil
+}
+
+// StartSpan starts span with the given name as a child of the span from the context if any
+// Returns context with the started span and the span itself which must be ended by the caller
+func StartSpan(ctx context.Context, name string, attrs ...kv.KeyValue) (context.Context, trace.Span) {
+	return global.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// StartChildSpan starts span only if the context holds span of the traced operation, either local one or extracted
+// from the custom resource, otherwise span isn't recorded. It's used for frequent calls, e.g. commands, CR updates or
+// reconciliations, which aren't worth separate traces
+func StartChildSpan(ctx context.Context, name string, attrs ...kv.KeyValue) (context.Context, trace.Span) {
+	if ctx == nil || (!trace.SpanFromContext(ctx).SpanContext().IsValid() && !trace.RemoteSpanContextFromContext(ctx).IsValid()) {
+		return ctx, trace.NoopSpan{}
+	}
+	return StartSpan(ctx, name, attrs...)
+}
+
+// EndSpan records error in the span if any and ends the span
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(context.Background(), err, trace.WithErrorStatus(codes.Internal))
+	}
+	span.End()
+}
+
+// Detach returns background context with the span from the given context.
+// It's used for operations which must not be cancelled together with the request but belong to its trace
+func Detach(ctx context.Context) context.Context {
+	return trace.ContextWithSpan(context.Background(), trace.SpanFromContext(ctx))
+}
+
+// Inject stores trace context of the span from the context in the annotations of the object,
+// nothing is stored if tracing is disabled
+func Inject(ctx context.Context, obj metav1.Object) {
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	trace.TraceContext{}.Inject(ctx, annotationSupplier(annotations))
+	if len(annotations) > 0 {
+		obj.SetAnnotations(annotations)
+	}
+}
+
+// Extract returns context with the remote span stored in the annotations of the object by Inject,
+// spans started from such context continue the trace of the operation which updated the object
+func Extract(ctx context.Context, obj metav1.Object) context.Context {
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		return ctx
+	}
+	return trace.TraceContext{}.Extract(ctx, annotationSupplier(annotations))
+}
+
+// annotationSupplier stores W3C trace context headers as annotations with apiV1.TraceAnnotationPrefix prefix
+type annotationSupplier map[string]string
+
+// Get returns value of the trace context header
+func (s annotationSupplier) Get(key string) string {
+	return s[apiV1.TraceAnnotationPrefix+key]
+}
+
+// Set stores value of the trace context header
+func (s annotationSupplier) Set(key, value string) {
+	s[apiV1.TraceAnnotationPrefix+key] = value
+}
@@ -0,0 +1,108 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/api/global"
+	"go.opentelemetry.io/otel/api/trace"
+	export "go.opentelemetry.io/otel/sdk/export/trace"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	"github.com/dell/csi-baremetal/api/v1/volumecrd"
+)
+
+// spanRecorder keeps ended spans
+type spanRecorder struct {
+	sync.Mutex
+	spans []*export.SpanData
+}
+
+func (r *spanRecorder) ExportSpan(_ context.Context, span *export.SpanData) {
+	r.Lock()
+	defer r.Unlock()
+	r.spans = append(r.spans, span)
+}
+
+func setupRecorder(t *testing.T) *spanRecorder {
+	recorder := &spanRecorder{}
+	provider, err := sdktrace.NewProvider(sdktrace.WithSyncer(recorder))
+	assert.Nil(t, err)
+	global.SetTraceProvider(provider)
+	return recorder
+}
+
+func TestInjectExtract(t *testing.T) {
+	recorder := setupRecorder(t)
+
+	// tracing context isn't stored without span
+	volume := &volumecrd.Volume{}
+	Inject(context.Background(), volume)
+	assert.Nil(t, volume.Annotations)
+
+	ctx, parent := StartSpan(context.Background(), "CreateVolume", VolumeIDKey.String("pvc-1"))
+	Inject(ctx, volume)
+	assert.NotEmpty(t, volume.Annotations[apiV1.TraceAnnotationPrefix+"traceparent"])
+
+	_, child := StartChildSpan(Extract(context.Background(), volume), "Reconcile")
+	EndSpan(child, errors.New("error"))
+	parent.End()
+
+	assert.Equal(t, 2, len(recorder.spans))
+	assert.Equal(t, "Reconcile", recorder.spans[0].Name)
+	assert.Equal(t, parent.SpanContext().TraceID, recorder.spans[0].SpanContext.TraceID)
+	assert.Equal(t, parent.SpanContext().SpanID, recorder.spans[0].ParentSpanID)
+	assert.Equal(t, 1, len(recorder.spans[0].MessageEvents))
+}
+
+func TestStartChildSpan(t *testing.T) {
+	recorder := setupRecorder(t)
+
+	// span isn't recorded without parent
+	_, span := StartChildSpan(context.Background(), "RunCmd")
+	assert.Equal(t, trace.NoopSpan{}, span)
+	_, span = StartChildSpan(Extract(context.Background(), &volumecrd.Volume{}), "RunCmd")
+	assert.Equal(t, trace.NoopSpan{}, span)
+
+	ctx, parent := StartSpan(context.Background(), "CreateVolume")
+	// detached context isn't cancelled with the parent one
+	cancelCtx, cancelFn := context.WithCancel(ctx)
+	cancelFn()
+	detached := Detach(cancelCtx)
+	assert.Nil(t, detached.Err())
+
+	_, span = StartChildSpan(detached, "RunCmd")
+	span.End()
+	parent.End()
+	assert.Equal(t, 2, len(recorder.spans))
+	assert.Equal(t, parent.SpanContext().SpanID, recorder.spans[0].ParentSpanID)
+}
+
+func TestInit(t *testing.T) {
+	stopFn, err := Init(ExporterNone, "", "csi-baremetal-node")
+	assert.Nil(t, err)
+	stopFn()
+
+	_, err = Init("jaeger", "", "csi-baremetal-node")
+	assert.NotNil(t, err)
+}
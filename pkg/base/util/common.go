@@ -106,6 +106,20 @@ func GetSubStorageClass(sc string) string {
 	}
 }
 
+// GetLVGStorageClass return LVM based storage class for underlying storage class, or empty string
+func GetLVGStorageClass(sc string) string {
+	switch sc {
+	case api.StorageClassHDD:
+		return api.StorageClassHDDLVG
+	case api.StorageClassSSD:
+		return api.StorageClassSSDLVG
+	case api.StorageClassNVMe:
+		return api.StorageClassNVMeLVG
+	default:
+		return ""
+	}
+}
+
 // IsStorageClassLVG returns whether provided sc relates to LVG or no
 func IsStorageClassLVG(sc string) bool {
 	return sc == api.StorageClassHDDLVG ||
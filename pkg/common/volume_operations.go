@@ -27,7 +27,9 @@ import (
 	"github.com/sirupsen/logrus"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	coreV1 "k8s.io/api/core/v1"
 	k8sError "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/utils/keymutex"
 
 	api "github.com/dell/csi-baremetal/api/generated/v1"
 	apiV1 "github.com/dell/csi-baremetal/api/v1"
@@ -40,6 +42,8 @@ import (
 	"github.com/dell/csi-baremetal/pkg/base/capacityplanner"
 	fc "github.com/dell/csi-baremetal/pkg/base/featureconfig"
 	"github.com/dell/csi-baremetal/pkg/base/k8s"
+	"github.com/dell/csi-baremetal/pkg/base/quota"
+	"github.com/dell/csi-baremetal/pkg/base/tracing"
 	"github.com/dell/csi-baremetal/pkg/base/util"
 	"github.com/dell/csi-baremetal/pkg/metrics"
 	"github.com/prometheus/client_golang/prometheus"
@@ -62,6 +66,9 @@ type VolumeOperationsImpl struct {
 	k8sClient              *k8s.KubeClient
 	capacityManagerBuilder capacityplanner.CapacityManagerBuilder
 	crHelper               *k8s.CRHelper
+	quotaChecker           *quota.Checker
+	// quotaMu serializes quota check and volume creation or expansion per namespace
+	quotaMu keymutex.KeyMutex
 
 	metrics        metrics.Statistic
 	cache          cache.Interface
@@ -86,6 +93,8 @@ func NewVolumeOperationsImpl(k8sClient *k8s.KubeClient, logger *logrus.Logger, c
 	vo := &VolumeOperationsImpl{
 		k8sClient:              k8sClient,
 		crHelper:               k8s.NewCRHelper(k8sClient, logger),
+		quotaChecker:           quota.NewChecker(k8sClient, logger.WithField("component", "QuotaChecker")),
+		quotaMu:                keymutex.NewHashed(0),
 		acProvider:             NewACOperationsImpl(k8sClient, logger),
 		log:                    logger.WithField("component", "VolumeOperationsImpl"),
 		featureChecker:         featureConf,
@@ -106,8 +115,10 @@ func (vo *VolumeOperationsImpl) CreateVolume(ctx context.Context, v api.Volume)
 		"method":   "CreateVolume",
 		"volumeID": v.Id,
 	})
+	ctx, span := tracing.StartSpan(ctx, "VolumeOperations.CreateVolume", tracing.VolumeIDKey.String(v.Id))
+	defer span.End()
 	var (
-		ctxWithID = context.WithValue(context.Background(), base.RequestUUID, v.Id)
+		ctxWithID = context.WithValue(tracing.Detach(ctx), base.RequestUUID, v.Id)
 		volumeCR  = &volumecrd.Volume{}
 		err       error
 	)
@@ -178,29 +189,27 @@ func (vo *VolumeOperationsImpl) handleVolumeCreation(ctx context.Context, log *l
 			fmt.Sprintf("there is no suitable drive for volume %s", v.Id))
 	}
 
-	if ac.Spec.StorageClass != v.StorageClass && util.IsStorageClassLVG(v.StorageClass) {
+	sc, allocatedBytes := volumePlacement(v, ac)
+	// volume CR must be created before quota of the namespace is checked for another volume
+	unlock := vo.lockNamespaceQuota(log, podNamespace)
+	defer unlock()
+	// quota is checked before underlying storage is modified
+	if err = vo.checkStorageQuota(ctx, podNamespace, quota.Request{StorageClass: sc, Bytes: allocatedBytes, Volumes: 1}); err != nil {
+		return nil, err
+	}
+
+	if util.IsStorageClassLVG(sc) && !util.IsStorageClassLVG(ac.Spec.StorageClass) {
 		// AC needs to be converted to LogicalVolumeGroup AC, LogicalVolumeGroup doesn't exist yet
-		if ac = vo.acProvider.RecreateACToLVGSC(ctx, v.StorageClass, *ac); ac == nil {
+		if ac = vo.acProvider.RecreateACToLVGSC(ctx, sc, *ac); ac == nil {
 			return nil, status.Errorf(codes.Internal,
-				"unable to prepare underlying storage for storage class %s", v.StorageClass)
+				"unable to prepare underlying storage for storage class %s", sc)
 		}
 	}
 	log.Infof("AC %v was selected", ac)
 
-	// if sc was parsed as an ANY then we can choose AC with any storage class and then
-	// volume should be created with that particular SC
-	var (
-		sc             = ac.Spec.StorageClass
-		allocatedBytes int64
-		locationType   string
-	)
-
+	locationType := apiV1.LocationTypeDrive
 	if util.IsStorageClassLVG(sc) {
-		allocatedBytes = capacityplanner.AlignSizeByPE(v.Size)
 		locationType = apiV1.LocationTypeLVM
-	} else {
-		allocatedBytes = ac.Spec.Size
-		locationType = apiV1.LocationTypeDrive
 	}
 
 	// create volume CR
@@ -220,6 +229,13 @@ func (vo *VolumeOperationsImpl) handleVolumeCreation(ctx context.Context, log *l
 		Type:              v.Type,
 	}
 	volumeCR := vo.k8sClient.ConstructVolumeCR(v.Id, podNamespace, apiVolume)
+	volumeCR.Annotations = map[string]string{apiV1.VolumeAnnotationPVCName: reservationName}
+	// node continues the trace of volume creation
+	tracing.Inject(ctx, volumeCR)
+	// volume group is used for spreading volumes of the same workload across failure domains
+	if group := vo.getVolumeGroup(ctx, log, podNamespace, reservationName, podReservation); group != "" {
+		volumeCR.Labels = map[string]string{apiV1.VolumeGroupKey: group}
+	}
 
 	if err = vo.k8sClient.CreateCR(ctx, v.Id, volumeCR); err != nil {
 		log.Errorf("Unable to create CR, error: %v", err)
@@ -307,6 +323,18 @@ func (vo *VolumeOperationsImpl) getVolumeReservation(ctx context.Context, log *l
 	return podReservation, requestNum, nil
 }
 
+// getVolumeGroup returns volume group from PVC annotation or from reservation which holds pod label
+func (vo *VolumeOperationsImpl) getVolumeGroup(ctx context.Context, log *logrus.Entry, namespace string,
+	pvcName string, reservation *acrcrd.AvailableCapacityReservation) string {
+	pvc := &coreV1.PersistentVolumeClaim{}
+	if err := vo.k8sClient.ReadCR(ctx, pvcName, namespace, pvc); err != nil {
+		log.Debugf("Unable to read PVC %s: %v", pvcName, err)
+	} else if group, ok := pvc.Annotations[apiV1.VolumeGroupKey]; ok {
+		return group
+	}
+	return reservation.Annotations[apiV1.VolumeGroupKey]
+}
+
 func (vo *VolumeOperationsImpl) deleteVolumeReservation(ctx context.Context,
 	reservation *acrcrd.AvailableCapacityReservation, number int) error {
 	// release reservation if exists
@@ -331,6 +359,8 @@ func (vo *VolumeOperationsImpl) DeleteVolume(ctx context.Context, volumeID strin
 		"volumeID": volumeID,
 	})
 	ll.Info("Processing")
+	ctx, span := tracing.StartSpan(ctx, "VolumeOperations.DeleteVolume", tracing.VolumeIDKey.String(volumeID))
+	defer span.End()
 
 	var (
 		volumeCR = &volumecrd.Volume{}
@@ -369,6 +399,7 @@ func (vo *VolumeOperationsImpl) DeleteVolume(ctx context.Context, volumeID strin
 	}
 
 	volumeCR.Spec.CSIStatus = apiV1.Removing
+	tracing.Inject(ctx, volumeCR)
 	return vo.k8sClient.UpdateCR(ctx, volumeCR)
 }
 
@@ -508,6 +539,8 @@ func (vo *VolumeOperationsImpl) ExpandVolume(ctx context.Context, volume *volume
 		"method":   "ExpandVolume",
 		"volumeID": volume.Spec.Id,
 	})
+	ctx, span := tracing.StartSpan(ctx, "VolumeOperations.ExpandVolume", tracing.VolumeIDKey.String(volume.Spec.Id))
+	defer span.End()
 	currStatus := volume.Spec.CSIStatus
 	switch currStatus {
 	case apiV1.Resizing, apiV1.Resized:
@@ -524,6 +557,12 @@ func (vo *VolumeOperationsImpl) ExpandVolume(ctx context.Context, volume *volume
 		}
 
 		acSize := requiredBytes - volume.Spec.Size
+		unlock := vo.lockNamespaceQuota(ll, volume.Namespace)
+		defer unlock()
+		if err := vo.checkStorageQuota(ctx, volume.Namespace,
+			quota.Request{StorageClass: volume.Spec.StorageClass, Bytes: acSize}); err != nil {
+			return err
+		}
 		if capacity.Spec.Size < acSize {
 			return status.Error(codes.OutOfRange,
 				fmt.Sprintf("Not enough capacity to expand volume: requested - %d, available - %d", requiredBytes, capacity.Spec.Size))
@@ -541,6 +580,7 @@ func (vo *VolumeOperationsImpl) ExpandVolume(ctx context.Context, volume *volume
 		volume.Annotations[apiV1.VolumePreviousCapacity] = strconv.FormatInt(volume.Spec.Size, 10)
 		volume.Spec.CSIStatus = apiV1.Resizing
 		volume.Spec.Size = requiredBytes
+		tracing.Inject(ctx, volume)
 
 		if err := vo.k8sClient.UpdateCRWithAttempts(ctx, volume, 5); err != nil {
 			ll.Errorf("Failed to update volume, error: %v", err)
@@ -625,7 +665,7 @@ func (vo *VolumeOperationsImpl) deleteLVGIfVolumesNotExistOrUpdate(lvg *lvgcrd.L
 	drivesUUIDs := vo.k8sClient.GetSystemDriveUUIDs()
 	// if only one volume remains - remove AC first and LogicalVolumeGroup then
 	if len(lvg.Spec.VolumeRefs) == 1 && !util.ContainsString(drivesUUIDs, lvg.Spec.Locations[0]) {
-		if err := vo.k8sClient.DeleteCR(context.Background(), ac); err != nil {
+		if err := vo.crHelper.DeleteConsumedAC(context.Background(), ac); err != nil {
 			log.Errorf("Unable to delete AC %s: %v", ac.Name, err)
 			return false, err
 		}
@@ -661,3 +701,45 @@ func (vo *VolumeOperationsImpl) fillCache() {
 		vo.cache.Set(volume.Name, volume.Namespace)
 	}
 }
+
+// volumePlacement returns storage class and size which volume has on the AC
+// if sc was parsed as an ANY then we can choose AC with any storage class and then
+// volume should be created with that particular SC
+func volumePlacement(v api.Volume, ac *accrd.AvailableCapacity) (string, int64) {
+	sc := ac.Spec.StorageClass
+	if util.IsStorageClassLVG(v.StorageClass) && !util.IsStorageClassLVG(sc) {
+		// AC might be reserved with fallback storage class, LogicalVolumeGroup must match the drive type
+		sc = v.StorageClass
+		if lvgSC := util.GetLVGStorageClass(ac.Spec.StorageClass); lvgSC != "" {
+			sc = lvgSC
+		}
+	}
+	if util.IsStorageClassLVG(sc) {
+		return sc, capacityplanner.AlignSizeByPE(v.Size)
+	}
+	return sc, ac.Spec.Size
+}
+
+// lockNamespaceQuota locks quota of the namespace, returns function which unlocks it
+func (vo *VolumeOperationsImpl) lockNamespaceQuota(log *logrus.Entry, namespace string) func() {
+	vo.quotaMu.LockKey(namespace)
+	return func() {
+		if err := vo.quotaMu.UnlockKey(namespace); err != nil {
+			log.Warnf("Unable to unlock quota of namespace %s: %v", namespace, err)
+		}
+	}
+}
+
+// checkStorageQuota returns ResourceExhausted error if request exceeds StorageQuota of the namespace
+func (vo *VolumeOperationsImpl) checkStorageQuota(ctx context.Context, namespace string, request quota.Request) error {
+	err := vo.quotaChecker.Check(ctx, namespace, []quota.Request{request})
+	switch err.(type) {
+	case nil:
+		return nil
+	case *quota.ExceededError:
+		return status.Error(codes.ResourceExhausted, err.Error())
+	default:
+		vo.log.WithField("method", "checkStorageQuota").Errorf("Unable to check storage quota: %v", err)
+		return status.Error(codes.Internal, "unable to check storage quota")
+	}
+}
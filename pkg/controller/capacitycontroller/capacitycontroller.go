@@ -3,6 +3,7 @@ package capacitycontroller
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"strconv"
 	"time"
 
@@ -19,6 +20,7 @@ import (
 
 	api "github.com/dell/csi-baremetal/api/generated/v1"
 	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	accrd "github.com/dell/csi-baremetal/api/v1/availablecapacitycrd"
 	"github.com/dell/csi-baremetal/api/v1/drivecrd"
 	"github.com/dell/csi-baremetal/api/v1/lvgcrd"
 	"github.com/dell/csi-baremetal/pkg/base"
@@ -32,23 +34,31 @@ import (
 // RequeueDriveTime is time between drives reconciliation
 const RequeueDriveTime = time.Second * 30
 
+// eventRecorder interface for sending events
+type eventRecorder interface {
+	Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{})
+}
+
 // Controller reconciles drive custom resource
 type Controller struct {
 	client   *k8s.KubeClient
 	crHelper *k8s.CRHelper
 	// CRHelper instance which reads from cache
 	cachedCrHelper *k8s.CRHelper
+	eventRecorder  eventRecorder
 	log            *logrus.Entry
 }
 
 // NewCapacityController creates new instance of Controller structure
-// Receives an instance of base.KubeClient and logrus logger
+// Receives an instance of base.KubeClient, event recorder and logrus logger
 // Returns an instance of Controller
-func NewCapacityController(client *k8s.KubeClient, k8sCache k8s.CRReader, log *logrus.Logger) *Controller {
+func NewCapacityController(client *k8s.KubeClient, k8sCache k8s.CRReader, eventRecorder eventRecorder,
+	log *logrus.Logger) *Controller {
 	return &Controller{
 		client:         client,
 		crHelper:       k8s.NewCRHelper(client, log),
 		cachedCrHelper: k8s.NewCRHelper(client, log).SetReader(k8sCache),
+		eventRecorder:  eventRecorder,
 		log:            log.WithField("component", "Controller"),
 	}
 }
@@ -58,6 +68,7 @@ func (d *Controller) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&drivecrd.Drive{}).
 		Watches(&source.Kind{Type: &lvgcrd.LogicalVolumeGroup{}}, &handler.EnqueueRequestForObject{}).
+		Watches(&source.Kind{Type: &accrd.AvailableCapacity{}}, &handler.EnqueueRequestForObject{}).
 		WithEventFilter(predicate.Funcs{
 			DeleteFunc: func(e event.DeleteEvent) bool {
 				return false
@@ -69,7 +80,7 @@ func (d *Controller) SetupWithManager(mgr ctrl.Manager) error {
 		Complete(d)
 }
 
-// Reconcile reconciles Drive custom resources
+// Reconcile reconciles Drive, LogicalVolumeGroup and AvailableCapacity custom resources
 func (d *Controller) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 	defer metricsC.ReconcileDuration.EvaluateDurationForType("csicontroller_drive_controller")()
 	resourceName := req.Name
@@ -83,13 +94,17 @@ func (d *Controller) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 		return d.reconcileDrive(ctx, drive)
 	}
 	lvg := &lvgcrd.LogicalVolumeGroup{}
-	if err := d.client.ReadCR(ctx, resourceName, "", lvg); err != nil {
+	if err := d.client.ReadCR(ctx, resourceName, "", lvg); err == nil {
+		return d.reconcileLVG(lvg)
+	}
+	ac := &accrd.AvailableCapacity{}
+	if err := d.client.ReadCR(ctx, resourceName, "", ac); err != nil {
 		if k8serrors.IsNotFound(err) {
-			log.Warnf("Failed to read LVG and Drive CRs, resource with name %s is not found", resourceName)
+			log.Warnf("Failed to read LVG, Drive and AC CRs, resource with name %s is not found", resourceName)
 		}
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
-	return d.reconcileLVG(lvg)
+	return d.reconcileAC(ctx, ac)
 }
 
 // reconcileLVG perform logic for LVG reconciliation
@@ -106,6 +121,10 @@ func (d *Controller) reconcileLVG(lvg *lvgcrd.LogicalVolumeGroup) (ctrl.Result,
 	if status == apiV1.Failed || health != apiV1.HealthGood {
 		return ctrl.Result{}, d.resetACSizeOfLVG(name)
 	}
+	// AC of LVG is resumed by drive reconciliation when maintenance is over
+	if d.isLVGUnderMaintenance(lvg) {
+		return ctrl.Result{}, d.suspendACOfLVG(name)
+	}
 	// If LVG is already presented on a machine but doesn't have AC, try to create its AC using annotation with
 	// VG free space
 	size, err := getFreeSpaceFromLVGAnnotation(lvg.Annotations)
@@ -125,30 +144,46 @@ func (d *Controller) reconcileDrive(ctx context.Context, drive *drivecrd.Drive)
 		health = drive.Spec.GetHealth()
 		status = drive.Spec.GetStatus()
 	)
+	if err := d.handleDriveMaintenance(ctx, drive); err != nil {
+		d.log.WithField("method", "reconcileDrive").
+			Errorf("Failed to handle maintenance mode of drive %s: %v", drive.Name, err)
+		return ctrl.Result{}, err
+	}
 	switch {
 	case health != apiV1.HealthGood || status != apiV1.DriveStatusOnline:
 		return d.handleInaccessibleDrive(ctx, drive.Spec)
 	default:
-		return d.createOrUpdateCapacity(ctx, drive.Spec)
+		return d.createOrUpdateCapacity(ctx, drive, isExcludedFromScheduling(drive))
 	}
 }
 
-// createOrUpdateCapacity tries to create AC for drive or update its size if AC already exists
-func (d *Controller) createOrUpdateCapacity(ctx context.Context, drive api.Drive) (ctrl.Result, error) {
+// isExcludedFromScheduling returns true if drive mustn't be offered as AvailableCapacity even if it is clean,
+// for example because of non-compliant firmware, maintenance mode or LVG of the replaced drive being restored on it
+func isExcludedFromScheduling(drive *drivecrd.Drive) bool {
+	firmware := apiV1.FindCondition(drive.Status.Conditions, apiV1.ConditionFirmwareCompliant)
+	_, restoring := drive.Annotations[apiV1.DriveAnnotationReplacementRestoring]
+	return (firmware != nil && firmware.Reason == apiV1.FirmwareExcluded) || isUnderMaintenance(drive) || restoring
+}
+
+// createOrUpdateCapacity tries to create AC for drive or update its size and drive attributes if AC already exists
+// if excluded is true AC size is set to 0
+func (d *Controller) createOrUpdateCapacity(ctx context.Context, driveCR *drivecrd.Drive, excluded bool) (ctrl.Result, error) {
 	log := d.log.WithFields(logrus.Fields{
 		"method": "createOrUpdateCapacity",
 	})
+	drive := driveCR.Spec
 	driveUUID := drive.GetUUID()
 	size := drive.GetSize()
-	// if drive is not clean, size is 0
-	if !drive.GetIsClean() {
+	// if drive is not clean or excluded from scheduling, size is 0
+	if !drive.GetIsClean() || excluded {
 		size = 0
 	}
 	ac, err := d.cachedCrHelper.GetACByLocation(driveUUID)
 	switch {
 	case err == nil:
 		// If ac is exists, update its size to drive size
-		if ac.Spec.Size != size {
+		attributesChanged := capacityplanner.SetDriveAttributes(ac, driveCR)
+		if ac.Spec.Size != size || attributesChanged {
 			ac.Spec.Size = size
 			if err := d.client.Update(context.WithValue(ctx, base.RequestUUID, ac.Name), ac); err != nil {
 				log.Errorf("Error during update AvailableCapacity request to k8s: %v, error: %v", ac, err)
@@ -158,7 +193,7 @@ func (d *Controller) createOrUpdateCapacity(ctx context.Context, drive api.Drive
 		return ctrl.Result{RequeueAfter: RequeueDriveTime}, nil
 	case err == errTypes.ErrorNotFound:
 		name := uuid.New().String()
-		if lvg, err := d.crHelper.GetLVGByDrive(ctx, driveUUID); err != nil || lvg != nil {
+		if lvg, err := d.crHelper.GetLVGContainingDrive(ctx, driveUUID); err != nil || lvg != nil {
 			return ctrl.Result{}, err
 		}
 		capacity := &api.AvailableCapacity{
@@ -168,6 +203,7 @@ func (d *Controller) createOrUpdateCapacity(ctx context.Context, drive api.Drive
 			NodeId:       drive.GetNodeId(),
 		}
 		newAC := d.client.ConstructACCR(name, *capacity)
+		capacityplanner.SetDriveAttributes(newAC, driveCR)
 		if err := d.client.CreateCR(context.WithValue(ctx, base.RequestUUID, name), name, newAC); err != nil {
 			log.Errorf("Error during create AvailableCapacity request to k8s: %v, error: %v",
 				capacity, err)
@@ -289,11 +325,15 @@ func (d *Controller) filterUpdateEvent(old runtime.Object, new runtime.Object) b
 		newDrive *drivecrd.Drive
 		ok       bool
 	)
+	if newAC, ok := new.(*accrd.AvailableCapacity); ok {
+		return filterAC(newAC)
+	}
 	if oldDrive, ok = old.(*drivecrd.Drive); !ok {
 		return handleLVGObjects(old, new)
 	}
 	if newDrive, ok = new.(*drivecrd.Drive); ok {
-		return filter(oldDrive.Spec, newDrive.Spec)
+		return filter(oldDrive.Spec, newDrive.Spec) || isExcludedFromScheduling(oldDrive) != isExcludedFromScheduling(newDrive) ||
+			!reflect.DeepEqual(oldDrive.Labels, newDrive.Labels)
 	}
 	return true
 }
@@ -34,6 +34,7 @@ import (
 	"github.com/dell/csi-baremetal/api/v1/lvgcrd"
 	"github.com/dell/csi-baremetal/pkg/base/k8s"
 	"github.com/dell/csi-baremetal/pkg/base/util"
+	"github.com/dell/csi-baremetal/pkg/mocks"
 )
 
 var (
@@ -129,7 +130,7 @@ var (
 )
 
 func Test_NewLVGController(t *testing.T) {
-	c := NewCapacityController(nil, nil, testLogger)
+	c := NewCapacityController(nil, nil, new(mocks.NoOpRecorder), testLogger)
 	assert.NotNil(t, c)
 }
 
@@ -137,7 +138,7 @@ func TestController_ReconcileDrive(t *testing.T) {
 	t.Run("Drive is good, AC is not present", func(t *testing.T) {
 		kubeClient, err := k8s.GetFakeKubeClient(ns, testLogger)
 		assert.Nil(t, err)
-		controller := NewCapacityController(kubeClient, kubeClient, testLogger)
+		controller := NewCapacityController(kubeClient, kubeClient, new(mocks.NoOpRecorder), testLogger)
 		assert.NotNil(t, controller)
 		testDrive := drive1CR
 		err = kubeClient.Create(tCtx, &testDrive)
@@ -154,7 +155,7 @@ func TestController_ReconcileDrive(t *testing.T) {
 	t.Run("Drive is good, AC is present", func(t *testing.T) {
 		kubeClient, err := k8s.GetFakeKubeClient(ns, testLogger)
 		assert.Nil(t, err)
-		controller := NewCapacityController(kubeClient, kubeClient, testLogger)
+		controller := NewCapacityController(kubeClient, kubeClient, new(mocks.NoOpRecorder), testLogger)
 		assert.NotNil(t, controller)
 		testDrive := drive1CR
 		err = kubeClient.Create(tCtx, &testDrive)
@@ -174,7 +175,7 @@ func TestController_ReconcileDrive(t *testing.T) {
 	t.Run("Drive is bad, AC is not present", func(t *testing.T) {
 		kubeClient, err := k8s.GetFakeKubeClient(ns, testLogger)
 		assert.Nil(t, err)
-		controller := NewCapacityController(kubeClient, kubeClient, testLogger)
+		controller := NewCapacityController(kubeClient, kubeClient, new(mocks.NoOpRecorder), testLogger)
 		assert.NotNil(t, controller)
 		testDrive := drive1CR
 		testDrive.Spec.Health = apiV1.HealthBad
@@ -191,7 +192,7 @@ func TestController_ReconcileDrive(t *testing.T) {
 	t.Run("Drive is bad, AC is present", func(t *testing.T) {
 		kubeClient, err := k8s.GetFakeKubeClient(ns, testLogger)
 		assert.Nil(t, err)
-		controller := NewCapacityController(kubeClient, kubeClient, testLogger)
+		controller := NewCapacityController(kubeClient, kubeClient, new(mocks.NoOpRecorder), testLogger)
 		assert.NotNil(t, controller)
 		testDrive := drive1CR
 		testDrive.Spec.Health = apiV1.HealthBad
@@ -211,7 +212,7 @@ func TestController_ReconcileDrive(t *testing.T) {
 	t.Run("Drive is good and not clean, AC is present", func(t *testing.T) {
 		kubeClient, err := k8s.GetFakeKubeClient(ns, testLogger)
 		assert.Nil(t, err)
-		controller := NewCapacityController(kubeClient, kubeClient, testLogger)
+		controller := NewCapacityController(kubeClient, kubeClient, new(mocks.NoOpRecorder), testLogger)
 		assert.NotNil(t, controller)
 		testDrive := drive1CR
 		testDrive.Spec.IsClean = false
@@ -231,7 +232,7 @@ func TestController_ReconcileDrive(t *testing.T) {
 	t.Run("Drive is good and not clean, AC is not present", func(t *testing.T) {
 		kubeClient, err := k8s.GetFakeKubeClient(ns, testLogger)
 		assert.Nil(t, err)
-		controller := NewCapacityController(kubeClient, kubeClient, testLogger)
+		controller := NewCapacityController(kubeClient, kubeClient, new(mocks.NoOpRecorder), testLogger)
 		assert.NotNil(t, controller)
 		testDrive := drive1CR
 		testDrive.Spec.IsClean = false
@@ -245,13 +246,57 @@ func TestController_ReconcileDrive(t *testing.T) {
 		assert.Equal(t, 1, len(acList.Items))
 		assert.Equal(t, int64(0), acList.Items[0].Spec.Size)
 	})
+	t.Run("Drive is good and clean but excluded by firmware policy, AC is present", func(t *testing.T) {
+		kubeClient, err := k8s.GetFakeKubeClient(ns, testLogger)
+		assert.Nil(t, err)
+		controller := NewCapacityController(kubeClient, kubeClient, new(mocks.NoOpRecorder), testLogger)
+		assert.NotNil(t, controller)
+		testDrive := drive1CR
+		testDrive.Status.Conditions = []apiV1.Condition{{Type: apiV1.ConditionFirmwareCompliant,
+			Status: v1.ConditionFalse, Reason: apiV1.FirmwareExcluded}}
+		err = kubeClient.Create(tCtx, &testDrive)
+		assert.Nil(t, err)
+		testAC := acCR
+		err = kubeClient.Create(tCtx, &testAC)
+		assert.Nil(t, err)
+		_, err = controller.Reconcile(ctrl.Request{NamespacedName: types.NamespacedName{Namespace: ns, Name: testDrive.Name}})
+		assert.Nil(t, err)
+		acList := &accrd.AvailableCapacityList{}
+		err = kubeClient.ReadList(tCtx, acList)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(acList.Items))
+		assert.Equal(t, int64(0), acList.Items[0].Spec.Size)
+	})
+	t.Run("Drive attributes and labels are set to AC", func(t *testing.T) {
+		kubeClient, err := k8s.GetFakeKubeClient(ns, testLogger)
+		assert.Nil(t, err)
+		controller := NewCapacityController(kubeClient, kubeClient, new(mocks.NoOpRecorder), testLogger)
+		testDrive := drive1CR
+		testDrive.Labels = map[string]string{"tier": "gold"}
+		err = kubeClient.Create(tCtx, &testDrive)
+		assert.Nil(t, err)
+		testAC := acCR
+		err = kubeClient.Create(tCtx, &testAC)
+		assert.Nil(t, err)
+		_, err = controller.Reconcile(ctrl.Request{NamespacedName: types.NamespacedName{Namespace: ns, Name: testDrive.Name}})
+		assert.Nil(t, err)
+		acList := &accrd.AvailableCapacityList{}
+		err = kubeClient.ReadList(tCtx, acList)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(acList.Items))
+		assert.Equal(t, apiDrive1.VID, acList.Items[0].Annotations[apiV1.ACAnnotationDriveVID])
+		assert.Equal(t, apiDrive1.PID, acList.Items[0].Annotations[apiV1.ACAnnotationDrivePID])
+		assert.Equal(t, apiDrive1.Type, acList.Items[0].Annotations[apiV1.ACAnnotationDriveType])
+		assert.Equal(t, strconv.FormatInt(apiDrive1.Size, 10), acList.Items[0].Annotations[apiV1.ACAnnotationDriveSize])
+		assert.Equal(t, "gold", acList.Items[0].Labels["tier"])
+	})
 }
 
 func TestController_ReconcileLVG(t *testing.T) {
 	t.Run("LVG is good, lvg doesn't have annotation", func(t *testing.T) {
 		kubeClient, err := k8s.GetFakeKubeClient(ns, testLogger)
 		assert.Nil(t, err)
-		controller := NewCapacityController(kubeClient, kubeClient, testLogger)
+		controller := NewCapacityController(kubeClient, kubeClient, new(mocks.NoOpRecorder), testLogger)
 		assert.NotNil(t, controller)
 		testLVG := lvgCR1
 		err = kubeClient.Create(tCtx, &testLVG)
@@ -263,7 +308,7 @@ func TestController_ReconcileLVG(t *testing.T) {
 	t.Run("LVG is good, Annotation is present", func(t *testing.T) {
 		kubeClient, err := k8s.GetFakeKubeClient(ns, testLogger)
 		assert.Nil(t, err)
-		controller := NewCapacityController(kubeClient, kubeClient, testLogger)
+		controller := NewCapacityController(kubeClient, kubeClient, new(mocks.NoOpRecorder), testLogger)
 		assert.NotNil(t, controller)
 		testDrive := drive1CR
 		testDrive.Spec.IsSystem = true
@@ -287,7 +332,7 @@ func TestController_ReconcileLVG(t *testing.T) {
 	t.Run("LVG is good, Annotation is present, wrong annotation value", func(t *testing.T) {
 		kubeClient, err := k8s.GetFakeKubeClient(ns, testLogger)
 		assert.Nil(t, err)
-		controller := NewCapacityController(kubeClient, kubeClient, testLogger)
+		controller := NewCapacityController(kubeClient, kubeClient, new(mocks.NoOpRecorder), testLogger)
 		assert.NotNil(t, controller)
 		testDrive := drive1CR
 		testDrive.Spec.IsSystem = true
@@ -303,7 +348,7 @@ func TestController_ReconcileLVG(t *testing.T) {
 	t.Run("LVG is bad, AC is not present", func(t *testing.T) {
 		kubeClient, err := k8s.GetFakeKubeClient(ns, testLogger)
 		assert.Nil(t, err)
-		controller := NewCapacityController(kubeClient, kubeClient, testLogger)
+		controller := NewCapacityController(kubeClient, kubeClient, new(mocks.NoOpRecorder), testLogger)
 		assert.NotNil(t, controller)
 		testLVG := lvgCR1
 		testLVG.Spec.Health = apiV1.HealthBad
@@ -315,7 +360,7 @@ func TestController_ReconcileLVG(t *testing.T) {
 	t.Run("LVG is bad, AC is present", func(t *testing.T) {
 		kubeClient, err := k8s.GetFakeKubeClient(ns, testLogger)
 		assert.Nil(t, err)
-		controller := NewCapacityController(kubeClient, kubeClient, testLogger)
+		controller := NewCapacityController(kubeClient, kubeClient, new(mocks.NoOpRecorder), testLogger)
 		assert.NotNil(t, controller)
 		testAC := acCR1
 		err = kubeClient.Create(tCtx, &testAC)
@@ -337,7 +382,7 @@ func TestController_ReconcileLVG(t *testing.T) {
 func TestController_ReconcileResourcesNotFound(t *testing.T) {
 	kubeClient, err := k8s.GetFakeKubeClient(ns, testLogger)
 	assert.Nil(t, err)
-	controller := NewCapacityController(kubeClient, kubeClient, testLogger)
+	controller := NewCapacityController(kubeClient, kubeClient, new(mocks.NoOpRecorder), testLogger)
 	assert.NotNil(t, controller)
 	_, err = controller.Reconcile(ctrl.Request{NamespacedName: types.NamespacedName{Namespace: ns, Name: drive1UUID}})
 	assert.Nil(t, err)
@@ -347,7 +392,7 @@ func TestController_filterUpdateEvent_Drive(t *testing.T) {
 	t.Run("Drives have different statuses", func(t *testing.T) {
 		kubeClient, err := k8s.GetFakeKubeClient(ns, testLogger)
 		assert.Nil(t, err)
-		controller := NewCapacityController(kubeClient, kubeClient, testLogger)
+		controller := NewCapacityController(kubeClient, kubeClient, new(mocks.NoOpRecorder), testLogger)
 		assert.NotNil(t, controller)
 		testDrive := drive1CR
 		testDrive2 := drive1CR
@@ -357,7 +402,7 @@ func TestController_filterUpdateEvent_Drive(t *testing.T) {
 	t.Run("Drives have different health statuses", func(t *testing.T) {
 		kubeClient, err := k8s.GetFakeKubeClient(ns, testLogger)
 		assert.Nil(t, err)
-		controller := NewCapacityController(kubeClient, kubeClient, testLogger)
+		controller := NewCapacityController(kubeClient, kubeClient, new(mocks.NoOpRecorder), testLogger)
 		assert.NotNil(t, controller)
 		testDrive := drive1CR
 		testDrive2 := drive1CR
@@ -367,17 +412,35 @@ func TestController_filterUpdateEvent_Drive(t *testing.T) {
 	t.Run("Drives have different clean", func(t *testing.T) {
 		kubeClient, err := k8s.GetFakeKubeClient(ns, testLogger)
 		assert.Nil(t, err)
-		controller := NewCapacityController(kubeClient, kubeClient, testLogger)
+		controller := NewCapacityController(kubeClient, kubeClient, new(mocks.NoOpRecorder), testLogger)
 		assert.NotNil(t, controller)
 		testDrive := drive1CR
 		testDrive2 := drive1CR
 		testDrive2.Spec.IsClean = !testDrive.Spec.IsClean
 		assert.True(t, controller.filterUpdateEvent(&testDrive, &testDrive2))
 	})
+	t.Run("Drives have different firmware exclude annotation", func(t *testing.T) {
+		kubeClient, err := k8s.GetFakeKubeClient(ns, testLogger)
+		assert.Nil(t, err)
+		controller := NewCapacityController(kubeClient, kubeClient, new(mocks.NoOpRecorder), testLogger)
+		assert.NotNil(t, controller)
+		testDrive := drive1CR
+		testDrive2 := drive1CR
+		testDrive2.Status.Conditions = []apiV1.Condition{{Type: apiV1.ConditionFirmwareCompliant,
+			Status: v1.ConditionFalse, Reason: apiV1.FirmwareExcluded}}
+		assert.True(t, controller.filterUpdateEvent(&testDrive, &testDrive2))
+	})
+	t.Run("Drives have different labels", func(t *testing.T) {
+		controller := NewCapacityController(nil, nil, new(mocks.NoOpRecorder), testLogger)
+		testDrive := drive1CR
+		testDrive2 := drive1CR
+		testDrive2.Labels = map[string]string{"tier": "gold"}
+		assert.True(t, controller.filterUpdateEvent(&testDrive, &testDrive2))
+	})
 	t.Run("Drives are filtered", func(t *testing.T) {
 		kubeClient, err := k8s.GetFakeKubeClient(ns, testLogger)
 		assert.Nil(t, err)
-		controller := NewCapacityController(kubeClient, kubeClient, testLogger)
+		controller := NewCapacityController(kubeClient, kubeClient, new(mocks.NoOpRecorder), testLogger)
 		assert.NotNil(t, controller)
 		testDrive := drive1CR
 		testDrive2 := drive1CR
@@ -389,7 +452,7 @@ func TestController_filterUpdateEvent_LVG(t *testing.T) {
 	t.Run("LVG have different health statuses", func(t *testing.T) {
 		kubeClient, err := k8s.GetFakeKubeClient(ns, testLogger)
 		assert.Nil(t, err)
-		controller := NewCapacityController(kubeClient, kubeClient, testLogger)
+		controller := NewCapacityController(kubeClient, kubeClient, new(mocks.NoOpRecorder), testLogger)
 		assert.NotNil(t, controller)
 		testLVG := lvgCR1
 		testLVG2 := lvgCR1
@@ -399,7 +462,7 @@ func TestController_filterUpdateEvent_LVG(t *testing.T) {
 	t.Run("LVG have different statuses", func(t *testing.T) {
 		kubeClient, err := k8s.GetFakeKubeClient(ns, testLogger)
 		assert.Nil(t, err)
-		controller := NewCapacityController(kubeClient, kubeClient, testLogger)
+		controller := NewCapacityController(kubeClient, kubeClient, new(mocks.NoOpRecorder), testLogger)
 		assert.NotNil(t, controller)
 		testLVG := lvgCR1
 		testLVG2 := lvgCR1
@@ -409,7 +472,7 @@ func TestController_filterUpdateEvent_LVG(t *testing.T) {
 	t.Run("Old lvg has annotation, new one doesn't", func(t *testing.T) {
 		kubeClient, err := k8s.GetFakeKubeClient(ns, testLogger)
 		assert.Nil(t, err)
-		controller := NewCapacityController(kubeClient, kubeClient, testLogger)
+		controller := NewCapacityController(kubeClient, kubeClient, new(mocks.NoOpRecorder), testLogger)
 		assert.NotNil(t, controller)
 		testLVG := lvgCR1
 		testLVG2 := lvgCR1
@@ -419,7 +482,7 @@ func TestController_filterUpdateEvent_LVG(t *testing.T) {
 	t.Run("Old lvg doesn't annotation, new one does", func(t *testing.T) {
 		kubeClient, err := k8s.GetFakeKubeClient(ns, testLogger)
 		assert.Nil(t, err)
-		controller := NewCapacityController(kubeClient, kubeClient, testLogger)
+		controller := NewCapacityController(kubeClient, kubeClient, new(mocks.NoOpRecorder), testLogger)
 		assert.NotNil(t, controller)
 		testLVG := lvgCR1
 		testLVG2 := lvgCR1
@@ -429,7 +492,7 @@ func TestController_filterUpdateEvent_LVG(t *testing.T) {
 	t.Run("Old lvg doesn't annotation, new one doesn't have annotation", func(t *testing.T) {
 		kubeClient, err := k8s.GetFakeKubeClient(ns, testLogger)
 		assert.Nil(t, err)
-		controller := NewCapacityController(kubeClient, kubeClient, testLogger)
+		controller := NewCapacityController(kubeClient, kubeClient, new(mocks.NoOpRecorder), testLogger)
 		assert.NotNil(t, controller)
 		testLVG := lvgCR1
 		testLVG2 := lvgCR1
@@ -438,7 +501,7 @@ func TestController_filterUpdateEvent_LVG(t *testing.T) {
 	t.Run("Both LVG have annotation", func(t *testing.T) {
 		kubeClient, err := k8s.GetFakeKubeClient(ns, testLogger)
 		assert.Nil(t, err)
-		controller := NewCapacityController(kubeClient, kubeClient, testLogger)
+		controller := NewCapacityController(kubeClient, kubeClient, new(mocks.NoOpRecorder), testLogger)
 		assert.NotNil(t, controller)
 		testLVG := lvgCR1
 		testLVG2 := lvgCR1
@@ -0,0 +1,78 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package capacitycontroller
+
+import (
+	"context"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	accrd "github.com/dell/csi-baremetal/api/v1/availablecapacitycrd"
+	"github.com/dell/csi-baremetal/pkg/base/util"
+	"github.com/dell/csi-baremetal/pkg/eventing"
+)
+
+// reconcileAC appends finalizer to the AvailableCapacity CR or removes it when AC is deleted and isn't reserved,
+// finalizer is removed immediately if AC has force delete annotation
+func (d *Controller) reconcileAC(ctx context.Context, ac *accrd.AvailableCapacity) (ctrl.Result, error) {
+	log := d.log.WithFields(logrus.Fields{"method": "reconcileAC", "name": ac.Name})
+
+	if ac.DeletionTimestamp.IsZero() {
+		if util.ContainsString(ac.Finalizers, apiV1.ACFinalizer) {
+			return ctrl.Result{}, nil
+		}
+		ac.Finalizers = append(ac.Finalizers, apiV1.ACFinalizer)
+		if err := d.client.UpdateCR(ctx, ac); err != nil {
+			log.Errorf("Unable to append finalizer %s: %v", apiV1.ACFinalizer, err)
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
+	if !util.ContainsString(ac.Finalizers, apiV1.ACFinalizer) {
+		return ctrl.Result{}, nil
+	}
+	acrs, err := d.crHelper.GetACRsByAC(ctx, ac.Name)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if len(acrs) > 0 {
+		if ac.Annotations[apiV1.AnnotationForceDelete] != apiV1.ForceDeleteOn {
+			log.Infof("AC is reserved by %s, deletion is postponed", strings.Join(acrs, ", "))
+			return ctrl.Result{RequeueAfter: RequeueDriveTime}, nil
+		}
+		log.Warnf("AC is force deleted while it is reserved by %s", strings.Join(acrs, ", "))
+		d.eventRecorder.Eventf(ac, eventing.WarningType, eventing.AvailableCapacityForceDeleted,
+			"AvailableCapacity on %s is force deleted while it is reserved by %s",
+			ac.Spec.Location, strings.Join(acrs, ", "))
+	}
+
+	ac.Finalizers = util.RemoveString(ac.Finalizers, apiV1.ACFinalizer)
+	if err := d.client.UpdateCR(ctx, ac); err != nil {
+		log.Errorf("Unable to remove finalizer %s: %v", apiV1.ACFinalizer, err)
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// filterAC passes update events of AvailableCapacity CRs without finalizer or which are being deleted
+func filterAC(ac *accrd.AvailableCapacity) bool {
+	return !util.ContainsString(ac.Finalizers, apiV1.ACFinalizer) || !ac.DeletionTimestamp.IsZero()
+}
@@ -0,0 +1,116 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package capacitycontroller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	accrd "github.com/dell/csi-baremetal/api/v1/availablecapacitycrd"
+	"github.com/dell/csi-baremetal/pkg/base/k8s"
+	"github.com/dell/csi-baremetal/pkg/base/util"
+	"github.com/dell/csi-baremetal/pkg/eventing"
+	"github.com/dell/csi-baremetal/pkg/mocks"
+)
+
+func markACDeleted(t *testing.T, kubeClient *k8s.KubeClient, annotations map[string]string) {
+	ac := &accrd.AvailableCapacity{}
+	assert.Nil(t, kubeClient.ReadCR(tCtx, acCRName, "", ac))
+	now := v1.Now()
+	ac.DeletionTimestamp = &now
+	ac.Annotations = annotations
+	assert.Nil(t, kubeClient.UpdateCR(tCtx, ac))
+}
+
+func readACFinalizers(t *testing.T, kubeClient *k8s.KubeClient) []string {
+	ac := &accrd.AvailableCapacity{}
+	assert.Nil(t, kubeClient.ReadCR(tCtx, acCRName, "", ac))
+	return ac.Finalizers
+}
+
+func TestController_ReconcileACFinalizer(t *testing.T) {
+	kubeClient, err := k8s.GetFakeKubeClient(ns, testLogger)
+	assert.Nil(t, err)
+	recorder := new(mocks.NoOpRecorder)
+	controller := NewCapacityController(kubeClient, kubeClient, recorder, testLogger)
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: ns, Name: acCRName}}
+	ac := acCR.DeepCopy()
+	assert.Nil(t, kubeClient.CreateCR(tCtx, acCRName, ac))
+
+	_, err = controller.Reconcile(req)
+	assert.Nil(t, err)
+	assert.True(t, util.ContainsString(readACFinalizers(t, kubeClient), apiV1.ACFinalizer))
+
+	acr := kubeClient.ConstructACRCR("acr", api.AvailableCapacityReservation{
+		Status: apiV1.ReservationConfirmed,
+		ReservationRequests: []*api.ReservationRequest{
+			{CapacityRequest: &api.CapacityRequest{Name: "pvc"}, Reservations: []string{acCRName}},
+		},
+	})
+	assert.Nil(t, kubeClient.CreateCR(tCtx, acr.Name, acr))
+
+	// reserved AC isn't released
+	markACDeleted(t, kubeClient, nil)
+	res, err := controller.Reconcile(req)
+	assert.Nil(t, err)
+	assert.Equal(t, ctrl.Result{RequeueAfter: RequeueDriveTime}, res)
+	assert.True(t, util.ContainsString(readACFinalizers(t, kubeClient), apiV1.ACFinalizer))
+
+	// force delete
+	markACDeleted(t, kubeClient, map[string]string{apiV1.AnnotationForceDelete: apiV1.ForceDeleteOn})
+	res, err = controller.Reconcile(req)
+	assert.Nil(t, err)
+	assert.Equal(t, ctrl.Result{}, res)
+	assert.False(t, util.ContainsString(readACFinalizers(t, kubeClient), apiV1.ACFinalizer))
+	assert.Equal(t, 1, len(recorder.Calls))
+	assert.Equal(t, eventing.AvailableCapacityForceDeleted, recorder.Calls[0].Reason)
+}
+
+func TestController_ReconcileACFinalizerNotReserved(t *testing.T) {
+	kubeClient, err := k8s.GetFakeKubeClient(ns, testLogger)
+	assert.Nil(t, err)
+	controller := NewCapacityController(kubeClient, kubeClient, new(mocks.NoOpRecorder), testLogger)
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: ns, Name: acCRName}}
+	ac := acCR.DeepCopy()
+	ac.Finalizers = []string{apiV1.ACFinalizer}
+	assert.Nil(t, kubeClient.CreateCR(tCtx, acCRName, ac))
+
+	markACDeleted(t, kubeClient, nil)
+	res, err := controller.Reconcile(req)
+	assert.Nil(t, err)
+	assert.Equal(t, ctrl.Result{}, res)
+	assert.False(t, util.ContainsString(readACFinalizers(t, kubeClient), apiV1.ACFinalizer))
+}
+
+func TestController_filterUpdateEvent_AC(t *testing.T) {
+	controller := NewCapacityController(nil, nil, new(mocks.NoOpRecorder), testLogger)
+	ac := acCR.DeepCopy()
+	assert.True(t, controller.filterUpdateEvent(ac, ac))
+
+	ac.Finalizers = []string{apiV1.ACFinalizer}
+	assert.False(t, controller.filterUpdateEvent(ac, ac))
+
+	now := v1.Now()
+	ac.DeletionTimestamp = &now
+	assert.True(t, controller.filterUpdateEvent(ac, ac))
+}
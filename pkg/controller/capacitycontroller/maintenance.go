@@ -0,0 +1,154 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package capacitycontroller
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/sirupsen/logrus"
+
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	"github.com/dell/csi-baremetal/api/v1/drivecrd"
+	"github.com/dell/csi-baremetal/api/v1/lvgcrd"
+	"github.com/dell/csi-baremetal/pkg/base"
+	errTypes "github.com/dell/csi-baremetal/pkg/base/error"
+)
+
+// isUnderMaintenance returns true if drive is in maintenance mode
+func isUnderMaintenance(drive *drivecrd.Drive) bool {
+	return drive.Annotations[apiV1.DriveAnnotationMaintenance] == apiV1.DriveMaintenanceOn
+}
+
+// isLVGUnderMaintenance returns true if at least one drive of LVG is in maintenance mode
+func (d *Controller) isLVGUnderMaintenance(lvg *lvgcrd.LogicalVolumeGroup) bool {
+	for _, driveUUID := range lvg.Spec.Locations {
+		if drive := d.cachedCrHelper.GetDriveCRByUUID(driveUUID); drive != nil && isUnderMaintenance(drive) {
+			return true
+		}
+	}
+	return false
+}
+
+// handleDriveMaintenance sets MAINTENANCE operational status for volumes of the drive or its LVG when drive enters
+// maintenance mode and returns OPERATIVE status when drive exits it. AC of LVG is suspended or resumed as well,
+// AC of the drive itself is handled by createOrUpdateCapacity
+func (d *Controller) handleDriveMaintenance(ctx context.Context, drive *drivecrd.Drive) error {
+	lvg, err := d.cachedCrHelper.GetLVGByDrive(ctx, drive.Spec.UUID)
+	if err != nil {
+		return err
+	}
+
+	location := drive.Spec.UUID
+	inMaintenance := isUnderMaintenance(drive)
+	if lvg != nil {
+		location = lvg.Name
+		inMaintenance = d.isLVGUnderMaintenance(lvg)
+		if inMaintenance {
+			err = d.suspendACOfLVG(lvg.Name)
+		} else {
+			err = d.resumeACOfLVG(lvg.Name)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return d.setVolumesMaintenanceStatus(ctx, drive.Spec.NodeId, location, inMaintenance)
+}
+
+// setVolumesMaintenanceStatus switches operational status of volumes with provided node and location
+// between OPERATIVE and MAINTENANCE
+func (d *Controller) setVolumesMaintenanceStatus(ctx context.Context, nodeID, location string, inMaintenance bool) error {
+	volumes, err := d.cachedCrHelper.GetVolumeCRs(nodeID)
+	if err != nil {
+		return err
+	}
+
+	from, to := apiV1.OperationalStatusMaintenance, apiV1.OperationalStatusOperative
+	if inMaintenance {
+		from, to = to, from
+	}
+	for _, volume := range volumes {
+		volume := volume
+		if volume.Spec.Location != location || volume.Spec.OperationalStatus != from {
+			continue
+		}
+		d.log.WithFields(logrus.Fields{
+			"method":   "setVolumesMaintenanceStatus",
+			"volumeID": volume.Spec.Id,
+		}).Infof("Change operational status from %s to %s", from, to)
+		volume.Spec.OperationalStatus = to
+		if err := d.client.UpdateCR(context.WithValue(ctx, base.RequestUUID, volume.Spec.Id), &volume); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// suspendACOfLVG saves size of LVG AC in annotation and sets it to 0 to avoid further allocations.
+// Size returned to suspended AC by removed volumes is added to the saved one
+func (d *Controller) suspendACOfLVG(lvgName string) error {
+	ac, err := d.cachedCrHelper.GetACByLocation(lvgName)
+	switch {
+	case err == errTypes.ErrorNotFound:
+		return nil
+	case err != nil:
+		return err
+	}
+	var savedSize int64
+	if sizeStr, ok := ac.Annotations[apiV1.ACAnnotationMaintenanceSize]; ok {
+		if ac.Spec.Size == 0 {
+			return nil
+		}
+		if savedSize, err = strconv.ParseInt(sizeStr, 10, 64); err != nil {
+			d.log.WithField("method", "suspendACOfLVG").
+				Errorf("Unable to parse size %s of AC %s: %v", sizeStr, ac.Name, err)
+			savedSize = 0
+		}
+	}
+	if ac.Annotations == nil {
+		ac.Annotations = map[string]string{}
+	}
+	ac.Annotations[apiV1.ACAnnotationMaintenanceSize] = strconv.FormatInt(savedSize+ac.Spec.Size, 10)
+	ac.Spec.Size = 0
+	return d.client.UpdateCR(context.WithValue(context.Background(), base.RequestUUID, ac.Name), ac)
+}
+
+// resumeACOfLVG returns size of LVG AC saved by suspendACOfLVG
+func (d *Controller) resumeACOfLVG(lvgName string) error {
+	ac, err := d.cachedCrHelper.GetACByLocation(lvgName)
+	switch {
+	case err == errTypes.ErrorNotFound:
+		return nil
+	case err != nil:
+		return err
+	}
+	sizeStr, ok := ac.Annotations[apiV1.ACAnnotationMaintenanceSize]
+	if !ok {
+		return nil
+	}
+	size, err := strconv.ParseInt(sizeStr, 10, 64)
+	if err != nil {
+		d.log.WithField("method", "resumeACOfLVG").
+			Errorf("Unable to parse size %s of AC %s: %v", sizeStr, ac.Name, err)
+		size = 0
+	}
+	// AC might be increased by removed volumes since the last suspension
+	ac.Spec.Size += size
+	delete(ac.Annotations, apiV1.ACAnnotationMaintenanceSize)
+	return d.client.UpdateCR(context.WithValue(context.Background(), base.RequestUUID, ac.Name), ac)
+}
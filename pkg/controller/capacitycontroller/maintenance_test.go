@@ -0,0 +1,140 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package capacitycontroller
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	accrd "github.com/dell/csi-baremetal/api/v1/availablecapacitycrd"
+	"github.com/dell/csi-baremetal/pkg/base/k8s"
+	"github.com/dell/csi-baremetal/pkg/mocks"
+)
+
+func TestController_ReconcileDrive_Maintenance(t *testing.T) {
+	t.Run("Drive enters and exits maintenance", func(t *testing.T) {
+		kubeClient, err := k8s.GetFakeKubeClient(ns, testLogger)
+		assert.Nil(t, err)
+		controller := NewCapacityController(kubeClient, kubeClient, new(mocks.NoOpRecorder), testLogger)
+		testDrive := drive1CR
+		testDrive.Annotations = map[string]string{apiV1.DriveAnnotationMaintenance: apiV1.DriveMaintenanceOn}
+		assert.Nil(t, kubeClient.Create(tCtx, &testDrive))
+		testAC := acCR
+		assert.Nil(t, kubeClient.Create(tCtx, &testAC))
+		volume := kubeClient.ConstructVolumeCR("volume", ns, api.Volume{
+			Id:                "volume",
+			NodeId:            node1ID,
+			Location:          drive1UUID,
+			OperationalStatus: apiV1.OperationalStatusOperative,
+		})
+		assert.Nil(t, kubeClient.Create(tCtx, volume))
+
+		req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: ns, Name: testDrive.Name}}
+		_, err = controller.Reconcile(req)
+		assert.Nil(t, err)
+		ac := &accrd.AvailableCapacity{}
+		assert.Nil(t, kubeClient.ReadCR(tCtx, acCRName, "", ac))
+		assert.Equal(t, int64(0), ac.Spec.Size)
+		assert.Nil(t, kubeClient.ReadCR(tCtx, volume.Name, ns, volume))
+		assert.Equal(t, apiV1.OperationalStatusMaintenance, volume.Spec.OperationalStatus)
+
+		assert.Nil(t, kubeClient.ReadCR(tCtx, testDrive.Name, "", &testDrive))
+		delete(testDrive.Annotations, apiV1.DriveAnnotationMaintenance)
+		assert.Nil(t, kubeClient.UpdateCR(tCtx, &testDrive))
+		_, err = controller.Reconcile(req)
+		assert.Nil(t, err)
+		assert.Nil(t, kubeClient.ReadCR(tCtx, acCRName, "", ac))
+		assert.Equal(t, apiDrive1.Size, ac.Spec.Size)
+		assert.Nil(t, kubeClient.ReadCR(tCtx, volume.Name, ns, volume))
+		assert.Equal(t, apiV1.OperationalStatusOperative, volume.Spec.OperationalStatus)
+	})
+
+	t.Run("Drive of LVG enters and exits maintenance", func(t *testing.T) {
+		kubeClient, err := k8s.GetFakeKubeClient(ns, testLogger)
+		assert.Nil(t, err)
+		controller := NewCapacityController(kubeClient, kubeClient, new(mocks.NoOpRecorder), testLogger)
+		testDrive := drive1CR
+		testDrive.Spec.IsClean = false
+		volumeSize := int64(1024 * 1024)
+		testDrive.Annotations = map[string]string{apiV1.DriveAnnotationMaintenance: apiV1.DriveMaintenanceOn}
+		assert.Nil(t, kubeClient.Create(tCtx, &testDrive))
+		testLVG := lvgCR1
+		assert.Nil(t, kubeClient.Create(tCtx, &testLVG))
+		testAC := acCR1
+		assert.Nil(t, kubeClient.Create(tCtx, &testAC))
+		volume := kubeClient.ConstructVolumeCR("volume", ns, api.Volume{
+			Id:                "volume",
+			NodeId:            node1ID,
+			Location:          lvg1Name,
+			Size:              volumeSize,
+			OperationalStatus: apiV1.OperationalStatusOperative,
+		})
+		assert.Nil(t, kubeClient.Create(tCtx, volume))
+
+		req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: ns, Name: testDrive.Name}}
+		_, err = controller.Reconcile(req)
+		assert.Nil(t, err)
+		ac := &accrd.AvailableCapacity{}
+		assert.Nil(t, kubeClient.ReadCR(tCtx, acCR1Name, "", ac))
+		assert.Equal(t, int64(0), ac.Spec.Size)
+		assert.Nil(t, kubeClient.ReadCR(tCtx, volume.Name, ns, volume))
+		assert.Equal(t, apiV1.OperationalStatusMaintenance, volume.Spec.OperationalStatus)
+
+		// LVG reconciliation keeps AC suspended
+		_, err = controller.Reconcile(ctrl.Request{NamespacedName: types.NamespacedName{Namespace: ns, Name: lvg1Name}})
+		assert.Nil(t, err)
+		assert.Nil(t, kubeClient.ReadCR(tCtx, acCR1Name, "", ac))
+		assert.Equal(t, int64(0), ac.Spec.Size)
+
+		// volume is deleted during maintenance and its size is returned to AC of LVG
+		ac.Spec.Size += volumeSize
+		assert.Nil(t, kubeClient.UpdateCR(tCtx, ac))
+		assert.Nil(t, kubeClient.DeleteCR(tCtx, volume))
+		_, err = controller.Reconcile(ctrl.Request{NamespacedName: types.NamespacedName{Namespace: ns, Name: lvg1Name}})
+		assert.Nil(t, err)
+		ac = &accrd.AvailableCapacity{}
+		assert.Nil(t, kubeClient.ReadCR(tCtx, acCR1Name, "", ac))
+		assert.Equal(t, int64(0), ac.Spec.Size)
+		assert.Equal(t, strconv.FormatInt(acCR1.Spec.Size+volumeSize, 10), ac.Annotations[apiV1.ACAnnotationMaintenanceSize])
+
+		assert.Nil(t, kubeClient.ReadCR(tCtx, testDrive.Name, "", &testDrive))
+		delete(testDrive.Annotations, apiV1.DriveAnnotationMaintenance)
+		assert.Nil(t, kubeClient.UpdateCR(tCtx, &testDrive))
+		_, err = controller.Reconcile(req)
+		assert.Nil(t, err)
+		ac = &accrd.AvailableCapacity{}
+		assert.Nil(t, kubeClient.ReadCR(tCtx, acCR1Name, "", ac))
+		assert.Equal(t, acCR1.Spec.Size+volumeSize, ac.Spec.Size)
+		assert.NotContains(t, ac.Annotations, apiV1.ACAnnotationMaintenanceSize)
+	})
+}
+
+func TestController_filterUpdateEvent_Maintenance(t *testing.T) {
+	controller := NewCapacityController(nil, nil, new(mocks.NoOpRecorder), testLogger)
+	oldDrive := drive1CR.DeepCopy()
+	newDrive := drive1CR.DeepCopy()
+	newDrive.Annotations = map[string]string{apiV1.DriveAnnotationMaintenance: apiV1.DriveMaintenanceOn}
+	assert.True(t, controller.filterUpdateEvent(oldDrive, newDrive))
+	assert.True(t, controller.filterUpdateEvent(newDrive, oldDrive))
+	assert.False(t, controller.filterUpdateEvent(newDrive, newDrive.DeepCopy()))
+}
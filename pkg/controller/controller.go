@@ -37,6 +37,7 @@ import (
 	"github.com/dell/csi-baremetal/pkg/base/capacityplanner"
 	"github.com/dell/csi-baremetal/pkg/base/featureconfig"
 	"github.com/dell/csi-baremetal/pkg/base/k8s"
+	"github.com/dell/csi-baremetal/pkg/base/tracing"
 	"github.com/dell/csi-baremetal/pkg/base/util"
 	"github.com/dell/csi-baremetal/pkg/common"
 	"github.com/dell/csi-baremetal/pkg/controller/node"
@@ -141,11 +142,13 @@ func (c *CSIControllerService) Watch(*grpc_health_v1.HealthCheckRequest, grpc_he
 // For example storageType: HDD, storageType: HDDLVG. If this field is not set then storage type would be ANY.
 // Receives golang context and CSI Spec CreateVolumeRequest
 // Returns CSI Spec CreateVolumeResponse or error if something went wrong
-func (c *CSIControllerService) CreateVolume(ctx context.Context, req *csi.CreateVolumeRequest) (*csi.CreateVolumeResponse, error) {
+func (c *CSIControllerService) CreateVolume(ctx context.Context, req *csi.CreateVolumeRequest) (resp *csi.CreateVolumeResponse, err error) {
 	ll := c.log.WithFields(logrus.Fields{
 		"method":   "CreateVolume",
 		"volumeID": req.GetName(),
 	})
+	ctx, span := tracing.StartSpan(ctx, "CSIController.CreateVolume", tracing.VolumeIDKey.String(req.GetName()))
+	defer func() { tracing.EndSpan(span, err) }()
 	ll.Infof("Processing request: %v", req)
 
 	if req.GetName() == "" {
@@ -221,7 +224,7 @@ func (c *CSIControllerService) CreateVolume(ctx context.Context, req *csi.Create
 // And waits for Volume to be removed by Reconcile loop of appropriate Node.
 // Receives golang context and CSI Spec DeleteVolumeRequest
 // Returns CSI Spec DeleteVolumeResponse or error if something went wrong
-func (c *CSIControllerService) DeleteVolume(ctx context.Context, req *csi.DeleteVolumeRequest) (*csi.DeleteVolumeResponse, error) {
+func (c *CSIControllerService) DeleteVolume(ctx context.Context, req *csi.DeleteVolumeRequest) (resp *csi.DeleteVolumeResponse, err error) {
 	ll := c.log.WithFields(logrus.Fields{
 		"method":   "DeleteVolume",
 		"volumeID": req.GetVolumeId(),
@@ -232,10 +235,12 @@ func (c *CSIControllerService) DeleteVolume(ctx context.Context, req *csi.Delete
 	if req.VolumeId == "" {
 		return nil, status.Error(codes.InvalidArgument, "Volume ID must be provided")
 	}
-	ctxWithID := context.WithValue(context.Background(), base.RequestUUID, req.VolumeId)
+	ctx, span := tracing.StartSpan(ctx, "CSIController.DeleteVolume", tracing.VolumeIDKey.String(req.VolumeId))
+	defer func() { tracing.EndSpan(span, err) }()
+	ctxWithID := context.WithValue(tracing.Detach(ctx), base.RequestUUID, req.VolumeId)
 
 	c.reqMu.Lock()
-	err := c.svc.DeleteVolume(ctxWithID, req.GetVolumeId())
+	err = c.svc.DeleteVolume(ctxWithID, req.GetVolumeId())
 	c.reqMu.Unlock()
 
 	if err != nil {
@@ -388,15 +393,18 @@ func (c *CSIControllerService) ListSnapshots(context.Context, *csi.ListSnapshots
 // In case of status different from Volume_Ready, Created, Published and Resizing Controller returns error
 // Receives golang context and CSI Spec ControllerExpandVolumeRequest
 // Returns CSI Spec ControllerExpandVolumeResponse or error if something went wrong
-func (c *CSIControllerService) ControllerExpandVolume(ctx context.Context, req *csi.ControllerExpandVolumeRequest) (*csi.ControllerExpandVolumeResponse, error) {
+func (c *CSIControllerService) ControllerExpandVolume(ctx context.Context,
+	req *csi.ControllerExpandVolumeRequest) (resp *csi.ControllerExpandVolumeResponse, err error) {
 	ll := c.log.WithFields(logrus.Fields{
 		"method":   "ControllerExpandVolume",
 		"volumeID": req.GetVolumeId(),
 	})
 	ll.Infof("Processing request: %v", req)
+	ctx, span := tracing.StartSpan(ctx, "CSIController.ControllerExpandVolume", tracing.VolumeIDKey.String(req.GetVolumeId()))
+	defer func() { tracing.EndSpan(span, err) }()
 	var (
 		volID         = req.GetVolumeId()
-		ctxWithID     = context.WithValue(context.Background(), base.RequestUUID, volID)
+		ctxWithID     = context.WithValue(tracing.Detach(ctx), base.RequestUUID, volID)
 		requiredBytes = capacityplanner.AlignSizeByPE(req.GetCapacityRange().GetRequiredBytes())
 	)
 
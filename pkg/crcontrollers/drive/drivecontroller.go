@@ -3,6 +3,7 @@ package drive
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/sirupsen/logrus"
@@ -17,26 +18,54 @@ import (
 	"github.com/dell/csi-baremetal/api/v1/drivecrd"
 	"github.com/dell/csi-baremetal/api/v1/volumecrd"
 	"github.com/dell/csi-baremetal/pkg/base"
+	"github.com/dell/csi-baremetal/pkg/base/command"
+	"github.com/dell/csi-baremetal/pkg/base/featureconfig"
 	"github.com/dell/csi-baremetal/pkg/base/k8s"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/lsblk"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/lvm"
 	"github.com/dell/csi-baremetal/pkg/eventing"
-	"github.com/dell/csi-baremetal/pkg/events"
 	metricsC "github.com/dell/csi-baremetal/pkg/metrics/common"
 )
 
+const (
+	// RequeueReplacementTime is the interval of checking whether removed drive was replaced by the new one
+	RequeueReplacementTime = 30 * time.Second
+	// ReplacementTimeout is the time after which CR of the removed drive is deleted if new drive isn't inserted
+	ReplacementTimeout = 7 * 24 * time.Hour
+	// RequeueEvacuationTime is the interval of checking progress of drive evacuation
+	RequeueEvacuationTime = 30 * time.Second
+	// RequeueDeletionTime is the interval of checking whether deleted drive is still in use
+	RequeueDeletionTime = 30 * time.Second
+)
+
+// eventRecorder interface for sending events
+type eventRecorder interface {
+	Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{})
+}
+
 // Controller to reconcile drive custom resource
 type Controller struct {
 	client         *k8s.KubeClient
 	crHelper       *k8s.CRHelper
 	nodeID         string
 	driveMgrClient api.DriveServiceClient
-	eventRecorder  *events.Recorder
+	eventRecorder  eventRecorder
 	log            *logrus.Entry
+
+	listBlk lsblk.WrapLsblk
+	lvmOps  lvm.WrapLVM
+	// whether data of SUSPECT drive is moved to another drive of LVG instead of volumes release
+	lvgEvacuation bool
+	// names of VGs for which pvmove was started or resumed by this instance of controller
+	pvMoves sync.Map
 }
 
 // NewController creates new instance of Controller structure
-// Receives an instance of base.KubeClient, node ID and logrus logger
+// Receives an instance of base.KubeClient, node ID, logrus logger and feature config
 // Returns an instance of Controller
-func NewController(client *k8s.KubeClient, nodeID string, serviceClient api.DriveServiceClient, eventRecorder *events.Recorder, log *logrus.Logger) *Controller {
+func NewController(client *k8s.KubeClient, nodeID string, serviceClient api.DriveServiceClient, eventRecorder eventRecorder,
+	log *logrus.Logger, featureConf featureconfig.FeatureChecker) *Controller {
+	e := command.NewExecutor(log)
 	return &Controller{
 		client:         client,
 		crHelper:       k8s.NewCRHelper(client, log),
@@ -44,6 +73,9 @@ func NewController(client *k8s.KubeClient, nodeID string, serviceClient api.Driv
 		driveMgrClient: serviceClient,
 		eventRecorder:  eventRecorder,
 		log:            log.WithField("component", "Controller"),
+		listBlk:        lsblk.NewLSBLK(log),
+		lvmOps:         lvm.NewLVM(e, log),
+		lvgEvacuation:  featureConf.IsEnabled(featureconfig.FeatureLVGEvacuation),
 	}
 }
 
@@ -100,6 +132,13 @@ func (c *Controller) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 
 	log.Infof("Drive changed: %v", drive)
 
+	if !drive.ObjectMeta.DeletionTimestamp.IsZero() {
+		return c.handleDriveRemoving(ctx, drive)
+	}
+	if err := c.appendFinalizer(ctx, drive); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
 	usage := drive.Spec.GetUsage()
 	health := drive.Spec.GetHealth()
 	id := drive.Spec.GetUUID()
@@ -110,9 +149,15 @@ func (c *Controller) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 		if health == apiV1.HealthSuspect || health == apiV1.HealthBad {
 			// TODO update health of volumes
 			drive.Spec.Usage = apiV1.DriveUsageReleasing
+			if err := c.startEvacuation(ctx, drive); err != nil {
+				return ctrl.Result{RequeueAfter: base.DefaultRequeueForVolume}, err
+			}
 			toUpdate = true
 		}
 	case apiV1.DriveUsageReleasing:
+		if drive.Annotations[apiV1.DriveAnnotationEvacuation] == apiV1.EvacuationInProgress {
+			return c.evacuate(ctx, drive)
+		}
 		volumes, err := c.crHelper.GetVolumesByLocation(ctx, id)
 		if err != nil {
 			return ctrl.Result{RequeueAfter: base.DefaultRequeueForVolume}, err
@@ -147,6 +192,10 @@ func (c *Controller) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 			return ctrl.Result{RequeueAfter: base.DefaultRequeueForVolume}, err
 		}
 		if c.checkAllVolsRemoved(volumes) {
+			// remember LVG of the drive to restore its membership on the replacement drive
+			if err := c.saveLVGMembership(ctx, drive); err != nil {
+				return ctrl.Result{RequeueAfter: base.DefaultRequeueForVolume}, err
+			}
 			drive.Spec.Usage = apiV1.DriveUsageRemoved
 			status, err := c.driveMgrClient.Locate(ctx, &api.DriveLocateRequest{Action: apiV1.LocateStart, DriveSerialNumber: drive.Spec.SerialNumber})
 			if err != nil || status.Status != apiV1.LocateStatusOn {
@@ -164,6 +213,14 @@ func (c *Controller) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 		}
 	case apiV1.DriveUsageRemoved:
 		if drive.Spec.Status == apiV1.DriveStatusOffline {
+			// drive CR in the known slot is kept as replacement history until the new drive is inserted
+			if waitsForReplacement(drive) {
+				return c.handleReplacement(ctx, drive)
+			}
+			if hasSlot(drive) {
+				log.Warnf("New drive wasn't inserted into slot %s/%s/%s during %s",
+					drive.Spec.Enclosure, drive.Spec.Slot, drive.Spec.Bay, ReplacementTimeout)
+			}
 			// drive was removed from the system. need to clean corresponding custom resource
 			if err := c.client.DeleteCR(ctx, drive); err != nil {
 				log.Errorf("Failed to delete Drive %s CR", driveName)
@@ -0,0 +1,258 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package drive
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	k8sError "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	accrd "github.com/dell/csi-baremetal/api/v1/availablecapacitycrd"
+	"github.com/dell/csi-baremetal/api/v1/drivecrd"
+	"github.com/dell/csi-baremetal/api/v1/lvgcrd"
+	"github.com/dell/csi-baremetal/pkg/base/featureconfig"
+	"github.com/dell/csi-baremetal/pkg/base/k8s"
+	"github.com/dell/csi-baremetal/pkg/eventing"
+	"github.com/dell/csi-baremetal/pkg/mocks"
+	mocklu "github.com/dell/csi-baremetal/pkg/mocks/linuxutils"
+)
+
+var (
+	testCtx    = context.Background()
+	testLogger = logrus.New()
+	testNs     = "default"
+	testNodeID = "node-1"
+
+	removedDrive = api.Drive{
+		UUID:         "uuid-removed",
+		SerialNumber: "sn-removed",
+		Type:         apiV1.DriveTypeHDD,
+		Health:       apiV1.HealthBad,
+		Status:       apiV1.DriveStatusOffline,
+		Usage:        apiV1.DriveUsageRemoved,
+		NodeId:       testNodeID,
+		Enclosure:    "enclosure-1",
+		Slot:         "1",
+		Bay:          "1",
+	}
+	newDrive = api.Drive{
+		UUID:         "uuid-new",
+		SerialNumber: "sn-new",
+		Type:         apiV1.DriveTypeHDD,
+		Size:         1024 * 1024 * 1024,
+		Health:       apiV1.HealthGood,
+		Status:       apiV1.DriveStatusOnline,
+		Usage:        apiV1.DriveUsageInUse,
+		NodeId:       testNodeID,
+		Enclosure:    "enclosure-1",
+		Slot:         "1",
+		Bay:          "1",
+		IsClean:      true,
+	}
+)
+
+func setup(t *testing.T) (*Controller, *mocks.NoOpRecorder) {
+	kubeClient, err := k8s.GetFakeKubeClient(testNs, testLogger)
+	assert.Nil(t, err)
+	recorder := &mocks.NoOpRecorder{}
+	return NewController(kubeClient, testNodeID, mocks.NewMockDriveMgrClient(nil), recorder, testLogger,
+		featureconfig.NewFeatureConfig()), recorder
+}
+
+func createDrive(t *testing.T, c *Controller, spec api.Drive, annotations map[string]string) {
+	drive := c.client.ConstructDriveCR(spec.UUID, spec)
+	drive.Namespace = testNs
+	drive.Annotations = annotations
+	assert.Nil(t, c.client.CreateCR(testCtx, drive.Name, drive))
+}
+
+func readDrive(t *testing.T, c *Controller, name string) *drivecrd.Drive {
+	drive := &drivecrd.Drive{}
+	assert.Nil(t, c.client.ReadCR(testCtx, name, "", drive))
+	return drive
+}
+
+func TestController_ReconcileRemovedWithoutSlot(t *testing.T) {
+	c, _ := setup(t)
+	spec := removedDrive
+	spec.Slot, spec.Bay = "", ""
+	createDrive(t, c, spec, nil)
+
+	res, err := c.Reconcile(ctrl.Request{NamespacedName: types.NamespacedName{Name: spec.UUID}})
+	assert.Nil(t, err)
+	assert.Equal(t, ctrl.Result{}, res)
+	err = c.client.ReadCR(testCtx, spec.UUID, "", &drivecrd.Drive{})
+	assert.True(t, k8sError.IsNotFound(err))
+}
+
+func TestController_ReconcileRemoving(t *testing.T) {
+	c, _ := setup(t)
+	spec := removedDrive
+	spec.Status = apiV1.DriveStatusOnline
+	spec.Usage = apiV1.DriveUsageRemoving
+	createDrive(t, c, spec, nil)
+	lvg := c.client.ConstructLVGCR("lvg", api.LogicalVolumeGroup{Name: "lvg", Node: testNodeID,
+		Locations: []string{spec.UUID}})
+	lvg.Namespace = testNs
+	assert.Nil(t, c.client.CreateCR(testCtx, lvg.Name, lvg))
+
+	_, err := c.Reconcile(ctrl.Request{NamespacedName: types.NamespacedName{Name: spec.UUID}})
+	assert.Nil(t, err)
+	drive := readDrive(t, c, spec.UUID)
+	assert.Equal(t, "lvg", drive.Annotations[apiV1.DriveAnnotationReplacementLVG])
+	assert.Equal(t, apiV1.StorageClassHDDLVG, drive.Annotations[apiV1.DriveAnnotationReplacementLVGSC])
+}
+
+func TestController_ReconcileReplacement(t *testing.T) {
+	c, recorder := setup(t)
+	createDrive(t, c, removedDrive, map[string]string{apiV1.DriveAnnotationReplacementLVG: "lvg",
+		apiV1.DriveAnnotationReplacementLVGSC: apiV1.StorageClassHDDLVG})
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: removedDrive.UUID}}
+
+	// new drive isn't inserted
+	res, err := c.Reconcile(req)
+	assert.Nil(t, err)
+	assert.Equal(t, RequeueReplacementTime, res.RequeueAfter)
+	assert.NotEmpty(t, readDrive(t, c, removedDrive.UUID).Annotations[apiV1.DriveAnnotationRemovalTime])
+
+	// drive in another slot isn't a replacement
+	otherSlot := newDrive
+	otherSlot.UUID, otherSlot.Slot = "uuid-other", "2"
+	createDrive(t, c, otherSlot, nil)
+	// AC of the new drive isn't created yet
+	createDrive(t, c, newDrive, nil)
+	res, err = c.Reconcile(req)
+	assert.Nil(t, err)
+	assert.Equal(t, RequeueReplacementTime, res.RequeueAfter)
+	assert.Empty(t, readDrive(t, c, newDrive.UUID).Annotations[apiV1.DriveAnnotationReplaces])
+	// capacity of the new drive isn't offered until LVG is restored
+	assert.Equal(t, "lvg", readDrive(t, c, newDrive.UUID).Annotations[apiV1.DriveAnnotationReplacementRestoring])
+
+	ac := c.client.ConstructACCR("ac", api.AvailableCapacity{Location: newDrive.UUID, NodeId: testNodeID,
+		StorageClass: apiV1.StorageClassHDD, Size: newDrive.Size})
+	ac.Namespace = testNs
+	assert.Nil(t, c.client.CreateCR(testCtx, ac.Name, ac))
+	res, err = c.Reconcile(req)
+	assert.Nil(t, err)
+	assert.Equal(t, ctrl.Result{}, res)
+
+	removed := readDrive(t, c, removedDrive.UUID)
+	assert.Equal(t, newDrive.UUID, removed.Annotations[apiV1.DriveAnnotationReplacedBy])
+	assert.NotEmpty(t, removed.Annotations[apiV1.DriveAnnotationReplacementTime])
+	assert.Equal(t, removedDrive.UUID, readDrive(t, c, newDrive.UUID).Annotations[apiV1.DriveAnnotationReplaces])
+	assert.Empty(t, readDrive(t, c, newDrive.UUID).Annotations[apiV1.DriveAnnotationReplacementRestoring])
+	assert.Empty(t, readDrive(t, c, otherSlot.UUID).Annotations[apiV1.DriveAnnotationReplaces])
+
+	// LVG with the same name and AC are restored on the new drive
+	lvg := &lvgcrd.LogicalVolumeGroup{}
+	assert.Nil(t, c.client.ReadCR(testCtx, "lvg", "", lvg))
+	assert.Equal(t, []string{newDrive.UUID}, lvg.Spec.Locations)
+	updatedAC := &accrd.AvailableCapacity{}
+	assert.Nil(t, c.client.ReadCR(testCtx, ac.Name, "", updatedAC))
+	assert.Equal(t, apiV1.StorageClassHDDLVG, updatedAC.Spec.StorageClass)
+	assert.Equal(t, lvg.Name, updatedAC.Spec.Location)
+
+	// replacement is completed and health alerts of the removed drive are resolved
+	reasons := make([]string, 0, len(recorder.Calls))
+	for _, call := range recorder.Calls {
+		reasons = append(reasons, call.Reason)
+	}
+	assert.Equal(t, []string{eventing.DriveReplacementCompleted, eventing.DriveReplacementCompleted,
+		eventing.DriveHealthGood, eventing.DriveStatusOnline}, reasons)
+
+	// replacement is handled once
+	res, err = c.Reconcile(req)
+	assert.Nil(t, err)
+	assert.Equal(t, ctrl.Result{}, res)
+	assert.Equal(t, 4, len(recorder.Calls))
+}
+
+func TestController_ReconcileReplacementExtendsLVG(t *testing.T) {
+	c, _ := setup(t)
+	lvmOps := &mocklu.MockWrapLVM{}
+	listBlk := &mocklu.MockWrapLsblk{}
+	listBlk.On("SearchDrivePath", mock.Anything).Return(targetDev, nil)
+	c.lvmOps, c.listBlk = lvmOps, listBlk
+
+	// data of the removed drive was evacuated to another drive of LVG
+	createDrive(t, c, removedDrive, map[string]string{apiV1.DriveAnnotationReplacementLVG: testLVG})
+	lvg := c.client.ConstructLVGCR(testLVG, api.LogicalVolumeGroup{Name: testLVG, Node: testNodeID,
+		Locations: []string{"uuid-other"}, Size: newDrive.Size})
+	lvg.Namespace = testNs
+	assert.Nil(t, c.client.CreateCR(testCtx, lvg.Name, lvg))
+	createDrive(t, c, newDrive, nil)
+
+	lvmOps.On("GetVGNameByPVName", targetDev).Return("", errors.New("not a PV")).Once()
+	lvmOps.On("PVCreate", targetDev).Return(nil).Once()
+	lvmOps.On("VGExtend", testLVG, []string{targetDev}).Return(nil).Once()
+	lvmOps.On("GetVgFreeSpace", testLVG).Return(int64(100), nil).Once()
+	res, err := c.Reconcile(ctrl.Request{NamespacedName: types.NamespacedName{Name: removedDrive.UUID}})
+	assert.Nil(t, err)
+	assert.Equal(t, ctrl.Result{}, res)
+	lvmOps.AssertExpectations(t)
+
+	assert.Nil(t, c.client.ReadCR(testCtx, testLVG, "", lvg))
+	assert.Equal(t, []string{"uuid-other", newDrive.UUID}, lvg.Spec.Locations)
+	assert.Equal(t, "100", lvg.Annotations[apiV1.LVGFreeSpaceAnnotation])
+	assert.Equal(t, newDrive.UUID, readDrive(t, c, removedDrive.UUID).Annotations[apiV1.DriveAnnotationReplacedBy])
+}
+
+func TestController_ReconcileReplacementExtendedLVGRetry(t *testing.T) {
+	c, _ := setup(t)
+	// drive was added to LVG, but deletion of its AC failed
+	createDrive(t, c, removedDrive, map[string]string{apiV1.DriveAnnotationReplacementLVG: testLVG})
+	lvg := c.client.ConstructLVGCR(testLVG, api.LogicalVolumeGroup{Name: testLVG, Node: testNodeID,
+		Locations: []string{"uuid-other", newDrive.UUID}, Size: 2 * newDrive.Size})
+	lvg.Namespace = testNs
+	assert.Nil(t, c.client.CreateCR(testCtx, lvg.Name, lvg))
+	createDrive(t, c, newDrive, nil)
+	ac := c.client.ConstructACCR("ac", api.AvailableCapacity{Location: newDrive.UUID, NodeId: testNodeID,
+		StorageClass: apiV1.StorageClassHDD, Size: newDrive.Size})
+	ac.Namespace = testNs
+	assert.Nil(t, c.client.CreateCR(testCtx, ac.Name, ac))
+
+	res, err := c.Reconcile(ctrl.Request{NamespacedName: types.NamespacedName{Name: removedDrive.UUID}})
+	assert.Nil(t, err)
+	assert.Equal(t, ctrl.Result{}, res)
+	// AC of the drive is deleted instead of being converted to the second AC of LVG
+	err = c.client.ReadCR(testCtx, ac.Name, "", &accrd.AvailableCapacity{})
+	assert.True(t, k8sError.IsNotFound(err))
+	assert.Equal(t, newDrive.UUID, readDrive(t, c, removedDrive.UUID).Annotations[apiV1.DriveAnnotationReplacedBy])
+}
+
+func TestController_ReconcileReplacementTimeout(t *testing.T) {
+	c, _ := setup(t)
+	removedAt := time.Now().Add(-ReplacementTimeout).UTC().Format(time.RFC3339)
+	createDrive(t, c, removedDrive, map[string]string{apiV1.DriveAnnotationRemovalTime: removedAt})
+
+	// new drive wasn't inserted in time, drive CR is deleted
+	res, err := c.Reconcile(ctrl.Request{NamespacedName: types.NamespacedName{Name: removedDrive.UUID}})
+	assert.Nil(t, err)
+	assert.Equal(t, ctrl.Result{}, res)
+	err = c.client.ReadCR(testCtx, removedDrive.UUID, "", &drivecrd.Drive{})
+	assert.True(t, k8sError.IsNotFound(err))
+}
@@ -0,0 +1,388 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package drive
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/sirupsen/logrus"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	acrcrd "github.com/dell/csi-baremetal/api/v1/acreservationcrd"
+	"github.com/dell/csi-baremetal/api/v1/drivecrd"
+	"github.com/dell/csi-baremetal/api/v1/lvgcrd"
+	"github.com/dell/csi-baremetal/api/v1/volumecrd"
+	"github.com/dell/csi-baremetal/pkg/base/audit"
+	"github.com/dell/csi-baremetal/pkg/base/capacityplanner"
+	errTypes "github.com/dell/csi-baremetal/pkg/base/error"
+	"github.com/dell/csi-baremetal/pkg/base/util"
+	"github.com/dell/csi-baremetal/pkg/eventing"
+)
+
+// maxEvacuationAttempts is the number of transient errors after which evacuation fails
+const maxEvacuationAttempts = 5
+
+// startEvacuation marks SUSPECT drive of LVG with several PVs for evacuation if LVG evacuation is enabled,
+// volumes of such drive aren't released by VolumeManager
+// Drive is updated by the caller
+func (c *Controller) startEvacuation(ctx context.Context, drive *drivecrd.Drive) error {
+	if !c.lvgEvacuation || drive.Spec.Health != apiV1.HealthSuspect || drive.Spec.IsSystem {
+		return nil
+	}
+	lvg, err := c.crHelper.GetLVGContainingDrive(ctx, drive.Spec.UUID)
+	if err != nil || lvg == nil {
+		return err
+	}
+	if len(lvg.Spec.Locations) < 2 {
+		// data of LVG on the single drive has nowhere to move, volumes are released
+		return nil
+	}
+	if drive.Annotations == nil {
+		drive.Annotations = map[string]string{}
+	}
+	drive.Annotations[apiV1.DriveAnnotationEvacuation] = apiV1.EvacuationInProgress
+	// evacuated drive is removed from LVG, membership is restored on the replacement drive
+	if err := c.saveLVGMembership(ctx, drive); err != nil {
+		return err
+	}
+	eventMsg := fmt.Sprintf("Data is being moved to another drives of LVG %s, %s", lvg.Name, drive.GetDriveDescription())
+	c.eventRecorder.Eventf(drive, eventing.WarningType, eventing.DriveEvacuationStarted, eventMsg)
+	return nil
+}
+
+// evacuate moves allocated extents of the drive to another PVs of LVG with pvmove and removes the drive from LVG.
+// If there is no enough free extents in LVG clean drive of the same type is added to LVG.
+// Each call performs next step of evacuation, progress is stored in drive annotations,
+// so evacuation is resumed after restart of the controller
+func (c *Controller) evacuate(ctx context.Context, drive *drivecrd.Drive) (ctrl.Result, error) {
+	log := c.log.WithFields(logrus.Fields{"method": "evacuate", "name": drive.Name})
+
+	lvg, err := c.crHelper.GetLVGContainingDrive(ctx, drive.Spec.UUID)
+	if err != nil {
+		return ctrl.Result{RequeueAfter: RequeueEvacuationTime}, err
+	}
+	if lvg == nil {
+		// drive was already removed from LVG
+		return c.completeEvacuation(ctx, drive)
+	}
+	if drive.Spec.Health == apiV1.HealthBad || drive.Spec.Status == apiV1.DriveStatusOffline {
+		// data can't be read from the drive anymore, volumes are released instead
+		if err := c.lvmOps.PVMoveAbort(ctx); err != nil {
+			log.Errorf("Failed to abort pvmove in VG %s: %v", lvg.Name, err)
+		}
+		c.pvMoves.Delete(lvg.Name)
+		return c.failEvacuation(ctx, drive, fmt.Errorf("drive health is %s, status is %s",
+			drive.Spec.Health, drive.Spec.Status))
+	}
+
+	src, err := c.listBlk.SearchDrivePath(ctx, drive)
+	if err != nil {
+		return c.retryEvacuation(ctx, drive, err)
+	}
+
+	percent, inProgress, err := c.lvmOps.GetPVMoveProgress(ctx, lvg.Name)
+	if err != nil {
+		log.Errorf("Failed to get pvmove progress of VG %s: %v", lvg.Name, err)
+		return ctrl.Result{RequeueAfter: RequeueEvacuationTime}, err
+	}
+	if inProgress {
+		if _, resumed := c.pvMoves.LoadOrStore(lvg.Name, true); !resumed {
+			// pvmove was interrupted by restart
+			log.Infof("Resuming pvmove from %s", src)
+			if err := c.lvmOps.PVMove(ctx, src); err != nil {
+				c.pvMoves.Delete(lvg.Name)
+				return c.retryEvacuation(ctx, drive, err)
+			}
+		}
+		return c.setEvacuationProgress(ctx, drive, percent)
+	}
+	c.pvMoves.Delete(lvg.Name)
+
+	used, free, err := c.lvmOps.GetPVUsage(ctx, src)
+	if err != nil {
+		log.Errorf("Failed to get usage of PV %s: %v", src, err)
+		return ctrl.Result{RequeueAfter: RequeueEvacuationTime}, err
+	}
+	if used == 0 {
+		if err := c.removeFromLVG(ctx, lvg, drive, src); err != nil {
+			return c.retryEvacuation(ctx, drive, err)
+		}
+		return c.completeEvacuation(ctx, drive)
+	}
+
+	var dst []string
+	targetUUID := drive.Annotations[apiV1.DriveAnnotationEvacuationTarget]
+	if targetUUID == "" {
+		vgFree, err := c.lvmOps.GetVgFreeSpace(ctx, lvg.Name)
+		if err != nil {
+			return ctrl.Result{RequeueAfter: RequeueEvacuationTime}, err
+		}
+		// extents of the drive are moved to another PVs of LVG if there is enough space
+		if vgFree-free < used {
+			target, err := c.findEvacuationTarget(ctx, drive, used)
+			if err != nil {
+				return ctrl.Result{RequeueAfter: RequeueEvacuationTime}, err
+			}
+			if target == nil {
+				return c.failEvacuation(ctx, drive,
+					fmt.Errorf("there is no free space in LVG %s and no clean drive to extend it", lvg.Name))
+			}
+			targetUUID = target.Spec.UUID
+			// target is stored before LVG is extended to resume evacuation with the same drive
+			drive.Annotations[apiV1.DriveAnnotationEvacuationTarget] = targetUUID
+			if err := c.client.UpdateCR(ctx, drive); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+	}
+	if targetUUID != "" {
+		dev, err := c.extendLVG(ctx, lvg, targetUUID)
+		if err != nil {
+			return c.retryEvacuation(ctx, drive, err)
+		}
+		dst = append(dst, dev)
+	}
+
+	log.Infof("Moving %d bytes from %s to %v", used, src, dst)
+	if err := c.lvmOps.PVMove(ctx, src, dst...); err != nil {
+		return c.retryEvacuation(ctx, drive, err)
+	}
+	c.pvMoves.Store(lvg.Name, true)
+	return c.setEvacuationProgress(ctx, drive, 0)
+}
+
+// setEvacuationProgress stores pvmove progress in drive annotation
+func (c *Controller) setEvacuationProgress(ctx context.Context, drive *drivecrd.Drive, percent float64) (ctrl.Result, error) {
+	progress := strconv.FormatFloat(percent, 'f', 2, 64)
+	if drive.Annotations[apiV1.DriveAnnotationEvacuationProgress] != progress {
+		drive.Annotations[apiV1.DriveAnnotationEvacuationProgress] = progress
+		if err := c.client.UpdateCR(ctx, drive); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	return ctrl.Result{RequeueAfter: RequeueEvacuationTime}, nil
+}
+
+// findEvacuationTarget searches for clean, not reserved drive of the same type on the node with size enough for
+// the data of the evacuated drive. Returns the smallest one or nil
+func (c *Controller) findEvacuationTarget(ctx context.Context, source *drivecrd.Drive, size int64) (*drivecrd.Drive, error) {
+	drives, err := c.crHelper.GetDriveCRs(c.nodeID)
+	if err != nil {
+		return nil, err
+	}
+	acrList := &acrcrd.AvailableCapacityReservationList{}
+	if err := c.client.ReadList(ctx, acrList); err != nil {
+		return nil, err
+	}
+
+	var target *drivecrd.Drive
+	for i := range drives {
+		drive := &drives[i]
+		if drive.Spec.UUID == source.Spec.UUID || drive.Spec.Type != source.Spec.Type || drive.Spec.IsSystem ||
+			!drive.Spec.IsClean || drive.Spec.Status != apiV1.DriveStatusOnline ||
+			drive.Spec.Health != apiV1.HealthGood || drive.Spec.Usage != apiV1.DriveUsageInUse ||
+			capacityplanner.SubtractLVMMetadataSize(drive.Spec.Size) < size {
+			continue
+		}
+		if target != nil && target.Spec.Size <= drive.Spec.Size {
+			continue
+		}
+		lvg, err := c.crHelper.GetLVGContainingDrive(ctx, drive.Spec.UUID)
+		if err != nil {
+			return nil, err
+		}
+		if lvg != nil {
+			continue
+		}
+		reserved, err := c.isReserved(drive, acrList)
+		if err != nil {
+			return nil, err
+		}
+		if !reserved {
+			target = drive
+		}
+	}
+	return target, nil
+}
+
+// isReserved returns true if AC of the drive is reserved by some pod
+func (c *Controller) isReserved(drive *drivecrd.Drive, acrList *acrcrd.AvailableCapacityReservationList) (bool, error) {
+	ac, err := c.crHelper.GetACByLocation(drive.Spec.UUID)
+	if err != nil {
+		if err == errTypes.ErrorNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+	for _, acr := range acrList.Items {
+		for _, request := range acr.Spec.ReservationRequests {
+			if util.ContainsString(request.Reservations, ac.Name) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// extendLVG adds the target drive to LVG, the drive isn't offered as separate AvailableCapacity anymore
+// Returns device path of the target drive
+func (c *Controller) extendLVG(ctx context.Context, lvg *lvgcrd.LogicalVolumeGroup, targetUUID string) (string, error) {
+	target := &drivecrd.Drive{}
+	if err := c.client.ReadCR(ctx, targetUUID, "", target); err != nil {
+		return "", err
+	}
+	if !util.ContainsString(lvg.Spec.Locations, targetUUID) {
+		lvg.Spec.Locations = append(lvg.Spec.Locations, targetUUID)
+		lvg.Spec.Size += capacityplanner.SubtractLVMMetadataSize(target.Spec.Size)
+		if err := c.client.UpdateCR(ctx, lvg); err != nil {
+			return "", err
+		}
+	}
+	ac, err := c.crHelper.GetACByLocation(targetUUID)
+	switch {
+	case err == nil:
+		if err := c.crHelper.DeleteConsumedAC(ctx, ac); err != nil {
+			return "", err
+		}
+	case err != errTypes.ErrorNotFound:
+		return "", err
+	}
+
+	dev, err := c.listBlk.SearchDrivePath(ctx, target)
+	if err != nil {
+		return "", err
+	}
+	if vg, err := c.lvmOps.GetVGNameByPVName(ctx, dev); err == nil && vg == lvg.Name {
+		return dev, nil
+	}
+	if err := c.lvmOps.PVCreate(audit.WithTarget(ctx, audit.Target{DriveSerial: target.Spec.SerialNumber}), dev); err != nil {
+		return "", err
+	}
+	if err := c.lvmOps.VGExtend(ctx, lvg.Name, dev); err != nil {
+		return "", err
+	}
+	return dev, nil
+}
+
+// removeFromLVG removes evacuated drive from VG and LVG CR, LVG becomes healthy
+func (c *Controller) removeFromLVG(ctx context.Context, lvg *lvgcrd.LogicalVolumeGroup, drive *drivecrd.Drive, dev string) error {
+	if err := c.lvmOps.VGReduce(ctx, lvg.Name, dev); err != nil {
+		return err
+	}
+	if err := c.lvmOps.PVRemove(audit.WithTarget(ctx, audit.Target{DriveSerial: drive.Spec.SerialNumber}), dev); err != nil {
+		c.log.WithField("method", "removeFromLVG").Errorf("Unable to remove PV %s: %v", dev, err)
+	}
+	free, err := c.lvmOps.GetVgFreeSpace(ctx, lvg.Name)
+	if err != nil {
+		return err
+	}
+	lvg.Spec.Locations = util.RemoveString(lvg.Spec.Locations, drive.Spec.UUID)
+	lvg.Spec.Size -= capacityplanner.SubtractLVMMetadataSize(drive.Spec.Size)
+	lvg.Spec.Health = apiV1.HealthGood
+	if lvg.Annotations == nil {
+		lvg.Annotations = map[string]string{}
+	}
+	// AC of LVG is restored by capacity controller
+	lvg.Annotations[apiV1.LVGFreeSpaceAnnotation] = strconv.FormatInt(free, 10)
+	return c.client.UpdateCR(ctx, lvg)
+}
+
+// completeEvacuation moves evacuated drive to RELEASED usage, volumes aren't touched
+func (c *Controller) completeEvacuation(ctx context.Context, drive *drivecrd.Drive) (ctrl.Result, error) {
+	drive.Annotations[apiV1.DriveAnnotationEvacuation] = apiV1.EvacuationDone
+	drive.Annotations[apiV1.DriveAnnotationEvacuationProgress] = strconv.FormatFloat(100, 'f', 2, 64)
+	drive.Spec.Usage = apiV1.DriveUsageReleased
+	if err := c.client.UpdateCR(ctx, drive); err != nil {
+		return ctrl.Result{}, err
+	}
+	eventMsg := fmt.Sprintf("Data was moved to another drives of LVG, %s", drive.GetDriveDescription())
+	c.eventRecorder.Eventf(drive, eventing.NormalType, eventing.DriveEvacuationCompleted, eventMsg)
+	eventMsg = fmt.Sprintf("Drive is ready for replacement, %s", drive.GetDriveDescription())
+	c.eventRecorder.Eventf(drive, eventing.NormalType, eventing.DriveReadyForReplacement, eventMsg)
+	return ctrl.Result{}, nil
+}
+
+// retryEvacuation requeues evacuation after transient error, evacuation fails after maxEvacuationAttempts errors
+func (c *Controller) retryEvacuation(ctx context.Context, drive *drivecrd.Drive, cause error) (ctrl.Result, error) {
+	attempts, _ := strconv.Atoi(drive.Annotations[apiV1.DriveAnnotationEvacuationAttempts])
+	attempts++
+	if attempts >= maxEvacuationAttempts {
+		return c.failEvacuation(ctx, drive, fmt.Errorf("%d attempts failed, last error: %v", attempts, cause))
+	}
+	c.log.WithFields(logrus.Fields{"method": "retryEvacuation", "name": drive.Name}).
+		Warnf("Evacuation attempt %d failed: %v", attempts, cause)
+	drive.Annotations[apiV1.DriveAnnotationEvacuationAttempts] = strconv.Itoa(attempts)
+	if err := c.client.UpdateCR(ctx, drive); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: RequeueEvacuationTime}, nil
+}
+
+// failEvacuation falls back to the release of the drive volumes, it is called when evacuation can't be completed:
+// drive went BAD or OFFLINE, there is no space to move data or transient errors repeat
+func (c *Controller) failEvacuation(ctx context.Context, drive *drivecrd.Drive, cause error) (ctrl.Result, error) {
+	log := c.log.WithFields(logrus.Fields{"method": "failEvacuation", "name": drive.Name})
+	log.Errorf("Evacuation failed: %v", cause)
+
+	volumes, err := c.getEvacuatedVolumes(ctx, drive)
+	if err != nil {
+		return ctrl.Result{RequeueAfter: RequeueEvacuationTime}, err
+	}
+	for i := range volumes {
+		vol := &volumes[i]
+		if vol.Spec.Usage != apiV1.VolumeUsageInUse {
+			continue
+		}
+		vol.Spec.Health = drive.Spec.Health
+		vol.Spec.Usage = apiV1.VolumeUsageReleasing
+		if err := c.client.UpdateCR(ctx, vol); err != nil {
+			log.Errorf("Failed to update volume %s: %v", vol.Name, err)
+			return ctrl.Result{RequeueAfter: RequeueEvacuationTime}, err
+		}
+	}
+
+	drive.Annotations[apiV1.DriveAnnotationEvacuation] = apiV1.EvacuationFailed
+	if err := c.client.UpdateCR(ctx, drive); err != nil {
+		return ctrl.Result{}, err
+	}
+	eventMsg := fmt.Sprintf("Failed to move data to another drives of LVG, volumes are released: %v, %s",
+		cause, drive.GetDriveDescription())
+	c.eventRecorder.Eventf(drive, eventing.ErrorType, eventing.DriveEvacuationFailed, eventMsg)
+	return ctrl.Result{}, nil
+}
+
+// getEvacuatedVolumes returns volumes of LVG which contains the drive, drive might be not the first PV of LVG
+func (c *Controller) getEvacuatedVolumes(ctx context.Context, drive *drivecrd.Drive) ([]volumecrd.Volume, error) {
+	lvg, err := c.crHelper.GetLVGContainingDrive(ctx, drive.Spec.UUID)
+	if err != nil || lvg == nil {
+		return nil, err
+	}
+	volumeList := &volumecrd.VolumeList{}
+	if err := c.client.ReadList(ctx, volumeList); err != nil {
+		return nil, err
+	}
+	var volumes []volumecrd.Volume
+	for _, volume := range volumeList.Items {
+		if volume.Spec.Location == lvg.Name {
+			volumes = append(volumes, volume)
+		}
+	}
+	return volumes, nil
+}
@@ -0,0 +1,240 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package drive
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	k8sError "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	accrd "github.com/dell/csi-baremetal/api/v1/availablecapacitycrd"
+	"github.com/dell/csi-baremetal/api/v1/drivecrd"
+	"github.com/dell/csi-baremetal/api/v1/lvgcrd"
+	"github.com/dell/csi-baremetal/api/v1/volumecrd"
+	"github.com/dell/csi-baremetal/pkg/eventing"
+	"github.com/dell/csi-baremetal/pkg/mocks"
+	mocklu "github.com/dell/csi-baremetal/pkg/mocks/linuxutils"
+)
+
+var (
+	suspectDrive = api.Drive{
+		UUID:         "uuid-suspect",
+		SerialNumber: "sn-suspect",
+		Type:         apiV1.DriveTypeHDD,
+		Size:         1024 * 1024 * 1024,
+		Health:       apiV1.HealthSuspect,
+		Status:       apiV1.DriveStatusOnline,
+		Usage:        apiV1.DriveUsageInUse,
+		NodeId:       testNodeID,
+	}
+	suspectDev = "/dev/sda"
+	targetDev  = "/dev/sdb"
+	testLVG    = "lvg"
+	// another PV of test LVG
+	otherUUID = "uuid-other"
+)
+
+func setupEvacuation(t *testing.T) (*Controller, *mocklu.MockWrapLVM, *mocks.NoOpRecorder) {
+	c, recorder := setup(t)
+	c.lvgEvacuation = true
+	lvmOps := &mocklu.MockWrapLVM{}
+	listBlk := &mocklu.MockWrapLsblk{}
+	listBlk.On("SearchDrivePath", mock.MatchedBy(func(d *drivecrd.Drive) bool {
+		return d.Spec.UUID == suspectDrive.UUID
+	})).Return(suspectDev, nil)
+	listBlk.On("SearchDrivePath", mock.MatchedBy(func(d *drivecrd.Drive) bool {
+		return d.Spec.UUID == newDrive.UUID
+	})).Return(targetDev, nil)
+	c.lvmOps, c.listBlk = lvmOps, listBlk
+
+	createDrive(t, c, suspectDrive, nil)
+	lvg := c.client.ConstructLVGCR(testLVG, api.LogicalVolumeGroup{Name: testLVG, Node: testNodeID,
+		Locations: []string{suspectDrive.UUID, otherUUID}, Size: suspectDrive.Size, Health: apiV1.HealthSuspect})
+	lvg.Namespace = testNs
+	assert.Nil(t, c.client.CreateCR(testCtx, lvg.Name, lvg))
+	volume := c.client.ConstructVolumeCR("volume", testNs, api.Volume{Id: "volume", Location: testLVG,
+		LocationType: apiV1.LocationTypeLVM, NodeId: testNodeID, Usage: apiV1.VolumeUsageInUse})
+	assert.Nil(t, c.client.CreateCR(testCtx, volume.Name, volume))
+
+	// SUSPECT drive of LVG is marked for evacuation
+	res, err := c.Reconcile(ctrl.Request{NamespacedName: types.NamespacedName{Name: suspectDrive.UUID}})
+	assert.Nil(t, err)
+	assert.Equal(t, ctrl.Result{}, res)
+	drive := readDrive(t, c, suspectDrive.UUID)
+	assert.Equal(t, apiV1.DriveUsageReleasing, drive.Spec.Usage)
+	assert.Equal(t, apiV1.EvacuationInProgress, drive.Annotations[apiV1.DriveAnnotationEvacuation])
+	assert.Equal(t, testLVG, drive.Annotations[apiV1.DriveAnnotationReplacementLVG])
+	return c, lvmOps, recorder
+}
+
+func readVolumeUsage(t *testing.T, c *Controller) string {
+	volume := &volumecrd.Volume{}
+	assert.Nil(t, c.client.ReadCR(testCtx, "volume", testNs, volume))
+	return volume.Spec.Usage
+}
+
+func TestController_Evacuate(t *testing.T) {
+	c, lvmOps, _ := setupEvacuation(t)
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: suspectDrive.UUID}}
+	createDrive(t, c, newDrive, nil)
+	ac := c.client.ConstructACCR("ac", api.AvailableCapacity{Location: newDrive.UUID, NodeId: testNodeID,
+		StorageClass: apiV1.StorageClassHDD, Size: newDrive.Size})
+	ac.Namespace = testNs
+	assert.Nil(t, c.client.CreateCR(testCtx, ac.Name, ac))
+
+	// there is no free space in LVG, new drive is added
+	lvmOps.On("GetPVMoveProgress", testLVG).Return(float64(0), false, nil).Once()
+	lvmOps.On("GetPVUsage", suspectDev).Return(int64(100), int64(0), nil).Once()
+	lvmOps.On("GetVgFreeSpace", testLVG).Return(int64(0), nil).Once()
+	lvmOps.On("GetVGNameByPVName", targetDev).Return("", errors.New("not a PV")).Once()
+	lvmOps.On("PVCreate", targetDev).Return(nil).Once()
+	lvmOps.On("VGExtend", testLVG, []string{targetDev}).Return(nil).Once()
+	lvmOps.On("PVMove", suspectDev, []string{targetDev}).Return(nil).Once()
+	res, err := c.Reconcile(req)
+	assert.Nil(t, err)
+	assert.Equal(t, RequeueEvacuationTime, res.RequeueAfter)
+	drive := readDrive(t, c, suspectDrive.UUID)
+	assert.Equal(t, newDrive.UUID, drive.Annotations[apiV1.DriveAnnotationEvacuationTarget])
+	assert.Equal(t, "0.00", drive.Annotations[apiV1.DriveAnnotationEvacuationProgress])
+	lvg := &lvgcrd.LogicalVolumeGroup{}
+	assert.Nil(t, c.client.ReadCR(testCtx, testLVG, "", lvg))
+	assert.Equal(t, []string{suspectDrive.UUID, otherUUID, newDrive.UUID}, lvg.Spec.Locations)
+	err = c.client.ReadCR(testCtx, ac.Name, "", &accrd.AvailableCapacity{})
+	assert.True(t, k8sError.IsNotFound(err))
+
+	// progress is reported
+	lvmOps.On("GetPVMoveProgress", testLVG).Return(42.5, true, nil).Once()
+	res, err = c.Reconcile(req)
+	assert.Nil(t, err)
+	assert.Equal(t, RequeueEvacuationTime, res.RequeueAfter)
+	assert.Equal(t, "42.50", readDrive(t, c, suspectDrive.UUID).Annotations[apiV1.DriveAnnotationEvacuationProgress])
+
+	// interrupted pvmove is resumed after restart
+	c.pvMoves.Delete(testLVG)
+	lvmOps.On("GetPVMoveProgress", testLVG).Return(50.0, true, nil).Once()
+	lvmOps.On("PVMove", suspectDev, []string(nil)).Return(nil).Once()
+	_, err = c.Reconcile(req)
+	assert.Nil(t, err)
+
+	// drive is removed from LVG
+	lvmOps.On("GetPVMoveProgress", testLVG).Return(float64(0), false, nil).Once()
+	lvmOps.On("GetPVUsage", suspectDev).Return(int64(0), int64(100), nil).Once()
+	lvmOps.On("VGReduce", testLVG, []string{suspectDev}).Return(nil).Once()
+	lvmOps.On("PVRemove", suspectDev).Return(nil).Once()
+	lvmOps.On("GetVgFreeSpace", testLVG).Return(int64(50), nil).Once()
+	res, err = c.Reconcile(req)
+	assert.Nil(t, err)
+	assert.Equal(t, ctrl.Result{}, res)
+	lvmOps.AssertExpectations(t)
+
+	drive = readDrive(t, c, suspectDrive.UUID)
+	assert.Equal(t, apiV1.DriveUsageReleased, drive.Spec.Usage)
+	assert.Equal(t, apiV1.EvacuationDone, drive.Annotations[apiV1.DriveAnnotationEvacuation])
+	assert.Nil(t, c.client.ReadCR(testCtx, testLVG, "", lvg))
+	assert.Equal(t, []string{otherUUID, newDrive.UUID}, lvg.Spec.Locations)
+	assert.Equal(t, apiV1.HealthGood, lvg.Spec.Health)
+	assert.Equal(t, "50", lvg.Annotations[apiV1.LVGFreeSpaceAnnotation])
+	assert.Equal(t, apiV1.VolumeUsageInUse, readVolumeUsage(t, c))
+}
+
+func TestController_EvacuateFailed(t *testing.T) {
+	c, lvmOps, recorder := setupEvacuation(t)
+
+	// there is no free space in LVG and no clean drives
+	lvmOps.On("GetPVMoveProgress", testLVG).Return(float64(0), false, nil).Once()
+	lvmOps.On("GetPVUsage", suspectDev).Return(int64(100), int64(0), nil).Once()
+	lvmOps.On("GetVgFreeSpace", testLVG).Return(int64(0), nil).Once()
+	res, err := c.Reconcile(ctrl.Request{NamespacedName: types.NamespacedName{Name: suspectDrive.UUID}})
+	assert.Nil(t, err)
+	assert.Equal(t, ctrl.Result{}, res)
+
+	drive := readDrive(t, c, suspectDrive.UUID)
+	assert.Equal(t, apiV1.DriveUsageReleasing, drive.Spec.Usage)
+	assert.Equal(t, apiV1.EvacuationFailed, drive.Annotations[apiV1.DriveAnnotationEvacuation])
+	// volumes are released instead
+	assert.Equal(t, apiV1.VolumeUsageReleasing, readVolumeUsage(t, c))
+	assert.Equal(t, eventing.DriveEvacuationFailed, recorder.Calls[len(recorder.Calls)-1].Reason)
+}
+
+func TestController_EvacuateRetry(t *testing.T) {
+	c, lvmOps, _ := setupEvacuation(t)
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: suspectDrive.UUID}}
+
+	// transient error, evacuation is retried
+	lvmOps.On("GetPVMoveProgress", testLVG).Return(float64(0), false, nil)
+	lvmOps.On("GetPVUsage", suspectDev).Return(int64(100), int64(0), nil)
+	lvmOps.On("GetVgFreeSpace", testLVG).Return(int64(200), nil)
+	lvmOps.On("PVMove", suspectDev, []string(nil)).Return(errors.New("error"))
+	res, err := c.Reconcile(req)
+	assert.Nil(t, err)
+	assert.Equal(t, RequeueEvacuationTime, res.RequeueAfter)
+	drive := readDrive(t, c, suspectDrive.UUID)
+	assert.Equal(t, apiV1.EvacuationInProgress, drive.Annotations[apiV1.DriveAnnotationEvacuation])
+	assert.Equal(t, "1", drive.Annotations[apiV1.DriveAnnotationEvacuationAttempts])
+	assert.Equal(t, apiV1.VolumeUsageInUse, readVolumeUsage(t, c))
+
+	// evacuation fails after several attempts
+	for i := 1; i < maxEvacuationAttempts; i++ {
+		_, err = c.Reconcile(req)
+		assert.Nil(t, err)
+	}
+	drive = readDrive(t, c, suspectDrive.UUID)
+	assert.Equal(t, apiV1.EvacuationFailed, drive.Annotations[apiV1.DriveAnnotationEvacuation])
+	assert.Equal(t, apiV1.VolumeUsageReleasing, readVolumeUsage(t, c))
+}
+
+func TestController_EvacuateBadDrive(t *testing.T) {
+	c, lvmOps, _ := setupEvacuation(t)
+
+	// drive becomes BAD during pvmove
+	drive := readDrive(t, c, suspectDrive.UUID)
+	drive.Spec.Health = apiV1.HealthBad
+	assert.Nil(t, c.client.UpdateCR(testCtx, drive))
+	lvmOps.On("PVMoveAbort").Return(nil).Once()
+	res, err := c.Reconcile(ctrl.Request{NamespacedName: types.NamespacedName{Name: suspectDrive.UUID}})
+	assert.Nil(t, err)
+	assert.Equal(t, ctrl.Result{}, res)
+	lvmOps.AssertExpectations(t)
+
+	drive = readDrive(t, c, suspectDrive.UUID)
+	assert.Equal(t, apiV1.EvacuationFailed, drive.Annotations[apiV1.DriveAnnotationEvacuation])
+	assert.Equal(t, apiV1.VolumeUsageReleasing, readVolumeUsage(t, c))
+}
+
+func TestController_StartEvacuationSinglePV(t *testing.T) {
+	c, _ := setup(t)
+	c.lvgEvacuation = true
+	createDrive(t, c, suspectDrive, nil)
+	lvg := c.client.ConstructLVGCR(testLVG, api.LogicalVolumeGroup{Name: testLVG, Node: testNodeID,
+		Locations: []string{suspectDrive.UUID}})
+	lvg.Namespace = testNs
+	assert.Nil(t, c.client.CreateCR(testCtx, lvg.Name, lvg))
+
+	// data of LVG on the single drive isn't evacuated
+	_, err := c.Reconcile(ctrl.Request{NamespacedName: types.NamespacedName{Name: suspectDrive.UUID}})
+	assert.Nil(t, err)
+	drive := readDrive(t, c, suspectDrive.UUID)
+	assert.Equal(t, apiV1.DriveUsageReleasing, drive.Spec.Usage)
+	assert.Empty(t, drive.Annotations[apiV1.DriveAnnotationEvacuation])
+}
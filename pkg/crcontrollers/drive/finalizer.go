@@ -0,0 +1,98 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package drive
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	"github.com/dell/csi-baremetal/api/v1/drivecrd"
+	"github.com/dell/csi-baremetal/pkg/base/util"
+	"github.com/dell/csi-baremetal/pkg/eventing"
+)
+
+// driveFinalizer protects Drive CR from removal while Volume or LogicalVolumeGroup CRs refer to the drive
+const driveFinalizer = "dell.emc.csi/drive-cleanup"
+
+// appendFinalizer appends finalizer to the Drive CR if it is absent, drive is updated in place
+func (c *Controller) appendFinalizer(ctx context.Context, drive *drivecrd.Drive) error {
+	if util.ContainsString(drive.Finalizers, driveFinalizer) {
+		return nil
+	}
+	drive.Finalizers = append(drive.Finalizers, driveFinalizer)
+	if err := c.client.UpdateCR(ctx, drive); err != nil {
+		c.log.WithField("driveName", drive.Name).
+			Errorf("Unable to append finalizer %s to Drive: %v", driveFinalizer, err)
+		return err
+	}
+	return nil
+}
+
+// handleDriveRemoving removes finalizer of the deleted Drive CR when there are no Volume and LogicalVolumeGroup CRs
+// on the drive, otherwise deletion is postponed. Finalizer is removed immediately if drive has force delete annotation
+func (c *Controller) handleDriveRemoving(ctx context.Context, drive *drivecrd.Drive) (ctrl.Result, error) {
+	if !util.ContainsString(drive.Finalizers, driveFinalizer) {
+		return ctrl.Result{}, nil
+	}
+	log := c.log.WithField("method", "handleDriveRemoving").WithField("driveName", drive.Name)
+
+	refs, err := c.getDriveReferences(ctx, drive)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if len(refs) > 0 {
+		if drive.Annotations[apiV1.AnnotationForceDelete] != apiV1.ForceDeleteOn {
+			log.Infof("Drive is in use by %s, deletion is postponed", strings.Join(refs, ", "))
+			return ctrl.Result{RequeueAfter: RequeueDeletionTime}, nil
+		}
+		log.Warnf("Drive is force deleted while it is in use by %s", strings.Join(refs, ", "))
+		eventMsg := fmt.Sprintf("Drive is force deleted while it is in use by %s, %s",
+			strings.Join(refs, ", "), drive.GetDriveDescription())
+		c.eventRecorder.Eventf(drive, eventing.WarningType, eventing.DriveForceDeleted, eventMsg)
+	}
+
+	drive.Finalizers = util.RemoveString(drive.Finalizers, driveFinalizer)
+	if err := c.client.UpdateCR(ctx, drive); err != nil {
+		log.Errorf("Unable to remove finalizer %s: %v", driveFinalizer, err)
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// getDriveReferences returns descriptions of Volume and LogicalVolumeGroup CRs which are located on the drive
+func (c *Controller) getDriveReferences(ctx context.Context, drive *drivecrd.Drive) ([]string, error) {
+	refs := make([]string, 0)
+	volumes, err := c.crHelper.GetVolumesByLocation(ctx, drive.Spec.UUID)
+	if err != nil {
+		return nil, err
+	}
+	for _, volume := range volumes {
+		refs = append(refs, "Volume "+volume.Name)
+	}
+	lvg, err := c.crHelper.GetLVGContainingDrive(ctx, drive.Spec.UUID)
+	if err != nil {
+		return nil, err
+	}
+	if lvg != nil {
+		refs = append(refs, "LogicalVolumeGroup "+lvg.Name)
+	}
+	return refs, nil
+}
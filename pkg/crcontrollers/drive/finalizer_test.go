@@ -0,0 +1,84 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package drive
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	"github.com/dell/csi-baremetal/pkg/base/util"
+	"github.com/dell/csi-baremetal/pkg/eventing"
+)
+
+func markDriveDeleted(t *testing.T, c *Controller, name string, annotations map[string]string) {
+	drive := readDrive(t, c, name)
+	now := metav1.Now()
+	drive.DeletionTimestamp = &now
+	drive.Annotations = annotations
+	assert.Nil(t, c.client.UpdateCR(testCtx, drive))
+}
+
+func TestController_ReconcileDriveFinalizer(t *testing.T) {
+	c, recorder := setup(t)
+	createDrive(t, c, newDrive, nil)
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: newDrive.UUID}}
+
+	_, err := c.Reconcile(req)
+	assert.Nil(t, err)
+	assert.True(t, util.ContainsString(readDrive(t, c, newDrive.UUID).Finalizers, driveFinalizer))
+
+	volume := c.client.ConstructVolumeCR("volume", testNs, api.Volume{Id: "volume", Location: newDrive.UUID,
+		NodeId: testNodeID, LocationType: apiV1.LocationTypeDrive})
+	assert.Nil(t, c.client.CreateCR(testCtx, volume.Name, volume))
+
+	// drive with volume isn't released
+	markDriveDeleted(t, c, newDrive.UUID, nil)
+	res, err := c.Reconcile(req)
+	assert.Nil(t, err)
+	assert.Equal(t, ctrl.Result{RequeueAfter: RequeueDeletionTime}, res)
+	assert.True(t, util.ContainsString(readDrive(t, c, newDrive.UUID).Finalizers, driveFinalizer))
+
+	// force delete
+	markDriveDeleted(t, c, newDrive.UUID, map[string]string{apiV1.AnnotationForceDelete: apiV1.ForceDeleteOn})
+	res, err = c.Reconcile(req)
+	assert.Nil(t, err)
+	assert.Equal(t, ctrl.Result{}, res)
+	assert.False(t, util.ContainsString(readDrive(t, c, newDrive.UUID).Finalizers, driveFinalizer))
+	assert.Equal(t, 1, len(recorder.Calls))
+	assert.Equal(t, eventing.DriveForceDeleted, recorder.Calls[0].Reason)
+}
+
+func TestController_ReconcileDriveFinalizerNotInUse(t *testing.T) {
+	c, recorder := setup(t)
+	createDrive(t, c, newDrive, nil)
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: newDrive.UUID}}
+	_, err := c.Reconcile(req)
+	assert.Nil(t, err)
+
+	markDriveDeleted(t, c, newDrive.UUID, nil)
+	res, err := c.Reconcile(req)
+	assert.Nil(t, err)
+	assert.Equal(t, ctrl.Result{}, res)
+	assert.False(t, util.ContainsString(readDrive(t, c, newDrive.UUID).Finalizers, driveFinalizer))
+	assert.Empty(t, recorder.Calls)
+}
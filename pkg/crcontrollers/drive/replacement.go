@@ -0,0 +1,328 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package drive
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	"github.com/dell/csi-baremetal/api/v1/drivecrd"
+	"github.com/dell/csi-baremetal/api/v1/lvgcrd"
+	"github.com/dell/csi-baremetal/pkg/base/capacityplanner"
+	errTypes "github.com/dell/csi-baremetal/pkg/base/error"
+	"github.com/dell/csi-baremetal/pkg/base/util"
+	"github.com/dell/csi-baremetal/pkg/eventing"
+)
+
+// hasSlot returns true if drive manager reports physical location of the drive
+func hasSlot(drive *drivecrd.Drive) bool {
+	return drive.Spec.Slot != "" || drive.Spec.Bay != ""
+}
+
+// isSameSlot returns true if drives are located in the same enclosure, slot and bay
+func isSameSlot(first, second *drivecrd.Drive) bool {
+	return first.Spec.Enclosure == second.Spec.Enclosure &&
+		first.Spec.Slot == second.Spec.Slot &&
+		first.Spec.Bay == second.Spec.Bay
+}
+
+// saveLVGMembership stores name of LVG of the drive and its LVM based storage class in drive annotations
+// to restore LVG membership on the replacement drive. Stored membership isn't overwritten, so it survives
+// removal of the drive from LVG during evacuation. Drive is updated by the caller
+func (c *Controller) saveLVGMembership(ctx context.Context, drive *drivecrd.Drive) error {
+	if drive.Spec.IsSystem {
+		return nil
+	}
+	if _, found := drive.Annotations[apiV1.DriveAnnotationReplacementLVG]; found {
+		return nil
+	}
+	lvg, err := c.crHelper.GetLVGContainingDrive(ctx, drive.Spec.UUID)
+	if err != nil || lvg == nil {
+		return err
+	}
+	if drive.Annotations == nil {
+		drive.Annotations = map[string]string{}
+	}
+	drive.Annotations[apiV1.DriveAnnotationReplacementLVG] = lvg.Name
+	if sc := util.GetLVGStorageClass(util.ConvertDriveTypeToStorageClass(drive.Spec.Type)); sc != "" {
+		drive.Annotations[apiV1.DriveAnnotationReplacementLVGSC] = sc
+	}
+	return nil
+}
+
+// waitsForReplacement returns true if CR of the removed drive is kept until the new drive is inserted into its slot.
+// Drive without slot information or not replaced during ReplacementTimeout is deleted
+func waitsForReplacement(drive *drivecrd.Drive) bool {
+	if !hasSlot(drive) {
+		return false
+	}
+	if _, replaced := drive.Annotations[apiV1.DriveAnnotationReplacedBy]; replaced {
+		return true
+	}
+	removalTime, found := drive.Annotations[apiV1.DriveAnnotationRemovalTime]
+	if !found {
+		return true
+	}
+	removedAt, err := time.Parse(time.RFC3339, removalTime)
+	return err == nil && time.Since(removedAt) < ReplacementTimeout
+}
+
+// findReplacement searches for clean drive which was inserted into the slot of the removed drive
+// Returns nil if there is no such drive yet
+func (c *Controller) findReplacement(removed *drivecrd.Drive) (*drivecrd.Drive, error) {
+	drives, err := c.crHelper.GetDriveCRs(c.nodeID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range drives {
+		drive := &drives[i]
+		if drive.Spec.UUID == removed.Spec.UUID || !isSameSlot(drive, removed) {
+			continue
+		}
+		if _, found := drive.Annotations[apiV1.DriveAnnotationReplaces]; found {
+			continue
+		}
+		if drive.Spec.Status == apiV1.DriveStatusOnline && drive.Spec.Usage == apiV1.DriveUsageInUse &&
+			drive.Spec.Health == apiV1.HealthGood && drive.Spec.IsClean {
+			return drive, nil
+		}
+	}
+	return nil, nil
+}
+
+// handleReplacement links removed drive with the new drive inserted into the same slot,
+// rebuilds LVG on the new drive if removed drive was a part of LVG and turns off locate LED
+// Drive CR of the removed drive is kept with replacement history in annotations
+func (c *Controller) handleReplacement(ctx context.Context, removed *drivecrd.Drive) (ctrl.Result, error) {
+	log := c.log.WithFields(logrus.Fields{"method": "handleReplacement", "name": removed.Name})
+
+	if replacedBy, found := removed.Annotations[apiV1.DriveAnnotationReplacedBy]; found {
+		log.Debugf("Drive was already replaced by %s", replacedBy)
+		return ctrl.Result{}, nil
+	}
+
+	if _, found := removed.Annotations[apiV1.DriveAnnotationRemovalTime]; !found {
+		// replacement is awaited during ReplacementTimeout since the drive went offline
+		if removed.Annotations == nil {
+			removed.Annotations = map[string]string{}
+		}
+		removed.Annotations[apiV1.DriveAnnotationRemovalTime] = time.Now().UTC().Format(time.RFC3339)
+		if err := c.client.UpdateCR(ctx, removed); err != nil {
+			log.Errorf("Failed to update Drive %s CR: %v", removed.Name, err)
+			return ctrl.Result{}, err
+		}
+	}
+
+	drive, err := c.findReplacement(removed)
+	if err != nil {
+		log.Errorf("Failed to search replacement drive: %v", err)
+		return ctrl.Result{}, err
+	}
+	if drive == nil {
+		log.Debugf("New drive isn't inserted into slot %s/%s/%s yet",
+			removed.Spec.Enclosure, removed.Spec.Slot, removed.Spec.Bay)
+		return ctrl.Result{RequeueAfter: RequeueReplacementTime}, nil
+	}
+	log.Infof("Drive %s was inserted instead of the removed one", drive.Spec.SerialNumber)
+
+	if lvgName, found := removed.Annotations[apiV1.DriveAnnotationReplacementLVG]; found {
+		// capacity of the new drive isn't offered as a plain drive until LVG is restored on it
+		if _, restoring := drive.Annotations[apiV1.DriveAnnotationReplacementRestoring]; !restoring {
+			if drive.Annotations == nil {
+				drive.Annotations = map[string]string{}
+			}
+			drive.Annotations[apiV1.DriveAnnotationReplacementRestoring] = lvgName
+			if err := c.client.UpdateCR(ctx, drive); err != nil {
+				log.Errorf("Failed to update Drive %s CR: %v", drive.Name, err)
+				return ctrl.Result{}, err
+			}
+		}
+		restored, err := c.restoreLVG(ctx, removed, drive, lvgName)
+		if err != nil {
+			log.Errorf("Failed to restore LVG %s on drive %s: %v", lvgName, drive.Spec.SerialNumber, err)
+			return ctrl.Result{}, err
+		}
+		if !restored {
+			return ctrl.Result{RequeueAfter: RequeueReplacementTime}, nil
+		}
+	}
+
+	status, err := c.driveMgrClient.Locate(ctx, &api.DriveLocateRequest{Action: apiV1.LocateStop,
+		DriveSerialNumber: removed.Spec.SerialNumber})
+	if err != nil || status.Status != apiV1.LocateStatusOff {
+		// LED of the removed drive might be unavailable, replacement is completed anyway
+		log.Warnf("Failed to turn off locate LED of drive %s, err %v", removed.Spec.SerialNumber, err)
+	}
+
+	if drive.Annotations == nil {
+		drive.Annotations = map[string]string{}
+	}
+	drive.Annotations[apiV1.DriveAnnotationReplaces] = removed.Spec.UUID
+	delete(drive.Annotations, apiV1.DriveAnnotationReplacementRestoring)
+	if err := c.client.UpdateCR(ctx, drive); err != nil {
+		log.Errorf("Failed to update Drive %s CR: %v", drive.Name, err)
+		return ctrl.Result{}, err
+	}
+
+	if removed.Annotations == nil {
+		removed.Annotations = map[string]string{}
+	}
+	removed.Annotations[apiV1.DriveAnnotationReplacedBy] = drive.Spec.UUID
+	removed.Annotations[apiV1.DriveAnnotationReplacementTime] = time.Now().UTC().Format(time.RFC3339)
+	if err := c.client.UpdateCR(ctx, removed); err != nil {
+		log.Errorf("Failed to update Drive %s CR: %v", removed.Name, err)
+		return ctrl.Result{}, err
+	}
+
+	eventMsg := fmt.Sprintf("Drive was replaced by drive with SN='%s', %s",
+		drive.Spec.SerialNumber, removed.GetDriveDescription())
+	c.eventRecorder.Eventf(removed, eventing.NormalType, eventing.DriveReplacementCompleted, eventMsg)
+	eventMsg = fmt.Sprintf("Drive replaces drive with SN='%s', %s",
+		removed.Spec.SerialNumber, drive.GetDriveDescription())
+	c.eventRecorder.Eventf(drive, eventing.NormalType, eventing.DriveReplacementCompleted, eventMsg)
+	c.restoreHealthAlerts(removed, drive)
+	return ctrl.Result{}, nil
+}
+
+// restoreHealthAlerts resolves health and status alerts raised for the removed drive,
+// alerts of the replacement drive are raised by its own health and status changes
+func (c *Controller) restoreHealthAlerts(removed, drive *drivecrd.Drive) {
+	if removed.Spec.Health != apiV1.HealthGood {
+		eventMsg := fmt.Sprintf("Drive health alert is resolved, drive was replaced by drive with SN='%s', %s",
+			drive.Spec.SerialNumber, removed.GetDriveDescription())
+		c.eventRecorder.Eventf(removed, eventing.NormalType, eventing.DriveHealthGood, eventMsg)
+	}
+	eventMsg := fmt.Sprintf("Drive status alert is resolved, drive was replaced by drive with SN='%s', %s",
+		drive.Spec.SerialNumber, removed.GetDriveDescription())
+	c.eventRecorder.Eventf(removed, eventing.NormalType, eventing.DriveStatusOnline, eventMsg)
+}
+
+// restoreLVG restores membership of the removed drive in LVG lvgName on the replacement drive.
+// If LVG still exists, data of the removed drive was evacuated, the replacement drive is added to LVG and its AC
+// is deleted. Otherwise LVG with the same name is created on the replacement drive and its AC is converted to LVM based
+// storage class of the removed drive. Returns false if LVG is being released or AC of the drive isn't created yet
+func (c *Controller) restoreLVG(ctx context.Context, removed, drive *drivecrd.Drive, lvgName string) (bool, error) {
+	log := c.log.WithFields(logrus.Fields{"method": "restoreLVG", "name": lvgName})
+
+	lvg := &lvgcrd.LogicalVolumeGroup{}
+	err := c.client.ReadCR(ctx, lvgName, "", lvg)
+	switch {
+	case k8serrors.IsNotFound(err):
+		return c.recreateLVG(ctx, removed, drive, lvgName)
+	case err != nil:
+		return false, err
+	case !lvg.DeletionTimestamp.IsZero() || util.ContainsString(lvg.Spec.Locations, removed.Spec.UUID):
+		// LVG on the removed drive is deleted after release of its volumes, then it is recreated
+		log.Debugf("LVG still contains drive %s", removed.Spec.UUID)
+		return false, nil
+	case util.ContainsString(lvg.Spec.Locations, drive.Spec.UUID) && len(lvg.Spec.Locations) == 1:
+		// LVG was already recreated on the drive
+		return c.convertACToLVG(ctx, removed, drive, lvg)
+	case util.ContainsString(lvg.Spec.Locations, drive.Spec.UUID):
+		// drive was already added to the existing LVG, its capacity is a part of AC of LVG
+		return c.deleteDriveAC(ctx, drive)
+	}
+
+	if _, err := c.extendLVG(ctx, lvg, drive.Spec.UUID); err != nil {
+		return false, err
+	}
+	free, err := c.lvmOps.GetVgFreeSpace(ctx, lvg.Name)
+	if err != nil {
+		return false, err
+	}
+	if lvg.Annotations == nil {
+		lvg.Annotations = map[string]string{}
+	}
+	// AC of LVG is updated by capacity controller
+	lvg.Annotations[apiV1.LVGFreeSpaceAnnotation] = strconv.FormatInt(free, 10)
+	if err := c.client.UpdateCR(ctx, lvg); err != nil {
+		return false, err
+	}
+	log.Infof("Drive %s was added to LVG", drive.Spec.SerialNumber)
+	return true, nil
+}
+
+// recreateLVG creates LVG with the name of LVG of the removed drive on the replacement drive
+func (c *Controller) recreateLVG(ctx context.Context, removed, drive *drivecrd.Drive, lvgName string) (bool, error) {
+	if _, err := c.crHelper.GetACByLocation(drive.Spec.UUID); err != nil {
+		if err == errTypes.ErrorNotFound {
+			// AC is created by capacity controller
+			return false, nil
+		}
+		return false, err
+	}
+	lvg := c.client.ConstructLVGCR(lvgName, api.LogicalVolumeGroup{
+		Name:      lvgName,
+		Node:      c.nodeID,
+		Locations: []string{drive.Spec.UUID},
+		Size:      capacityplanner.SubtractLVMMetadataSize(drive.Spec.Size),
+		Status:    apiV1.Creating,
+		Health:    apiV1.HealthGood,
+	})
+	if err := c.client.CreateCR(ctx, lvgName, lvg); err != nil {
+		return false, err
+	}
+	c.log.WithField("method", "recreateLVG").Infof("LVG %s was recreated on drive %s", lvgName, drive.Spec.SerialNumber)
+	return c.convertACToLVG(ctx, removed, drive, lvg)
+}
+
+// deleteDriveAC deletes AC of the drive which was added to the existing LVG
+func (c *Controller) deleteDriveAC(ctx context.Context, drive *drivecrd.Drive) (bool, error) {
+	ac, err := c.crHelper.GetACByLocation(drive.Spec.UUID)
+	switch {
+	case err == errTypes.ErrorNotFound:
+		return true, nil
+	case err != nil:
+		return false, err
+	}
+	if err := c.crHelper.DeleteConsumedAC(ctx, ac); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// convertACToLVG converts AC of the drive to AC of LVG with LVM based storage class of the removed drive
+func (c *Controller) convertACToLVG(ctx context.Context, removed, drive *drivecrd.Drive,
+	lvg *lvgcrd.LogicalVolumeGroup) (bool, error) {
+	ac, err := c.crHelper.GetACByLocation(drive.Spec.UUID)
+	switch {
+	case err == errTypes.ErrorNotFound:
+		// AC was already converted or removed after the drive was added to LVG
+		return true, nil
+	case err != nil:
+		return false, err
+	}
+	sc := removed.Annotations[apiV1.DriveAnnotationReplacementLVGSC]
+	if sc == "" {
+		sc = util.GetLVGStorageClass(util.ConvertDriveTypeToStorageClass(drive.Spec.Type))
+	}
+	ac.Spec.Size = lvg.Spec.Size
+	ac.Spec.Location = lvg.Name
+	ac.Spec.StorageClass = sc
+	if err := c.client.UpdateCR(ctx, ac); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,129 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package firmware
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	fwcrd "github.com/dell/csi-baremetal/api/v1/firmwarepolicycrd"
+)
+
+// findRule returns rule which matches VID/PID of the drive, rule with exact PID has priority over VID-only rule
+// Returns nil if there is no suitable rule
+func findRule(rules []fwcrd.FirmwareRule, drive *api.Drive) *fwcrd.FirmwareRule {
+	var vendorRule *fwcrd.FirmwareRule
+	for i, rule := range rules {
+		if !strings.EqualFold(rule.VID, drive.VID) {
+			continue
+		}
+		if rule.PID == "" {
+			if vendorRule == nil {
+				vendorRule = &rules[i]
+			}
+			continue
+		}
+		if strings.EqualFold(rule.PID, drive.PID) {
+			return &rules[i]
+		}
+	}
+	return vendorRule
+}
+
+// evaluate checks firmware against the rule
+// Returns FirmwareCompliant, FirmwareNonCompliant or FirmwareUnknown if firmware is not reported by the drive
+func evaluate(rule *fwcrd.FirmwareRule, firmware string) string {
+	firmware = strings.TrimSpace(firmware)
+	if firmware == "" {
+		return apiV1.FirmwareUnknown
+	}
+	if len(rule.Blessed) > 0 {
+		for _, blessed := range rule.Blessed {
+			if strings.EqualFold(strings.TrimSpace(blessed), firmware) {
+				return apiV1.FirmwareCompliant
+			}
+		}
+		return apiV1.FirmwareNonCompliant
+	}
+	if rule.MinVersion != "" && compareVersions(firmware, rule.MinVersion) < 0 {
+		return apiV1.FirmwareNonCompliant
+	}
+	return apiV1.FirmwareCompliant
+}
+
+// compareVersions compares firmware versions chunk by chunk, where chunk is a sequence of digits or a sequence of
+// other symbols (for example "GXT5404Q" -> "GXT", "5404", "Q"). Digit chunks are compared as numbers,
+// other chunks are compared case insensitive, separators '.', '-' and '_' are ignored
+// Returns -1 if a < b, 1 if a > b and 0 if versions are equal
+func compareVersions(a, b string) int {
+	chunksA, chunksB := splitVersion(a), splitVersion(b)
+	for i := 0; i < len(chunksA) && i < len(chunksB); i++ {
+		if res := compareChunks(chunksA[i], chunksB[i]); res != 0 {
+			return res
+		}
+	}
+	switch {
+	case len(chunksA) < len(chunksB):
+		return -1
+	case len(chunksA) > len(chunksB):
+		return 1
+	}
+	return 0
+}
+
+func compareChunks(a, b string) int {
+	numA, errA := strconv.ParseUint(a, 10, 64)
+	numB, errB := strconv.ParseUint(b, 10, 64)
+	if errA == nil && errB == nil {
+		switch {
+		case numA < numB:
+			return -1
+		case numA > numB:
+			return 1
+		}
+		return 0
+	}
+	return strings.Compare(strings.ToUpper(a), strings.ToUpper(b))
+}
+
+func splitVersion(version string) []string {
+	chunks := make([]string, 0)
+	current := strings.Builder{}
+	isDigit := false
+	flush := func() {
+		if current.Len() > 0 {
+			chunks = append(chunks, current.String())
+			current.Reset()
+		}
+	}
+	for _, r := range version {
+		if r == '.' || r == '-' || r == '_' || unicode.IsSpace(r) {
+			flush()
+			continue
+		}
+		if current.Len() > 0 && unicode.IsDigit(r) != isDigit {
+			flush()
+		}
+		isDigit = unicode.IsDigit(r)
+		current.WriteRune(r)
+	}
+	flush()
+	return chunks
+}
@@ -0,0 +1,272 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package firmware contains controller which checks firmware of the drives against FirmwarePolicy custom resources
+package firmware
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	"github.com/dell/csi-baremetal/api/v1/drivecrd"
+	fwcrd "github.com/dell/csi-baremetal/api/v1/firmwarepolicycrd"
+	"github.com/dell/csi-baremetal/pkg/base/k8s"
+	"github.com/dell/csi-baremetal/pkg/eventing"
+	metricsC "github.com/dell/csi-baremetal/pkg/metrics/common"
+)
+
+// eventRecorder interface for sending events
+type eventRecorder interface {
+	Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{})
+}
+
+// Controller reconciles FirmwarePolicy custom resources and reports firmware compliance of Drive CRs
+// by FirmwareCompliant condition
+type Controller struct {
+	client        *k8s.KubeClient
+	eventRecorder eventRecorder
+	log           *logrus.Entry
+}
+
+// NewController creates new instance of Controller structure
+// Receives an instance of base.KubeClient, event recorder and logrus logger
+// Returns an instance of Controller
+func NewController(client *k8s.KubeClient, eventRecorder eventRecorder, log *logrus.Logger) *Controller {
+	return &Controller{
+		client:        client,
+		eventRecorder: eventRecorder,
+		log:           log.WithField("component", "FirmwareController"),
+	}
+}
+
+// SetupWithManager registers Controller to ControllerManager
+// All policies are reconciled when drive is created or its firmware or model is changed
+func (c *Controller) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&fwcrd.FirmwarePolicy{}).
+		Watches(&source.Kind{Type: &drivecrd.Drive{}},
+			&handler.EnqueueRequestsFromMapFunc{ToRequests: handler.ToRequestsFunc(c.policiesForDrive)}).
+		WithEventFilter(predicate.Funcs{
+			UpdateFunc: func(e event.UpdateEvent) bool {
+				return filterUpdateEvent(e.ObjectOld, e.ObjectNew)
+			},
+		}).
+		Complete(c)
+}
+
+// Reconcile evaluates FirmwarePolicy against all Drive CRs
+func (c *Controller) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+	defer metricsC.ReconcileDuration.EvaluateDurationForType("csicontroller_firmware_controller")()
+	ctx, cancelFn := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancelFn()
+
+	log := c.log.WithFields(logrus.Fields{"method": "Reconcile", "name": req.Name})
+
+	drives := &drivecrd.DriveList{}
+	if err := c.client.ReadList(ctx, drives); err != nil {
+		log.Errorf("Failed to read Drive CRs: %v", err)
+		return ctrl.Result{}, err
+	}
+
+	policy := &fwcrd.FirmwarePolicy{}
+	if err := c.client.ReadCR(ctx, req.Name, "", policy); err != nil {
+		if k8serrors.IsNotFound(err) {
+			log.Infof("FirmwarePolicy was removed, clean up drives")
+			return ctrl.Result{}, c.cleanupDrives(ctx, req.Name, drives.Items)
+		}
+		log.Errorf("Failed to read FirmwarePolicy: %v", err)
+		return ctrl.Result{}, err
+	}
+
+	status := fwcrd.FirmwarePolicyStatus{NonCompliant: []string{}}
+	for i := range drives.Items {
+		drive := &drives.Items[i]
+		owner, owned := drive.Annotations[apiV1.DriveAnnotationFirmwarePolicy]
+		if owned && owner != policy.Name {
+			// drive is handled by another policy
+			continue
+		}
+		rule := findRule(policy.Spec.Rules, &drive.Spec)
+		if rule == nil {
+			if owned {
+				if err := c.removeCompliance(ctx, drive); err != nil {
+					return ctrl.Result{}, err
+				}
+			}
+			continue
+		}
+
+		compliance := evaluate(rule, drive.Spec.Firmware)
+		switch compliance {
+		case apiV1.FirmwareCompliant:
+			status.CompliantDrives++
+		case apiV1.FirmwareNonCompliant:
+			status.NonCompliantDrives++
+			status.NonCompliant = append(status.NonCompliant, drive.Name)
+		}
+		exclude := policy.Spec.ExcludeNonCompliant && compliance == apiV1.FirmwareNonCompliant
+		if err := c.markDrive(ctx, drive, policy.Name, compliance, exclude); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	sort.Strings(status.NonCompliant)
+	status.LastEvaluationTime = metav1.Now()
+	policy.Status = status
+	if err := c.client.Status().Update(ctx, policy); err != nil {
+		log.Errorf("Failed to update FirmwarePolicy status: %v", err)
+		return ctrl.Result{}, err
+	}
+	log.Infof("Policy evaluated, compliant drives: %d, non-compliant drives: %d",
+		status.CompliantDrives, status.NonCompliantDrives)
+	return ctrl.Result{}, nil
+}
+
+// markDrive sets FirmwareCompliant condition and policy annotation of Drive CR and sends event if compliance was changed
+func (c *Controller) markDrive(ctx context.Context, drive *drivecrd.Drive, policyName, compliance string,
+	exclude bool) error {
+	condition := complianceCondition(drive.Spec.Firmware, policyName, compliance, exclude)
+	previous := apiV1.FindCondition(drive.Status.Conditions, apiV1.ConditionFirmwareCompliant)
+	if drive.Annotations[apiV1.DriveAnnotationFirmwarePolicy] == policyName && previous != nil &&
+		previous.Status == condition.Status && previous.Reason == condition.Reason && previous.Message == condition.Message {
+		return nil
+	}
+	wasNonCompliant := previous != nil && previous.Status == metav1.ConditionFalse
+
+	if drive.Annotations == nil {
+		drive.Annotations = map[string]string{}
+	}
+	drive.Annotations[apiV1.DriveAnnotationFirmwarePolicy] = policyName
+	apiV1.SetCondition(&drive.Status.Conditions, condition)
+	if err := c.client.UpdateCR(ctx, drive); err != nil {
+		c.log.Errorf("Failed to update Drive %s CR: %v", drive.Name, err)
+		return err
+	}
+
+	switch {
+	case compliance == apiV1.FirmwareNonCompliant && !wasNonCompliant:
+		c.eventRecorder.Eventf(drive, eventing.WarningType, eventing.DriveFirmwareOutdated,
+			"Drive firmware doesn't comply with policy %s, %s", policyName, drive.GetDriveDescription())
+	case compliance == apiV1.FirmwareCompliant && wasNonCompliant:
+		c.eventRecorder.Eventf(drive, eventing.NormalType, eventing.DriveFirmwareCompliant,
+			"Drive firmware complies with policy %s, %s", policyName, drive.GetDriveDescription())
+	}
+	return nil
+}
+
+// complianceCondition returns FirmwareCompliant condition for the result of the firmware check,
+// reason of non-compliant drive which is excluded from AvailableCapacity is FirmwareExcluded
+func complianceCondition(firmware, policyName, compliance string, exclude bool) apiV1.Condition {
+	switch compliance {
+	case apiV1.FirmwareCompliant:
+		return apiV1.Condition{
+			Type:    apiV1.ConditionFirmwareCompliant,
+			Status:  metav1.ConditionTrue,
+			Reason:  apiV1.FirmwareCompliant,
+			Message: fmt.Sprintf("firmware %s complies with policy %s", firmware, policyName),
+		}
+	case apiV1.FirmwareNonCompliant:
+		condition := apiV1.Condition{
+			Type:    apiV1.ConditionFirmwareCompliant,
+			Status:  metav1.ConditionFalse,
+			Reason:  apiV1.FirmwareNonCompliant,
+			Message: fmt.Sprintf("firmware %s doesn't comply with policy %s", firmware, policyName),
+		}
+		if exclude {
+			condition.Reason = apiV1.FirmwareExcluded
+			condition.Message += ", drive is excluded from AvailableCapacity"
+		}
+		return condition
+	default:
+		return apiV1.Condition{
+			Type:    apiV1.ConditionFirmwareCompliant,
+			Status:  metav1.ConditionUnknown,
+			Reason:  apiV1.FirmwareUnknown,
+			Message: fmt.Sprintf("firmware isn't reported by the drive, policy %s", policyName),
+		}
+	}
+}
+
+// cleanupDrives removes compliance of the drives which were checked by removed policy
+func (c *Controller) cleanupDrives(ctx context.Context, policyName string, drives []drivecrd.Drive) error {
+	for i := range drives {
+		if drives[i].Annotations[apiV1.DriveAnnotationFirmwarePolicy] != policyName {
+			continue
+		}
+		if err := c.removeCompliance(ctx, &drives[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeCompliance removes policy annotation and FirmwareCompliant condition of the drive
+func (c *Controller) removeCompliance(ctx context.Context, drive *drivecrd.Drive) error {
+	delete(drive.Annotations, apiV1.DriveAnnotationFirmwarePolicy)
+	apiV1.RemoveCondition(&drive.Status.Conditions, apiV1.ConditionFirmwareCompliant)
+	if err := c.client.UpdateCR(ctx, drive); err != nil {
+		c.log.Errorf("Failed to update Drive %s CR: %v", drive.Name, err)
+		return err
+	}
+	return nil
+}
+
+// policiesForDrive maps Drive event to reconcile requests for all FirmwarePolicies
+func (c *Controller) policiesForDrive(_ handler.MapObject) []reconcile.Request {
+	policies := &fwcrd.FirmwarePolicyList{}
+	if err := c.client.ReadList(context.Background(), policies); err != nil {
+		c.log.Errorf("Failed to read FirmwarePolicy CRs: %v", err)
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(policies.Items))
+	for _, policy := range policies.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: policy.Name}})
+	}
+	return requests
+}
+
+// filterUpdateEvent skips status updates of the policies and drive updates which don't affect compliance
+func filterUpdateEvent(old runtime.Object, new runtime.Object) bool {
+	if oldPolicy, ok := old.(*fwcrd.FirmwarePolicy); ok {
+		newPolicy, ok := new.(*fwcrd.FirmwarePolicy)
+		return !ok || !reflect.DeepEqual(oldPolicy.Spec, newPolicy.Spec)
+	}
+	if oldDrive, ok := old.(*drivecrd.Drive); ok {
+		newDrive, ok := new.(*drivecrd.Drive)
+		return !ok ||
+			oldDrive.Spec.Firmware != newDrive.Spec.Firmware ||
+			oldDrive.Spec.VID != newDrive.Spec.VID ||
+			oldDrive.Spec.PID != newDrive.Spec.PID ||
+			oldDrive.Annotations[apiV1.DriveAnnotationFirmwarePolicy] != newDrive.Annotations[apiV1.DriveAnnotationFirmwarePolicy]
+	}
+	return true
+}
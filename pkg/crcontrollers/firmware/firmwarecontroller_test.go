@@ -0,0 +1,191 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package firmware
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	"github.com/dell/csi-baremetal/api/v1/drivecrd"
+	fwcrd "github.com/dell/csi-baremetal/api/v1/firmwarepolicycrd"
+	"github.com/dell/csi-baremetal/pkg/base/k8s"
+	"github.com/dell/csi-baremetal/pkg/eventing"
+	"github.com/dell/csi-baremetal/pkg/mocks"
+)
+
+var (
+	tCtx       = context.Background()
+	testLogger = logrus.New()
+	ns         = "default"
+	policyName = "policy"
+
+	testPolicy = fwcrd.FirmwarePolicy{
+		TypeMeta:   metav1.TypeMeta{Kind: apiV1.FirmwarePolicyKind, APIVersion: apiV1.APIV1Version},
+		ObjectMeta: metav1.ObjectMeta{Name: policyName},
+		Spec: fwcrd.FirmwarePolicySpec{
+			Rules: []fwcrd.FirmwareRule{
+				{VID: "vendor", PID: "model-1", Blessed: []string{"GXT5404Q", "GXT5405Q"}},
+				{VID: "vendor", MinVersion: "1.2.0"},
+			},
+		},
+	}
+)
+
+func newDriveCR(name, vid, pid, firmware string) *drivecrd.Drive {
+	return &drivecrd.Drive{
+		TypeMeta:   metav1.TypeMeta{Kind: apiV1.DriveKind, APIVersion: apiV1.APIV1Version},
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: api.Drive{
+			UUID:     name,
+			VID:      vid,
+			PID:      pid,
+			Firmware: firmware,
+			IsClean:  true,
+		},
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	assert.Equal(t, 0, compareVersions("1.2.0", "1.2.0"))
+	assert.Equal(t, -1, compareVersions("1.2.0", "1.10.0"))
+	assert.Equal(t, 1, compareVersions("1.2.1", "1.2"))
+	assert.Equal(t, -1, compareVersions("GXT5404Q", "GXT5405Q"))
+	assert.Equal(t, 0, compareVersions("gxt5404q", "GXT5404Q"))
+	assert.Equal(t, 1, compareVersions("DL63", "DL9"))
+}
+
+func TestFindRuleAndEvaluate(t *testing.T) {
+	rules := testPolicy.Spec.Rules
+
+	rule := findRule(rules, &api.Drive{VID: "VENDOR", PID: "MODEL-1"})
+	assert.Equal(t, &rules[0], rule)
+	assert.Equal(t, apiV1.FirmwareCompliant, evaluate(rule, "GXT5405Q"))
+	assert.Equal(t, apiV1.FirmwareNonCompliant, evaluate(rule, "GXT5406Q"))
+	assert.Equal(t, apiV1.FirmwareUnknown, evaluate(rule, ""))
+
+	rule = findRule(rules, &api.Drive{VID: "vendor", PID: "model-2"})
+	assert.Equal(t, &rules[1], rule)
+	assert.Equal(t, apiV1.FirmwareCompliant, evaluate(rule, "1.10"))
+	assert.Equal(t, apiV1.FirmwareNonCompliant, evaluate(rule, "1.1.9"))
+
+	assert.Nil(t, findRule(rules, &api.Drive{VID: "other", PID: "model-1"}))
+}
+
+func assertCompliance(t *testing.T, drive *drivecrd.Drive, status metav1.ConditionStatus, reason string) {
+	condition := apiV1.FindCondition(drive.Status.Conditions, apiV1.ConditionFirmwareCompliant)
+	if assert.NotNil(t, condition) {
+		assert.Equal(t, status, condition.Status)
+		assert.Equal(t, reason, condition.Reason)
+	}
+}
+
+func TestController_Reconcile(t *testing.T) {
+	kubeClient, err := k8s.GetFakeKubeClient(ns, testLogger)
+	assert.Nil(t, err)
+	recorder := &mocks.NoOpRecorder{}
+	c := NewController(kubeClient, recorder, testLogger)
+
+	policy := testPolicy.DeepCopy()
+	policy.Spec.ExcludeNonCompliant = true
+	assert.Nil(t, kubeClient.Create(tCtx, policy))
+	drives := []*drivecrd.Drive{
+		newDriveCR("compliant", "vendor", "model-1", "GXT5404Q"),
+		newDriveCR("outdated", "vendor", "model-2", "1.0.0"),
+		newDriveCR("other", "other", "model-1", "1.0.0"),
+	}
+	for _, d := range drives {
+		assert.Nil(t, kubeClient.Create(tCtx, d))
+	}
+
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: policyName}}
+	_, err = c.Reconcile(req)
+	assert.Nil(t, err)
+
+	readDrive := func(name string) *drivecrd.Drive {
+		drive := &drivecrd.Drive{}
+		assert.Nil(t, kubeClient.ReadCR(tCtx, name, "", drive))
+		return drive
+	}
+
+	drive := readDrive("compliant")
+	assert.Equal(t, policyName, drive.Annotations[apiV1.DriveAnnotationFirmwarePolicy])
+	assertCompliance(t, drive, metav1.ConditionTrue, apiV1.FirmwareCompliant)
+
+	drive = readDrive("outdated")
+	assertCompliance(t, drive, metav1.ConditionFalse, apiV1.FirmwareExcluded)
+
+	drive = readDrive("other")
+	assert.Empty(t, drive.Annotations)
+	assert.Nil(t, apiV1.FindCondition(drive.Status.Conditions, apiV1.ConditionFirmwareCompliant))
+
+	assert.Equal(t, 1, len(recorder.Calls))
+	assert.Equal(t, eventing.DriveFirmwareOutdated, recorder.Calls[0].Reason)
+
+	assert.Nil(t, kubeClient.ReadCR(tCtx, policyName, "", policy))
+	assert.Equal(t, 1, policy.Status.CompliantDrives)
+	assert.Equal(t, 1, policy.Status.NonCompliantDrives)
+	assert.Equal(t, []string{"outdated"}, policy.Status.NonCompliant)
+
+	// second reconcile doesn't send events again
+	_, err = c.Reconcile(req)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(recorder.Calls))
+
+	// firmware was updated
+	drive = readDrive("outdated")
+	drive.Spec.Firmware = "1.2.1"
+	assert.Nil(t, kubeClient.UpdateCR(tCtx, drive))
+	_, err = c.Reconcile(req)
+	assert.Nil(t, err)
+	drive = readDrive("outdated")
+	assertCompliance(t, drive, metav1.ConditionTrue, apiV1.FirmwareCompliant)
+	assert.Equal(t, 2, len(recorder.Calls))
+	assert.Equal(t, eventing.DriveFirmwareCompliant, recorder.Calls[1].Reason)
+
+	// policy was removed
+	assert.Nil(t, kubeClient.DeleteCR(tCtx, policy))
+	_, err = c.Reconcile(req)
+	assert.Nil(t, err)
+	for _, name := range []string{"compliant", "outdated"} {
+		drive = readDrive(name)
+		assert.Empty(t, drive.Annotations[apiV1.DriveAnnotationFirmwarePolicy])
+		assert.Nil(t, apiV1.FindCondition(drive.Status.Conditions, apiV1.ConditionFirmwareCompliant))
+	}
+}
+
+func TestFilterUpdateEvent(t *testing.T) {
+	oldDrive := newDriveCR("drive", "vendor", "model", "1.0")
+	newDrive := oldDrive.DeepCopy()
+	assert.False(t, filterUpdateEvent(oldDrive, newDrive))
+	newDrive.Spec.Firmware = "1.1"
+	assert.True(t, filterUpdateEvent(oldDrive, newDrive))
+
+	oldPolicy := testPolicy.DeepCopy()
+	newPolicy := oldPolicy.DeepCopy()
+	newPolicy.Status.CompliantDrives = 10
+	assert.False(t, filterUpdateEvent(oldPolicy, newPolicy))
+	newPolicy.Spec.ExcludeNonCompliant = true
+	assert.True(t, filterUpdateEvent(oldPolicy, newPolicy))
+}
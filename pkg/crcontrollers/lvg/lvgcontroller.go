@@ -34,6 +34,7 @@ import (
 	"github.com/dell/csi-baremetal/api/v1/lvgcrd"
 	vccrd "github.com/dell/csi-baremetal/api/v1/volumecrd"
 	"github.com/dell/csi-baremetal/pkg/base"
+	"github.com/dell/csi-baremetal/pkg/base/audit"
 	"github.com/dell/csi-baremetal/pkg/base/command"
 	"github.com/dell/csi-baremetal/pkg/base/k8s"
 	"github.com/dell/csi-baremetal/pkg/base/linuxutils/lsblk"
@@ -84,9 +85,10 @@ func (c *Controller) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 		"LVGName": req.Name,
 	})
 
+	ctx := context.Background()
 	lvg := &lvgcrd.LogicalVolumeGroup{}
 
-	if err := c.k8sClient.ReadCR(context.Background(), req.Name, "", lvg); err != nil {
+	if err := c.k8sClient.ReadCR(ctx, req.Name, "", lvg); err != nil {
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
@@ -95,7 +97,7 @@ func (c *Controller) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 	switch {
 	case !lvg.ObjectMeta.DeletionTimestamp.IsZero():
 		ll.Info("Delete LogicalVolumeGroup")
-		return c.handleLVGRemoving(lvg)
+		return c.handleLVGRemoving(ctx, lvg)
 	case !util.ContainsString(lvg.ObjectMeta.Finalizers, lvgFinalizer):
 		return c.appendFinalizer(lvg)
 	// if lvg.Spec.VolumeRefs == 0 it means that LogicalVolumeGroup just being created
@@ -108,7 +110,7 @@ func (c *Controller) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 	// check for LogicalVolumeGroup state
 	if lvg.Spec.Status == apiV1.Creating {
 		ll.Info("Creating LogicalVolumeGroup")
-		return c.handlerLVGCreation(lvg)
+		return c.handlerLVGCreation(ctx, lvg)
 	}
 
 	return ctrl.Result{}, nil
@@ -145,15 +147,16 @@ func (c *Controller) removeFinalizer(lvg *lvgcrd.LogicalVolumeGroup) (ctrl.Resul
 
 // handlerLVGCreation handles LogicalVolumeGroup CR with creating status, create LogicalVolumeGroup on the system drive
 // updates corresponding LogicalVolumeGroup CR (set status)
-func (c *Controller) handlerLVGCreation(lvg *lvgcrd.LogicalVolumeGroup) (ctrl.Result, error) {
+func (c *Controller) handlerLVGCreation(ctx context.Context, lvg *lvgcrd.LogicalVolumeGroup) (ctrl.Result, error) {
 	ll := logrus.WithField("LVGName", lvg.Name)
 
 	newStatus := apiV1.Created
 	var err error
 	var locations []string
-	if locations, err = c.createSystemLVG(lvg); err != nil {
+	if locations, err = c.createSystemLVG(ctx, lvg); err != nil {
 		ll.Errorf("Unable to create system LogicalVolumeGroup: %v", err)
 		newStatus = apiV1.Failed
+		apiV1.SetFailedCondition(&lvg.Status.Conditions, apiV1.ConditionReady, err)
 	}
 	lvg.Spec.Status = newStatus
 	lvg.Spec.Locations = locations
@@ -166,7 +169,7 @@ func (c *Controller) handlerLVGCreation(lvg *lvgcrd.LogicalVolumeGroup) (ctrl.Re
 }
 
 // handleLVGRemoving handles removing of LogicalVolumeGroup CR, removes LogicalVolumeGroup from the system and removes finalizers
-func (c *Controller) handleLVGRemoving(lvg *lvgcrd.LogicalVolumeGroup) (ctrl.Result, error) {
+func (c *Controller) handleLVGRemoving(ctx context.Context, lvg *lvgcrd.LogicalVolumeGroup) (ctrl.Result, error) {
 	ll := logrus.WithField("LVGName", lvg.Name)
 
 	if !util.ContainsString(lvg.ObjectMeta.Finalizers, lvgFinalizer) {
@@ -197,7 +200,7 @@ func (c *Controller) handleLVGRemoving(lvg *lvgcrd.LogicalVolumeGroup) (ctrl.Res
 	drivesUUIDs := c.k8sClient.GetSystemDriveUUIDs()
 	if !util.ContainsString(drivesUUIDs, lvg.Spec.Locations[0]) {
 		// cleanup LVM artifacts
-		if err := c.removeLVGArtifacts(lvg.Name); err != nil {
+		if err := c.removeLVGArtifacts(ctx, lvg); err != nil {
 			ll.Errorf("Unable to cleanup LVM artifacts: %v", err)
 			return ctrl.Result{}, err
 		}
@@ -239,7 +242,7 @@ func (c *Controller) filterCRs(obj runtime.Object) bool {
 // createSystemLVG creates LogicalVolumeGroup in the system and put all drives from lvg.Spec.Location in that LogicalVolumeGroup
 // if some drive doesn't read that drive will not pass in lvg.Location
 // return list of drives in LogicalVolumeGroup that should be used as a locations for this LogicalVolumeGroup
-func (c *Controller) createSystemLVG(lvg *lvgcrd.LogicalVolumeGroup) (locations []string, err error) {
+func (c *Controller) createSystemLVG(ctx context.Context, lvg *lvgcrd.LogicalVolumeGroup) (locations []string, err error) {
 	ll := c.log.WithFields(logrus.Fields{
 		"method":  "createSystemLVG",
 		"lvgName": lvg.Name,
@@ -257,13 +260,13 @@ func (c *Controller) createSystemLVG(lvg *lvgcrd.LogicalVolumeGroup) (locations
 		// get serial number
 		sn := drive.Spec.SerialNumber
 		// get device path
-		dev, err := c.listBlk.SearchDrivePath(drive)
+		dev, err := c.listBlk.SearchDrivePath(ctx, drive)
 		if err != nil {
 			ll.Error(err)
 			continue
 		}
 		// create PV
-		if err := c.lvmOps.PVCreate(dev); err != nil {
+		if err := c.lvmOps.PVCreate(audit.WithTarget(ctx, audit.Target{DriveSerial: sn}), dev); err != nil {
 			ll.Errorf("Unable to create PV for device %s: %v", dev, err)
 			continue
 		}
@@ -275,7 +278,7 @@ func (c *Controller) createSystemLVG(lvg *lvgcrd.LogicalVolumeGroup) (locations
 		return locations, errors.New("no one PVs were created")
 	}
 	// create vg
-	if err = c.lvmOps.VGCreate(lvg.Name, deviceFiles...); err != nil {
+	if err = c.lvmOps.VGCreate(ctx, lvg.Name, deviceFiles...); err != nil {
 		ll.Errorf("Unable to create VG: %v", err)
 		return locations, err
 	}
@@ -284,23 +287,33 @@ func (c *Controller) createSystemLVG(lvg *lvgcrd.LogicalVolumeGroup) (locations
 
 // removeLVGArtifacts removes LogicalVolumeGroup and PVs that doesn't correspond to particular LogicalVolumeGroup
 // when LogicalVolumeGroup is removed all PVs that were in that LogicalVolumeGroup becomes orphans
-func (c *Controller) removeLVGArtifacts(lvgName string) error {
+func (c *Controller) removeLVGArtifacts(ctx context.Context, lvg *lvgcrd.LogicalVolumeGroup) error {
+	lvgName := lvg.Name
 	ll := c.log.WithFields(logrus.Fields{
 		"method":  "removeLVGArtifacts",
 		"lvgName": lvgName,
 	})
 	ll.Info("Processing ...")
 
-	if c.lvmOps.IsVGContainsLVs(lvgName) {
+	// commands affect drives of LogicalVolumeGroup
+	serials := make([]string, 0, len(lvg.Spec.Locations))
+	for _, driveUUID := range lvg.Spec.Locations {
+		if drive := c.crHelper.GetDriveCRByUUID(driveUUID); drive != nil {
+			serials = append(serials, drive.Spec.SerialNumber)
+		}
+	}
+	ctx = audit.WithTarget(ctx, audit.DrivesTarget(serials...))
+
+	if c.lvmOps.IsVGContainsLVs(ctx, lvgName) {
 		ll.Errorf("There are LVs in LogicalVolumeGroup. Unable to remove it.")
 		return fmt.Errorf("there are LVs in LogicalVolumeGroup %s", lvgName)
 	}
 
 	var err error
-	if err = c.lvmOps.VGRemove(lvgName); err != nil {
+	if err = c.lvmOps.VGRemove(ctx, lvgName); err != nil {
 		return fmt.Errorf("unable to remove LogicalVolumeGroup %s: %v", lvgName, err)
 	}
-	_ = c.lvmOps.RemoveOrphanPVs() // ignore error since LogicalVolumeGroup was removed successfully
+	_ = c.lvmOps.RemoveOrphanPVs(ctx) // ignore error since LogicalVolumeGroup was removed successfully
 	return nil
 }
 
@@ -45,6 +45,7 @@ import (
 )
 
 var (
+	testCtx            = context.Background()
 	lsblkAllDevicesCmd = fmt.Sprintf(lsblk.CmdTmpl, "")
 	tCtx               = context.Background()
 	testLogger         = logrus.New()
@@ -367,13 +368,13 @@ func Test_removeLVGArtifacts_Success(t *testing.T) {
 	e.OnCommand(fmt.Sprintf(lvm.LVsInVGCmdTmpl, lvgCR1.Name)).Return("", "", nil)
 	e.OnCommand(fmt.Sprintf(lvm.VGRemoveCmdTmpl, vg)).Return("", "", nil)
 	e.OnCommand(fmt.Sprintf(lvm.PVsInVGCmdTmpl, lvm.EmptyName)).Return("", "", nil).Times(1)
-	err = c.removeLVGArtifacts(vg)
+	err = c.removeLVGArtifacts(testCtx, &lvgCR1)
 	assert.Nil(t, err)
 
 	// expect that RemoveOrphanPVs failed and ignore it
 	e.OnCommand(fmt.Sprintf(lvm.PVsInVGCmdTmpl, lvm.EmptyName)).
 		Return("", "", errors.New("error")).Times(1)
-	err = c.removeLVGArtifacts(vg)
+	err = c.removeLVGArtifacts(testCtx, &lvgCR1)
 	assert.Nil(t, err)
 }
 
@@ -389,13 +390,13 @@ func Test_removeLVGArtifacts_Fail(t *testing.T) {
 
 	// expect that VG contains LV
 	e.OnCommand(fmt.Sprintf(lvm.LVsInVGCmdTmpl, vg)).Return("some-lv1", "", nil).Times(1)
-	err = c.removeLVGArtifacts(vg)
+	err = c.removeLVGArtifacts(testCtx, &lvgCR1)
 	assert.Equal(t, fmt.Errorf("there are LVs in LogicalVolumeGroup %s", vg), err)
 
 	// expect that VGRemove failed
 	e.OnCommand(fmt.Sprintf(lvm.LVsInVGCmdTmpl, vg)).Return("", "", nil).Times(1)
 	e.OnCommand(fmt.Sprintf(lvm.VGRemoveCmdTmpl, vg)).Return("", "", errors.New("error"))
-	err = c.removeLVGArtifacts(vg)
+	err = c.removeLVGArtifacts(testCtx, &lvgCR1)
 	assert.Contains(t, err.Error(), "unable to remove LogicalVolumeGroup")
 }
 
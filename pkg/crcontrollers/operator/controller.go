@@ -51,6 +51,7 @@ const (
 // Controller is a controller for Node CR
 type Controller struct {
 	k8sClient    *k8s.KubeClient
+	crHelper     *k8s.CRHelper
 	nodeSelector *label
 	cache        nodesMapping
 
@@ -98,6 +99,7 @@ func NewController(nodeSelector string, useExternalAnnotaion bool, nodeAnnotaion
 	k8sClient *k8s.KubeClient, logger *logrus.Logger) (*Controller, error) {
 	c := &Controller{
 		k8sClient: k8sClient,
+		crHelper:  k8s.NewCRHelper(k8sClient, logger),
 		cache: nodesMapping{
 			k8sToBMNode: make(map[string]string),
 			bmToK8sNode: make(map[string]string),
@@ -336,6 +338,10 @@ func (bmc *Controller) reconcileForCSIBMNode(bmNode *nodecrd.Node) (ctrl.Result,
 		"name":   bmNode.Name,
 	})
 
+	if isDecommissionRequested(bmNode) && bmNode.GetDeletionTimestamp().IsZero() {
+		return bmc.decommission(bmNode)
+	}
+
 	if len(bmNode.Spec.Addresses) == 0 {
 		err := errors.New("addresses are missing for current Node instance")
 		ll.Error(err)
@@ -0,0 +1,319 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operator
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	coreV1 "k8s.io/api/core/v1"
+	k8sError "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	acrcrd "github.com/dell/csi-baremetal/api/v1/acreservationcrd"
+	accrd "github.com/dell/csi-baremetal/api/v1/availablecapacitycrd"
+	"github.com/dell/csi-baremetal/api/v1/drivecrd"
+	"github.com/dell/csi-baremetal/api/v1/nodecrd"
+	"github.com/dell/csi-baremetal/api/v1/volumecrd"
+	"github.com/dell/csi-baremetal/pkg/base/util"
+)
+
+// RequeueDecommissionTime is the time between checks of PVCs which block node decommission
+const RequeueDecommissionTime = 30 * time.Second
+
+// crObject is a custom resource with metadata
+type crObject interface {
+	runtime.Object
+	metaV1.Object
+}
+
+// isDecommissionRequested returns true if decommission policy annotation is set on Node CR
+func isDecommissionRequested(bmNode *nodecrd.Node) bool {
+	_, ok := bmNode.GetAnnotations()[apiV1.NodeAnnotationDecommission]
+	return ok
+}
+
+// decommission removes all CSI resources of the node according to the policy from Node CR annotation:
+// waits for (wait policy) or deletes (force policy) PVCs of the node volumes, waits for deletion of their PVs and
+// force deletes Volume, LogicalVolumeGroup, AvailableCapacity, AvailableCapacityReservation and Drive CRs.
+// Capacity of the node isn't reserved by the capacity planner since decommission is requested.
+// Progress and final status are reported in Node CR annotations
+func (bmc *Controller) decommission(bmNode *nodecrd.Node) (ctrl.Result, error) {
+	ll := bmc.log.WithFields(logrus.Fields{
+		"method": "decommission",
+		"name":   bmNode.Name,
+	})
+	ctx := context.Background()
+
+	status := bmNode.GetAnnotations()[apiV1.NodeAnnotationDecommissionStatus]
+	if status == apiV1.DecommissionDone || status == apiV1.DecommissionFailed {
+		ll.Debugf("Decommission is already finished with status %s", status)
+		return ctrl.Result{}, nil
+	}
+
+	policy := bmNode.GetAnnotations()[apiV1.NodeAnnotationDecommission]
+	if policy != apiV1.DecommissionPolicyWait && policy != apiV1.DecommissionPolicyForce {
+		ll.Errorf("Unknown decommission policy %s", policy)
+		return bmc.setDecommissionStatus(bmNode, apiV1.DecommissionFailed,
+			fmt.Sprintf("unknown policy %s, supported policies are %s and %s",
+				policy, apiV1.DecommissionPolicyWait, apiV1.DecommissionPolicyForce))
+	}
+
+	nodeID := bmNode.Spec.UUID
+	ll.Infof("Decommission node %s with policy %s", nodeID, policy)
+
+	volumes, err := bmc.crHelper.GetVolumeCRs(nodeID)
+	if err != nil {
+		ll.Errorf("Unable to read Volume CRs: %v", err)
+		return ctrl.Result{Requeue: true}, err
+	}
+
+	waitStatus, progress, err := bmc.releaseVolumes(ctx, policy, volumes)
+	if err != nil {
+		ll.Errorf("Unable to release volumes: %v", err)
+		return ctrl.Result{Requeue: true}, err
+	}
+	if waitStatus != "" {
+		ll.Infof("Waiting for volumes of the node: %s", progress)
+		if _, err := bmc.setDecommissionStatus(bmNode, waitStatus, progress); err != nil {
+			return ctrl.Result{Requeue: true}, err
+		}
+		return ctrl.Result{RequeueAfter: RequeueDecommissionTime}, nil
+	}
+
+	if _, err := bmc.setDecommissionStatus(bmNode, apiV1.DecommissionInProgress,
+		fmt.Sprintf("deleting %d volumes", len(volumes))); err != nil {
+		return ctrl.Result{Requeue: true}, err
+	}
+
+	deleted, err := bmc.deleteNodeResources(ctx, nodeID, volumes)
+	if err != nil {
+		ll.Errorf("Unable to delete CSI resources of the node: %v", err)
+		return ctrl.Result{Requeue: true}, err
+	}
+
+	ll.Infof("Node %s is decommissioned: %s", nodeID, deleted)
+	return bmc.setDecommissionStatus(bmNode, apiV1.DecommissionDone, deleted)
+}
+
+// releaseVolumes waits for (wait policy) or deletes (force policy) PVCs of the volumes and waits for deletion of
+// their PVs. Volume CRs are kept until then, so data of the used volumes isn't lost
+// Returns decommission status and progress if decommission must wait or empty status if volumes are released
+func (bmc *Controller) releaseVolumes(ctx context.Context, policy string, volumes []volumecrd.Volume) (string, string, error) {
+	pvcs, err := bmc.getBoundPVCs(ctx, volumes)
+	if err != nil {
+		return "", "", fmt.Errorf("unable to read PVCs: %v", err)
+	}
+	if len(pvcs) > 0 && policy == apiV1.DecommissionPolicyWait {
+		return apiV1.DecommissionWaitingForPVCs, fmt.Sprintf("%d PVCs are bound to volumes of the node", len(pvcs)), nil
+	}
+	for _, pvc := range pvcs {
+		bmc.log.WithField("method", "releaseVolumes").Infof("Deleting PVC %s/%s", pvc.Namespace, pvc.Name)
+		if err := bmc.k8sClient.DeleteCR(ctx, pvc); err != nil && !k8sError.IsNotFound(err) {
+			return "", "", fmt.Errorf("unable to delete PVC %s/%s: %v", pvc.Namespace, pvc.Name, err)
+		}
+	}
+
+	pvs, err := bmc.getVolumesWithPVs(ctx, volumes)
+	if err != nil {
+		return "", "", fmt.Errorf("unable to read PVs: %v", err)
+	}
+	if len(pvs) > 0 {
+		return apiV1.DecommissionWaitingForPVs, fmt.Sprintf("%d PVs of the node volumes aren't deleted", len(pvs)), nil
+	}
+	return "", "", nil
+}
+
+// getBoundPVCs returns existing PVCs which are bound to PVs of the volumes
+func (bmc *Controller) getBoundPVCs(ctx context.Context, volumes []volumecrd.Volume) ([]*coreV1.PersistentVolumeClaim, error) {
+	pvcs := make([]*coreV1.PersistentVolumeClaim, 0)
+	for _, volume := range volumes {
+		// Volume CR name is the same as PV name
+		pv := &coreV1.PersistentVolume{}
+		err := bmc.k8sClient.ReadCR(ctx, volume.Name, "", pv)
+		switch {
+		case k8sError.IsNotFound(err):
+			continue
+		case err != nil:
+			return nil, err
+		}
+		if pv.Spec.ClaimRef == nil {
+			continue
+		}
+
+		pvc := &coreV1.PersistentVolumeClaim{}
+		err = bmc.k8sClient.ReadCR(ctx, pv.Spec.ClaimRef.Name, pv.Spec.ClaimRef.Namespace, pvc)
+		switch {
+		case k8sError.IsNotFound(err):
+			continue
+		case err != nil:
+			return nil, err
+		}
+		pvcs = append(pvcs, pvc)
+	}
+	return pvcs, nil
+}
+
+// getVolumesWithPVs returns names of the volumes which PVs still exist.
+// Volumes which are removed or released are skipped, their data is already deleted or lost
+func (bmc *Controller) getVolumesWithPVs(ctx context.Context, volumes []volumecrd.Volume) ([]string, error) {
+	names := make([]string, 0)
+	for _, volume := range volumes {
+		if volume.Spec.CSIStatus == apiV1.Removed || volume.Spec.Usage == apiV1.VolumeUsageReleased {
+			continue
+		}
+		// Volume CR name is the same as PV name
+		err := bmc.k8sClient.ReadCR(ctx, volume.Name, "", &coreV1.PersistentVolume{})
+		switch {
+		case k8sError.IsNotFound(err):
+			continue
+		case err != nil:
+			return nil, err
+		}
+		names = append(names, volume.Name)
+	}
+	return names, nil
+}
+
+// deleteNodeResources force deletes CSI custom resources of the node
+// Returns summary of deleted resources
+func (bmc *Controller) deleteNodeResources(ctx context.Context, nodeID string, volumes []volumecrd.Volume) (string, error) {
+	for i := range volumes {
+		if err := bmc.forceDelete(ctx, &volumes[i]); err != nil {
+			return "", err
+		}
+	}
+
+	lvgs, err := bmc.crHelper.GetLVGCRs(nodeID)
+	if err != nil {
+		return "", err
+	}
+	for i := range lvgs {
+		if err := bmc.forceDelete(ctx, &lvgs[i]); err != nil {
+			return "", err
+		}
+	}
+
+	acs, err := bmc.crHelper.GetACCRs(nodeID)
+	if err != nil {
+		return "", err
+	}
+	acNames := make([]string, 0, len(acs))
+	for i := range acs {
+		acNames = append(acNames, acs[i].Name)
+		if err := bmc.forceDelete(ctx, &acs[i]); err != nil {
+			return "", err
+		}
+	}
+
+	acrs := &acrcrd.AvailableCapacityReservationList{}
+	if err := bmc.k8sClient.ReadList(ctx, acrs); err != nil {
+		return "", err
+	}
+	acrCount := 0
+	for i := range acrs.Items {
+		if !isReservedOnNode(&acrs.Items[i], nodeID, acNames) {
+			continue
+		}
+		if err := bmc.forceDelete(ctx, &acrs.Items[i]); err != nil {
+			return "", err
+		}
+		acrCount++
+	}
+
+	drives, err := bmc.crHelper.GetDriveCRs(nodeID)
+	if err != nil {
+		return "", err
+	}
+	for i := range drives {
+		if err := bmc.forceDelete(ctx, &drives[i]); err != nil {
+			return "", err
+		}
+	}
+
+	return fmt.Sprintf("deleted %d volumes, %d LVGs, %d ACs, %d ACRs, %d drives",
+		len(volumes), len(lvgs), len(acs), acrCount, len(drives)), nil
+}
+
+// isReservedOnNode returns true if reservation holds the node or one of its ACs
+func isReservedOnNode(acr *acrcrd.AvailableCapacityReservation, nodeID string, acNames []string) bool {
+	if acr.Spec.NodeRequests != nil && util.ContainsString(acr.Spec.NodeRequests.Reserved, nodeID) {
+		return true
+	}
+	for _, request := range acr.Spec.ReservationRequests {
+		for _, name := range request.Reservations {
+			if util.ContainsString(acNames, name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// forceDelete sets force delete annotation on the object and deletes it. Finalizers of Drive and AvailableCapacity
+// CRs are removed by their controllers, which report force deletion of the CR in use with warning event.
+// Finalizers of other CRs are removed here, components of the decommissioned node might not release them
+func (bmc *Controller) forceDelete(ctx context.Context, obj crObject) error {
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[apiV1.AnnotationForceDelete] = apiV1.ForceDeleteOn
+	obj.SetAnnotations(annotations)
+	switch obj.(type) {
+	case *drivecrd.Drive, *accrd.AvailableCapacity:
+	default:
+		if len(obj.GetFinalizers()) > 0 {
+			bmc.log.WithField("method", "forceDelete").
+				Warnf("Finalizers %v of %s are removed", obj.GetFinalizers(), obj.GetName())
+			obj.SetFinalizers(nil)
+		}
+	}
+	if err := bmc.k8sClient.UpdateCR(ctx, obj); err != nil {
+		if k8sError.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if err := bmc.k8sClient.DeleteCR(ctx, obj); err != nil && !k8sError.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// setDecommissionStatus updates decommission status and progress annotations of Node CR
+func (bmc *Controller) setDecommissionStatus(bmNode *nodecrd.Node, status, progress string) (ctrl.Result, error) {
+	annotations := bmNode.GetAnnotations()
+	if annotations[apiV1.NodeAnnotationDecommissionStatus] == status &&
+		annotations[apiV1.NodeAnnotationDecommissionProgress] == progress {
+		return ctrl.Result{}, nil
+	}
+	annotations[apiV1.NodeAnnotationDecommissionStatus] = status
+	annotations[apiV1.NodeAnnotationDecommissionProgress] = progress
+	bmNode.SetAnnotations(annotations)
+	if err := bmc.k8sClient.UpdateCR(context.Background(), bmNode); err != nil {
+		bmc.log.WithField("method", "setDecommissionStatus").
+			Errorf("Unable to update Node %s: %v", bmNode.Name, err)
+		return ctrl.Result{Requeue: true}, err
+	}
+	return ctrl.Result{}, nil
+}
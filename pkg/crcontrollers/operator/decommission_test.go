@@ -0,0 +1,207 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	coreV1 "k8s.io/api/core/v1"
+	k8sError "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	acrcrd "github.com/dell/csi-baremetal/api/v1/acreservationcrd"
+	accrd "github.com/dell/csi-baremetal/api/v1/availablecapacitycrd"
+	"github.com/dell/csi-baremetal/api/v1/drivecrd"
+	"github.com/dell/csi-baremetal/api/v1/lvgcrd"
+	"github.com/dell/csi-baremetal/api/v1/nodecrd"
+	"github.com/dell/csi-baremetal/api/v1/volumecrd"
+)
+
+var (
+	decommissionNodeID = testCSIBMNode1.Spec.UUID
+	decommissionReq    = ctrl.Request{NamespacedName: types.NamespacedName{Name: testCSIBMNode1.Name}}
+)
+
+// setupDecommission creates Node CR with decommission policy and CSI resources of the node
+func setupDecommission(t *testing.T, policy string) *Controller {
+	c := setup(t)
+	bmNode := testCSIBMNode1.DeepCopy()
+	bmNode.Annotations = map[string]string{apiV1.NodeAnnotationDecommission: policy}
+
+	volume := c.k8sClient.ConstructVolumeCR("pvc-1", testNS, api.Volume{Id: "pvc-1", NodeId: decommissionNodeID,
+		Location: "lvg-1", LocationType: apiV1.LocationTypeLVM})
+	volume.Finalizers = []string{"dell.emc.csi/volume-cleanup"}
+	lvg := c.k8sClient.ConstructLVGCR("lvg-1", api.LogicalVolumeGroup{Name: "lvg-1", Node: decommissionNodeID,
+		Locations: []string{"drive-1"}})
+	lvg.Namespace = testNS
+	lvg.Finalizers = []string{"dell.emc.csi/lvg-cleanup"}
+	ac := c.k8sClient.ConstructACCR("ac-1", api.AvailableCapacity{Location: "lvg-1", NodeId: decommissionNodeID,
+		Size: 1024, StorageClass: apiV1.StorageClassHDDLVG})
+	ac.Namespace = testNS
+	otherAC := c.k8sClient.ConstructACCR("ac-2", api.AvailableCapacity{Location: "drive-2",
+		NodeId: testCSIBMNode2.Spec.UUID, Size: 1024, StorageClass: apiV1.StorageClassHDD})
+	otherAC.Namespace = testNS
+	acr := c.k8sClient.ConstructACRCR("acr-1", api.AvailableCapacityReservation{
+		ReservationRequests: []*api.ReservationRequest{{Reservations: []string{ac.Name}}}})
+	acr.Namespace = testNS
+	drive := c.k8sClient.ConstructDriveCR("drive-1", api.Drive{UUID: "drive-1", NodeId: decommissionNodeID})
+	drive.Namespace = testNS
+	pv := &coreV1.PersistentVolume{
+		ObjectMeta: metaV1.ObjectMeta{Name: volume.Name, Namespace: testNS},
+		Spec:       coreV1.PersistentVolumeSpec{ClaimRef: &coreV1.ObjectReference{Name: "pvc", Namespace: testNS}},
+	}
+	pvc := &coreV1.PersistentVolumeClaim{ObjectMeta: metaV1.ObjectMeta{Name: "pvc", Namespace: testNS}}
+
+	createObjects(t, c.k8sClient, bmNode, volume, lvg, ac, otherAC, acr, drive, pv, pvc)
+	return c
+}
+
+func readDecommissionAnnotations(t *testing.T, c *Controller) map[string]string {
+	bmNode := &nodecrd.Node{}
+	assert.Nil(t, c.k8sClient.ReadCR(testCtx, testCSIBMNode1.Name, "", bmNode))
+	return bmNode.Annotations
+}
+
+func assertNodeResourcesDeleted(t *testing.T, c *Controller) {
+	err := c.k8sClient.ReadCR(testCtx, "pvc-1", testNS, &volumecrd.Volume{})
+	assert.True(t, k8sError.IsNotFound(err))
+	err = c.k8sClient.ReadCR(testCtx, "lvg-1", "", &lvgcrd.LogicalVolumeGroup{})
+	assert.True(t, k8sError.IsNotFound(err))
+	err = c.k8sClient.ReadCR(testCtx, "ac-1", "", &accrd.AvailableCapacity{})
+	assert.True(t, k8sError.IsNotFound(err))
+	err = c.k8sClient.ReadCR(testCtx, "acr-1", "", &acrcrd.AvailableCapacityReservation{})
+	assert.True(t, k8sError.IsNotFound(err))
+	err = c.k8sClient.ReadCR(testCtx, "drive-1", "", &drivecrd.Drive{})
+	assert.True(t, k8sError.IsNotFound(err))
+	// resources of another node are kept
+	assert.Nil(t, c.k8sClient.ReadCR(testCtx, "ac-2", "", &accrd.AvailableCapacity{}))
+}
+
+func deletePV(t *testing.T, c *Controller) {
+	pv := &coreV1.PersistentVolume{}
+	assert.Nil(t, c.k8sClient.ReadCR(testCtx, "pvc-1", "", pv))
+	assert.Nil(t, c.k8sClient.DeleteCR(testCtx, pv))
+}
+
+func TestDecommission_ReleasedVolume(t *testing.T) {
+	c := setupDecommission(t, apiV1.DecommissionPolicyWait)
+	pvc := &coreV1.PersistentVolumeClaim{}
+	assert.Nil(t, c.k8sClient.ReadCR(testCtx, "pvc", testNS, pvc))
+	assert.Nil(t, c.k8sClient.DeleteCR(testCtx, pvc))
+	// data of the released volume is already lost
+	volume := &volumecrd.Volume{}
+	assert.Nil(t, c.k8sClient.ReadCR(testCtx, "pvc-1", testNS, volume))
+	volume.Spec.Usage = apiV1.VolumeUsageReleased
+	assert.Nil(t, c.k8sClient.UpdateCR(testCtx, volume))
+
+	res, err := c.Reconcile(decommissionReq)
+	assert.Nil(t, err)
+	assert.Equal(t, ctrl.Result{}, res)
+	assertNodeResourcesDeleted(t, c)
+}
+
+func TestController_forceDelete(t *testing.T) {
+	c := setup(t)
+	drive := c.k8sClient.ConstructDriveCR("drive-1", api.Drive{UUID: "drive-1", NodeId: decommissionNodeID})
+	drive.Namespace = testNS
+	drive.Finalizers = []string{"dell.emc.csi/drive-cleanup"}
+	volume := c.k8sClient.ConstructVolumeCR("pvc-1", testNS, api.Volume{Id: "pvc-1"})
+	volume.Finalizers = []string{"dell.emc.csi/volume-cleanup"}
+	createObjects(t, c.k8sClient, drive, volume)
+
+	// finalizer of the drive is removed by drive controller
+	assert.Nil(t, c.forceDelete(testCtx, drive))
+	assert.Equal(t, apiV1.ForceDeleteOn, drive.Annotations[apiV1.AnnotationForceDelete])
+	assert.Equal(t, []string{"dell.emc.csi/drive-cleanup"}, drive.Finalizers)
+
+	assert.Nil(t, c.forceDelete(testCtx, volume))
+	assert.Equal(t, apiV1.ForceDeleteOn, volume.Annotations[apiV1.AnnotationForceDelete])
+	assert.Empty(t, volume.Finalizers)
+}
+
+func TestDecommission_Force(t *testing.T) {
+	c := setupDecommission(t, apiV1.DecommissionPolicyForce)
+
+	res, err := c.Reconcile(decommissionReq)
+	assert.Nil(t, err)
+	assert.Equal(t, RequeueDecommissionTime, res.RequeueAfter)
+	err = c.k8sClient.ReadCR(testCtx, "pvc", testNS, &coreV1.PersistentVolumeClaim{})
+	assert.True(t, k8sError.IsNotFound(err))
+	// Volume CRs are kept until PV is deleted
+	assert.Equal(t, apiV1.DecommissionWaitingForPVs, readDecommissionAnnotations(t, c)[apiV1.NodeAnnotationDecommissionStatus])
+	assert.Nil(t, c.k8sClient.ReadCR(testCtx, "pvc-1", testNS, &volumecrd.Volume{}))
+
+	deletePV(t, c)
+	res, err = c.Reconcile(decommissionReq)
+	assert.Nil(t, err)
+	assert.Equal(t, ctrl.Result{}, res)
+	assertNodeResourcesDeleted(t, c)
+	annotations := readDecommissionAnnotations(t, c)
+	assert.Equal(t, apiV1.DecommissionDone, annotations[apiV1.NodeAnnotationDecommissionStatus])
+	assert.Equal(t, "deleted 1 volumes, 1 LVGs, 1 ACs, 1 ACRs, 1 drives",
+		annotations[apiV1.NodeAnnotationDecommissionProgress])
+
+	// decommission is handled once
+	res, err = c.Reconcile(decommissionReq)
+	assert.Nil(t, err)
+	assert.Equal(t, ctrl.Result{}, res)
+}
+
+func TestDecommission_Wait(t *testing.T) {
+	c := setupDecommission(t, apiV1.DecommissionPolicyWait)
+
+	res, err := c.Reconcile(decommissionReq)
+	assert.Nil(t, err)
+	assert.Equal(t, RequeueDecommissionTime, res.RequeueAfter)
+	annotations := readDecommissionAnnotations(t, c)
+	assert.Equal(t, apiV1.DecommissionWaitingForPVCs, annotations[apiV1.NodeAnnotationDecommissionStatus])
+	// volumes are kept
+	assert.Nil(t, c.k8sClient.ReadCR(testCtx, "ac-1", "", &accrd.AvailableCapacity{}))
+	assert.Nil(t, c.k8sClient.ReadCR(testCtx, "pvc-1", testNS, &volumecrd.Volume{}))
+
+	// PVC is deleted by user
+	pvc := &coreV1.PersistentVolumeClaim{}
+	assert.Nil(t, c.k8sClient.ReadCR(testCtx, "pvc", testNS, pvc))
+	assert.Nil(t, c.k8sClient.DeleteCR(testCtx, pvc))
+	res, err = c.Reconcile(decommissionReq)
+	assert.Nil(t, err)
+	assert.Equal(t, RequeueDecommissionTime, res.RequeueAfter)
+	assert.Equal(t, apiV1.DecommissionWaitingForPVs, readDecommissionAnnotations(t, c)[apiV1.NodeAnnotationDecommissionStatus])
+
+	// PV is deleted by provisioner
+	deletePV(t, c)
+	res, err = c.Reconcile(decommissionReq)
+	assert.Nil(t, err)
+	assert.Equal(t, ctrl.Result{}, res)
+	assertNodeResourcesDeleted(t, c)
+	assert.Equal(t, apiV1.DecommissionDone, readDecommissionAnnotations(t, c)[apiV1.NodeAnnotationDecommissionStatus])
+}
+
+func TestDecommission_UnknownPolicy(t *testing.T) {
+	c := setupDecommission(t, "unknown")
+
+	res, err := c.Reconcile(decommissionReq)
+	assert.Nil(t, err)
+	assert.Equal(t, ctrl.Result{}, res)
+	assert.Equal(t, apiV1.DecommissionFailed, readDecommissionAnnotations(t, c)[apiV1.NodeAnnotationDecommissionStatus])
+	assert.Nil(t, c.k8sClient.ReadCR(testCtx, "pvc-1", testNS, &volumecrd.Volume{}))
+}
@@ -0,0 +1,127 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package quota contains controller which reports usage of StorageQuota custom resources
+package quota
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/dell/csi-baremetal/api/v1/lvgcrd"
+	sqcrd "github.com/dell/csi-baremetal/api/v1/storagequotacrd"
+	"github.com/dell/csi-baremetal/api/v1/volumecrd"
+	"github.com/dell/csi-baremetal/pkg/base/k8s"
+	"github.com/dell/csi-baremetal/pkg/base/quota"
+	metricsC "github.com/dell/csi-baremetal/pkg/metrics/common"
+)
+
+// RequeueQuotaTime is time between usage calculations,
+// volumes are located in the namespaces which aren't watched by controller manager
+const RequeueQuotaTime = time.Second * 30
+
+// Controller reconciles StorageQuota custom resources and reports their usage in status
+type Controller struct {
+	client *k8s.KubeClient
+	log    *logrus.Entry
+}
+
+// NewController creates new instance of Controller structure
+// Receives an instance of base.KubeClient and logrus logger
+// Returns an instance of Controller
+func NewController(client *k8s.KubeClient, log *logrus.Logger) *Controller {
+	return &Controller{
+		client: client,
+		log:    log.WithField("component", "StorageQuotaController"),
+	}
+}
+
+// SetupWithManager registers Controller to ControllerManager
+func (c *Controller) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&sqcrd.StorageQuota{}).
+		WithEventFilter(predicate.Funcs{
+			UpdateFunc: func(e event.UpdateEvent) bool {
+				return filterUpdateEvent(e.ObjectOld, e.ObjectNew)
+			},
+		}).
+		Complete(c)
+}
+
+// Reconcile calculates usage of StorageQuota
+func (c *Controller) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+	defer metricsC.ReconcileDuration.EvaluateDurationForType("csicontroller_storage_quota_controller")()
+	ctx, cancelFn := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancelFn()
+
+	log := c.log.WithFields(logrus.Fields{"method": "Reconcile", "name": req.Name})
+
+	storageQuota := &sqcrd.StorageQuota{}
+	if err := c.client.ReadCR(ctx, req.Name, "", storageQuota); err != nil {
+		log.Warningf("Failed to read StorageQuota: %v", err)
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	volumes := &volumecrd.VolumeList{}
+	if err := c.client.ReadList(ctx, volumes); err != nil {
+		log.Errorf("Failed to read Volume CRs: %v", err)
+		return ctrl.Result{}, err
+	}
+	lvgs := &lvgcrd.LogicalVolumeGroupList{}
+	if err := c.client.ReadList(ctx, lvgs); err != nil {
+		log.Errorf("Failed to read LogicalVolumeGroup CRs: %v", err)
+		return ctrl.Result{}, err
+	}
+
+	usage := quota.CalculateUsage(storageQuota, volumes.Items, lvgs.Items)
+	status := sqcrd.StorageQuotaStatus{
+		UsedBytes:      usage.Bytes,
+		UsedVolumes:    usage.Volumes,
+		UsedDrives:     usage.Drives,
+		LastUpdateTime: storageQuota.Status.LastUpdateTime,
+	}
+	if status == storageQuota.Status {
+		return ctrl.Result{RequeueAfter: RequeueQuotaTime}, nil
+	}
+
+	status.LastUpdateTime = metav1.Now()
+	storageQuota.Status = status
+	if err := c.client.Status().Update(ctx, storageQuota); err != nil {
+		log.Errorf("Failed to update StorageQuota status: %v", err)
+		return ctrl.Result{}, err
+	}
+	log.Infof("Usage of namespace %s: %d bytes, %d volumes, %d drives",
+		storageQuota.Spec.Namespace, usage.Bytes, usage.Volumes, usage.Drives)
+	return ctrl.Result{RequeueAfter: RequeueQuotaTime}, nil
+}
+
+// filterUpdateEvent skips status updates of the quotas
+func filterUpdateEvent(old runtime.Object, new runtime.Object) bool {
+	if oldQuota, ok := old.(*sqcrd.StorageQuota); ok {
+		newQuota, ok := new.(*sqcrd.StorageQuota)
+		return !ok || !reflect.DeepEqual(oldQuota.Spec, newQuota.Spec)
+	}
+	return true
+}
@@ -0,0 +1,93 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quota
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	sqcrd "github.com/dell/csi-baremetal/api/v1/storagequotacrd"
+	"github.com/dell/csi-baremetal/api/v1/volumecrd"
+	"github.com/dell/csi-baremetal/pkg/base/k8s"
+)
+
+var (
+	tCtx       = context.Background()
+	testLogger = logrus.New()
+	ns         = "default"
+	quotaName  = "quota"
+)
+
+func TestController_Reconcile(t *testing.T) {
+	kubeClient, err := k8s.GetFakeKubeClient(ns, testLogger)
+	assert.Nil(t, err)
+	c := NewController(kubeClient, testLogger)
+
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: quotaName}}
+	// quota doesn't exist
+	res, err := c.Reconcile(req)
+	assert.Nil(t, err)
+	assert.Equal(t, ctrl.Result{}, res)
+
+	storageQuota := &sqcrd.StorageQuota{
+		TypeMeta:   metav1.TypeMeta{Kind: apiV1.StorageQuotaKind, APIVersion: apiV1.APIV1Version},
+		ObjectMeta: metav1.ObjectMeta{Name: quotaName, Namespace: ns},
+		Spec:       sqcrd.StorageQuotaSpec{Namespace: ns, StorageClass: apiV1.StorageClassHDD},
+	}
+	assert.Nil(t, kubeClient.Create(tCtx, storageQuota))
+	for _, name := range []string{"volume-1", "volume-2"} {
+		assert.Nil(t, kubeClient.Create(tCtx, &volumecrd.Volume{
+			TypeMeta:   metav1.TypeMeta{Kind: apiV1.VolumeKind, APIVersion: apiV1.APIV1Version},
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns},
+			Spec: api.Volume{Id: name, StorageClass: apiV1.StorageClassHDD, Location: name,
+				LocationType: apiV1.LocationTypeDrive, Size: 1024, CSIStatus: apiV1.Created},
+		}))
+	}
+
+	res, err = c.Reconcile(req)
+	assert.Nil(t, err)
+	assert.Equal(t, RequeueQuotaTime, res.RequeueAfter)
+	assert.Nil(t, kubeClient.ReadCR(tCtx, quotaName, "", storageQuota))
+	assert.Equal(t, int64(2048), storageQuota.Status.UsedBytes)
+	assert.Equal(t, int64(2), storageQuota.Status.UsedVolumes)
+	assert.Equal(t, int64(2), storageQuota.Status.UsedDrives)
+	assert.False(t, storageQuota.Status.LastUpdateTime.IsZero())
+
+	// status isn't updated if usage wasn't changed
+	version := storageQuota.ResourceVersion
+	_, err = c.Reconcile(req)
+	assert.Nil(t, err)
+	assert.Nil(t, kubeClient.ReadCR(tCtx, quotaName, "", storageQuota))
+	assert.Equal(t, version, storageQuota.ResourceVersion)
+}
+
+func TestFilterUpdateEvent(t *testing.T) {
+	oldQuota := &sqcrd.StorageQuota{Spec: sqcrd.StorageQuotaSpec{Namespace: ns}}
+	newQuota := oldQuota.DeepCopy()
+	newQuota.Status.UsedVolumes = 1
+	assert.False(t, filterUpdateEvent(oldQuota, newQuota))
+	newQuota.Spec.StorageClass = apiV1.StorageClassHDD
+	assert.True(t, filterUpdateEvent(oldQuota, newQuota))
+}
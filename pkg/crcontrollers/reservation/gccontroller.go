@@ -0,0 +1,213 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reservation
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	coreV1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1 "github.com/dell/csi-baremetal/api/v1"
+	acrcrd "github.com/dell/csi-baremetal/api/v1/acreservationcrd"
+	fc "github.com/dell/csi-baremetal/pkg/base/featureconfig"
+	"github.com/dell/csi-baremetal/pkg/base/k8s"
+	"github.com/dell/csi-baremetal/pkg/base/util"
+	annotations "github.com/dell/csi-baremetal/pkg/crcontrollers/operator/common"
+	"github.com/dell/csi-baremetal/pkg/eventing"
+	metricsC "github.com/dell/csi-baremetal/pkg/metrics/common"
+)
+
+const (
+	// DefaultReservationTTL is a time after which REQUESTED and REJECTED reservations are removed
+	DefaultReservationTTL = 10 * time.Minute
+	// gcResyncPeriod is a period of checking pod of the reservation
+	gcResyncPeriod = time.Minute
+
+	// reasons of reservation release, used as metric label
+	reasonPodDeleted     = "pod-deleted"
+	reasonPodRecreated   = "pod-recreated"
+	reasonPodCompleted   = "pod-completed"
+	reasonPodRunning     = "pod-running"
+	reasonBoundElsewhere = "bound-elsewhere"
+	reasonExpired        = "expired"
+)
+
+// eventRecorder interface for sending events
+type eventRecorder interface {
+	Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{})
+}
+
+// GCController releases AvailableCapacityReservations which are not needed anymore:
+// pod of the reservation is gone, recreated, finished, already running or bound to the node which wasn't reserved,
+// REQUESTED and REJECTED reservations are removed after TTL
+type GCController struct {
+	client        *k8s.KubeClient
+	eventRecorder eventRecorder
+	ttl           time.Duration
+	log           *logrus.Entry
+
+	// node ID is taken from UID or annotation of the node according to the node service settings
+	featureChecker fc.FeatureChecker
+	annotationKey  string
+}
+
+// NewGCController creates new instance of GCController structure
+// Receives an instance of base.KubeClient, event recorder, TTL for not confirmed reservations, feature checker and
+// node ID annotation key which define how node ID is set and logrus logger
+// Returns an instance of GCController
+func NewGCController(client *k8s.KubeClient, eventRecorder eventRecorder, ttl time.Duration,
+	featureChecker fc.FeatureChecker, annotationKey string, log *logrus.Logger) *GCController {
+	return &GCController{
+		client:         client,
+		eventRecorder:  eventRecorder,
+		ttl:            ttl,
+		featureChecker: featureChecker,
+		annotationKey:  annotationKey,
+		log:            log.WithField("component", "ReservationGCController"),
+	}
+}
+
+// SetupWithManager registers GCController to ControllerManager
+// Pods might be in any namespace so they aren't watched, reservations are checked periodically instead
+func (c *GCController) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("reservation-gc").
+		For(&acrcrd.AvailableCapacityReservation{}).
+		Complete(c)
+}
+
+// Reconcile checks whether ACR is still needed and removes it otherwise
+func (c *GCController) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+	defer metricsC.ReconcileDuration.EvaluateDurationForType("reservation_gc_controller")()
+
+	ctx, cancelFn := context.WithTimeout(context.Background(), contextTimeoutSeconds*time.Second)
+	defer cancelFn()
+
+	log := c.log.WithFields(logrus.Fields{"method": "Reconcile", "name": req.Name})
+
+	reservation := &acrcrd.AvailableCapacityReservation{}
+	if err := c.client.ReadCR(ctx, req.Name, "", reservation); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	reason, err := c.checkReservation(ctx, reservation)
+	if err != nil {
+		log.Errorf("Unable to check reservation: %v", err)
+		return ctrl.Result{Requeue: true}, err
+	}
+	if reason == "" {
+		return ctrl.Result{RequeueAfter: c.nextCheck(reservation)}, nil
+	}
+
+	if err := c.client.DeleteCR(ctx, reservation); err != nil && !k8serrors.IsNotFound(err) {
+		log.Errorf("Unable to release reservation: %v", err)
+		return ctrl.Result{Requeue: true}, err
+	}
+	log.Infof("Reservation with status %s was released, reason: %s", reservation.Spec.Status, reason)
+	metricsC.LeakedReservations.WithLabelValues(reason).Inc()
+
+	eventReason := eventing.ReservationReleased
+	if reason == reasonExpired {
+		eventReason = eventing.ReservationExpired
+	}
+	c.eventRecorder.Eventf(reservation, eventing.NormalType, eventReason,
+		"Reservation with status %s was released, reason: %s", reservation.Spec.Status, reason)
+	return ctrl.Result{}, nil
+}
+
+// checkReservation returns reason why reservation should be released or empty string if it's still needed
+func (c *GCController) checkReservation(ctx context.Context, reservation *acrcrd.AvailableCapacityReservation) (string, error) {
+	status := reservation.Spec.Status
+	if status == v1.ReservationRequested || status == v1.ReservationRejected {
+		if time.Since(reservation.CreationTimestamp.Time) > c.ttl {
+			return reasonExpired, nil
+		}
+	}
+
+	name, namespace := podOfReservation(reservation)
+	pod := &coreV1.Pod{}
+	if err := c.client.ReadCR(ctx, name, namespace, pod); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return reasonPodDeleted, nil
+		}
+		return "", err
+	}
+
+	if uid, ok := reservation.Annotations[v1.ReservationAnnotationPodUID]; ok && uid != "" && uid != string(pod.UID) {
+		return reasonPodRecreated, nil
+	}
+
+	switch pod.Status.Phase {
+	case coreV1.PodSucceeded, coreV1.PodFailed:
+		return reasonPodCompleted, nil
+	case coreV1.PodRunning:
+		// volumes were created and reservation should have been removed by CreateVolume
+		return reasonPodRunning, nil
+	}
+
+	if status != v1.ReservationConfirmed || pod.Spec.NodeName == "" {
+		return "", nil
+	}
+	node := &coreV1.Node{}
+	if err := c.client.Get(ctx, client.ObjectKey{Name: pod.Spec.NodeName}, node); err != nil {
+		return "", err
+	}
+	// node ID annotation might be not set yet, reservation is checked again
+	nodeID, err := annotations.GetNodeID(node, c.annotationKey, c.featureChecker)
+	if err != nil {
+		return "", err
+	}
+	if reservation.Spec.NodeRequests != nil && util.ContainsString(reservation.Spec.NodeRequests.Reserved, nodeID) {
+		return "", nil
+	}
+	return reasonBoundElsewhere, nil
+}
+
+// nextCheck returns duration after which reservation should be checked again
+func (c *GCController) nextCheck(reservation *acrcrd.AvailableCapacityReservation) time.Duration {
+	if reservation.Spec.Status == v1.ReservationConfirmed {
+		return gcResyncPeriod
+	}
+	expiresIn := time.Until(reservation.CreationTimestamp.Add(c.ttl))
+	if expiresIn > 0 && expiresIn < gcResyncPeriod {
+		return expiresIn
+	}
+	return gcResyncPeriod
+}
+
+// podOfReservation returns name and namespace of the pod for which reservation was created
+// reservations without annotations are matched by name which is <namespace>-<pod name>
+func podOfReservation(reservation *acrcrd.AvailableCapacityReservation) (string, string) {
+	name := reservation.Annotations[v1.ReservationAnnotationPodName]
+	namespace := reservation.Annotations[v1.ReservationAnnotationPodNamespace]
+	if name != "" && namespace != "" {
+		return name, namespace
+	}
+
+	namespace = reservation.Spec.Namespace
+	if namespace == "" {
+		namespace = "default"
+	}
+	return strings.TrimPrefix(reservation.Name, namespace+"-"), namespace
+}
@@ -0,0 +1,224 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reservation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	genV1 "github.com/dell/csi-baremetal/api/generated/v1"
+	v1 "github.com/dell/csi-baremetal/api/v1"
+	acrcrd "github.com/dell/csi-baremetal/api/v1/acreservationcrd"
+	fc "github.com/dell/csi-baremetal/pkg/base/featureconfig"
+	"github.com/dell/csi-baremetal/pkg/base/k8s"
+	annotations "github.com/dell/csi-baremetal/pkg/crcontrollers/operator/common"
+	"github.com/dell/csi-baremetal/pkg/eventing"
+	"github.com/dell/csi-baremetal/pkg/mocks"
+)
+
+var (
+	testCtx    = context.Background()
+	testLogger = logrus.New()
+	testNs     = "default"
+	testPodUID = "pod-uid"
+
+	testNode = coreV1.Node{ObjectMeta: metaV1.ObjectMeta{Name: "node-1", UID: types.UID("node-1-uid")}}
+)
+
+func setupGC(t *testing.T) (*GCController, *mocks.NoOpRecorder) {
+	k, err := k8s.GetFakeKubeClient(testNs, testLogger)
+	assert.Nil(t, err)
+	recorder := &mocks.NoOpRecorder{}
+	c := NewGCController(k8s.NewKubeClient(k, testLogger, testNs), recorder, DefaultReservationTTL,
+		fc.NewFeatureConfig(), "", testLogger)
+	node := testNode
+	assert.Nil(t, c.client.Create(testCtx, &node))
+	return c, recorder
+}
+
+func createPod(t *testing.T, c *GCController, nodeName string, phase coreV1.PodPhase) {
+	pod := &coreV1.Pod{
+		ObjectMeta: metaV1.ObjectMeta{Name: "pod-1", Namespace: testNs, UID: types.UID(testPodUID)},
+		Spec:       coreV1.PodSpec{NodeName: nodeName},
+		Status:     coreV1.PodStatus{Phase: phase},
+	}
+	assert.Nil(t, c.client.Create(testCtx, pod))
+}
+
+func createReservation(t *testing.T, c *GCController, status string, age time.Duration,
+	annotations map[string]string) *acrcrd.AvailableCapacityReservation {
+	acr := c.client.ConstructACRCR(testNs+"-pod-1", genV1.AvailableCapacityReservation{
+		Namespace:    testNs,
+		Status:       status,
+		NodeRequests: &genV1.NodeRequests{Requested: []string{"node-1-uid"}, Reserved: []string{"node-1-uid"}},
+	})
+	acr.Annotations = annotations
+	acr.CreationTimestamp = metaV1.NewTime(time.Now().Add(-age))
+	assert.Nil(t, c.client.Create(testCtx, acr))
+	return acr
+}
+
+func reconcile(t *testing.T, c *GCController, acr *acrcrd.AvailableCapacityReservation) (ctrl.Result, bool) {
+	res, err := c.Reconcile(ctrl.Request{NamespacedName: types.NamespacedName{Name: acr.Name}})
+	assert.Nil(t, err)
+	err = c.client.ReadCR(testCtx, acr.Name, "", &acrcrd.AvailableCapacityReservation{})
+	return res, err == nil
+}
+
+func TestGCController_Reconcile_PodDeleted(t *testing.T) {
+	c, recorder := setupGC(t)
+	acr := createReservation(t, c, v1.ReservationConfirmed, time.Second, nil)
+
+	_, exists := reconcile(t, c, acr)
+	assert.False(t, exists)
+	assert.Equal(t, 1, len(recorder.Calls))
+	assert.Equal(t, eventing.ReservationReleased, recorder.Calls[0].Reason)
+}
+
+func TestGCController_Reconcile_PodPending(t *testing.T) {
+	c, recorder := setupGC(t)
+	createPod(t, c, "", coreV1.PodPending)
+	acr := createReservation(t, c, v1.ReservationConfirmed, time.Second, map[string]string{
+		v1.ReservationAnnotationPodName:      "pod-1",
+		v1.ReservationAnnotationPodNamespace: testNs,
+		v1.ReservationAnnotationPodUID:       testPodUID,
+	})
+
+	res, exists := reconcile(t, c, acr)
+	assert.True(t, exists)
+	assert.Equal(t, gcResyncPeriod, res.RequeueAfter)
+	assert.Equal(t, 0, len(recorder.Calls))
+}
+
+func TestGCController_Reconcile_PodRecreated(t *testing.T) {
+	c, _ := setupGC(t)
+	createPod(t, c, "", coreV1.PodPending)
+	acr := createReservation(t, c, v1.ReservationConfirmed, time.Second, map[string]string{
+		v1.ReservationAnnotationPodUID: "another-uid",
+	})
+
+	_, exists := reconcile(t, c, acr)
+	assert.False(t, exists)
+}
+
+func TestGCController_Reconcile_PodCompleted(t *testing.T) {
+	c, _ := setupGC(t)
+	createPod(t, c, testNode.Name, coreV1.PodSucceeded)
+	acr := createReservation(t, c, v1.ReservationConfirmed, time.Second, nil)
+
+	_, exists := reconcile(t, c, acr)
+	assert.False(t, exists)
+}
+
+func TestGCController_Reconcile_Bound(t *testing.T) {
+	c, _ := setupGC(t)
+	createPod(t, c, testNode.Name, coreV1.PodPending)
+	acr := createReservation(t, c, v1.ReservationConfirmed, time.Second, nil)
+
+	// pod is bound to reserved node
+	_, exists := reconcile(t, c, acr)
+	assert.True(t, exists)
+
+	// pod is bound to another node
+	node := coreV1.Node{ObjectMeta: metaV1.ObjectMeta{Name: "node-2", UID: types.UID("node-2-uid")}}
+	assert.Nil(t, c.client.Create(testCtx, &node))
+	pod := &coreV1.Pod{}
+	assert.Nil(t, c.client.ReadCR(testCtx, "pod-1", testNs, pod))
+	pod.Spec.NodeName = node.Name
+	assert.Nil(t, c.client.Update(testCtx, pod))
+
+	_, exists = reconcile(t, c, acr)
+	assert.False(t, exists)
+}
+
+func TestGCController_Reconcile_Expired(t *testing.T) {
+	c, recorder := setupGC(t)
+	createPod(t, c, "", coreV1.PodPending)
+
+	acr := createReservation(t, c, v1.ReservationRequested, DefaultReservationTTL-30*time.Second, nil)
+	res, exists := reconcile(t, c, acr)
+	assert.True(t, exists)
+	assert.True(t, res.RequeueAfter <= 30*time.Second)
+	assert.Nil(t, c.client.DeleteCR(testCtx, acr))
+
+	acr = createReservation(t, c, v1.ReservationRejected, DefaultReservationTTL+time.Second, nil)
+	_, exists = reconcile(t, c, acr)
+	assert.False(t, exists)
+	assert.Equal(t, 1, len(recorder.Calls))
+	assert.Equal(t, eventing.ReservationExpired, recorder.Calls[0].Reason)
+
+	// cancelled reservations aren't expired
+	acr = createReservation(t, c, v1.ReservationCancelled, DefaultReservationTTL+time.Second, nil)
+	_, exists = reconcile(t, c, acr)
+	assert.True(t, exists)
+}
+
+func TestGCController_Reconcile_BoundNodeAnnotation(t *testing.T) {
+	c, _ := setupGC(t)
+	featureConf := fc.NewFeatureConfig()
+	featureConf.Update(fc.FeatureNodeIDFromAnnotation, true)
+	c.featureChecker = featureConf
+	createPod(t, c, testNode.Name, coreV1.PodPending)
+	acr := createReservation(t, c, v1.ReservationConfirmed, time.Second, nil)
+
+	// node ID annotation isn't set yet, reservation is kept
+	_, err := c.Reconcile(ctrl.Request{NamespacedName: types.NamespacedName{Name: acr.Name}})
+	assert.NotNil(t, err)
+	assert.Nil(t, c.client.ReadCR(testCtx, acr.Name, "", &acrcrd.AvailableCapacityReservation{}))
+
+	// node ID is taken from the operator annotation only, another annotation with reserved ID is ignored
+	node := &coreV1.Node{}
+	assert.Nil(t, c.client.Get(testCtx, client.ObjectKey{Name: testNode.Name}, node))
+	node.Annotations = map[string]string{annotations.DeafultNodeIDAnnotationKey: "node-1-id", "custom/id": "node-1-uid"}
+	assert.Nil(t, c.client.Update(testCtx, node))
+	_, exists := reconcile(t, c, acr)
+	assert.False(t, exists)
+}
+
+func TestGCController_Reconcile_NotFound(t *testing.T) {
+	c, _ := setupGC(t)
+	res, err := c.Reconcile(ctrl.Request{NamespacedName: types.NamespacedName{Name: "not-found"}})
+	assert.Nil(t, err)
+	assert.Equal(t, ctrl.Result{}, res)
+}
+
+func TestPodOfReservation(t *testing.T) {
+	acr := &acrcrd.AvailableCapacityReservation{
+		ObjectMeta: metaV1.ObjectMeta{Name: "ns-1-pod-1"},
+		Spec:       genV1.AvailableCapacityReservation{Namespace: "ns-1"},
+	}
+	name, namespace := podOfReservation(acr)
+	assert.Equal(t, "pod-1", name)
+	assert.Equal(t, "ns-1", namespace)
+
+	acr.Annotations = map[string]string{
+		v1.ReservationAnnotationPodName:      "pod-2",
+		v1.ReservationAnnotationPodNamespace: "ns-2",
+	}
+	name, namespace = podOfReservation(acr)
+	assert.Equal(t, "pod-2", name)
+	assert.Equal(t, "ns-2", namespace)
+}
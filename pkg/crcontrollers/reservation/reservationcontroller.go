@@ -2,9 +2,11 @@ package reservation
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/sirupsen/logrus"
+	coreV1 "k8s.io/api/core/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -25,16 +27,19 @@ type Controller struct {
 	client                 *k8s.KubeClient
 	log                    *logrus.Entry
 	capacityManagerBuilder capacityplanner.CapacityManagerBuilder
+	// capacityIndex is an in-memory index of ACs and ACRs, all CRs are read for each request if nil
+	capacityIndex *capacityplanner.CapacityIndex
 }
 
 // NewController creates new instance of Controller structure
-// Receives an instance of base.KubeClient, node ID and logrus logger
+// Receives an instance of base.KubeClient, capacity index (might be nil) and logrus logger
 // Returns an instance of Controller
-func NewController(client *k8s.KubeClient, log *logrus.Logger) *Controller {
+func NewController(client *k8s.KubeClient, capacityIndex *capacityplanner.CapacityIndex, log *logrus.Logger) *Controller {
 	return &Controller{
 		client:                 client,
 		log:                    log.WithField("component", "ReservationController"),
 		capacityManagerBuilder: &capacityplanner.DefaultCapacityManagerBuilder{},
+		capacityIndex:          capacityIndex,
 	}
 }
 
@@ -85,11 +90,14 @@ func (c *Controller) handleReservationUpdate(ctx context.Context, log *logrus.En
 			volumes[i] = &v1api.Volume{Id: capacity.Name, Size: capacity.Size, StorageClass: capacity.StorageClass}
 		}
 
-		// TODO: do not read all ACs and ACRs for each request: https://github.com/dell/csi-baremetal/issues/89
-		acReader := capacityplanner.NewACReader(c.client, c.log, true)
-		acrReader := capacityplanner.NewACRReader(c.client, c.log, true)
-		reservedCapReader := capacityplanner.NewUnreservedACReader(c.log, acReader, acrReader)
-		capManager := c.capacityManagerBuilder.GetCapacityManager(c.log, reservedCapReader)
+		var err error
+		if ctx, err = c.withPlanningConstraints(ctx, reservation); err != nil {
+			log.Errorf("Failed to resolve placing constraints: %v", err)
+			return ctrl.Result{Requeue: true}, err
+		}
+
+		acReader, unreservedCapReader := c.getCapacityReaders()
+		capManager := c.capacityManagerBuilder.GetCapacityManager(c.log, unreservedCapReader)
 
 		requestedNodes := reservationSpec.NodeRequests.Requested
 		placingPlan, err := capManager.PlanVolumesPlacing(ctx, volumes, requestedNodes)
@@ -115,6 +123,7 @@ func (c *Controller) handleReservationUpdate(ctx context.Context, log *logrus.En
 				c.log.Errorf("Failed to update reservation: %s", err.Error())
 				return ctrl.Result{Requeue: true}, err
 			}
+			c.updateIndex(reservation)
 		} else {
 			// reject reservation
 			reservation.Spec.Status = v1.ReservationRejected
@@ -122,9 +131,58 @@ func (c *Controller) handleReservationUpdate(ctx context.Context, log *logrus.En
 				c.log.Errorf("Unable to reject reservation %s: %v", reservation.Name, err)
 				return ctrl.Result{Requeue: true}, err
 			}
+			c.updateIndex(reservation)
 		}
 		return ctrl.Result{}, nil
 	default:
 		return ctrl.Result{}, nil
 	}
 }
+
+// getCapacityReaders returns reader of all ACs and reader of unreserved ACs
+// capacity index is used if it's set, otherwise all ACs and ACRs are read from API
+func (c *Controller) getCapacityReaders() (capacityplanner.CapacityReader, capacityplanner.CapacityReader) {
+	if c.capacityIndex != nil {
+		return c.capacityIndex, c.capacityIndex
+	}
+	acReader := capacityplanner.NewACReader(c.client, c.log, true)
+	acrReader := capacityplanner.NewACRReader(c.client, c.log, true)
+	return acReader, capacityplanner.NewUnreservedACReader(c.log, acReader, acrReader)
+}
+
+// updateIndex puts updated reservation to the capacity index without waiting for informer event,
+// so next reservation request doesn't use ACs which were just reserved
+func (c *Controller) updateIndex(reservation *acrcrd.AvailableCapacityReservation) {
+	if c.capacityIndex != nil {
+		c.capacityIndex.UpdateACR(reservation)
+	}
+}
+
+// withPlanningConstraints returns context with constraints of capacity planning for volumes of the reservation
+func (c *Controller) withPlanningConstraints(ctx context.Context,
+	reservation *acrcrd.AvailableCapacityReservation) (context.Context, error) {
+	// pod might be already removed, volumes aren't spread in that case
+	name, namespace := podOfReservation(reservation)
+	pod := &coreV1.Pod{}
+	if err := c.client.ReadCR(ctx, name, namespace, pod); err != nil {
+		if client.IgnoreNotFound(err) != nil {
+			return nil, fmt.Errorf("unable to read pod %s: %v", name, err)
+		}
+		pod = nil
+	}
+	constraints, err := capacityplanner.ResolvePlanningConstraints(ctx, c.client, reservation.Spec.Namespace, pod,
+		requestsOfReservation(reservation))
+	if err != nil {
+		return nil, err
+	}
+	return capacityplanner.WithPlanningConstraints(ctx, constraints), nil
+}
+
+// requestsOfReservation returns capacity requests of the reservation
+func requestsOfReservation(reservation *acrcrd.AvailableCapacityReservation) []*v1api.CapacityRequest {
+	requests := make([]*v1api.CapacityRequest, 0, len(reservation.Spec.ReservationRequests))
+	for _, request := range reservation.Spec.ReservationRequests {
+		requests = append(requests, request.CapacityRequest)
+	}
+	return requests
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package basemgr
 
 import (
+	"context"
 	"strconv"
 
 	"github.com/sirupsen/logrus"
@@ -41,17 +42,17 @@ type BaseManager struct {
 }
 
 // GetDrivesList gets api.Drive slice using Linux system utils
-func (mgr BaseManager) GetDrivesList() ([]*api.Drive, error) {
+func (mgr BaseManager) GetDrivesList(ctx context.Context) ([]*api.Drive, error) {
 	ll := mgr.log.WithField("method", "GetDrivesList")
 	var (
 		devices    []*api.Drive
 		nvmDevices []*api.Drive
 		err        error
 	)
-	if devices, err = mgr.GetSCSIDevices(); err != nil {
+	if devices, err = mgr.GetSCSIDevices(ctx); err != nil {
 		ll.Errorf("Failed to initialize devices, Error: %v", err)
 	}
-	if nvmDevices, err = mgr.GetNVMDevices(); err != nil {
+	if nvmDevices, err = mgr.GetNVMDevices(ctx); err != nil {
 		ll.Errorf("Failed to initialize devices, Error: %v", err)
 	}
 	devices = append(devices, nvmDevices...)
@@ -75,10 +76,10 @@ func New(exec command.CmdExecutor, logger *logrus.Logger) *BaseManager {
 }
 
 // GetSCSIDevices get []*api.Drive using lsscsi system util
-func (mgr *BaseManager) GetSCSIDevices() ([]*api.Drive, error) {
+func (mgr *BaseManager) GetSCSIDevices(ctx context.Context) ([]*api.Drive, error) {
 	ll := mgr.log.WithField("method", "GetSCSIDevices")
 	allDevices := make([]*api.Drive, 0)
-	scsiDevices, err := mgr.lsscsi.GetSCSIDevices()
+	scsiDevices, err := mgr.lsscsi.GetSCSIDevices(ctx)
 	if err != nil {
 		ll.Errorf("Failed to get SCSI allDevices, Error: %v", err)
 		return nil, err
@@ -94,7 +95,7 @@ func (mgr *BaseManager) GetSCSIDevices() ([]*api.Drive, error) {
 	}
 	devices := make([]*api.Drive, 0)
 	for i, device := range allDevices {
-		smartInfo, err := mgr.smartctl.GetDriveInfoByPath(device.Path)
+		smartInfo, err := mgr.smartctl.GetDriveInfoByPath(ctx, device.Path)
 		if err != nil {
 			// We don't fail whole drivemgr because of error with just one device, we don't add it in allDevices slice
 			ll.Errorf("Failed to get SMART information for Device %v, Error: %v", allDevices[i], err)
@@ -121,10 +122,10 @@ func (mgr *BaseManager) GetSCSIDevices() ([]*api.Drive, error) {
 }
 
 // GetNVMDevices get []*api.Drive using nvme_cli system util
-func (mgr *BaseManager) GetNVMDevices() ([]*api.Drive, error) {
+func (mgr *BaseManager) GetNVMDevices(ctx context.Context) ([]*api.Drive, error) {
 	ll := mgr.log.WithField("method", "GetNVMDevices")
 	devices := make([]*api.Drive, 0)
-	nvmeDevices, err := mgr.nvme.GetNVMDevices()
+	nvmeDevices, err := mgr.nvme.GetNVMDevices(ctx)
 	if err != nil {
 		ll.Errorf("Failed to get NVMe devices, Error: %v", err)
 		return nil, err
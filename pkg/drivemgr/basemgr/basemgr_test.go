@@ -17,6 +17,7 @@ limitations under the License.
 package basemgr
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -32,6 +33,8 @@ import (
 	"github.com/dell/csi-baremetal/pkg/mocks/linuxutils"
 )
 
+var testCtx = context.Background()
+
 var logger = logrus.New()
 
 func TestLoopBackManager_GetNVMDevicesSuccess(t *testing.T) {
@@ -54,7 +57,7 @@ func TestLoopBackManager_GetNVMDevicesSuccess(t *testing.T) {
 		Return(nvmeDevice, nil).Once()
 
 	manager.nvme = mockNvme
-	devices, err := manager.GetNVMDevices()
+	devices, err := manager.GetNVMDevices(testCtx)
 
 	assert.Nil(t, err)
 	assert.Equal(t, 1, len(devices))
@@ -88,7 +91,7 @@ func TestLoopBackManager_GetNVMDevicesEmptyVidPidSn(t *testing.T) {
 		Return(nvmeDevice, nil).Once()
 
 	manager.nvme = mockNvme
-	devices, err := manager.GetNVMDevices()
+	devices, err := manager.GetNVMDevices(testCtx)
 
 	assert.Nil(t, err)
 	assert.Equal(t, 0, len(devices))
@@ -104,7 +107,7 @@ func TestLoopBackManager_GetNVMDevicesFail(t *testing.T) {
 		Return([]nvmecli.NVMDevice{}, fmt.Errorf("error")).Once()
 
 	manager.nvme = mockNvme
-	_, err := manager.GetNVMDevices()
+	_, err := manager.GetNVMDevices(testCtx)
 
 	assert.NotNil(t, err)
 }
@@ -141,7 +144,7 @@ func TestLoopBackManager_GetSCSIDevices(t *testing.T) {
 	manager.lsscsi = mockLsscsi
 	manager.smartctl = mockSmartctl
 
-	devices, err := manager.GetSCSIDevices()
+	devices, err := manager.GetSCSIDevices(testCtx)
 
 	assert.Nil(t, err)
 	assert.Equal(t, 1, len(devices))
@@ -156,7 +159,7 @@ func TestLoopBackManager_GetSCSIDevices(t *testing.T) {
 
 	smart.SmartStatus["passed"] = false
 	smart.Rotation = 7200
-	devices, err = manager.GetSCSIDevices()
+	devices, err = manager.GetSCSIDevices(testCtx)
 	assert.Nil(t, err)
 	assert.Equal(t, 1, len(devices))
 	assert.Equal(t, apiV1.HealthBad, devices[0].Health)
@@ -195,7 +198,7 @@ func TestLoopBackManager_GetSCSIDevicesEmptyVidPidSn(t *testing.T) {
 	manager.lsscsi = mockLsscsi
 	manager.smartctl = mockSmartctl
 
-	devices, err := manager.GetSCSIDevices()
+	devices, err := manager.GetSCSIDevices(testCtx)
 
 	assert.Nil(t, err)
 	assert.Equal(t, 0, len(devices))
@@ -225,7 +228,7 @@ func TestLoopBackManager_GetSCSIDevicesFail(t *testing.T) {
 	manager.smartctl = mockSmartctl
 	manager.lsscsi = mockLsscsi
 
-	devs, err := manager.GetSCSIDevices()
+	devs, err := manager.GetSCSIDevices(testCtx)
 
 	assert.Nil(t, err)
 	assert.Equal(t, len(devs), 0)
@@ -241,7 +244,7 @@ func TestLoopBackManager_GetSCSIDevicesLsscsiFail(t *testing.T) {
 		Return([]*lsscsi.SCSIDevice{}, fmt.Errorf("error"))
 	manager.lsscsi = mockLsscsi
 
-	_, err := manager.GetSCSIDevices()
+	_, err := manager.GetSCSIDevices(testCtx)
 
 	assert.NotNil(t, err)
 }
@@ -261,7 +264,7 @@ func TestLoopBackManager_GetDrivesListFail(t *testing.T) {
 	manager.lsscsi = mockLsscsi
 	manager.nvme = mockNvme
 
-	_, err := manager.GetDrivesList()
+	_, err := manager.GetDrivesList(testCtx)
 
 	assert.Nil(t, err)
 }
@@ -282,7 +285,7 @@ func TestLoopBackManager_GetDrivesListLsscsiFail(t *testing.T) {
 		Return([]nvmecli.NVMDevice{}, nil)
 	manager.nvme = mockNvme
 
-	_, err := manager.GetDrivesList()
+	_, err := manager.GetDrivesList(testCtx)
 
 	assert.Nil(t, err)
 }
@@ -302,7 +305,7 @@ func TestLoopBackManager_GetDrivesListSuccess(t *testing.T) {
 	manager.lsscsi = mockLsscsi
 	manager.nvme = mockNvme
 
-	_, err := manager.GetDrivesList()
+	_, err := manager.GetDrivesList(testCtx)
 
 	assert.Nil(t, err)
 }
@@ -17,12 +17,16 @@ limitations under the License.
 // Package drivemgr contains a code for managers of storage hardware such as drives
 package drivemgr
 
-import api "github.com/dell/csi-baremetal/api/generated/v1"
+import (
+	"context"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+)
 
 // DriveManager is the interface for managers that provide information about drives on a node
 type DriveManager interface {
 	// get list of drives
-	GetDrivesList() ([]*api.Drive, error)
+	GetDrivesList(ctx context.Context) ([]*api.Drive, error)
 	// manipulate of drive's led state, receive drive serial number and type of action
 	// returns current led status or error
 	Locate(serialNumber string, action int32) (currentStatus int32, err error)
@@ -49,7 +49,7 @@ func NewDriveServer(logger *logrus.Logger, manager DriveManager) DriveServiceSer
 // Receives go context and DrivesRequest which contains node id
 // Returns DrivesResponse with slice of api.Drives structs
 func (svc *DriveServiceServerImpl) GetDrivesList(ctx context.Context, req *api.DrivesRequest) (*api.DrivesResponse, error) {
-	drives, err := svc.mgr.GetDrivesList()
+	drives, err := svc.mgr.GetDrivesList(ctx)
 	if err != nil {
 		svc.log.Errorf("DriveManager failed with error: %s", err.Error())
 		return nil, status.Error(codes.Internal, err.Error())
@@ -19,6 +19,7 @@ package idracmgr
 
 import "C"
 import (
+	"context"
 	"crypto/tls"
 	"encoding/json"
 	"errors"
@@ -102,7 +103,7 @@ type IDRACDrive struct {
 
 // GetDrivesList returns slice of *api.Drive created from iDRAC drives
 // Returns slice of *api.Drives struct or error if something went wrong
-func (mgr *IDRACManager) GetDrivesList() ([]*api.Drive, error) {
+func (mgr *IDRACManager) GetDrivesList(_ context.Context) ([]*api.Drive, error) {
 	controllerURL := mgr.getControllerURLs()
 	if len(controllerURL) == 0 {
 		return nil, errors.New("unable to inspect iDRAC controller")
@@ -18,6 +18,7 @@ limitations under the License.
 package loopbackmgr
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -434,7 +435,7 @@ func (mgr *LoopBackManager) Init() {
 		sizeMb, _ := util.ToSizeUnit(sizeBytes, util.BYTE, util.MBYTE)
 		// skip creation if file exists (manager restarted)
 		if _, err := os.Stat(file); err != nil {
-			freeBytes, err := fsOps.GetFSSpace(rootPath)
+			freeBytes, err := fsOps.GetFSSpace(context.Background(), rootPath)
 			if err != nil {
 				ll.Fatal("Failed to check root fs space")
 			}
@@ -488,7 +489,7 @@ func (mgr *LoopBackManager) Init() {
 
 // GetDrivesList returns list of loopback devices as *api.Drive slice
 // Returns *api.Drive slice or error if something went wrong
-func (mgr *LoopBackManager) GetDrivesList() ([]*api.Drive, error) {
+func (mgr *LoopBackManager) GetDrivesList(_ context.Context) ([]*api.Drive, error) {
 	mgr.Lock()
 	defer mgr.Unlock()
 	drives := make([]*api.Drive, 0, len(mgr.devices))
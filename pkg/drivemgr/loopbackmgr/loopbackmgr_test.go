@@ -17,6 +17,7 @@ limitations under the License.
 package loopbackmgr
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -30,6 +31,8 @@ import (
 	"github.com/dell/csi-baremetal/pkg/mocks"
 )
 
+var testCtx = context.Background()
+
 var logger = logrus.New()
 
 func TestLoopBackManager_GetBackFileToLoopMap(t *testing.T) {
@@ -293,7 +296,7 @@ func TestLoopBackManager_GetDrivesList(t *testing.T) {
 	}
 	indexOfDriveToOffline := 0
 	manager.devices[indexOfDriveToOffline].Removed = true
-	drives, err := manager.GetDrivesList()
+	drives, err := manager.GetDrivesList(testCtx)
 
 	assert.Nil(t, err)
 	assert.Equal(t, defaultNumberOfDevices, len(drives))
@@ -26,11 +26,12 @@ const (
 
 // Volume event reason list
 const (
-	VolumeDiscovered    = "VolumeDiscovered"
-	VolumeBadHealth     = "VolumeBadHealth"
-	VolumeUnknownHealth = "VolumeUnknownHealth"
-	VolumeGoodHealth    = "VolumeGoodHealth"
-	VolumeSuspectHealth = "VolumeSuspectHealth"
+	VolumeDiscovered       = "VolumeDiscovered"
+	VolumeBadHealth        = "VolumeBadHealth"
+	VolumeUnknownHealth    = "VolumeUnknownHealth"
+	VolumeGoodHealth       = "VolumeGoodHealth"
+	VolumeSuspectHealth    = "VolumeSuspectHealth"
+	VolumeTopologyOutdated = "VolumeTopologyOutdated"
 
 	DriveDiscovered           = "DriveDiscovered"
 	DriveHealthSuspect        = "DriveHealthSuspect"
@@ -42,6 +43,26 @@ const (
 	DriveReplacementFailed    = "DriveReplacementFailed"
 	DriveReadyForReplacement  = "DriveReadyForReplacement"
 	DriveSuccessfullyReplaced = "DriveSuccessfullyReplaced"
+	DriveReplacementCompleted = "DriveReplacementCompleted"
+	DriveEvacuationStarted    = "DriveEvacuationStarted"
+	DriveEvacuationCompleted  = "DriveEvacuationCompleted"
+	DriveEvacuationFailed     = "DriveEvacuationFailed"
 	DriveHasData              = "DriveHasData"
 	DriveClean                = "DriveClean"
+	DriveFirmwareOutdated     = "DriveFirmwareOutdated"
+	DriveFirmwareCompliant    = "DriveFirmwareCompliant"
+	DriveAdopted              = "DriveAdopted"
+	DriveAdoptionFailed       = "DriveAdoptionFailed"
+	DriveForceDeleted         = "DriveForceDeleted"
+	DriveDestructiveOperation = "DriveDestructiveOperation"
+
+	StorageOrphanOnDisk    = "StorageOrphanOnDisk"
+	StorageMissingOnDisk   = "StorageMissingOnDisk"
+	StorageSizeMismatch    = "StorageSizeMismatch"
+	StorageOrphanCollected = "StorageOrphanCollected"
+
+	ReservationReleased = "ReservationReleased"
+	ReservationExpired  = "ReservationExpired"
+
+	AvailableCapacityForceDeleted = "AvailableCapacityForceDeleted"
 )
@@ -29,7 +29,14 @@ var ReservationDuration = metrics.NewMetrics(prometheus.HistogramOpts{
 	Buckets: prometheus.ExponentialBuckets(0.005, 1.5, 10),
 }, "method")
 
+// LeakedReservations used to count AvailableCapacityReservations released by reservation garbage collector
+var LeakedReservations = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "ac_reservation_leaked_total",
+	Help: "AvailableCapacityReservations which were released by garbage collector",
+}, []string{"reason"})
+
 // nolint: gochecknoinits
 func init() {
 	prometheus.MustRegister(ReservationDuration.Collect())
+	prometheus.MustRegister(LeakedReservations)
 }
@@ -29,7 +29,21 @@ var SystemCMDDuration = metrics.NewMetrics(prometheus.HistogramOpts{
 	Buckets: metrics.ExtendedDefBuckets,
 }, "name")
 
+// SystemCMDTimeouts used to count system utils which were killed because they didn't finish in time
+var SystemCMDTimeouts = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "system_utils_timeouts_total",
+	Help: "System utils which were killed on timeout",
+}, []string{"name"})
+
+// SystemCMDFailures used to count system utils which were finished with error, timeouts are counted too
+var SystemCMDFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "system_utils_failures_total",
+	Help: "System utils which were finished with error",
+}, []string{"name"})
+
 // nolint: gochecknoinits
 func init() {
 	prometheus.MustRegister(SystemCMDDuration.Collect())
+	prometheus.MustRegister(SystemCMDTimeouts)
+	prometheus.MustRegister(SystemCMDFailures)
 }
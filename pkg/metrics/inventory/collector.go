@@ -0,0 +1,223 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package inventory contains prometheus collector of drive, volume and capacity inventory
+package inventory
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	acrcrd "github.com/dell/csi-baremetal/api/v1/acreservationcrd"
+	accrd "github.com/dell/csi-baremetal/api/v1/availablecapacitycrd"
+	"github.com/dell/csi-baremetal/api/v1/drivecrd"
+	"github.com/dell/csi-baremetal/api/v1/lvgcrd"
+	"github.com/dell/csi-baremetal/api/v1/volumecrd"
+	"github.com/dell/csi-baremetal/pkg/base/k8s"
+)
+
+// readTimeout is a timeout of reading custom resources during one scrape
+const readTimeout = 10 * time.Second
+
+var (
+	driveLabels = []string{"node", "drive", "serial_number", "type"}
+
+	driveHealthDesc = prometheus.NewDesc("drive_health",
+		"Health of the drive, 1 for the current health and 0 for others",
+		append(driveLabels, "health"), nil)
+	driveStatusDesc = prometheus.NewDesc("drive_status",
+		"Status of the drive, 1 for the current status and 0 for others",
+		append(driveLabels, "status"), nil)
+	driveUsageDesc = prometheus.NewDesc("drive_usage",
+		"Usage of the drive, 1 for the current usage and 0 for others",
+		append(driveLabels, "usage"), nil)
+	driveSizeDesc = prometheus.NewDesc("drive_size_bytes",
+		"Size of the drive in bytes",
+		driveLabels, nil)
+	acBytesDesc = prometheus.NewDesc("available_capacity_bytes",
+		"Size of AvailableCapacity on the node in bytes, AvailableCapacity which is reserved or being deleted isn't counted",
+		[]string{"node", "storage_class"}, nil)
+	acReservedBytesDesc = prometheus.NewDesc("reserved_capacity_bytes",
+		"Size of AvailableCapacity on the node in bytes which is reserved by AvailableCapacityReservations",
+		[]string{"node", "storage_class"}, nil)
+	volumeCountDesc = prometheus.NewDesc("volume_count",
+		"Number of volumes on the node",
+		[]string{"node", "storage_class", "csi_status", "operational_status"}, nil)
+	lvgSizeDesc = prometheus.NewDesc("lvg_size_bytes",
+		"Size of the LogicalVolumeGroup in bytes",
+		[]string{"node", "lvg"}, nil)
+	lvgFreeDesc = prometheus.NewDesc("lvg_free_bytes",
+		"Free space of the LogicalVolumeGroup in bytes which is available for new volumes",
+		[]string{"node", "lvg"}, nil)
+
+	driveHealths  = []string{apiV1.HealthGood, apiV1.HealthSuspect, apiV1.HealthBad, apiV1.HealthUnknown}
+	driveStatuses = []string{apiV1.DriveStatusOnline, apiV1.DriveStatusOffline}
+	driveUsages   = []string{apiV1.DriveUsageInUse, apiV1.DriveUsageReleasing, apiV1.DriveUsageReleased,
+		apiV1.DriveUsageRemoving, apiV1.DriveUsageRemoved, apiV1.DriveUsageFailed}
+)
+
+// Collector is a prometheus collector which exposes inventory of Drive, AvailableCapacity, Volume and
+// LogicalVolumeGroup custom resources. Custom resources are read on each scrape, reader is supposed to be a cache
+type Collector struct {
+	reader k8s.CRReader
+	log    *logrus.Entry
+}
+
+// NewCollector is a constructor for Collector
+func NewCollector(reader k8s.CRReader, log *logrus.Logger) *Collector {
+	return &Collector{
+		reader: reader,
+		log:    log.WithField("component", "InventoryCollector"),
+	}
+}
+
+// Describe sends descriptors of inventory metrics
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range []*prometheus.Desc{driveHealthDesc, driveStatusDesc, driveUsageDesc, driveSizeDesc,
+		acBytesDesc, acReservedBytesDesc, volumeCountDesc, lvgSizeDesc, lvgFreeDesc} {
+		ch <- desc
+	}
+}
+
+// Collect reads custom resources and sends inventory metrics,
+// metrics of the resources which can't be read are skipped
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancelFn := context.WithTimeout(context.Background(), readTimeout)
+	defer cancelFn()
+
+	drives := &drivecrd.DriveList{}
+	if err := c.reader.ReadList(ctx, drives); err != nil {
+		c.log.Errorf("Unable to read Drive CRs: %v", err)
+	} else {
+		collectDrives(ch, drives.Items)
+	}
+
+	acs := &accrd.AvailableCapacityList{}
+	acrs := &acrcrd.AvailableCapacityReservationList{}
+	if err := c.reader.ReadList(ctx, acs); err != nil {
+		c.log.Errorf("Unable to read AvailableCapacity CRs: %v", err)
+		acs = nil
+	} else if err := c.reader.ReadList(ctx, acrs); err != nil {
+		c.log.Errorf("Unable to read AvailableCapacityReservation CRs: %v", err)
+	} else {
+		collectACs(ch, acs.Items, acrs.Items)
+	}
+
+	volumes := &volumecrd.VolumeList{}
+	if err := c.reader.ReadList(ctx, volumes); err != nil {
+		c.log.Errorf("Unable to read Volume CRs: %v", err)
+	} else {
+		collectVolumes(ch, volumes.Items)
+	}
+
+	lvgs := &lvgcrd.LogicalVolumeGroupList{}
+	if err := c.reader.ReadList(ctx, lvgs); err != nil {
+		c.log.Errorf("Unable to read LogicalVolumeGroup CRs: %v", err)
+	} else if acs != nil {
+		collectLVGs(ch, lvgs.Items, acs.Items)
+	}
+}
+
+func collectDrives(ch chan<- prometheus.Metric, drives []drivecrd.Drive) {
+	for _, drive := range drives {
+		labels := []string{drive.Spec.NodeId, drive.Name, drive.Spec.SerialNumber, drive.Spec.Type}
+		collectState(ch, driveHealthDesc, labels, drive.Spec.Health, driveHealths)
+		collectState(ch, driveStatusDesc, labels, drive.Spec.Status, driveStatuses)
+		collectState(ch, driveUsageDesc, labels, drive.Spec.Usage, driveUsages)
+		ch <- prometheus.MustNewConstMetric(driveSizeDesc, prometheus.GaugeValue, float64(drive.Spec.Size), labels...)
+	}
+}
+
+// collectState sends metric for each of the known states, metric of the current state is 1 and others are 0
+func collectState(ch chan<- prometheus.Metric, desc *prometheus.Desc, labels []string, current string, states []string) {
+	for _, state := range states {
+		value := 0.0
+		if state == current {
+			value = 1
+		}
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, value, append(labels, state)...)
+	}
+}
+
+// collectACs sends free and reserved size of ACs, the whole AC is counted as reserved if confirmed
+// AvailableCapacityReservation refers to it
+func collectACs(ch chan<- prometheus.Metric, acs []accrd.AvailableCapacity,
+	acrs []acrcrd.AvailableCapacityReservation) {
+	reserved := map[string]struct{}{}
+	for _, acr := range acrs {
+		if acr.Spec.Status != apiV1.ReservationConfirmed {
+			continue
+		}
+		for _, request := range acr.Spec.ReservationRequests {
+			for _, acName := range request.Reservations {
+				reserved[acName] = struct{}{}
+			}
+		}
+	}
+
+	type key struct{ node, sc string }
+	free, reservedSizes := map[key]int64{}, map[key]int64{}
+	for _, ac := range acs {
+		k := key{ac.Spec.NodeId, ac.Spec.StorageClass}
+		var freeSize, reservedSize int64
+		if ac.DeletionTimestamp.IsZero() {
+			if _, ok := reserved[ac.Name]; ok {
+				reservedSize = ac.Spec.Size
+			} else {
+				freeSize = ac.Spec.Size
+			}
+		}
+		free[k] += freeSize
+		reservedSizes[k] += reservedSize
+	}
+	for k, size := range free {
+		ch <- prometheus.MustNewConstMetric(acBytesDesc, prometheus.GaugeValue, float64(size), k.node, k.sc)
+		ch <- prometheus.MustNewConstMetric(acReservedBytesDesc, prometheus.GaugeValue, float64(reservedSizes[k]),
+			k.node, k.sc)
+	}
+}
+
+func collectVolumes(ch chan<- prometheus.Metric, volumes []volumecrd.Volume) {
+	type key struct{ node, sc, csiStatus, opStatus string }
+	counts := map[key]int{}
+	for _, volume := range volumes {
+		counts[key{volume.Spec.NodeId, volume.Spec.StorageClass, volume.Spec.CSIStatus, volume.Spec.OperationalStatus}]++
+	}
+	for k, count := range counts {
+		ch <- prometheus.MustNewConstMetric(volumeCountDesc, prometheus.GaugeValue, float64(count),
+			k.node, k.sc, k.csiStatus, k.opStatus)
+	}
+}
+
+// collectLVGs sends size of LVGs and their free space which is a size of AvailableCapacity located on LVG
+func collectLVGs(ch chan<- prometheus.Metric, lvgs []lvgcrd.LogicalVolumeGroup, acs []accrd.AvailableCapacity) {
+	free := map[string]int64{}
+	for _, ac := range acs {
+		if ac.DeletionTimestamp.IsZero() {
+			free[ac.Spec.Location] += ac.Spec.Size
+		}
+	}
+	for _, lvg := range lvgs {
+		ch <- prometheus.MustNewConstMetric(lvgSizeDesc, prometheus.GaugeValue, float64(lvg.Spec.Size),
+			lvg.Spec.Node, lvg.Name)
+		ch <- prometheus.MustNewConstMetric(lvgFreeDesc, prometheus.GaugeValue, float64(free[lvg.Name]),
+			lvg.Spec.Node, lvg.Name)
+	}
+}
@@ -0,0 +1,113 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inventory
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	"github.com/dell/csi-baremetal/pkg/base/k8s"
+)
+
+var (
+	testCtx    = context.Background()
+	testLogger = logrus.New()
+	testNs     = "default"
+)
+
+func TestCollector(t *testing.T) {
+	kubeClient, err := k8s.GetFakeKubeClient(testNs, testLogger)
+	assert.Nil(t, err)
+
+	drive := kubeClient.ConstructDriveCR("drive-1", api.Drive{
+		UUID: "drive-1", SerialNumber: "sn-1", NodeId: "node-1", Type: apiV1.DriveTypeNVMe, Size: 1000,
+		Health: apiV1.HealthBad, Status: apiV1.DriveStatusOnline, Usage: apiV1.DriveUsageInUse,
+	})
+	lvg := kubeClient.ConstructLVGCR("lvg-1", api.LogicalVolumeGroup{
+		Name: "lvg-1", Node: "node-1", Locations: []string{"drive-2"}, Size: 500,
+	})
+	acs := []*api.AvailableCapacity{
+		{Location: "drive-1", NodeId: "node-1", StorageClass: apiV1.StorageClassNVMe, Size: 1000},
+		{Location: "drive-3", NodeId: "node-1", StorageClass: apiV1.StorageClassNVMe, Size: 2000},
+		{Location: "lvg-1", NodeId: "node-1", StorageClass: apiV1.StorageClassSystemLVG, Size: 300},
+	}
+	volumes := []*api.Volume{
+		{Id: "pvc-1", NodeId: "node-1", StorageClass: apiV1.StorageClassSystemLVG, CSIStatus: apiV1.Published,
+			OperationalStatus: apiV1.OperationalStatusOperative},
+		{Id: "pvc-2", NodeId: "node-1", StorageClass: apiV1.StorageClassSystemLVG, CSIStatus: apiV1.Published,
+			OperationalStatus: apiV1.OperationalStatusOperative},
+	}
+	// AC of drive-1 is reserved, ACR which is rejected doesn't reserve AC of LVG
+	acrs := []*api.AvailableCapacityReservation{
+		{Status: apiV1.ReservationConfirmed, ReservationRequests: []*api.ReservationRequest{{Reservations: []string{"drive-1"}}}},
+		{Status: apiV1.ReservationRejected, ReservationRequests: []*api.ReservationRequest{{Reservations: []string{"lvg-1"}}}},
+	}
+	for i, spec := range acrs {
+		acr := kubeClient.ConstructACRCR(fmt.Sprintf("acr-%d", i), *spec)
+		assert.Nil(t, kubeClient.CreateCR(testCtx, acr.Name, acr))
+	}
+	assert.Nil(t, kubeClient.CreateCR(testCtx, drive.Name, drive))
+	assert.Nil(t, kubeClient.CreateCR(testCtx, lvg.Name, lvg))
+	for i, spec := range acs {
+		ac := kubeClient.ConstructACCR(spec.Location, *spec)
+		// AC which is being deleted isn't counted
+		if i == 1 {
+			now := metav1.Now()
+			ac.DeletionTimestamp = &now
+		}
+		assert.Nil(t, kubeClient.CreateCR(testCtx, ac.Name, ac))
+	}
+	for _, spec := range volumes {
+		volume := kubeClient.ConstructVolumeCR(spec.Id, testNs, *spec)
+		assert.Nil(t, kubeClient.CreateCR(testCtx, volume.Name, volume))
+	}
+
+	expected := `
+# HELP available_capacity_bytes Size of AvailableCapacity on the node in bytes, AvailableCapacity which is reserved or being deleted isn't counted
+# TYPE available_capacity_bytes gauge
+available_capacity_bytes{node="node-1",storage_class="NVME"} 0
+available_capacity_bytes{node="node-1",storage_class="SYSLVG"} 300
+# HELP drive_health Health of the drive, 1 for the current health and 0 for others
+# TYPE drive_health gauge
+drive_health{drive="drive-1",health="BAD",node="node-1",serial_number="sn-1",type="NVME"} 1
+drive_health{drive="drive-1",health="GOOD",node="node-1",serial_number="sn-1",type="NVME"} 0
+drive_health{drive="drive-1",health="SUSPECT",node="node-1",serial_number="sn-1",type="NVME"} 0
+drive_health{drive="drive-1",health="UNKNOWN",node="node-1",serial_number="sn-1",type="NVME"} 0
+# HELP lvg_free_bytes Free space of the LogicalVolumeGroup in bytes which is available for new volumes
+# TYPE lvg_free_bytes gauge
+lvg_free_bytes{lvg="lvg-1",node="node-1"} 300
+# HELP reserved_capacity_bytes Size of AvailableCapacity on the node in bytes which is reserved by AvailableCapacityReservations
+# TYPE reserved_capacity_bytes gauge
+reserved_capacity_bytes{node="node-1",storage_class="NVME"} 1000
+reserved_capacity_bytes{node="node-1",storage_class="SYSLVG"} 0
+# HELP volume_count Number of volumes on the node
+# TYPE volume_count gauge
+volume_count{csi_status="PUBLISHED",node="node-1",operational_status="OPERATIVE",storage_class="SYSLVG"} 2
+`
+	err = testutil.CollectAndCompare(NewCollector(kubeClient, testLogger), strings.NewReader(expected),
+		"available_capacity_bytes", "drive_health", "lvg_free_bytes", "reserved_capacity_bytes", "volume_count")
+	assert.Nil(t, err)
+}
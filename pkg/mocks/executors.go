@@ -17,6 +17,7 @@ limitations under the License.
 package mocks
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"time"
@@ -49,12 +50,25 @@ func (e EmptyExecutorSuccess) RunCmd(interface{}, ...command.Options) (string, s
 	return "", "", nil
 }
 
+// RunCmdContext simulates successful execution of a command
+// Returns "" as stdout, "" as stderr and nil as error
+func (e EmptyExecutorSuccess) RunCmdContext(context.Context, interface{}, ...command.Options) (string, string, error) {
+	return "", "", nil
+}
+
 // RunCmdWithAttempts simulates successful execution of a command with attempts and given timeout between attempts
 // Returns "" as stdout, "" as stderr and nil as error
 func (e EmptyExecutorSuccess) RunCmdWithAttempts(interface{}, int, time.Duration, ...command.Options) (string, string, error) {
 	return "", "", nil
 }
 
+// RunCmdWithAttemptsContext simulates successful execution of a command with attempts and given timeout between attempts
+// Returns "" as stdout, "" as stderr and nil as error
+func (e EmptyExecutorSuccess) RunCmdWithAttemptsContext(context.Context, interface{}, int, time.Duration,
+	...command.Options) (string, string, error) {
+	return "", "", nil
+}
+
 // EmptyExecutorFail implements CmdExecutor interface for test purposes, each command will finish with error
 type EmptyExecutorFail struct {
 	LevelSetter
@@ -66,12 +80,25 @@ func (e EmptyExecutorFail) RunCmd(interface{}, ...command.Options) (string, stri
 	return "error happened", "error", errors.New("error")
 }
 
+// RunCmdContext simulates failed execution of a command
+// Returns "error happened" as stdout, "error" as stderr and errors.New("error") as error
+func (e EmptyExecutorFail) RunCmdContext(context.Context, interface{}, ...command.Options) (string, string, error) {
+	return "error happened", "error", errors.New("error")
+}
+
 // RunCmdWithAttempts simulates failed execution of a command with attempts and given timeout between attempts
 // Returns "error happened" as stdout, "error" as stderr and errors.New("error") as error
 func (e EmptyExecutorFail) RunCmdWithAttempts(interface{}, int, time.Duration, ...command.Options) (string, string, error) {
 	return "error happened", "error", errors.New("error")
 }
 
+// RunCmdWithAttemptsContext simulates failed execution of a command with attempts and given timeout between attempts
+// Returns "error happened" as stdout, "error" as stderr and errors.New("error") as error
+func (e EmptyExecutorFail) RunCmdWithAttemptsContext(context.Context, interface{}, int, time.Duration,
+	...command.Options) (string, string, error) {
+	return "error happened", "error", errors.New("error")
+}
+
 // CmdOut is the struct for command output
 type CmdOut struct {
 	Stdout string
@@ -163,6 +190,21 @@ func (e *MockExecutor) RunCmdWithAttempts(cmd interface{}, attempts int, timeout
 	return e.RunCmd(cmd)
 }
 
+// RunCmdContext simulates execution of a command. Execute RunCmd.
+// Receives context and cmd as interface
+// Returns stdout, stderr, error for a given command
+func (e *MockExecutor) RunCmdContext(_ context.Context, cmd interface{}, opts ...command.Options) (string, string, error) {
+	return e.RunCmd(cmd)
+}
+
+// RunCmdWithAttemptsContext simulates execution of a command. Execute RunCmd.
+// Receives context, cmd as interface, number of attempts, timeout
+// Returns stdout, stderr, error for a given command
+func (e *MockExecutor) RunCmdWithAttemptsContext(_ context.Context, cmd interface{}, attempts int, timeout time.Duration,
+	opts ...command.Options) (string, string, error) {
+	return e.RunCmd(cmd)
+}
+
 // RunCmd is the name of CmdExecutor method name
 var (
 	RunCmd             = "RunCmd"
@@ -181,12 +223,27 @@ func (g *GoMockExecutor) RunCmdWithAttempts(cmd interface{}, attempts int, timeo
 	return args.String(0), args.String(1), args.Error(2)
 }
 
+// RunCmdWithAttemptsContext simulates execution of a command with OnCommandWithAttempts where user can set
+// what the method should return, context isn't a part of expectation
+func (g *GoMockExecutor) RunCmdWithAttemptsContext(_ context.Context, cmd interface{}, attempts int, timeout time.Duration,
+	opts ...command.Options) (string, string, error) {
+	args := g.Mock.MethodCalled(RunCmdWithAttempts, cmd.(string), attempts, timeout)
+	return args.String(0), args.String(1), args.Error(2)
+}
+
 // RunCmd simulates execution of a command with OnCommand where user can set what the method should return
 func (g *GoMockExecutor) RunCmd(cmd interface{}, opts ...command.Options) (string, string, error) {
 	args := g.Mock.Called(cmd.(string))
 	return args.String(0), args.String(1), args.Error(2)
 }
 
+// RunCmdContext simulates execution of a command with OnCommand where user can set what the method should return,
+// context isn't a part of expectation
+func (g *GoMockExecutor) RunCmdContext(_ context.Context, cmd interface{}, opts ...command.Options) (string, string, error) {
+	args := g.Mock.MethodCalled(RunCmd, cmd.(string))
+	return args.String(0), args.String(1), args.Error(2)
+}
+
 // OnCommand is the method of mock.Mock where user can set what to return on specified command
 // For example e.OnCommand("/sbin/lvm pvcreate --yes /dev/sda").Return("", "", errors.New("pvcreate failed"))
 // Returns mock.Call where need to set what to return with Return() method
@@ -1,6 +1,7 @@
 package linuxutils
 
 import (
+	"context"
 	"github.com/stretchr/testify/mock"
 
 	"github.com/dell/csi-baremetal/pkg/base/linuxutils/datadiscover/types"
@@ -12,7 +13,7 @@ type MockWrapDataDiscover struct {
 }
 
 // DiscoverData is a mock implementations
-func (m *MockWrapDataDiscover) DiscoverData(device, serialNumber string) (*types.DiscoverResult, error) {
+func (m *MockWrapDataDiscover) DiscoverData(_ context.Context, device, serialNumber string) (*types.DiscoverResult, error) {
 	args := m.Mock.Called(device, serialNumber)
 
 	return args.Get(0).(*types.DiscoverResult), args.Error(1)
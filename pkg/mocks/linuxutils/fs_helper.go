@@ -17,6 +17,7 @@ limitations under the License.
 package linuxutils
 
 import (
+	"context"
 	"github.com/stretchr/testify/mock"
 
 	"github.com/dell/csi-baremetal/pkg/base/linuxutils/fs"
@@ -28,84 +29,84 @@ type MockWrapFS struct {
 }
 
 // DeviceFs is a mock implementations
-func (m *MockWrapFS) DeviceFs(device string) (string, error) {
+func (m *MockWrapFS) DeviceFs(_ context.Context, device string) (string, error) {
 	args := m.Mock.Called(device)
 
 	return args.String(0), args.Error(1)
 }
 
 // GetFSSpace is a mock implementations
-func (m *MockWrapFS) GetFSSpace(src string) (int64, error) {
+func (m *MockWrapFS) GetFSSpace(_ context.Context, src string) (int64, error) {
 	args := m.Mock.Called(src)
 
 	return args.Get(0).(int64), args.Error(1)
 }
 
 // MkDir is a mock implementations
-func (m *MockWrapFS) MkDir(src string) error {
+func (m *MockWrapFS) MkDir(_ context.Context, src string) error {
 	args := m.Mock.Called(src)
 
 	return args.Error(0)
 }
 
 // MkFile is a mock implementations
-func (m *MockWrapFS) MkFile(src string) error {
+func (m *MockWrapFS) MkFile(_ context.Context, src string) error {
 	args := m.Mock.Called(src)
 
 	return args.Error(0)
 }
 
 // RmDir is a mock implementations
-func (m *MockWrapFS) RmDir(src string) error {
+func (m *MockWrapFS) RmDir(_ context.Context, src string) error {
 	args := m.Mock.Called(src)
 
 	return args.Error(0)
 }
 
 // CreateFS is a mock implementations
-func (m *MockWrapFS) CreateFS(fsType fs.FileSystem, device string) error {
+func (m *MockWrapFS) CreateFS(_ context.Context, fsType fs.FileSystem, device string) error {
 	args := m.Mock.Called(fsType, device)
 
 	return args.Error(0)
 }
 
 // WipeFS is a mock implementations
-func (m *MockWrapFS) WipeFS(device string) error {
+func (m *MockWrapFS) WipeFS(_ context.Context, device string) error {
 	args := m.Mock.Called(device)
 
 	return args.Error(0)
 }
 
 // GetFSType is a mock implementations
-func (m *MockWrapFS) GetFSType(device string) (fs.FileSystem, error) {
+func (m *MockWrapFS) GetFSType(_ context.Context, device string) (fs.FileSystem, error) {
 	args := m.Mock.Called(device)
 
 	return args.Get(0).(fs.FileSystem), args.Error(1)
 }
 
 // IsMounted is a mock implementations
-func (m *MockWrapFS) IsMounted(src string) (bool, error) {
+func (m *MockWrapFS) IsMounted(_ context.Context, src string) (bool, error) {
 	args := m.Mock.Called(src)
 
 	return args.Bool(0), args.Error(1)
 }
 
 // FindMountPoint is a mock implementations
-func (m *MockWrapFS) FindMountPoint(target string) (string, error) {
+func (m *MockWrapFS) FindMountPoint(_ context.Context, target string) (string, error) {
 	args := m.Mock.Called(target)
 
 	return args.String(0), args.Error(1)
 }
 
 // Mount is a mock implementations
-func (m *MockWrapFS) Mount(src, dst string, opts ...string) error {
+func (m *MockWrapFS) Mount(_ context.Context, src, dst string, opts ...string) error {
 	args := m.Mock.Called(src, dst, opts)
 
 	return args.Error(0)
 }
 
 // Unmount is a mock implementations
-func (m *MockWrapFS) Unmount(src string) error {
+func (m *MockWrapFS) Unmount(_ context.Context, src string) error {
 	args := m.Mock.Called(src)
 
 	return args.Error(0)
@@ -17,6 +17,7 @@ limitations under the License.
 package linuxutils
 
 import (
+	"context"
 	"github.com/stretchr/testify/mock"
 
 	"github.com/dell/csi-baremetal/api/v1/drivecrd"
@@ -39,7 +40,7 @@ func GetMockWrapLsblk(everytimePath string) *MockWrapLsblk {
 }
 
 // GetBlockDevices is a mock implementations
-func (m *MockWrapLsblk) GetBlockDevices(device string) ([]lsblk.BlockDevice, error) {
+func (m *MockWrapLsblk) GetBlockDevices(_ context.Context, device string) ([]lsblk.BlockDevice, error) {
 	args := m.Mock.Called(device)
 
 	if args.Get(0) == nil {
@@ -49,7 +50,7 @@ func (m *MockWrapLsblk) GetBlockDevices(device string) ([]lsblk.BlockDevice, err
 }
 
 // SearchDrivePath is a mock implementations
-func (m *MockWrapLsblk) SearchDrivePath(drive *drivecrd.Drive) (string, error) {
+func (m *MockWrapLsblk) SearchDrivePath(_ context.Context, drive *drivecrd.Drive) (string, error) {
 	args := m.Mock.Called(drive)
 
 	return args.String(0), args.Error(1)
@@ -17,6 +17,7 @@ limitations under the License.
 package linuxutils
 
 import (
+	"context"
 	"github.com/stretchr/testify/mock"
 
 	"github.com/dell/csi-baremetal/pkg/base/linuxutils/lsscsi"
@@ -28,7 +29,7 @@ type MockWrapLsscsi struct {
 }
 
 // GetSCSIDevices is a mock implementations
-func (m *MockWrapLsscsi) GetSCSIDevices() ([]*lsscsi.SCSIDevice, error) {
+func (m *MockWrapLsscsi) GetSCSIDevices(_ context.Context) ([]*lsscsi.SCSIDevice, error) {
 	args := m.Mock.Called()
 
 	return args.Get(0).([]*lsscsi.SCSIDevice), args.Error(1)
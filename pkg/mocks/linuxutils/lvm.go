@@ -17,6 +17,7 @@ limitations under the License.
 package linuxutils
 
 import (
+	"context"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -26,77 +27,77 @@ type MockWrapLVM struct {
 }
 
 // ExpandLV is a mock implementations
-func (m *MockWrapLVM) ExpandLV(lvName string, requiredSize int64) error {
+func (m *MockWrapLVM) ExpandLV(_ context.Context, lvName string, requiredSize int64) error {
 	args := m.Mock.Called(lvName, requiredSize)
 
 	return args.Error(0)
 }
 
 // PVCreate is a mock implementations
-func (m *MockWrapLVM) PVCreate(dev string) error {
+func (m *MockWrapLVM) PVCreate(_ context.Context, dev string) error {
 	args := m.Mock.Called(dev)
 
 	return args.Error(0)
 }
 
 // PVRemove is a mock implementations
-func (m *MockWrapLVM) PVRemove(name string) error {
+func (m *MockWrapLVM) PVRemove(_ context.Context, name string) error {
 	args := m.Mock.Called(name)
 
 	return args.Error(0)
 }
 
 // VGCreate is a mock implementations
-func (m *MockWrapLVM) VGCreate(name string, pvs ...string) error {
+func (m *MockWrapLVM) VGCreate(_ context.Context, name string, pvs ...string) error {
 	args := m.Mock.Called(name, pvs)
 
 	return args.Error(0)
 }
 
 // VGRemove is a mock implementations
-func (m *MockWrapLVM) VGRemove(name string) error {
+func (m *MockWrapLVM) VGRemove(_ context.Context, name string) error {
 	args := m.Mock.Called(name)
 
 	return args.Error(0)
 }
 
 // LVCreate is a mock implementations
-func (m *MockWrapLVM) LVCreate(name, size, vgName string) error {
+func (m *MockWrapLVM) LVCreate(_ context.Context, name, size, vgName string) error {
 	args := m.Mock.Called(name, size, vgName)
 
 	return args.Error(0)
 }
 
 // LVRemove is a mock implementations
-func (m *MockWrapLVM) LVRemove(fullLVName string) error {
+func (m *MockWrapLVM) LVRemove(_ context.Context, fullLVName string) error {
 	args := m.Mock.Called(fullLVName)
 
 	return args.Error(0)
 }
 
 // IsVGContainsLVs is a mock implementations
-func (m *MockWrapLVM) IsVGContainsLVs(vgName string) bool {
+func (m *MockWrapLVM) IsVGContainsLVs(_ context.Context, vgName string) bool {
 	args := m.Mock.Called(vgName)
 
 	return args.Bool(0)
 }
 
 // RemoveOrphanPVs is a mock implementations
-func (m *MockWrapLVM) RemoveOrphanPVs() error {
+func (m *MockWrapLVM) RemoveOrphanPVs(_ context.Context) error {
 	args := m.Mock.Called()
 
 	return args.Error(0)
 }
 
 // GetVgFreeSpace is a mock implementations
-func (m *MockWrapLVM) GetVgFreeSpace(vgName string) (int64, error) {
+func (m *MockWrapLVM) GetVgFreeSpace(_ context.Context, vgName string) (int64, error) {
 	args := m.Mock.Called(vgName)
 
 	return args.Get(0).(int64), args.Error(1)
 }
 
 // GetLVsInVG is a mock implementations
-func (m *MockWrapLVM) GetLVsInVG(vgName string) ([]string, error) {
+func (m *MockWrapLVM) GetLVsInVG(_ context.Context, vgName string) ([]string, error) {
 	args := m.Mock.Called(vgName)
 
 	if args.Get(0) == nil {
@@ -107,7 +108,7 @@ func (m *MockWrapLVM) GetLVsInVG(vgName string) ([]string, error) {
 }
 
 // GetAllPVs is a mock implementations
-func (m *MockWrapLVM) GetAllPVs() ([]string, error) {
+func (m *MockWrapLVM) GetAllPVs(_ context.Context) ([]string, error) {
 	args := m.Mock.Called()
 
 	if args.Get(0) == nil {
@@ -118,8 +119,50 @@ func (m *MockWrapLVM) GetAllPVs() ([]string, error) {
 }
 
 // GetVGNameByPVName is a mock implementations
-func (m *MockWrapLVM) GetVGNameByPVName(pvName string) (string, error) {
+func (m *MockWrapLVM) GetVGNameByPVName(_ context.Context, pvName string) (string, error) {
 	args := m.Mock.Called(pvName)
 
 	return args.String(0), args.Error(1)
 }
+
+// VGExtend is a mock implementations
+func (m *MockWrapLVM) VGExtend(_ context.Context, name string, pvs ...string) error {
+	args := m.Mock.Called(name, pvs)
+
+	return args.Error(0)
+}
+
+// VGReduce is a mock implementations
+func (m *MockWrapLVM) VGReduce(_ context.Context, name string, pvs ...string) error {
+	args := m.Mock.Called(name, pvs)
+
+	return args.Error(0)
+}
+
+// PVMove is a mock implementations
+func (m *MockWrapLVM) PVMove(_ context.Context, src string, dst ...string) error {
+	args := m.Mock.Called(src, dst)
+
+	return args.Error(0)
+}
+
+// PVMoveAbort is a mock implementations
+func (m *MockWrapLVM) PVMoveAbort(_ context.Context) error {
+	args := m.Mock.Called()
+
+	return args.Error(0)
+}
+
+// GetPVMoveProgress is a mock implementations
+func (m *MockWrapLVM) GetPVMoveProgress(_ context.Context, vgName string) (float64, bool, error) {
+	args := m.Mock.Called(vgName)
+
+	return args.Get(0).(float64), args.Bool(1), args.Error(2)
+}
+
+// GetPVUsage is a mock implementations
+func (m *MockWrapLVM) GetPVUsage(_ context.Context, pvName string) (int64, int64, error) {
+	args := m.Mock.Called(pvName)
+
+	return args.Get(0).(int64), args.Get(1).(int64), args.Error(2)
+}
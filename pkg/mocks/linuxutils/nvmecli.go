@@ -17,6 +17,7 @@ limitations under the License.
 package linuxutils
 
 import (
+	"context"
 	"github.com/stretchr/testify/mock"
 
 	"github.com/dell/csi-baremetal/pkg/base/linuxutils/nvmecli"
@@ -28,7 +29,7 @@ type MockWrapNvmecli struct {
 }
 
 // GetNVMDevices is a mock implementations
-func (m *MockWrapNvmecli) GetNVMDevices() ([]nvmecli.NVMDevice, error) {
+func (m *MockWrapNvmecli) GetNVMDevices(_ context.Context) ([]nvmecli.NVMDevice, error) {
 	args := m.Mock.Called()
 
 	return args.Get(0).([]nvmecli.NVMDevice), args.Error(1)
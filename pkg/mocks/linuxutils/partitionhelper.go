@@ -17,6 +17,7 @@ limitations under the License.
 package linuxutils
 
 import (
+	"context"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -26,77 +27,77 @@ type MockWrapPartition struct {
 }
 
 // DeviceHasPartitionTable is a mock implementations
-func (m *MockWrapPartition) DeviceHasPartitionTable(device string) (bool, error) {
+func (m *MockWrapPartition) DeviceHasPartitionTable(_ context.Context, device string) (bool, error) {
 	args := m.Mock.Called(device)
 
 	return args.Bool(0), args.Error(1)
 }
 
 // DeviceHasPartitions is a mock implementations
-func (m *MockWrapPartition) DeviceHasPartitions(device, serialNumber string) (bool, error) {
+func (m *MockWrapPartition) DeviceHasPartitions(_ context.Context, device, serialNumber string) (bool, error) {
 	args := m.Mock.Called(device, serialNumber)
 
 	return args.Bool(0), args.Error(1)
 }
 
 // IsPartitionExists is a mock implementations
-func (m *MockWrapPartition) IsPartitionExists(device, partNum string) (exists bool, err error) {
+func (m *MockWrapPartition) IsPartitionExists(_ context.Context, device, partNum string) (exists bool, err error) {
 	args := m.Mock.Called(device, partNum)
 
 	return args.Bool(0), args.Error(1)
 }
 
 // GetPartitionTableType is a mock implementations
-func (m *MockWrapPartition) GetPartitionTableType(device string) (ptType string, err error) {
+func (m *MockWrapPartition) GetPartitionTableType(_ context.Context, device string) (ptType string, err error) {
 	args := m.Mock.Called(device)
 
 	return args.String(0), args.Error(1)
 }
 
 // CreatePartitionTable is a mock implementations
-func (m *MockWrapPartition) CreatePartitionTable(device, partTableType string) (err error) {
+func (m *MockWrapPartition) CreatePartitionTable(_ context.Context, device, partTableType string) (err error) {
 	args := m.Mock.Called(device, partTableType)
 
 	return args.Error(0)
 }
 
 // CreatePartition is a mock implementations
-func (m *MockWrapPartition) CreatePartition(device, label string) (err error) {
+func (m *MockWrapPartition) CreatePartition(_ context.Context, device, label string) (err error) {
 	args := m.Mock.Called(device, label)
 
 	return args.Error(0)
 }
 
 // DeletePartition is a mock implementations
-func (m *MockWrapPartition) DeletePartition(device, partNum string) (err error) {
+func (m *MockWrapPartition) DeletePartition(_ context.Context, device, partNum string) (err error) {
 	args := m.Mock.Called(device, partNum)
 
 	return args.Error(0)
 }
 
 // SetPartitionUUID is a mock implementations
-func (m *MockWrapPartition) SetPartitionUUID(device, partNum, partUUID string) error {
+func (m *MockWrapPartition) SetPartitionUUID(_ context.Context, device, partNum, partUUID string) error {
 	args := m.Mock.Called(device, partNum, partUUID)
 
 	return args.Error(0)
 }
 
 // GetPartitionUUID is a mock implementations
-func (m *MockWrapPartition) GetPartitionUUID(device, partNum string) (string, error) {
+func (m *MockWrapPartition) GetPartitionUUID(_ context.Context, device, partNum string) (string, error) {
 	args := m.Mock.Called(device, partNum)
 
 	return args.String(0), args.Error(1)
 }
 
 // SyncPartitionTable is a mock implementations
-func (m *MockWrapPartition) SyncPartitionTable(device string) error {
+func (m *MockWrapPartition) SyncPartitionTable(_ context.Context, device string) error {
 	args := m.Mock.Called(device)
 
 	return args.Error(0)
 }
 
 // GetPartitionNameByUUID is a mock implementations
-func (m *MockWrapPartition) GetPartitionNameByUUID(device, partUUID string) (string, error) {
+func (m *MockWrapPartition) GetPartitionNameByUUID(_ context.Context, device, partUUID string) (string, error) {
 	args := m.Mock.Called(device, partUUID)
 
 	return args.String(0), args.Error(1)
@@ -17,6 +17,7 @@ limitations under the License.
 package linuxutils
 
 import (
+	"context"
 	"github.com/stretchr/testify/mock"
 
 	"github.com/dell/csi-baremetal/pkg/base/linuxutils/smartctl"
@@ -28,7 +29,7 @@ type MockWrapSmartctl struct {
 }
 
 // GetDriveInfoByPath is a mock implementations
-func (m *MockWrapSmartctl) GetDriveInfoByPath(path string) (*smartctl.DeviceSMARTInfo, error) {
+func (m *MockWrapSmartctl) GetDriveInfoByPath(_ context.Context, path string) (*smartctl.DeviceSMARTInfo, error) {
 	args := m.Mock.Called(path)
 
 	return args.Get(0).(*smartctl.DeviceSMARTInfo), args.Error(1)
@@ -16,7 +16,11 @@ limitations under the License.
 
 package provisioners
 
-import mocklu "github.com/dell/csi-baremetal/pkg/mocks/linuxutils"
+import (
+	"context"
+
+	mocklu "github.com/dell/csi-baremetal/pkg/mocks/linuxutils"
+)
 
 // MockFsOpts is a mock implementation of FSOperation interface from volumeprovisioner package
 type MockFsOpts struct {
@@ -24,14 +28,14 @@ type MockFsOpts struct {
 }
 
 // PrepareAndPerformMount is a mock implementation
-func (m *MockFsOpts) PrepareAndPerformMount(src, dst string, bindMount, dstIsDir bool) error {
+func (m *MockFsOpts) PrepareAndPerformMount(_ context.Context, src, dst string, bindMount, dstIsDir bool) error {
 	args := m.Mock.Called(src, dst, bindMount, dstIsDir)
 
 	return args.Error(0)
 }
 
 // UnmountWithCheck is a mock implementation
-func (m *MockFsOpts) UnmountWithCheck(path string) error {
+func (m *MockFsOpts) UnmountWithCheck(_ context.Context, path string) error {
 	args := m.Mock.Called(path)
 
 	return args.Error(0)
@@ -17,6 +17,7 @@ limitations under the License.
 package provisioners
 
 import (
+	"context"
 	"github.com/stretchr/testify/mock"
 
 	mocklu "github.com/dell/csi-baremetal/pkg/mocks/linuxutils"
@@ -30,21 +31,21 @@ type MockPartitionOps struct {
 }
 
 // PreparePartition is a mock implementation
-func (m *MockPartitionOps) PreparePartition(p utilwrappers.Partition) (*utilwrappers.Partition, error) {
+func (m *MockPartitionOps) PreparePartition(_ context.Context, p utilwrappers.Partition) (*utilwrappers.Partition, error) {
 	args := m.Mock.Called(p)
 
 	return args.Get(0).(*utilwrappers.Partition), args.Error(1)
 }
 
 // ReleasePartition is a mock implementation
-func (m *MockPartitionOps) ReleasePartition(p utilwrappers.Partition) error {
+func (m *MockPartitionOps) ReleasePartition(_ context.Context, p utilwrappers.Partition) error {
 	args := m.Mock.Called(p)
 
 	return args.Error(0)
 }
 
 // SearchPartName is a mock implementation
-func (m *MockPartitionOps) SearchPartName(device, partUUID string) string {
+func (m *MockPartitionOps) SearchPartName(_ context.Context, device, partUUID string) string {
 	args := m.Mock.Called(device, partUUID)
 
 	return args.String(0)
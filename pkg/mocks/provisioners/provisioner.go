@@ -17,6 +17,7 @@ limitations under the License.
 package provisioners
 
 import (
+	"context"
 	"github.com/stretchr/testify/mock"
 
 	api "github.com/dell/csi-baremetal/api/generated/v1"
@@ -39,21 +40,21 @@ func GetMockProvisionerSuccess(everytimePath string) *MockProvisioner {
 }
 
 // PrepareVolume is the mock implementation of PrepareVolume method from Provisioner interface
-func (m *MockProvisioner) PrepareVolume(volume api.Volume) error {
+func (m *MockProvisioner) PrepareVolume(_ context.Context, volume api.Volume) error {
 	args := m.Mock.Called(volume)
 
 	return args.Error(0)
 }
 
 // ReleaseVolume is the mock implementation of ReleaseVolume method from Provisioner interface
-func (m *MockProvisioner) ReleaseVolume(volume api.Volume) error {
+func (m *MockProvisioner) ReleaseVolume(_ context.Context, volume api.Volume) error {
 	args := m.Mock.Called(volume)
 
 	return args.Error(0)
 }
 
 // GetVolumePath is the mock implementation of GetVolumePath method from Provisioner interface
-func (m *MockProvisioner) GetVolumePath(volume api.Volume) (string, error) {
+func (m *MockProvisioner) GetVolumePath(_ context.Context, volume api.Volume) (string, error) {
 	args := m.Mock.Called(volume)
 
 	return args.String(0), args.Error(1)
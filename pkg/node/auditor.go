@@ -0,0 +1,526 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	"github.com/dell/csi-baremetal/api/v1/drivecrd"
+	"github.com/dell/csi-baremetal/api/v1/lvgcrd"
+	"github.com/dell/csi-baremetal/api/v1/volumecrd"
+	"github.com/dell/csi-baremetal/pkg/base/audit"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/lsblk"
+	"github.com/dell/csi-baremetal/pkg/base/util"
+	"github.com/dell/csi-baremetal/pkg/eventing"
+)
+
+const (
+	// AuditOrphanOnDisk is a finding for partition, LV or VG which doesn't have corresponding custom resource
+	AuditOrphanOnDisk = "orphan-on-disk"
+	// AuditMissingOnDisk is a finding for Volume or LogicalVolumeGroup CR which doesn't have corresponding device
+	AuditMissingOnDisk = "missing-on-disk"
+	// AuditSizeMismatch is a finding for partition or LV which is smaller than its Volume CR
+	AuditSizeMismatch = "size-mismatch"
+
+	// AuditPolicyDryRun means that findings are only reported
+	AuditPolicyDryRun = "dry-run"
+	// AuditPolicyDelete means that orphan LVs of LogicalVolumeGroups and empty orphan VGs are removed.
+	// Partitions and custom resources are never removed since they might hold user data or be used by PVs
+	AuditPolicyDelete = "delete"
+
+	// DefaultAuditInterval is the default period between storage audits
+	DefaultAuditInterval = 10 * time.Minute
+
+	auditResourcePartition = "partition"
+	auditResourceLV        = "lv"
+	auditResourceVG        = "vg"
+	auditResourceVolume    = "volume"
+	auditResourceLVG       = "lvg"
+
+	// partition of the drive volume is smaller than the drive because of partition table
+	partitionSizeTolerance = int64(16 * util.MBYTE)
+
+	lsblkPartitionType = "part"
+	lsblkLVType        = "lvm"
+)
+
+// auditFinding is a mismatch between on-disk state and custom resources
+type auditFinding struct {
+	Type     string
+	Resource string
+	Name     string
+	Message  string
+	// custom resource for event
+	object runtime.Object
+	// removes orphan from the disk, nil if finding can't be collected
+	collect func() error
+}
+
+func (f *auditFinding) key() string {
+	return strings.Join([]string{f.Type, f.Resource, f.Name}, "/")
+}
+
+// StorageAuditor periodically compares on-disk state (partitions, LVs and VGs) with Drive, LogicalVolumeGroup and
+// Volume CRs of the node, reports mismatches in events and metrics and removes orphans if policy allows
+type StorageAuditor struct {
+	vm     *VolumeManager
+	policy string
+	// keys of findings which were reported by previous audit, event is sent only for new findings
+	reported map[string]struct{}
+
+	metricFindings  *prometheus.GaugeVec
+	metricCollected *prometheus.CounterVec
+
+	log *logrus.Entry
+}
+
+// NewStorageAuditor is the constructor for StorageAuditor struct
+// Receives VolumeManager which provides access to CRs and system utilities, garbage collection policy and logrus logger
+// Returns an instance of StorageAuditor
+func NewStorageAuditor(vm *VolumeManager, policy string, logger *logrus.Logger) *StorageAuditor {
+	findings := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "storage_audit_findings",
+		Help: "mismatches between on-disk state and custom resources found by last storage audit",
+	}, []string{"type", "resource"})
+	collected := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "storage_audit_collected_total",
+		Help: "orphans removed from disks by storage audit",
+	}, []string{"resource"})
+	for _, c := range []prometheus.Collector{findings, collected} {
+		if err := prometheus.Register(c); err != nil {
+			logger.WithField("component", "NewStorageAuditor").
+				Errorf("Failed to register metric: %v", err)
+		}
+	}
+
+	if policy != AuditPolicyDelete {
+		policy = AuditPolicyDryRun
+	}
+	return &StorageAuditor{
+		vm:              vm,
+		policy:          policy,
+		reported:        map[string]struct{}{},
+		metricFindings:  findings,
+		metricCollected: collected,
+		log:             logger.WithField("component", "StorageAuditor"),
+	}
+}
+
+// Run performs audit each interval until stopCh is closed
+func (a *StorageAuditor) Run(interval time.Duration, stopCh <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+			if !a.vm.initialized {
+				continue
+			}
+			if _, err := a.Audit(context.Background()); err != nil {
+				a.log.Errorf("Storage audit failed: %v", err)
+			}
+		}
+	}
+}
+
+// Audit compares on-disk state with custom resources of the node, reports findings and collects orphans
+// if policy is AuditPolicyDelete
+// Returns list of findings or error if custom resources can't be read
+func (a *StorageAuditor) Audit(ctx context.Context) ([]auditFinding, error) {
+	ll := a.log.WithField("method", "Audit")
+
+	drives, err := a.vm.crHelper.GetDriveCRs(a.vm.nodeID)
+	if err != nil {
+		return nil, err
+	}
+	lvgs, err := a.vm.crHelper.GetLVGCRs(a.vm.nodeID)
+	if err != nil {
+		return nil, err
+	}
+	volumes, err := a.vm.crHelper.GetVolumeCRs(a.vm.nodeID)
+	if err != nil {
+		return nil, err
+	}
+
+	findings := make([]auditFinding, 0)
+	// LV device name -> size, filled during partitions audit
+	lvSizes := map[string]int64{}
+	lvgDrives := map[string]struct{}{}
+	for _, lvg := range lvgs {
+		for _, location := range lvg.Spec.Locations {
+			lvgDrives[location] = struct{}{}
+		}
+	}
+
+	for i := range drives {
+		drive := &drives[i]
+		if drive.Spec.Status != apiV1.DriveStatusOnline || drive.Spec.Path == "" {
+			continue
+		}
+		devs, err := a.vm.listBlk.GetBlockDevices(ctx, drive.Spec.Path)
+		if err != nil {
+			ll.Errorf("Unable to read block devices of drive %s: %v", drive.Name, err)
+			continue
+		}
+		collectLVSizes(devs, lvSizes)
+		if _, ok := lvgDrives[drive.Spec.UUID]; ok || drive.Spec.IsSystem {
+			continue
+		}
+		findings = append(findings, a.auditPartitions(drive, devs, volumes)...)
+	}
+
+	for i := range lvgs {
+		findings = append(findings, a.auditLVG(ctx, &lvgs[i], drives, volumes, lvSizes)...)
+	}
+	findings = append(findings, a.auditVGs(ctx, drives, lvgs)...)
+
+	a.report(findings)
+	return findings, nil
+}
+
+// auditPartitions compares partitions of the drive with drive volume
+func (a *StorageAuditor) auditPartitions(drive *drivecrd.Drive, devs []lsblk.BlockDevice,
+	volumes []volumecrd.Volume) []auditFinding {
+	findings := make([]auditFinding, 0)
+
+	var volume *volumecrd.Volume
+	for i := range volumes {
+		if volumes[i].Spec.Location == drive.Spec.UUID {
+			volume = &volumes[i]
+			break
+		}
+	}
+
+	found := false
+	for _, dev := range devs {
+		for _, part := range dev.Children {
+			if part.Type != lsblkPartitionType {
+				continue
+			}
+			if volume == nil {
+				findings = append(findings, auditFinding{
+					Type:     AuditOrphanOnDisk,
+					Resource: auditResourcePartition,
+					Name:     part.Name,
+					Message: fmt.Sprintf("partition %s (UUID %s) of drive %s doesn't belong to any volume",
+						part.Name, part.PartUUID, drive.Spec.SerialNumber),
+					object: drive,
+				})
+				continue
+			}
+			volUUID, _ := util.GetVolumeUUID(volume.Spec.Id)
+			if part.PartUUID != volUUID {
+				continue
+			}
+			found = true
+			if part.Size.Int64+partitionSizeTolerance < volume.Spec.Size {
+				findings = append(findings, auditFinding{
+					Type:     AuditSizeMismatch,
+					Resource: auditResourcePartition,
+					Name:     volume.Spec.Id,
+					Message: fmt.Sprintf("partition %s has size %d, volume %s requires %d",
+						part.Name, part.Size.Int64, volume.Spec.Id, volume.Spec.Size),
+					object: volume,
+				})
+			}
+		}
+	}
+
+	// partition isn't created for RAW volumes
+	if volume != nil && !found && isVolumeSettled(volume) && volume.Spec.Mode != apiV1.ModeRAW {
+		findings = append(findings, auditFinding{
+			Type:     AuditMissingOnDisk,
+			Resource: auditResourceVolume,
+			Name:     volume.Spec.Id,
+			Message: fmt.Sprintf("partition of volume %s is not found on drive %s",
+				volume.Spec.Id, drive.Spec.SerialNumber),
+			object: volume,
+		})
+	}
+	return findings
+}
+
+// auditLVG compares LVs of VG with volumes of LogicalVolumeGroup
+func (a *StorageAuditor) auditLVG(ctx context.Context, lvg *lvgcrd.LogicalVolumeGroup, drives []drivecrd.Drive, volumes []volumecrd.Volume,
+	lvSizes map[string]int64) []auditFinding {
+	if lvg.Spec.Status != apiV1.Created {
+		return nil
+	}
+	findings := make([]auditFinding, 0)
+
+	vgName := lvg.Spec.Name
+	lvs, err := a.vm.lvmOps.GetLVsInVG(ctx, vgName)
+	if err != nil {
+		return append(findings, auditFinding{
+			Type:     AuditMissingOnDisk,
+			Resource: auditResourceLVG,
+			Name:     lvg.Name,
+			Message:  fmt.Sprintf("VG %s of LogicalVolumeGroup %s is not found: %v", vgName, lvg.Name, err),
+			object:   lvg,
+		})
+	}
+
+	lvgVolumes := map[string]*volumecrd.Volume{}
+	for i := range volumes {
+		if volumes[i].Spec.Location == lvg.Name {
+			lvgVolumes[volumes[i].Spec.Id] = &volumes[i]
+		}
+	}
+
+	for _, volume := range lvgVolumes {
+		if !isVolumeSettled(volume) {
+			continue
+		}
+		if !util.ContainsString(lvs, volume.Spec.Id) {
+			findings = append(findings, auditFinding{
+				Type:     AuditMissingOnDisk,
+				Resource: auditResourceVolume,
+				Name:     volume.Spec.Id,
+				Message:  fmt.Sprintf("LV of volume %s is not found in VG %s", volume.Spec.Id, vgName),
+				object:   volume,
+			})
+			continue
+		}
+		if size, ok := lvSizes[lvDeviceName(vgName, volume.Spec.Id)]; ok && size < volume.Spec.Size {
+			findings = append(findings, auditFinding{
+				Type:     AuditSizeMismatch,
+				Resource: auditResourceLV,
+				Name:     volume.Spec.Id,
+				Message: fmt.Sprintf("LV %s/%s has size %d, volume requires %d",
+					vgName, volume.Spec.Id, size, volume.Spec.Size),
+				object: volume,
+			})
+		}
+	}
+
+	// LVs of the system VG which aren't volumes belong to OS
+	if isSystemLVG(lvg, drives) {
+		return findings
+	}
+	serials := make([]string, 0, len(lvg.Spec.Locations))
+	for i := range drives {
+		if util.ContainsString(lvg.Spec.Locations, drives[i].Spec.UUID) {
+			serials = append(serials, drives[i].Spec.SerialNumber)
+		}
+	}
+	targetCtx := audit.WithTarget(ctx, audit.DrivesTarget(serials...))
+	for _, lv := range lvs {
+		if _, ok := lvgVolumes[lv]; ok {
+			continue
+		}
+		lvPath := fmt.Sprintf("/dev/%s/%s", vgName, lv)
+		findings = append(findings, auditFinding{
+			Type:     AuditOrphanOnDisk,
+			Resource: auditResourceLV,
+			Name:     lvPath,
+			Message:  fmt.Sprintf("LV %s of LogicalVolumeGroup %s doesn't belong to any volume", lvPath, lvg.Name),
+			object:   lvg,
+			collect: func() error {
+				return a.vm.lvmOps.LVRemove(targetCtx, lvPath)
+			},
+		})
+	}
+	return findings
+}
+
+// auditVGs searches VGs on non-system drives of the node which don't have LogicalVolumeGroup CR
+func (a *StorageAuditor) auditVGs(ctx context.Context, drives []drivecrd.Drive, lvgs []lvgcrd.LogicalVolumeGroup) []auditFinding {
+	ll := a.log.WithField("method", "auditVGs")
+	findings := make([]auditFinding, 0)
+
+	pvs, err := a.vm.lvmOps.GetAllPVs(ctx)
+	if err != nil {
+		ll.Errorf("Unable to list PVs: %v", err)
+		return findings
+	}
+
+	reported := map[string]struct{}{}
+	for _, pv := range pvs {
+		drive := findDriveByDevice(drives, pv)
+		if drive == nil || drive.Spec.IsSystem {
+			continue
+		}
+		// PV without VG is removed by RemoveOrphanPVs
+		vgName, err := a.vm.lvmOps.GetVGNameByPVName(ctx, pv)
+		if err != nil || vgName == "" {
+			continue
+		}
+		// VG might consist of several PVs
+		if _, ok := reported[vgName]; ok {
+			continue
+		}
+		reported[vgName] = struct{}{}
+		exists := false
+		for _, lvg := range lvgs {
+			if lvg.Spec.Name == vgName {
+				exists = true
+				break
+			}
+		}
+		if exists {
+			continue
+		}
+		pvName := pv
+		targetCtx := audit.WithTarget(ctx, audit.Target{DriveSerial: drive.Spec.SerialNumber})
+		finding := auditFinding{
+			Type:     AuditOrphanOnDisk,
+			Resource: auditResourceVG,
+			Name:     vgName,
+			Message: fmt.Sprintf("VG %s on drive %s doesn't belong to any LogicalVolumeGroup",
+				vgName, drive.Spec.SerialNumber),
+			object: drive,
+		}
+		// VG with LVs might hold user data
+		if !a.vm.lvmOps.IsVGContainsLVs(ctx, vgName) {
+			finding.collect = func() error {
+				if err := a.vm.lvmOps.VGRemove(targetCtx, vgName); err != nil {
+					return err
+				}
+				return a.vm.lvmOps.PVRemove(targetCtx, pvName)
+			}
+		}
+		findings = append(findings, finding)
+	}
+	return findings
+}
+
+// report sends events for new findings, updates metrics and collects orphans if policy allows
+func (a *StorageAuditor) report(findings []auditFinding) {
+	ll := a.log.WithField("method", "report")
+
+	a.metricFindings.Reset()
+	current := make(map[string]struct{}, len(findings))
+	for _, f := range findings {
+		f := f
+		a.metricFindings.WithLabelValues(f.Type, f.Resource).Inc()
+		key := f.key()
+		current[key] = struct{}{}
+
+		if f.collect != nil && a.policy == AuditPolicyDelete {
+			if err := f.collect(); err != nil {
+				ll.Errorf("Unable to remove %s %s: %v", f.Resource, f.Name, err)
+			} else {
+				ll.Infof("Removed %s %s", f.Resource, f.Name)
+				a.metricCollected.WithLabelValues(f.Resource).Inc()
+				a.vm.recorder.Eventf(f.object, eventing.NormalType, eventing.StorageOrphanCollected,
+					"Storage audit removed %s", f.Message)
+				delete(current, key)
+				continue
+			}
+		}
+
+		if _, ok := a.reported[key]; ok {
+			continue
+		}
+		ll.Warnf("Storage audit finding %s: %s", f.Type, f.Message)
+		a.vm.recorder.Eventf(f.object, eventing.WarningType, auditEventReason(f.Type),
+			"Storage audit (%s): %s", a.policy, f.Message)
+	}
+	a.reported = current
+}
+
+// auditEventReason returns event reason for finding type
+func auditEventReason(findingType string) string {
+	switch findingType {
+	case AuditOrphanOnDisk:
+		return eventing.StorageOrphanOnDisk
+	case AuditMissingOnDisk:
+		return eventing.StorageMissingOnDisk
+	default:
+		return eventing.StorageSizeMismatch
+	}
+}
+
+// isVolumeSettled returns true if volume isn't being created or removed, so its device must exist
+func isVolumeSettled(volume *volumecrd.Volume) bool {
+	switch volume.Spec.CSIStatus {
+	case apiV1.Created, apiV1.VolumeReady, apiV1.Published:
+		return true
+	}
+	return false
+}
+
+// isSystemLVG returns true if LogicalVolumeGroup is based on system drive
+func isSystemLVG(lvg *lvgcrd.LogicalVolumeGroup, drives []drivecrd.Drive) bool {
+	for _, drive := range drives {
+		if drive.Spec.IsSystem && util.ContainsString(lvg.Spec.Locations, drive.Spec.UUID) {
+			return true
+		}
+	}
+	return false
+}
+
+// findDriveByDevice returns drive which is the device itself or contains it as partition
+func findDriveByDevice(drives []drivecrd.Drive, device string) *drivecrd.Drive {
+	for i := range drives {
+		path := drives[i].Spec.Path
+		if path == "" || !strings.HasPrefix(device, path) {
+			continue
+		}
+		if device == path || isPartitionSuffix(path, strings.TrimPrefix(device, path)) {
+			return &drives[i]
+		}
+	}
+	return nil
+}
+
+// isPartitionSuffix checks whether suffix is a partition number of the drive with provided path,
+// partition number follows "p" if drive name ends with digit (/dev/nvme0n1p1) and follows name otherwise (/dev/sda1)
+func isPartitionSuffix(path, suffix string) bool {
+	if isDigit(path[len(path)-1]) {
+		if !strings.HasPrefix(suffix, "p") {
+			return false
+		}
+		suffix = suffix[1:]
+	}
+	for i := range suffix {
+		if !isDigit(suffix[i]) {
+			return false
+		}
+	}
+	return suffix != ""
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// collectLVSizes fills map of LV device name to its size from lsblk output
+func collectLVSizes(devs []lsblk.BlockDevice, sizes map[string]int64) {
+	for _, dev := range devs {
+		if dev.Type == lsblkLVType {
+			sizes[dev.Name] = dev.Size.Int64
+		}
+		collectLVSizes(dev.Children, sizes)
+	}
+}
+
+// lvDeviceName returns device mapper path of LV as it is reported by lsblk
+func lvDeviceName(vgName, lvName string) string {
+	return fmt.Sprintf("/dev/mapper/%s-%s",
+		strings.ReplaceAll(vgName, "-", "--"), strings.ReplaceAll(lvName, "-", "--"))
+}
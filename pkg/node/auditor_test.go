@@ -0,0 +1,157 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	"github.com/dell/csi-baremetal/api/v1/drivecrd"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/lsblk"
+	mocklu "github.com/dell/csi-baremetal/pkg/mocks/linuxutils"
+)
+
+const (
+	auditVolumeID  = "pvc-8c0d9e1a-55f4-4f5b-b7a2-6d1c1e2f3a4b"
+	auditPartUUID  = "8c0d9e1a-55f4-4f5b-b7a2-6d1c1e2f3a4b"
+	auditLVGName   = "4a1b2c3d-0000-4000-8000-000000000001"
+	auditLVVolID   = "pvc-lv-volume"
+	auditOrphanLV  = "pvc-orphan-lv"
+	auditStaleVG   = "stale-vg"
+	auditDriveSize = int64(100 * 1024 * 1024 * 1024)
+)
+
+// prepareAuditor creates drives:
+// /dev/sdb with volume and too small partition, /dev/sdc with orphan partition,
+// /dev/sdd in LVG with missing and orphan LVs, /dev/sde with VG without LVG CR
+func prepareAuditor(t *testing.T, policy string) (*StorageAuditor, *mocklu.MockWrapLVM) {
+	vm := prepareSuccessVolumeManager(t)
+	drives := []api.Drive{
+		{UUID: "drive-b", SerialNumber: "sn-b", Path: "/dev/sdb", Size: auditDriveSize},
+		{UUID: "drive-c", SerialNumber: "sn-c", Path: "/dev/sdc", Size: auditDriveSize},
+		{UUID: "drive-d", SerialNumber: "sn-d", Path: "/dev/sdd", Size: auditDriveSize},
+		{UUID: "drive-e", SerialNumber: "sn-e", Path: "/dev/sde", Size: auditDriveSize},
+	}
+	for _, d := range drives {
+		d.NodeId = nodeID
+		d.Status = apiV1.DriveStatusOnline
+		d.Health = apiV1.HealthGood
+		addDriveCRs(vm.k8sClient, vm.k8sClient.ConstructDriveCR(d.UUID, d))
+	}
+
+	lvg := vm.k8sClient.ConstructLVGCR(auditLVGName, api.LogicalVolumeGroup{
+		Name:      auditLVGName,
+		Node:      nodeID,
+		Locations: []string{"drive-d"},
+		Status:    apiV1.Created,
+	})
+	assert.Nil(t, vm.k8sClient.CreateCR(testCtx, lvg.Name, lvg))
+
+	for _, v := range []api.Volume{
+		{Id: auditVolumeID, NodeId: nodeID, Location: "drive-b", Size: auditDriveSize, CSIStatus: apiV1.Published},
+		{Id: auditLVVolID, NodeId: nodeID, Location: auditLVGName, Size: auditDriveSize / 2, CSIStatus: apiV1.Created},
+	} {
+		volume := vm.k8sClient.ConstructVolumeCR(v.Id, testNs, v)
+		assert.Nil(t, vm.k8sClient.CreateCR(testCtx, volume.Name, volume))
+	}
+
+	listBlk := &mocklu.MockWrapLsblk{}
+	listBlk.On("GetBlockDevices", "/dev/sdb").Return([]lsblk.BlockDevice{{Name: "/dev/sdb", Children: []lsblk.BlockDevice{
+		{Name: "/dev/sdb1", Type: "part", PartUUID: auditPartUUID, Size: lsblk.CustomInt64{Int64: auditDriveSize / 2}},
+	}}}, nil)
+	listBlk.On("GetBlockDevices", "/dev/sdc").Return([]lsblk.BlockDevice{{Name: "/dev/sdc", Children: []lsblk.BlockDevice{
+		{Name: "/dev/sdc1", Type: "part", PartUUID: "some-uuid"},
+	}}}, nil)
+	listBlk.On("GetBlockDevices", "/dev/sdd").Return([]lsblk.BlockDevice{{Name: "/dev/sdd", Children: []lsblk.BlockDevice{
+		{Name: lvDeviceName(auditLVGName, auditOrphanLV), Type: "lvm"},
+	}}}, nil)
+	listBlk.On("GetBlockDevices", "/dev/sde").Return([]lsblk.BlockDevice{{Name: "/dev/sde"}}, nil)
+	vm.listBlk = listBlk
+
+	lvmOps := &mocklu.MockWrapLVM{}
+	lvmOps.On("GetLVsInVG", auditLVGName).Return([]string{auditOrphanLV}, nil)
+	lvmOps.On("GetAllPVs").Return([]string{"/dev/sdd", "/dev/sde", "/dev/sda2"}, nil)
+	lvmOps.On("GetVGNameByPVName", "/dev/sdd").Return(auditLVGName, nil)
+	lvmOps.On("GetVGNameByPVName", "/dev/sde").Return(auditStaleVG, nil)
+	lvmOps.On("IsVGContainsLVs", auditStaleVG).Return(false)
+	vm.lvmOps = lvmOps
+
+	return NewStorageAuditor(vm, policy, testLogger), lvmOps
+}
+
+func TestStorageAuditor_Audit(t *testing.T) {
+	auditor, lvmOps := prepareAuditor(t, "")
+	assert.Equal(t, AuditPolicyDryRun, auditor.policy)
+
+	findings, err := auditor.Audit(testCtx)
+	assert.Nil(t, err)
+
+	found := map[string]string{}
+	for _, f := range findings {
+		found[f.Resource+"/"+f.Name] = f.Type
+	}
+	assert.Equal(t, map[string]string{
+		auditResourcePartition + "/" + auditVolumeID:                         AuditSizeMismatch,
+		auditResourcePartition + "/" + "/dev/sdc1":                           AuditOrphanOnDisk,
+		auditResourceVolume + "/" + auditLVVolID:                             AuditMissingOnDisk,
+		auditResourceLV + "/" + "/dev/" + auditLVGName + "/" + auditOrphanLV: AuditOrphanOnDisk,
+		auditResourceVG + "/" + auditStaleVG:                                 AuditOrphanOnDisk,
+	}, found)
+	assert.Len(t, auditor.reported, len(findings))
+	// nothing is removed in dry-run mode
+	lvmOps.AssertNotCalled(t, "LVRemove", "/dev/"+auditLVGName+"/"+auditOrphanLV)
+	lvmOps.AssertNotCalled(t, "VGRemove", auditStaleVG)
+}
+
+func TestStorageAuditor_AuditDelete(t *testing.T) {
+	auditor, lvmOps := prepareAuditor(t, AuditPolicyDelete)
+	orphanLV := "/dev/" + auditLVGName + "/" + auditOrphanLV
+	lvmOps.On("LVRemove", orphanLV).Return(nil)
+	lvmOps.On("VGRemove", auditStaleVG).Return(nil)
+	lvmOps.On("PVRemove", "/dev/sde").Return(nil)
+
+	findings, err := auditor.Audit(testCtx)
+	assert.Nil(t, err)
+	assert.Len(t, findings, 5)
+	lvmOps.AssertCalled(t, "LVRemove", orphanLV)
+	lvmOps.AssertCalled(t, "VGRemove", auditStaleVG)
+	lvmOps.AssertCalled(t, "PVRemove", "/dev/sde")
+	// collected orphans aren't kept as reported
+	assert.Len(t, auditor.reported, 3)
+}
+
+func TestStorageAuditor_findDriveByDevice(t *testing.T) {
+	auditor, _ := prepareAuditor(t, "")
+	drives, err := auditor.vm.crHelper.GetDriveCRs(nodeID)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "drive-b", findDriveByDevice(drives, "/dev/sdb").Spec.UUID)
+	assert.Equal(t, "drive-b", findDriveByDevice(drives, "/dev/sdb1").Spec.UUID)
+	assert.Nil(t, findDriveByDevice(drives, "/dev/sdba"))
+	assert.Nil(t, findDriveByDevice(drives, "/dev/sda2"))
+	assert.Nil(t, findDriveByDevice(drives, "/dev/sdbp1"))
+
+	nvme := []drivecrd.Drive{{Spec: api.Drive{UUID: "nvme", Path: "/dev/nvme0n1"}}}
+	assert.Equal(t, "nvme", findDriveByDevice(nvme, "/dev/nvme0n1").Spec.UUID)
+	assert.Equal(t, "nvme", findDriveByDevice(nvme, "/dev/nvme0n1p12").Spec.UUID)
+	// another namespace of the controller isn't partition of the drive
+	assert.Nil(t, findDriveByDevice(nvme, "/dev/nvme0n10"))
+	assert.Nil(t, findDriveByDevice(nvme, "/dev/nvme0n1p"))
+}
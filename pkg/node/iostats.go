@@ -0,0 +1,206 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+)
+
+const (
+	// DefaultSysfsPath is a mount point of sysfs, block device statistics are read from <sysfs>/class/block/<dev>/stat
+	// which contains both whole devices (sdb, dm-0) and partitions (sdb1) unlike <sysfs>/block
+	DefaultSysfsPath = "/sys"
+	// sectorSize is a unit of sectors in block device statistics which doesn't depend on the device sector size
+	sectorSize = 512
+	// minStatFields is an amount of fields in block device statistics which are used by collector
+	minStatFields = 11
+)
+
+var (
+	volumeIOLabels = []string{"volume_id", "pvc_namespace", "pvc_name", "storage_class"}
+
+	volumeReadIOsDesc = prometheus.NewDesc("volume_read_ios_total",
+		"Number of read I/Os completed on the volume", volumeIOLabels, nil)
+	volumeWriteIOsDesc = prometheus.NewDesc("volume_write_ios_total",
+		"Number of write I/Os completed on the volume", volumeIOLabels, nil)
+	volumeReadBytesDesc = prometheus.NewDesc("volume_read_bytes_total",
+		"Number of bytes read from the volume", volumeIOLabels, nil)
+	volumeWriteBytesDesc = prometheus.NewDesc("volume_write_bytes_total",
+		"Number of bytes written to the volume", volumeIOLabels, nil)
+	volumeReadTimeDesc = prometheus.NewDesc("volume_read_time_seconds_total",
+		"Total time spent by read I/Os on the volume", volumeIOLabels, nil)
+	volumeWriteTimeDesc = prometheus.NewDesc("volume_write_time_seconds_total",
+		"Total time spent by write I/Os on the volume", volumeIOLabels, nil)
+	volumeIOTimeDesc = prometheus.NewDesc("volume_io_time_seconds_total",
+		"Total time during which the volume had I/Os in progress", volumeIOLabels, nil)
+	volumeInFlightDesc = prometheus.NewDesc("volume_io_in_flight",
+		"Number of I/Os currently in progress on the volume", volumeIOLabels, nil)
+)
+
+// blockStat holds fields of the block device statistics, see Documentation/block/stat.rst of the kernel
+type blockStat struct {
+	readIOs, readSectors, readTicks    uint64
+	writeIOs, writeSectors, writeTicks uint64
+	inFlight, ioTicks                  uint64
+}
+
+// VolumeIOCollector is a prometheus collector which exposes block I/O statistics of the volumes on the node.
+// Device of the volume is resolved through its provisioner and cached until the volume is removed
+type VolumeIOCollector struct {
+	vm        *VolumeManager
+	sysfsPath string
+	// volume ID to the name of the block device
+	devices   map[string]string
+	devicesMu sync.Mutex
+
+	log *logrus.Entry
+}
+
+// NewVolumeIOCollector is the constructor for VolumeIOCollector struct
+// Receives VolumeManager which provides Volume CRs and provisioners, mount point of sysfs and logrus logger
+// Returns an instance of VolumeIOCollector
+func NewVolumeIOCollector(vm *VolumeManager, sysfsPath string, logger *logrus.Logger) *VolumeIOCollector {
+	return &VolumeIOCollector{
+		vm:        vm,
+		sysfsPath: sysfsPath,
+		devices:   map[string]string{},
+		log:       logger.WithField("component", "VolumeIOCollector"),
+	}
+}
+
+// Describe sends descriptors of volume I/O metrics
+func (c *VolumeIOCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range []*prometheus.Desc{volumeReadIOsDesc, volumeWriteIOsDesc, volumeReadBytesDesc,
+		volumeWriteBytesDesc, volumeReadTimeDesc, volumeWriteTimeDesc, volumeIOTimeDesc, volumeInFlightDesc} {
+		ch <- desc
+	}
+}
+
+// Collect reads block device statistics of the volumes which have underlying storage and sends volume I/O metrics
+func (c *VolumeIOCollector) Collect(ch chan<- prometheus.Metric) {
+	volumes, err := c.vm.cachedCrHelper.GetVolumeCRs(c.vm.nodeID)
+	if err != nil {
+		c.log.Errorf("Unable to read Volume CRs: %v", err)
+		return
+	}
+
+	c.devicesMu.Lock()
+	defer c.devicesMu.Unlock()
+
+	existing := make(map[string]struct{}, len(volumes))
+	for _, volume := range volumes {
+		volume := volume
+		existing[volume.Spec.Id] = struct{}{}
+		switch volume.Spec.CSIStatus {
+		case apiV1.Created, apiV1.VolumeReady, apiV1.Published:
+		default:
+			continue
+		}
+		ll := c.log.WithField("volumeID", volume.Spec.Id)
+
+		device, ok := c.devices[volume.Spec.Id]
+		if !ok {
+			if device, err = c.resolveDevice(context.Background(), &volume.Spec); err != nil {
+				ll.Warnf("Unable to find device of the volume: %v", err)
+				continue
+			}
+			c.devices[volume.Spec.Id] = device
+		}
+		stat, err := c.readStat(device)
+		if err != nil {
+			ll.Warnf("Unable to read statistics of device %s: %v", device, err)
+			// device might be changed after restart of the node
+			delete(c.devices, volume.Spec.Id)
+			continue
+		}
+
+		labels := []string{volume.Spec.Id, volume.Namespace, volume.Annotations[apiV1.VolumeAnnotationPVCName],
+			volume.Spec.StorageClass}
+		for desc, value := range map[*prometheus.Desc]float64{
+			volumeReadIOsDesc:    float64(stat.readIOs),
+			volumeWriteIOsDesc:   float64(stat.writeIOs),
+			volumeReadBytesDesc:  float64(stat.readSectors * sectorSize),
+			volumeWriteBytesDesc: float64(stat.writeSectors * sectorSize),
+			volumeReadTimeDesc:   float64(stat.readTicks) / 1000,
+			volumeWriteTimeDesc:  float64(stat.writeTicks) / 1000,
+			volumeIOTimeDesc:     float64(stat.ioTicks) / 1000,
+		} {
+			ch <- prometheus.MustNewConstMetric(desc, prometheus.CounterValue, value, labels...)
+		}
+		ch <- prometheus.MustNewConstMetric(volumeInFlightDesc, prometheus.GaugeValue, float64(stat.inFlight), labels...)
+	}
+
+	for id := range c.devices {
+		if _, ok := existing[id]; !ok {
+			delete(c.devices, id)
+		}
+	}
+}
+
+// resolveDevice returns name of the block device of the volume, e.g. sdb1 for partition or dm-0 for logical volume
+func (c *VolumeIOCollector) resolveDevice(ctx context.Context, volume *api.Volume) (string, error) {
+	path, err := c.vm.getProvisionerForVolume(volume).GetVolumePath(ctx, *volume)
+	if err != nil {
+		return "", err
+	}
+	// path of the logical volume is a symlink to device mapper device
+	device, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Base(device), nil
+}
+
+// readStat reads and parses statistics of the block device
+func (c *VolumeIOCollector) readStat(device string) (*blockStat, error) {
+	data, err := ioutil.ReadFile(filepath.Join(c.sysfsPath, "class", "block", device, "stat"))
+	if err != nil {
+		return nil, err
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) < minStatFields {
+		return nil, fmt.Errorf("unexpected format of block device statistics: %q", string(data))
+	}
+	values := make([]uint64, minStatFields)
+	for i := range values {
+		if values[i], err = strconv.ParseUint(fields[i], 10, 64); err != nil {
+			return nil, fmt.Errorf("unable to parse field %d of block device statistics: %v", i+1, err)
+		}
+	}
+	return &blockStat{
+		readIOs:      values[0],
+		readSectors:  values[2],
+		readTicks:    values[3],
+		writeIOs:     values[4],
+		writeSectors: values[6],
+		writeTicks:   values[7],
+		inFlight:     values[8],
+		ioTicks:      values[9],
+	}, nil
+}
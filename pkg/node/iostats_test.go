@@ -0,0 +1,127 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	mockProv "github.com/dell/csi-baremetal/pkg/mocks/provisioners"
+	p "github.com/dell/csi-baremetal/pkg/node/provisioners"
+)
+
+// prepareFakeSysfs creates partition sdb1 and logical volume vg/lv linked to dm-0 with their statistics in sysfs
+func prepareFakeSysfs(t *testing.T, root string) (partPath, lvPath string) {
+	devDir := filepath.Join(root, "dev")
+	assert.Nil(t, os.MkdirAll(filepath.Join(devDir, "vg"), 0755))
+	partPath = filepath.Join(devDir, "sdb1")
+	lvPath = filepath.Join(devDir, "vg", "lv")
+	assert.Nil(t, ioutil.WriteFile(partPath, nil, 0644))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(devDir, "dm-0"), nil, 0644))
+	assert.Nil(t, os.Symlink(filepath.Join(devDir, "dm-0"), lvPath))
+
+	for device, stat := range map[string]string{
+		"sdb1": "     100        0     2048       40       50        0     4096       30        1       60       70",
+		"dm-0": "      10        0        8        5       20        0       16       15        0       10       20" +
+			"        0        0        0        0",
+	} {
+		dir := filepath.Join(root, "sys", "class", "block", device)
+		assert.Nil(t, os.MkdirAll(dir, 0755))
+		assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "stat"), []byte(stat+"\n"), 0644))
+	}
+	return partPath, lvPath
+}
+
+func TestVolumeIOCollector(t *testing.T) {
+	root, err := ioutil.TempDir("", "iostats")
+	assert.Nil(t, err)
+	defer func() { _ = os.RemoveAll(root) }()
+	partPath, lvPath := prepareFakeSysfs(t, root)
+
+	vm := prepareSuccessVolumeManager(t)
+	vm.SetProvisioners(map[p.VolumeType]p.Provisioner{
+		p.DriveBasedVolumeType: mockProv.GetMockProvisionerSuccess(partPath),
+		p.LVMBasedVolumeType:   mockProv.GetMockProvisionerSuccess(lvPath),
+	})
+	for _, v := range []api.Volume{
+		{Id: "pvc-drive", NodeId: nodeID, StorageClass: apiV1.StorageClassHDD, CSIStatus: apiV1.Published},
+		{Id: "pvc-lvm", NodeId: nodeID, StorageClass: apiV1.StorageClassHDDLVG, CSIStatus: apiV1.Created},
+		// volume without storage is skipped
+		{Id: "pvc-creating", NodeId: nodeID, StorageClass: apiV1.StorageClassHDD, CSIStatus: apiV1.Creating},
+	} {
+		volume := vm.k8sClient.ConstructVolumeCR(v.Id, testNs, v)
+		volume.Annotations = map[string]string{apiV1.VolumeAnnotationPVCName: "claim-" + v.Id}
+		assert.Nil(t, vm.k8sClient.CreateCR(testCtx, volume.Name, volume))
+	}
+
+	expected := `
+# HELP volume_io_in_flight Number of I/Os currently in progress on the volume
+# TYPE volume_io_in_flight gauge
+volume_io_in_flight{pvc_name="claim-pvc-drive",pvc_namespace="default",storage_class="HDD",volume_id="pvc-drive"} 1
+volume_io_in_flight{pvc_name="claim-pvc-lvm",pvc_namespace="default",storage_class="HDDLVG",volume_id="pvc-lvm"} 0
+# HELP volume_read_bytes_total Number of bytes read from the volume
+# TYPE volume_read_bytes_total counter
+volume_read_bytes_total{pvc_name="claim-pvc-drive",pvc_namespace="default",storage_class="HDD",volume_id="pvc-drive"} 1.048576e+06
+volume_read_bytes_total{pvc_name="claim-pvc-lvm",pvc_namespace="default",storage_class="HDDLVG",volume_id="pvc-lvm"} 4096
+# HELP volume_write_time_seconds_total Total time spent by write I/Os on the volume
+# TYPE volume_write_time_seconds_total counter
+volume_write_time_seconds_total{pvc_name="claim-pvc-drive",pvc_namespace="default",storage_class="HDD",volume_id="pvc-drive"} 0.03
+volume_write_time_seconds_total{pvc_name="claim-pvc-lvm",pvc_namespace="default",storage_class="HDDLVG",volume_id="pvc-lvm"} 0.015
+`
+	collector := NewVolumeIOCollector(vm, filepath.Join(root, "sys"), testLogger)
+	err = testutil.CollectAndCompare(collector, strings.NewReader(expected),
+		"volume_io_in_flight", "volume_read_bytes_total", "volume_write_time_seconds_total")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"pvc-drive": "sdb1", "pvc-lvm": "dm-0"}, collector.devices)
+
+	// device of the removed volume is forgotten
+	volume := vm.k8sClient.ConstructVolumeCR("pvc-lvm", testNs, api.Volume{})
+	assert.Nil(t, vm.k8sClient.DeleteCR(testCtx, volume))
+	collector.Collect(make(chan prometheus.Metric, 100))
+	assert.Equal(t, map[string]string{"pvc-drive": "sdb1"}, collector.devices)
+}
+
+func TestVolumeIOCollector_readStat(t *testing.T) {
+	root, err := ioutil.TempDir("", "iostats")
+	assert.Nil(t, err)
+	defer func() { _ = os.RemoveAll(root) }()
+	prepareFakeSysfs(t, root)
+	collector := NewVolumeIOCollector(nil, filepath.Join(root, "sys"), testLogger)
+
+	stat, err := collector.readStat("sdb1")
+	assert.Nil(t, err)
+	assert.Equal(t, &blockStat{readIOs: 100, readSectors: 2048, readTicks: 40, writeIOs: 50, writeSectors: 4096,
+		writeTicks: 30, inFlight: 1, ioTicks: 60}, stat)
+
+	_, err = collector.readStat("sdc")
+	assert.NotNil(t, err)
+
+	dir := filepath.Join(root, "sys", "class", "block", "sdc")
+	assert.Nil(t, os.MkdirAll(dir, 0755))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "stat"), []byte("1 2 3\n"), 0644))
+	_, err = collector.readStat("sdc")
+	assert.NotNil(t, err)
+}
@@ -25,6 +25,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/container-storage-interface/spec/lib/go/csi"
 	"github.com/golang/protobuf/ptypes/wrappers"
@@ -71,8 +72,16 @@ const (
 	UnknownPodName = "UNKNOWN"
 	// EphemeralKey in volume context means that in node publish request we need to create ephemeral volume
 	EphemeralKey = "csi.storage.k8s.io/ephemeral"
+	// MountOperationsTimeout is the timeout for mount and unmount of the volume
+	MountOperationsTimeout = 5 * time.Minute
 )
 
+// mountContext returns context for mount and unmount of the volume. They aren't interrupted when kubelet cancels
+// the request, otherwise mount point might be left in unknown state, and are bounded by MountOperationsTimeout
+func mountContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(command.Detach(ctx), MountOperationsTimeout)
+}
+
 // NewCSINodeService is the constructor for CSINodeService struct
 // Receives an instance of DriveServiceClient to interact with DriveManager, ID of a node where it works, logrus logger
 // and base.KubeClient
@@ -92,6 +101,8 @@ func NewCSINodeService(client api.DriveServiceClient,
 		volMu:          keymutex.NewHashed(0),
 		livenessCheck:  NewLivenessCheckHelper(logger, nil, nil),
 	}
+	s.lvgEvacuation = featureConf.IsEnabled(featureconfig.FeatureLVGEvacuation)
+	s.nodeReplacement = featureConf.IsEnabled(featureconfig.FeatureNodeReplacement)
 	s.log = logger.WithField("component", "CSINodeService")
 	return s
 }
@@ -180,7 +191,7 @@ func (s *CSINodeService) NodeStageVolume(ctx context.Context, req *csi.NodeStage
 
 	targetPath := getStagingPath(ll, req.GetStagingTargetPath())
 
-	partition, err := s.getProvisionerForVolume(&volumeCR.Spec).GetVolumePath(volumeCR.Spec)
+	partition, err := s.getProvisionerForVolume(&volumeCR.Spec).GetVolumePath(ctx, volumeCR.Spec)
 	if err != nil {
 		ll.Errorf("failed to get partition, for volume %v: %v", volumeCR.Spec, err)
 		return nil, status.Error(codes.Internal, "failed to stage volume: partition error")
@@ -192,7 +203,9 @@ func (s *CSINodeService) NodeStageVolume(ctx context.Context, req *csi.NodeStage
 		errToReturn error
 		newStatus   = apiV1.VolumeReady
 	)
-	if err := s.fsOps.PrepareAndPerformMount(partition, targetPath, true, false); err != nil {
+	mountCtx, cancelFn := mountContext(ctx)
+	defer cancelFn()
+	if err := s.fsOps.PrepareAndPerformMount(mountCtx, partition, targetPath, true, false); err != nil {
 		ll.Errorf("Unable to prepare and mount: %v. Going to set volumes status to failed", err)
 		newStatus = apiV1.Failed
 		resp, errToReturn = nil, status.Error(codes.Internal, "failed to stage volume: mount error")
@@ -260,7 +273,9 @@ func (s *CSINodeService) NodeUnstageVolume(ctx context.Context, req *csi.NodeUns
 		resp        = &csi.NodeUnstageVolumeResponse{}
 		errToReturn error
 	)
-	if errToReturn = s.fsOps.UnmountWithCheck(getStagingPath(ll, req.GetStagingTargetPath())); errToReturn != nil {
+	mountCtx, cancelFn := mountContext(ctx)
+	defer cancelFn()
+	if errToReturn = s.fsOps.UnmountWithCheck(mountCtx, getStagingPath(ll, req.GetStagingTargetPath())); errToReturn != nil {
 		volumeCR.Spec.CSIStatus = apiV1.Failed
 		resp = nil
 	}
@@ -336,7 +351,7 @@ func (s *CSINodeService) NodePublishVolume(ctx context.Context, req *csi.NodePub
 			ll.Errorf("Failed to create inline volume: %v", err)
 			return nil, status.Error(codes.Internal, "unable to create inline volume")
 		}
-		srcPath, err = s.getProvisionerForVolume(vol).GetVolumePath(*vol)
+		srcPath, err = s.getProvisionerForVolume(vol).GetVolumePath(ctx, *vol)
 		if err != nil {
 			ll.Errorf("failed to get partition for volume %v: %v", vol, err)
 			return nil, status.Error(codes.Internal, "failed to publish inline volume: partition error")
@@ -366,7 +381,9 @@ func (s *CSINodeService) NodePublishVolume(ctx context.Context, req *csi.NodePub
 	)
 
 	_, isBlock := req.GetVolumeCapability().GetAccessType().(*csi.VolumeCapability_Block)
-	if err := s.fsOps.PrepareAndPerformMount(srcPath, dstPath, isBlock, !isBlock); err != nil {
+	mountCtx, cancelFn := mountContext(ctx)
+	defer cancelFn()
+	if err := s.fsOps.PrepareAndPerformMount(mountCtx, srcPath, dstPath, isBlock, !isBlock); err != nil {
 		ll.Errorf("Unable to mount volume: %v", err)
 		newStatus = apiV1.Failed
 		resp, errToReturn = nil, fmt.Errorf("failed to publish volume: mount error")
@@ -504,7 +521,9 @@ func (s *CSINodeService) NodeUnpublishVolume(ctx context.Context, req *csi.NodeU
 	}
 
 	ctxWithID := context.WithValue(context.Background(), base.RequestUUID, req.GetVolumeId())
-	if err := s.fsOps.UnmountWithCheck(req.GetTargetPath()); err != nil {
+	mountCtx, cancelFn := mountContext(ctx)
+	defer cancelFn()
+	if err := s.fsOps.UnmountWithCheck(mountCtx, req.GetTargetPath()); err != nil {
 		ll.Errorf("Unable to unmount volume: %v", err)
 		volumeCR.Spec.CSIStatus = apiV1.Failed
 		if updateErr := s.k8sClient.UpdateCR(ctxWithID, volumeCR); updateErr != nil {
@@ -27,6 +27,7 @@ import (
 	apiV1 "github.com/dell/csi-baremetal/api/v1"
 	"github.com/dell/csi-baremetal/api/v1/drivecrd"
 	"github.com/dell/csi-baremetal/pkg/base"
+	"github.com/dell/csi-baremetal/pkg/base/audit"
 	"github.com/dell/csi-baremetal/pkg/base/command"
 	"github.com/dell/csi-baremetal/pkg/base/k8s"
 	"github.com/dell/csi-baremetal/pkg/base/linuxutils/fs"
@@ -75,7 +76,7 @@ func NewDriveProvisioner(
 
 // PrepareVolume create partition and FS based on vol attributes.
 // After that partition is ready for mount operations
-func (d *DriveProvisioner) PrepareVolume(vol api.Volume) error {
+func (d *DriveProvisioner) PrepareVolume(ctx context.Context, vol api.Volume) error {
 	ll := d.log.WithFields(logrus.Fields{
 		"method":   "PrepareVolume",
 		"volumeID": vol.Id,
@@ -92,9 +93,10 @@ func (d *DriveProvisioner) PrepareVolume(vol api.Volume) error {
 	if err = d.k8sClient.ReadCR(ctxWithID, vol.Location, "", drive); err != nil {
 		return fmt.Errorf("failed to read drive CR with name %s, error %v", vol.Location, err)
 	}
+	ctx = audit.WithTarget(ctx, audit.Target{DriveSerial: drive.Spec.SerialNumber, VolumeID: vol.Id})
 
 	ll.Infof("Search device file for drive with S/N %s", drive.Spec.SerialNumber)
-	device, err := d.listBlk.SearchDrivePath(drive)
+	device, err := d.listBlk.SearchDrivePath(ctx, drive)
 	if err != nil {
 		return err
 	}
@@ -114,7 +116,7 @@ func (d *DriveProvisioner) PrepareVolume(vol api.Volume) error {
 	}
 
 	ll.Infof("Create partition %v on device %s and set UUID", part, device)
-	partPtr, err := d.partOps.PreparePartition(part)
+	partPtr, err := d.partOps.PreparePartition(ctx, part)
 	if err != nil {
 		ll.Errorf("Unable to prepare partition: %v", err)
 		return fmt.Errorf("unable to prepare partition for volume %v", vol)
@@ -122,12 +124,12 @@ func (d *DriveProvisioner) PrepareVolume(vol api.Volume) error {
 	ll.Infof("Partition was created successfully %v", partPtr)
 
 	// create FS
-	return d.fsOps.CreateFS(fs.FileSystem(vol.Type), partPtr.GetFullPath())
+	return d.fsOps.CreateFS(ctx, fs.FileSystem(vol.Type), partPtr.GetFullPath())
 }
 
 // ReleaseVolume remove FS and partition based on vol attributes.
 // After that partition is completely removed
-func (d *DriveProvisioner) ReleaseVolume(vol api.Volume) error {
+func (d *DriveProvisioner) ReleaseVolume(ctx context.Context, vol api.Volume) error {
 	ll := d.log.WithFields(logrus.Fields{
 		"method":   "ReleaseVolume",
 		"volumeID": vol.Id,
@@ -139,10 +141,11 @@ func (d *DriveProvisioner) ReleaseVolume(vol api.Volume) error {
 	if drive == nil {
 		return errors.New("unable to find drive by vol location")
 	}
+	ctx = audit.WithTarget(ctx, audit.Target{DriveSerial: drive.Spec.SerialNumber, VolumeID: vol.Id})
 	ll.Debugf("Got drive %v", drive)
 
 	// get deviceFile path
-	device, err := d.listBlk.SearchDrivePath(drive)
+	device, err := d.listBlk.SearchDrivePath(ctx, drive)
 	if err != nil {
 		return fmt.Errorf("unable to find device for drive with S/N %s", vol.Location)
 	}
@@ -159,48 +162,48 @@ func (d *DriveProvisioner) ReleaseVolume(vol api.Volume) error {
 
 	// TODO: temporary solution because of ephemeral volumes volume id - https://github.com/dell/csi-baremetal/issues/87
 	if vol.Ephemeral {
-		part.PartUUID, err = d.partOps.GetPartitionUUID(device, DefaultPartitionNumber)
+		part.PartUUID, err = d.partOps.GetPartitionUUID(ctx, device, DefaultPartitionNumber)
 		if err != nil {
-			return d.wipeDevice(device,
+			return d.wipeDevice(ctx, device,
 				fmt.Errorf("unable to determine partition UUID for ephemeral volume: %v", err), ll)
 		}
 	}
 
-	part.Name = d.partOps.SearchPartName(device, part.PartUUID)
+	part.Name = d.partOps.SearchPartName(ctx, device, part.PartUUID)
 	if part.Name == "" {
-		return d.wipeDevice(device,
+		return d.wipeDevice(ctx, device,
 			fmt.Errorf("unable to find partition name for volume %s", vol.Id), ll)
 	}
 
 	// wipe FS on partition
-	if err = d.fsOps.WipeFS(part.GetFullPath()); err != nil {
+	if err = d.fsOps.WipeFS(ctx, part.GetFullPath()); err != nil {
 		return err
 	}
 
-	err = d.partOps.ReleasePartition(part)
+	err = d.partOps.ReleasePartition(ctx, part)
 	if err != nil {
 		return fmt.Errorf("unable to release partition: %v", err)
 	}
 
 	// wipe all superblocks (wipe partition table signature)
-	return d.fsOps.WipeFS(device)
+	return d.fsOps.WipeFS(ctx, device)
 }
 
 // wipeDevice check is there any partition on device or not,
 // if there are no partition - wipe device and return nil, if any - returns error that had been provided
 // device - device to check, err - error to return, ll - logger for logging
-func (d *DriveProvisioner) wipeDevice(device string, err error, ll *logrus.Entry) error {
+func (d *DriveProvisioner) wipeDevice(ctx context.Context, device string, err error, ll *logrus.Entry) error {
 	// DriveProvisioner assumes that there could be only one partition per drive
-	bdevs, sErr := d.listBlk.GetBlockDevices(device)
+	bdevs, sErr := d.listBlk.GetBlockDevices(ctx, device)
 	if sErr == nil && (len(bdevs) == 0 || bdevs[0].Children == nil) {
 		ll.Infof("No partitions found for device %s", device)
-		return d.fsOps.WipeFS(device) // wipe partition table
+		return d.fsOps.WipeFS(ctx, device) // wipe partition table
 	}
 	return err
 }
 
 // GetVolumePath constructs full partition path - /dev/DEVICE_NAME+PARTITION_NAME
-func (d *DriveProvisioner) GetVolumePath(vol api.Volume) (string, error) {
+func (d *DriveProvisioner) GetVolumePath(ctx context.Context, vol api.Volume) (string, error) {
 	ll := d.log.WithFields(logrus.Fields{
 		"method":   "GetVolumePath",
 		"volumeID": vol.Id,
@@ -214,7 +217,7 @@ func (d *DriveProvisioner) GetVolumePath(vol api.Volume) (string, error) {
 	ll.Debugf("Got drive %v", drive)
 
 	// get deviceFile path
-	device, err := d.listBlk.SearchDrivePath(drive)
+	device, err := d.listBlk.SearchDrivePath(ctx, drive)
 	if err != nil {
 		return "", fmt.Errorf("unable to find device for drive with S/N %s: %v", vol.Location, err)
 	}
@@ -223,7 +226,7 @@ func (d *DriveProvisioner) GetVolumePath(vol api.Volume) (string, error) {
 	var volumeUUID = vol.Id
 	// TODO: temporary solution because of ephemeral volumes volume id - https://github.com/dell/csi-baremetal/issues/87
 	if vol.Ephemeral {
-		volumeUUID, err = d.partOps.GetPartitionUUID(device, DefaultPartitionNumber)
+		volumeUUID, err = d.partOps.GetPartitionUUID(ctx, device, DefaultPartitionNumber)
 		if err != nil {
 			return "", fmt.Errorf("unable to determine partition UUID: %v", err)
 		}
@@ -233,7 +236,7 @@ func (d *DriveProvisioner) GetVolumePath(vol api.Volume) (string, error) {
 	}
 	volumeUUID, _ = util.GetVolumeUUID(volumeUUID)
 
-	partNum := d.partOps.SearchPartName(device, volumeUUID)
+	partNum := d.partOps.SearchPartName(ctx, device, volumeUUID)
 	if partNum == "" {
 		return "", fmt.Errorf("unable to find part name for device %s by uuid %s", device, volumeUUID)
 	}
@@ -92,7 +92,7 @@ func TestDriveProvisioner_PrepareVolume_Success(t *testing.T) {
 	mockFS.On("CreateFS", fs.FileSystem(testVolume2.Type), expectedPart.GetFullPath()).
 		Return(nil)
 
-	err = dp.PrepareVolume(testVolume2)
+	err = dp.PrepareVolume(testCtx, testVolume2)
 	assert.Nil(t, err)
 }
 
@@ -103,7 +103,7 @@ func TestDriveProvisioner_PrepareVolume_Fail(t *testing.T) {
 	)
 
 	// drive CR isn't exist
-	err = dp.PrepareVolume(testVolume2)
+	err = dp.PrepareVolume(testCtx, testVolume2)
 	assert.NotNil(t, err)
 	assert.Contains(t, err.Error(), "failed to read drive CR with name")
 
@@ -115,7 +115,7 @@ func TestDriveProvisioner_PrepareVolume_Fail(t *testing.T) {
 	mockLsblk.On("SearchDrivePath", mock.Anything).
 		Return("", errTest).Once()
 
-	err = dp.PrepareVolume(testVolume2)
+	err = dp.PrepareVolume(testCtx, testVolume2)
 	assert.Error(t, err)
 	assert.Equal(t, errTest, err)
 
@@ -127,7 +127,7 @@ func TestDriveProvisioner_PrepareVolume_Fail(t *testing.T) {
 	mockPH.On("PreparePartition", mock.Anything).
 		Return(&uw.Partition{}, errTest).Once()
 
-	err = dp.PrepareVolume(testVolume2)
+	err = dp.PrepareVolume(testCtx, testVolume2)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "unable to prepare partition for volume")
 
@@ -136,7 +136,7 @@ func TestDriveProvisioner_PrepareVolume_Fail(t *testing.T) {
 		Return(&uw.Partition{}, nil).Once()
 	mockFS.On("CreateFS", fs.FileSystem(testVolume2.Type), mock.Anything).Return(errTest)
 
-	err = dp.PrepareVolume(testVolume2)
+	err = dp.PrepareVolume(testCtx, testVolume2)
 	assert.Error(t, err)
 	assert.Equal(t, errTest, err)
 }
@@ -172,7 +172,7 @@ func TestDriveProvisioner_ReleaseVolume_Success(t *testing.T) {
 	mockPH.On("ReleasePartition", part).Return(nil)
 	mockFS.On("WipeFS", deviceFile).Return(nil).Once()
 
-	err = dp.ReleaseVolume(testVolume2)
+	err = dp.ReleaseVolume(testCtx, testVolume2)
 	assert.Nil(t, err)
 
 	// SearchPartName failed but partition isn't exist (was removed before)
@@ -183,7 +183,7 @@ func TestDriveProvisioner_ReleaseVolume_Success(t *testing.T) {
 	mockLsblk.On("GetBlockDevices", deviceFile).Return(nil, nil).Once()
 	mockFS.On("WipeFS", deviceFile).Return(nil).Once()
 
-	err = dp.ReleaseVolume(testVolume2)
+	err = dp.ReleaseVolume(testCtx, testVolume2)
 	assert.Nil(t, err)
 }
 
@@ -194,7 +194,7 @@ func TestDriveProvisioner_ReleaseVolume_Fail(t *testing.T) {
 	)
 
 	// failed to find DriveCR
-	err = dp.ReleaseVolume(api.Volume{})
+	err = dp.ReleaseVolume(testCtx, api.Volume{})
 	assert.Error(t, err)
 	assert.EqualError(t, err, "unable to find drive by vol location")
 
@@ -206,7 +206,7 @@ func TestDriveProvisioner_ReleaseVolume_Fail(t *testing.T) {
 		mock.MatchedBy(func(d *drivecrd.Drive) bool { return d.Name == testDriveCR.Name })).
 		Return("", errTest).Once()
 
-	err = dp.ReleaseVolume(testVolume2)
+	err = dp.ReleaseVolume(testCtx, testVolume2)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "unable to find device for drive with S/N")
 
@@ -221,7 +221,7 @@ func TestDriveProvisioner_ReleaseVolume_Fail(t *testing.T) {
 	mockLsblk.On("GetBlockDevices", deviceFile).
 		Return(nil, errTest)
 
-	err = dp.ReleaseVolume(testVolume2)
+	err = dp.ReleaseVolume(testCtx, testVolume2)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "unable to find partition name")
 
@@ -233,7 +233,7 @@ func TestDriveProvisioner_ReleaseVolume_Fail(t *testing.T) {
 	// WipeFS failed
 	mockFS.On("WipeFS", deviceFile+partName).Return(errTest).Once()
 
-	err = dp.ReleaseVolume(testVolume2)
+	err = dp.ReleaseVolume(testCtx, testVolume2)
 	assert.Error(t, err)
 	assert.Equal(t, errTest, err)
 
@@ -241,7 +241,7 @@ func TestDriveProvisioner_ReleaseVolume_Fail(t *testing.T) {
 	mockFS.On("WipeFS", mock.Anything).Return(nil).Once()
 	mockPH.On("ReleasePartition", mock.Anything).Return(errTest).Once()
 
-	err = dp.ReleaseVolume(testVolume2)
+	err = dp.ReleaseVolume(testCtx, testVolume2)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "unable to release partition")
 
@@ -250,7 +250,7 @@ func TestDriveProvisioner_ReleaseVolume_Fail(t *testing.T) {
 	mockPH.On("ReleasePartition", mock.Anything).Return(nil)
 	mockFS.On("WipeFS", deviceFile).Return(errTest)
 
-	err = dp.ReleaseVolume(testVolume2)
+	err = dp.ReleaseVolume(testCtx, testVolume2)
 	assert.Error(t, err)
 	assert.Equal(t, errTest, err)
 }
@@ -276,7 +276,7 @@ func TestDriveProvisioner_GetVolumePath_Success(t *testing.T) {
 	mockPH.On("SearchPartName", deviceFile, testVolume2.Id).
 		Return(partName, nil).Once()
 
-	fullPath, err = dp.GetVolumePath(testVolume2)
+	fullPath, err = dp.GetVolumePath(testCtx, testVolume2)
 	assert.Nil(t, err)
 	assert.Equal(t, deviceFile+partName, fullPath)
 }
@@ -289,7 +289,7 @@ func TestDriveProvisioner_GetVolumePath_Fail(t *testing.T) {
 	)
 
 	// failed to find DriveCR
-	fullPath, err = dp.GetVolumePath(api.Volume{})
+	fullPath, err = dp.GetVolumePath(testCtx, api.Volume{})
 	assert.Error(t, err)
 	assert.Equal(t, "", fullPath)
 	assert.Contains(t, err.Error(), "unable to find drive by location")
@@ -302,7 +302,7 @@ func TestDriveProvisioner_GetVolumePath_Fail(t *testing.T) {
 		mock.MatchedBy(func(d *drivecrd.Drive) bool { return d.Name == testDriveCR.Name })).
 		Return("", errTest).Once()
 
-	fullPath, err = dp.GetVolumePath(testVolume2)
+	fullPath, err = dp.GetVolumePath(testCtx, testVolume2)
 	assert.Error(t, err)
 	assert.Equal(t, "", fullPath)
 	assert.Contains(t, err.Error(), "unable to find device for drive with S/N")
@@ -314,7 +314,7 @@ func TestDriveProvisioner_GetVolumePath_Fail(t *testing.T) {
 	mockPH.On("SearchPartName", deviceFile, testVolume2.Id).
 		Return("").Once()
 
-	fullPath, err = dp.GetVolumePath(testVolume2)
+	fullPath, err = dp.GetVolumePath(testCtx, testVolume2)
 	assert.Error(t, err)
 	assert.Equal(t, "", fullPath)
 	assert.Contains(t, err.Error(), "unable to find part name for device")
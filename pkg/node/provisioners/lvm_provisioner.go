@@ -17,6 +17,7 @@ limitations under the License.
 package provisioners
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 
@@ -24,6 +25,7 @@ import (
 
 	api "github.com/dell/csi-baremetal/api/generated/v1"
 	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	"github.com/dell/csi-baremetal/pkg/base/audit"
 	"github.com/dell/csi-baremetal/pkg/base/command"
 	"github.com/dell/csi-baremetal/pkg/base/k8s"
 	"github.com/dell/csi-baremetal/pkg/base/linuxutils/fs"
@@ -52,12 +54,13 @@ func NewLVMProvisioner(e command.CmdExecutor, k *k8s.KubeClient, log *logrus.Log
 
 // PrepareVolume search volume group based on vol attributes, creates Logical Volume
 // and create file system on it. After that Logical Volume is ready for mount operations
-func (l *LVMProvisioner) PrepareVolume(vol api.Volume) error {
+func (l *LVMProvisioner) PrepareVolume(ctx context.Context, vol api.Volume) error {
 	ll := l.log.WithFields(logrus.Fields{
 		"method":   "PrepareVolume",
 		"volumeID": vol.Id,
 	})
 	ll.Infof("Processing for volume %#v", vol)
+	ctx = l.withVolumeTarget(ctx, vol)
 
 	var (
 		vgName string
@@ -76,7 +79,7 @@ func (l *LVMProvisioner) PrepareVolume(vol api.Volume) error {
 
 	// create lv with name /dev/VG_NAME/vol.Id
 	ll.Infof("Creating LV %s sizeof %s in VG %s", vol.Id, sizeStr, vgName)
-	if err = l.lvmOps.LVCreate(vol.Id, sizeStr, vgName); err != nil {
+	if err = l.lvmOps.LVCreate(ctx, vol.Id, sizeStr, vgName); err != nil {
 		return fmt.Errorf("unable to create LV: %v", err)
 	}
 
@@ -85,31 +88,32 @@ func (l *LVMProvisioner) PrepareVolume(vol api.Volume) error {
 	if vol.Mode == apiV1.ModeRAW {
 		return nil
 	}
-	return l.fsOps.CreateFS(fs.FileSystem(vol.Type), deviceFile)
+	return l.fsOps.CreateFS(ctx, fs.FileSystem(vol.Type), deviceFile)
 }
 
 // ReleaseVolume search volume group based on vol attributes, remove Logical Volume
 // and wipe file system on it. After that Logical Volume that had consumed by vol is completely removed
-func (l *LVMProvisioner) ReleaseVolume(vol api.Volume) error {
+func (l *LVMProvisioner) ReleaseVolume(ctx context.Context, vol api.Volume) error {
 	ll := logrus.WithFields(logrus.Fields{
 		"method":   "ReleaseVolume",
 		"volumeID": vol.Id,
 	})
 	ll.Infof("Processing for volume %v", vol)
+	ctx = l.withVolumeTarget(ctx, vol)
 
-	deviceFile, err := l.GetVolumePath(vol)
+	deviceFile, err := l.GetVolumePath(ctx, vol)
 	if err != nil {
 		return fmt.Errorf("unable to determine full path of the volume: %v", err)
 	}
 
-	if err := l.fsOps.WipeFS(deviceFile); err != nil {
+	if err := l.fsOps.WipeFS(ctx, deviceFile); err != nil {
 		// check whether such LV (deviceFile) exist or not
 		vgName, sErr := l.getVGName(&vol)
 		if sErr != nil {
 			return fmt.Errorf("unable to remove LV %s: %v and unable to determine VG name: %v",
 				deviceFile, err, sErr)
 		}
-		lvs, sErr := l.lvmOps.GetLVsInVG(vgName)
+		lvs, sErr := l.lvmOps.GetLVsInVG(ctx, vgName)
 		if sErr != nil {
 			return fmt.Errorf("unable to remove LV %s: %v and unable to list LVs in VG %s: %v",
 				deviceFile, err, vgName, sErr)
@@ -121,12 +125,12 @@ func (l *LVMProvisioner) ReleaseVolume(vol api.Volume) error {
 		return fmt.Errorf("failed to wipe FS on device %s: %v", deviceFile, err)
 	}
 
-	return l.lvmOps.LVRemove(deviceFile)
+	return l.lvmOps.LVRemove(ctx, deviceFile)
 }
 
 // GetVolumePath search Volume Group name by vol attributes and construct
 // full path to the volume using template: /dev/VG_NAME/LV_NAME
-func (l *LVMProvisioner) GetVolumePath(vol api.Volume) (string, error) {
+func (l *LVMProvisioner) GetVolumePath(ctx context.Context, vol api.Volume) (string, error) {
 	ll := l.log.WithFields(logrus.Fields{
 		"method":   "GetVolumePath",
 		"volumeID": vol.Id,
@@ -141,6 +145,20 @@ func (l *LVMProvisioner) GetVolumePath(vol api.Volume) (string, error) {
 	return fmt.Sprintf("/dev/%s/%s", vgName, vol.Id), nil // /dev/VG_NAME/LV_NAME
 }
 
+// withVolumeTarget returns context with the audit target of LV commands: volume and drives of its LogicalVolumeGroup,
+// drives are omitted if they can't be determined
+func (l *LVMProvisioner) withVolumeTarget(ctx context.Context, vol api.Volume) context.Context {
+	target := audit.Target{VolumeID: vol.Id}
+	serials, err := l.crHelper.GetLVGDriveSerials(ctx, vol.Location)
+	if err != nil {
+		l.log.WithField("volumeID", vol.Id).Warnf("Unable to determine drives of LogicalVolumeGroup %s: %v",
+			vol.Location, err)
+	} else {
+		target.DriveSerial = audit.DrivesTarget(serials...).DriveSerial
+	}
+	return audit.WithTarget(ctx, target)
+}
+
 func (l *LVMProvisioner) getVGName(vol *api.Volume) (string, error) {
 	var vgName = vol.Location
 
@@ -61,7 +61,7 @@ func TestLVMProvisioner_PrepareVolume_Success(t *testing.T) {
 	fsOps.On("CreateFS", fs.FileSystem(testVolume1.Type), devFile).
 		Return(nil).Times(1)
 
-	err := lp.PrepareVolume(testVolume1)
+	err := lp.PrepareVolume(testCtx, testVolume1)
 	assert.Nil(t, err)
 }
 
@@ -74,7 +74,7 @@ func TestLVMProvisioner_PrepareVolume_Fail(t *testing.T) {
 	// in that case vgName will be searching in CRs and here we get error
 	vol.StorageClass = apiV1.StorageClassSystemLVG
 
-	err = lp.PrepareVolume(vol)
+	err = lp.PrepareVolume(testCtx, vol)
 	assert.NotNil(t, err)
 	assert.Contains(t, err.Error(), "unable to determine VG name")
 
@@ -82,7 +82,7 @@ func TestLVMProvisioner_PrepareVolume_Fail(t *testing.T) {
 	lvmOps.On("LVCreate", testVolume1.Id, mock.Anything, testVolume1.Location).
 		Return(errTest).Times(1)
 
-	err = lp.PrepareVolume(testVolume1)
+	err = lp.PrepareVolume(testCtx, testVolume1)
 	assert.NotNil(t, err)
 	assert.Contains(t, err.Error(), "unable to create LV")
 
@@ -94,7 +94,7 @@ func TestLVMProvisioner_PrepareVolume_Fail(t *testing.T) {
 	fsOps.On("CreateFS", fs.FileSystem(testVolume1.Type), devFile).
 		Return(errTest).Times(1)
 
-	err = lp.PrepareVolume(testVolume1)
+	err = lp.PrepareVolume(testCtx, testVolume1)
 	assert.NotNil(t, err)
 	assert.Equal(t, errTest, err)
 }
@@ -110,14 +110,14 @@ func TestLVMProvisioner_ReleaseVolume_Success(t *testing.T) {
 	fsOps.On("WipeFS", devFile).Return(nil).Times(1)
 	lvmOps.On("LVRemove", devFile).Return(nil).Times(1)
 
-	err = lp.ReleaseVolume(testVolume1)
+	err = lp.ReleaseVolume(testCtx, testVolume1)
 	assert.Nil(t, err)
 
 	// WipeFS failed, LV isn't exist - ReleaseVolume success
 	fsOps.On("WipeFS", devFile).Return(errTest).Times(1)
 	lvmOps.On("GetLVsInVG", testVolume1.Location).Return(nil, nil).Times(1)
 
-	err = lp.ReleaseVolume(testVolume1)
+	err = lp.ReleaseVolume(testCtx, testVolume1)
 	assert.Nil(t, err)
 }
 
@@ -131,7 +131,7 @@ func TestLVMProvisioner_ReleaseVolume_Fail(t *testing.T) {
 	// in that case vgName will be searching in CRs and here we get error
 	vol.StorageClass = apiV1.StorageClassSystemLVG
 
-	err = lp.PrepareVolume(vol)
+	err = lp.PrepareVolume(testCtx, vol)
 	assert.NotNil(t, err)
 	assert.Contains(t, err.Error(), "unable to determine VG name")
 
@@ -140,7 +140,7 @@ func TestLVMProvisioner_ReleaseVolume_Fail(t *testing.T) {
 	fsOps.On("WipeFS", devFile).Return(errTest).Times(1)
 	lvmOps.On("GetLVsInVG", testVolume1.Location).Return([]string{testVolume1.Id}, nil).Times(1)
 
-	err = lp.ReleaseVolume(testVolume1)
+	err = lp.ReleaseVolume(testCtx, testVolume1)
 	assert.NotNil(t, err)
 	assert.Contains(t, err.Error(), "failed to wipe FS")
 
@@ -148,7 +148,7 @@ func TestLVMProvisioner_ReleaseVolume_Fail(t *testing.T) {
 	fsOps.On("WipeFS", devFile).Return(errTest).Times(1)
 	lvmOps.On("GetLVsInVG", testVolume1.Location).Return(nil, errTest).Times(1)
 
-	err = lp.ReleaseVolume(testVolume1)
+	err = lp.ReleaseVolume(testCtx, testVolume1)
 	assert.NotNil(t, err)
 	assert.Contains(t, err.Error(), "unable to remove LV")
 	assert.Contains(t, err.Error(), "and unable to list LVs in VG")
@@ -159,7 +159,7 @@ func TestLVMProvisioner_ReleaseVolume_Fail(t *testing.T) {
 	lvmOps.On("GetLVsInVG", testVolume1.Location).
 		Return([]string{testVolume1.Id}, nil).Times(1)
 
-	err = lp.ReleaseVolume(testVolume1)
+	err = lp.ReleaseVolume(testCtx, testVolume1)
 	assert.NotNil(t, err)
 	assert.Equal(t, errTest, err)
 }
@@ -168,7 +168,7 @@ func TestLVMProvisioner_GetVolumePath_Success(t *testing.T) {
 	setupTestLVMProvisioner()
 
 	expectedPath := fmt.Sprintf("/dev/%s/%s", testVolume1.Location, testVolume1.Id)
-	currentPath, err := lp.GetVolumePath(testVolume1)
+	currentPath, err := lp.GetVolumePath(testCtx, testVolume1)
 	assert.Nil(t, err)
 	assert.Equal(t, expectedPath, currentPath)
 }
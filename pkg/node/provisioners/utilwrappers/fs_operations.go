@@ -17,6 +17,7 @@ limitations under the License.
 package utilwrappers
 
 import (
+	"context"
 	"fmt"
 	"os"
 
@@ -30,9 +31,9 @@ import (
 type FSOperations interface {
 	// PrepareAndPerformMount composite methods which is prepare source and destination directories
 	// and performs mount operation from src to dst
-	PrepareAndPerformMount(src, dst string, bindMount, dstIsDir bool) error
+	PrepareAndPerformMount(ctx context.Context, src, dst string, bindMount, dstIsDir bool) error
 	// UnmountWithCheck unmount operation
-	UnmountWithCheck(path string) error
+	UnmountWithCheck(ctx context.Context, path string) error
 	fs.WrapFS
 }
 
@@ -54,7 +55,7 @@ func NewFSOperationsImpl(e command.CmdExecutor, log *logrus.Logger) *FSOperation
 // create (if isn't exist) dst folder on node and perform mount from src to dst
 // if bindMount set to true - mount operation will contain "--bind" option
 // if error occurs and dst has created during current method call then dst will be removed
-func (fsOp *FSOperationsImpl) PrepareAndPerformMount(src, dst string, bindMount, dstIsDir bool) error {
+func (fsOp *FSOperationsImpl) PrepareAndPerformMount(ctx context.Context, src, dst string, bindMount, dstIsDir bool) error {
 	ll := fsOp.log.WithFields(logrus.Fields{
 		"method": "PrepareAndPerformMount",
 	})
@@ -71,7 +72,7 @@ func (fsOp *FSOperationsImpl) PrepareAndPerformMount(src, dst string, bindMount,
 		if !dstIsDir {
 			createCMD = fsOp.MkFile
 		}
-		if err = createCMD(dst); err != nil {
+		if err = createCMD(ctx, dst); err != nil {
 			return err
 		}
 		wasCreated = true // if something went wrong we will remove path that had created based on that flag
@@ -79,9 +80,9 @@ func (fsOp *FSOperationsImpl) PrepareAndPerformMount(src, dst string, bindMount,
 
 	// dst folder is exist, check whether it is a mount point
 	if !wasCreated {
-		alreadyMounted, err := fsOp.IsMounted(dst)
+		alreadyMounted, err := fsOp.IsMounted(ctx, dst)
 		if err != nil {
-			_ = fsOp.RmDir(dst)
+			_ = fsOp.RmDir(ctx, dst)
 			return fmt.Errorf("unable to determine whether %s is a mountpoint or no: %v", dst, err)
 		}
 		if alreadyMounted {
@@ -94,9 +95,9 @@ func (fsOp *FSOperationsImpl) PrepareAndPerformMount(src, dst string, bindMount,
 	if bindMount {
 		opts = fs.BindOption
 	}
-	if err := fsOp.Mount(src, dst, opts); err != nil {
+	if err := fsOp.Mount(ctx, src, dst, opts); err != nil {
 		if wasCreated {
-			_ = fsOp.RmDir(dst)
+			_ = fsOp.RmDir(ctx, dst)
 		}
 
 		if srcInfo, err := os.Stat(src); err != nil {
@@ -109,18 +110,18 @@ func (fsOp *FSOperationsImpl) PrepareAndPerformMount(src, dst string, bindMount,
 			ll.Debugf("Stat of src with failed mount: %s", srcInfo)
 		}
 
-		isSrcMounted, err := fsOp.IsMounted(src)
+		isSrcMounted, err := fsOp.IsMounted(ctx, src)
 		if err != nil {
 			ll.Warnf("failed to execute isMount: %s", err)
 		}
 		if !isSrcMounted {
 			ll.Debugf("Src %s is not mounted", src)
 		} else {
-			if srcMount, err := fsOp.FindMountPoint(src); err != nil {
+			if srcMount, err := fsOp.FindMountPoint(ctx, src); err != nil {
 				ll.Warnf("failed to find mountPoint for src %s: %s", src, err)
 			} else {
 				ll.Debugf("Src mount point: %s", srcMount)
-				if spaceOnMountPoint, err := fsOp.GetFSSpace(srcMount); err != nil {
+				if spaceOnMountPoint, err := fsOp.GetFSSpace(ctx, srcMount); err != nil {
 					ll.Warnf("failed to get FS Space on %s, err: %s", srcMount, err)
 				} else {
 					ll.Debugf("FS Space on %s, is %d", srcMount, spaceOnMountPoint)
@@ -135,8 +136,8 @@ func (fsOp *FSOperationsImpl) PrepareAndPerformMount(src, dst string, bindMount,
 
 // UnmountWithCheck idempotent implemetation of unmount operation
 // check whether path is mounted and only if yes - try to unmount
-func (fsOp *FSOperationsImpl) UnmountWithCheck(path string) error {
-	isMounted, err := fsOp.IsMounted(path)
+func (fsOp *FSOperationsImpl) UnmountWithCheck(ctx context.Context, path string) error {
+	isMounted, err := fsOp.IsMounted(ctx, path)
 	if err != nil {
 		return fmt.Errorf("unable to check wthether path mounted or no: %v", err)
 	}
@@ -145,5 +146,5 @@ func (fsOp *FSOperationsImpl) UnmountWithCheck(path string) error {
 		return nil
 	}
 
-	return fsOp.Unmount(path)
+	return fsOp.Unmount(ctx, path)
 }
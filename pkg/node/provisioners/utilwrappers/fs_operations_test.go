@@ -17,6 +17,7 @@ limitations under the License.
 package utilwrappers
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -28,6 +29,8 @@ import (
 	mocklu "github.com/dell/csi-baremetal/pkg/mocks/linuxutils"
 )
 
+var testCtx = context.Background()
+
 func TestFSOperationsImpl_PrepareAndPerformMount_Success(t *testing.T) {
 	var (
 		fsOps      = NewFSOperationsImpl(&command.Executor{}, logrus.New())
@@ -42,20 +45,20 @@ func TestFSOperationsImpl_PrepareAndPerformMount_Success(t *testing.T) {
 	// dst folder isn't exist
 	wrapFS.On("MkDir", dst).Return(nil).Once()
 	wrapFS.On("Mount", src, dst, bindOption).Return(nil).Once()
-	err = fsOps.PrepareAndPerformMount(src, dst, false, true)
+	err = fsOps.PrepareAndPerformMount(testCtx, src, dst, false, true)
 	assert.Nil(t, err)
 	wrapFS.AssertCalled(t, "MkDir", dst) // ensure that folder was created
 
 	// dst folder is exist and has already mounted
 	dst = "/tmp"
 	wrapFS.On("IsMounted", dst).Return(true, nil).Once()
-	err = fsOps.PrepareAndPerformMount(src, dst, false, true)
+	err = fsOps.PrepareAndPerformMount(testCtx, src, dst, false, true)
 
 	// dst folder is exist and isn't a mount point, also use bind = true
 	wrapFS.On("IsMounted", dst).Return(false, nil).Once()
 	wrapFS.On("Mount", src, dst, []string{fs.BindOption}).Return(nil).Once()
 
-	err = fsOps.PrepareAndPerformMount(src, dst, true, true)
+	err = fsOps.PrepareAndPerformMount(testCtx, src, dst, true, true)
 	wrapFS.AssertCalled(t, "IsMounted", dst)
 }
 
@@ -74,7 +77,7 @@ func TestFSOperationsImpl_PrepareAndPerformMount_Fail(t *testing.T) {
 	// dst ins't exist and MkDir failed
 	wrapFS.On("MkDir", dst).Return(expectedErr).Once()
 
-	err = fsOps.PrepareAndPerformMount(src, dst, false, true)
+	err = fsOps.PrepareAndPerformMount(testCtx, src, dst, false, true)
 	assert.Error(t, err)
 	assert.Equal(t, expectedErr, err)
 
@@ -83,7 +86,7 @@ func TestFSOperationsImpl_PrepareAndPerformMount_Fail(t *testing.T) {
 	wrapFS.On("IsMounted", dst).Return(false, expectedErr).Once()
 	wrapFS.On("RmDir", dst).Return(nil).Once()
 
-	err = fsOps.PrepareAndPerformMount(src, dst, false, true)
+	err = fsOps.PrepareAndPerformMount(testCtx, src, dst, false, true)
 
 	assert.Error(t, err)
 	wrapFS.AssertCalled(t, "RmDir", dst)
@@ -95,7 +98,7 @@ func TestFSOperationsImpl_PrepareAndPerformMount_Fail(t *testing.T) {
 	wrapFS.On("RmDir", dst).Return(nil).Once()
 	wrapFS.On("IsMounted", src).Return(false, nil).Once()
 
-	err = fsOps.PrepareAndPerformMount(src, dst, false, true)
+	err = fsOps.PrepareAndPerformMount(testCtx, src, dst, false, true)
 	assert.Error(t, err)
 	wrapFS.AssertCalled(t, "MkDir", dst)
 	wrapFS.AssertCalled(t, "RmDir", dst)
@@ -106,7 +109,7 @@ func TestFSOperationsImpl_PrepareAndPerformMount_Fail(t *testing.T) {
 	wrapFS.On("IsMounted", src).Return(false, nil).Once()
 	wrapFS.On("Mount", src, dst, bindOption).Return(expectedErr).Once()
 
-	err = fsOps.PrepareAndPerformMount(src, dst, false, true)
+	err = fsOps.PrepareAndPerformMount(testCtx, src, dst, false, true)
 	assert.Error(t, err)
 	wrapFS.AssertCalled(t, "IsMounted", dst)
 	wrapFS.AssertNotCalled(t, "RmDir", dst)
@@ -123,7 +126,7 @@ func TestFSOperationsImpl_MountWithCheck_Success(t *testing.T) {
 
 	// not mounted
 	wrapFS.On("IsMounted", path).Return(false, nil).Once()
-	err = fsOps.UnmountWithCheck(path)
+	err = fsOps.UnmountWithCheck(testCtx, path)
 	assert.Nil(t, err)
 	for _, c := range wrapFS.Calls {
 		if c.Method == "Unmount" {
@@ -134,7 +137,7 @@ func TestFSOperationsImpl_MountWithCheck_Success(t *testing.T) {
 	// Unmount successfully
 	wrapFS.On("IsMounted", path).Return(true, nil).Once()
 	wrapFS.On("Unmount", path).Return(nil).Once()
-	err = fsOps.UnmountWithCheck(path)
+	err = fsOps.UnmountWithCheck(testCtx, path)
 	assert.Nil(t, err)
 	unmountCalled := false
 	for _, c := range wrapFS.Calls {
@@ -158,7 +161,7 @@ func TestFSOperationsImpl_MountWithCheck_Fail(t *testing.T) {
 	// IsMounted failed
 	isMountedErr := errors.New("isMounted failed")
 	wrapFS.On("IsMounted", path).Return(false, isMountedErr).Once()
-	err = fsOps.UnmountWithCheck(path)
+	err = fsOps.UnmountWithCheck(testCtx, path)
 	assert.NotNil(t, err)
 	assert.Contains(t, err.Error(), isMountedErr.Error())
 
@@ -166,7 +169,7 @@ func TestFSOperationsImpl_MountWithCheck_Fail(t *testing.T) {
 	unmountErr := errors.New("unmount failed")
 	wrapFS.On("IsMounted", path).Return(true, nil).Once()
 	wrapFS.On("Unmount", path).Return(unmountErr).Once()
-	err = fsOps.UnmountWithCheck(path)
+	err = fsOps.UnmountWithCheck(testCtx, path)
 	assert.NotNil(t, err)
 	assert.Equal(t, unmountErr, err)
 }
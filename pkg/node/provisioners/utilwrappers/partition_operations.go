@@ -17,6 +17,7 @@ limitations under the License.
 package utilwrappers
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -32,11 +33,11 @@ import (
 // that encapsulates all low-level operations with partitions on node
 type PartitionOperations interface {
 	// PreparePartition is fully prepare partition on node for use
-	PreparePartition(p Partition) (*Partition, error)
+	PreparePartition(ctx context.Context, p Partition) (*Partition, error)
 	// ReleasePartition is fully release resources that had consumed by partition on node
-	ReleasePartition(p Partition) error
+	ReleasePartition(ctx context.Context, p Partition) error
 	// SearchPartName returns partition name
-	SearchPartName(device, partUUID string) string
+	SearchPartName(ctx context.Context, device, partUUID string) string
 	ph.WrapPartition
 }
 
@@ -90,7 +91,7 @@ func NewPartitionOperationsImpl(e command.CmdExecutor, log *logrus.Logger) *Part
 
 // PreparePartition completely creates and prepares partition p on node
 // After that FS could be created on partition
-func (d *PartitionOperationsImpl) PreparePartition(p Partition) (*Partition, error) {
+func (d *PartitionOperationsImpl) PreparePartition(ctx context.Context, p Partition) (*Partition, error) {
 	defer d.metrics.EvaluateDurationForMethod("PreparePartition")()
 	ll := d.log.WithFields(logrus.Fields{
 		"method":   "PreparePartition",
@@ -98,13 +99,13 @@ func (d *PartitionOperationsImpl) PreparePartition(p Partition) (*Partition, err
 	})
 	ll.Debugf("Processing for partition %#v", p)
 
-	exist, err := d.IsPartitionExists(p.Device, p.Num)
+	exist, err := d.IsPartitionExists(ctx, p.Device, p.Num)
 	if err != nil {
 		return nil, fmt.Errorf("unable to determine partition existence: %v", err)
 	}
 
 	if exist { // check partition UUID
-		currUUID, err := d.GetPartitionUUID(p.Device, p.Num)
+		currUUID, err := d.GetPartitionUUID(ctx, p.Device, p.Num)
 		if err != nil {
 			return nil, fmt.Errorf("partition has already exist on device %s, fail to get it UUID", p.Device)
 		}
@@ -116,26 +117,26 @@ func (d *PartitionOperationsImpl) PreparePartition(p Partition) (*Partition, err
 	}
 
 	// create partition table
-	if err = d.CreatePartitionTable(p.Device, p.TableType); err != nil {
+	if err = d.CreatePartitionTable(ctx, p.Device, p.TableType); err != nil {
 		return nil, fmt.Errorf("unable to create partition table: %v", err)
 	}
 
 	// create partition
-	if err = d.CreatePartition(p.Device, p.Label); err != nil {
+	if err = d.CreatePartition(ctx, p.Device, p.Label); err != nil {
 		return nil, fmt.Errorf("unable to create partition: %v", err)
 	}
-	_ = d.SyncPartitionTable(p.Device)
+	_ = d.SyncPartitionTable(ctx, p.Device)
 
 	if p.Ephemeral {
-		p.PartUUID, err = d.GetPartitionUUID(p.Device, p.Num)
+		p.PartUUID, err = d.GetPartitionUUID(ctx, p.Device, p.Num)
 		if err != nil {
 			return nil, fmt.Errorf("unable to get partition UUID for ephemeral volume: %v", err)
 		}
-	} else if err = d.SetPartitionUUID(p.Device, p.Num, p.PartUUID); err != nil {
+	} else if err = d.SetPartitionUUID(ctx, p.Device, p.Num, p.PartUUID); err != nil {
 		return nil, fmt.Errorf("unable to set partition UUID: %v", err)
 	}
 
-	p.Name = d.SearchPartName(p.Device, p.PartUUID)
+	p.Name = d.SearchPartName(ctx, p.Device, p.PartUUID)
 	if p.Name == "" {
 		return nil, fmt.Errorf("unable to determine partition name after it being created: %v", err)
 	}
@@ -144,26 +145,26 @@ func (d *PartitionOperationsImpl) PreparePartition(p Partition) (*Partition, err
 }
 
 // ReleasePartition completely removes partition p
-func (d *PartitionOperationsImpl) ReleasePartition(p Partition) error {
+func (d *PartitionOperationsImpl) ReleasePartition(ctx context.Context, p Partition) error {
 	defer d.metrics.EvaluateDurationForMethod("ReleasePartition")()
 	d.log.WithFields(logrus.Fields{
 		"method":   "ReleasePartition",
 		"volumeID": p.PartUUID,
 	}).Infof("Processing for %v", p)
 
-	exist, err := d.IsPartitionExists(p.Device, p.Num)
+	exist, err := d.IsPartitionExists(ctx, p.Device, p.Num)
 	if err != nil {
 		return fmt.Errorf("unable to determine partition existence: %v", err)
 	}
 	if exist {
-		return d.DeletePartition(p.Device, p.Num)
+		return d.DeletePartition(ctx, p.Device, p.Num)
 	}
 	return nil
 }
 
 // SearchPartName search (with retries) partition with UUID partUUID on device and returns partition name
 // e.g. "1" for /dev/sda1, "p1n1" for /dev/loopbackp1n1
-func (d *PartitionOperationsImpl) SearchPartName(device, partUUID string) string {
+func (d *PartitionOperationsImpl) SearchPartName(ctx context.Context, device, partUUID string) string {
 	defer d.metrics.EvaluateDurationForMethod("SearchPartName")()
 	ll := d.log.WithFields(logrus.Fields{
 		"method":   "SearchPartName",
@@ -179,13 +180,13 @@ func (d *PartitionOperationsImpl) SearchPartName(device, partUUID string) string
 	// get partition name
 	for i := 0; i < NumberOfRetriesToSyncPartTable; i++ {
 		// sync partition table
-		err = d.SyncPartitionTable(device)
+		err = d.SyncPartitionTable(ctx, device)
 		if err != nil {
 			// log and ignore error
 			ll.Warningf("Unable to sync partition table for device %s", device)
 		}
 		time.Sleep(SleepBetweenRetriesToSyncPartTable)
-		partName, err = d.GetPartitionNameByUUID(device, partUUID)
+		partName, err = d.GetPartitionNameByUUID(ctx, device, partUUID)
 		if err != nil {
 			ll.Debugf("unable to find part name: %v", err)
 			continue
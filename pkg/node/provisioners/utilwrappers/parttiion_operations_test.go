@@ -72,7 +72,7 @@ func TestDriveProvisioner_PreparePartition_Success(t *testing.T) {
 	mockPH.On("GetPartitionUUID", testPart1.Device, testPart1.Num).
 		Return(testPart1.PartUUID, nil).Once()
 
-	currentPPtr, err = partOps.PreparePartition(testPart1)
+	currentPPtr, err = partOps.PreparePartition(testCtx, testPart1)
 	assert.Nil(t, err)
 	assert.Equal(t, testPart1, *currentPPtr)
 	mockPH.Calls = []mock.Call{} // flush mock call records
@@ -100,7 +100,7 @@ func TestDriveProvisioner_PreparePartition_Success(t *testing.T) {
 	// not ephemeral
 	mockPH.On("GetPartitionNameByUUID", testPart1.Device, testPart1.PartUUID).
 		Return(partName, nil).Once()
-	currentPPtr, err = partOps.PreparePartition(testPart1)
+	currentPPtr, err = partOps.PreparePartition(testCtx, testPart1)
 	assert.Nil(t, err)
 	p := testPart1
 	p.Name = partName
@@ -114,7 +114,7 @@ func TestDriveProvisioner_PreparePartition_Success(t *testing.T) {
 		Return(partName, nil).Once()
 	pE := testPart1
 	pE.Ephemeral = true
-	currentPPtr, err = partOps.PreparePartition(pE)
+	currentPPtr, err = partOps.PreparePartition(testCtx, pE)
 	assert.Nil(t, err)
 	pE.Name = partName
 	pE.PartUUID = partUUIDForEphemeral
@@ -135,7 +135,7 @@ func TestDriveProvisioner_PreparePartition_Failed(t *testing.T) {
 	mockPH.On("IsPartitionExists", testPart1.Device, testPart1.Num).
 		Return(false, expectedErr).Once()
 
-	currentPPtr, err = partOps.PreparePartition(testPart1)
+	currentPPtr, err = partOps.PreparePartition(testCtx, testPart1)
 	assert.Nil(t, currentPPtr)
 	assert.NotNil(t, err)
 
@@ -146,7 +146,7 @@ func TestDriveProvisioner_PreparePartition_Failed(t *testing.T) {
 	mockPH.On("GetPartitionUUID", testPart1.Device, testPart1.Num).
 		Return("", expectedErr).Once()
 
-	currentPPtr, err = partOps.PreparePartition(testPart1)
+	currentPPtr, err = partOps.PreparePartition(testCtx, testPart1)
 	assert.Nil(t, currentPPtr)
 	assert.NotNil(t, err)
 	assert.Contains(t, err.Error(), "fail to get it UUID")
@@ -155,7 +155,7 @@ func TestDriveProvisioner_PreparePartition_Failed(t *testing.T) {
 	mockPH.On("GetPartitionUUID", testPart1.Device, testPart1.Num).
 		Return("another-uuid", nil).Once()
 
-	currentPPtr, err = partOps.PreparePartition(testPart1)
+	currentPPtr, err = partOps.PreparePartition(testCtx, testPart1)
 	assert.Nil(t, currentPPtr)
 	assert.NotNil(t, err)
 	assert.Contains(t, err.Error(), "has already exist but have another UUID")
@@ -168,7 +168,7 @@ func TestDriveProvisioner_PreparePartition_Failed(t *testing.T) {
 	mockPH.On("CreatePartitionTable", testPart1.Device, testPart1.TableType).
 		Return(expectedErr).Once()
 
-	currentPPtr, err = partOps.PreparePartition(testPart1)
+	currentPPtr, err = partOps.PreparePartition(testCtx, testPart1)
 	assert.Nil(t, currentPPtr)
 	assert.NotNil(t, err)
 	assert.Contains(t, err.Error(), "unable to create partition table")
@@ -181,7 +181,7 @@ func TestDriveProvisioner_PreparePartition_Failed(t *testing.T) {
 	mockPH.On("CreatePartition", testPart1.Device, testPart1.Label).
 		Return(expectedErr).Once()
 
-	currentPPtr, err = partOps.PreparePartition(testPart1)
+	currentPPtr, err = partOps.PreparePartition(testCtx, testPart1)
 	assert.Nil(t, currentPPtr)
 	assert.NotNil(t, err)
 	assert.Contains(t, err.Error(), "unable to create partition")
@@ -196,7 +196,7 @@ func TestDriveProvisioner_PreparePartition_Failed(t *testing.T) {
 	mockPH.On("SetPartitionUUID", testPart1.Device, testPart1.Num, testPart1.PartUUID).
 		Return(expectedErr).Once()
 
-	currentPPtr, err = partOps.PreparePartition(testPart1)
+	currentPPtr, err = partOps.PreparePartition(testCtx, testPart1)
 	assert.Nil(t, currentPPtr)
 	assert.NotNil(t, err)
 	assert.Contains(t, err.Error(), "unable to set partition UUID")
@@ -207,7 +207,7 @@ func TestDriveProvisioner_PreparePartition_Failed(t *testing.T) {
 
 	pE := testPart1
 	pE.Ephemeral = true
-	currentPPtr, err = partOps.PreparePartition(pE)
+	currentPPtr, err = partOps.PreparePartition(testCtx, pE)
 	assert.Nil(t, currentPPtr)
 	assert.NotNil(t, err)
 	assert.Contains(t, err.Error(), "unable to get partition UUID for ephemeral volume")
@@ -223,7 +223,7 @@ func TestDriveProvisioner_ReleasePartition_Success(t *testing.T) {
 	mockPH.On("IsPartitionExists", testPart1.Device, testPart1.Num).
 		Return(false, nil).Once()
 
-	err = partOps.ReleasePartition(testPart1)
+	err = partOps.ReleasePartition(testCtx, testPart1)
 	assert.Nil(t, err)
 
 	// partition exists and deleted successfully
@@ -232,7 +232,7 @@ func TestDriveProvisioner_ReleasePartition_Success(t *testing.T) {
 	mockPH.On("DeletePartition", testPart1.Device, testPart1.Num).
 		Return(nil).Once()
 
-	err = partOps.ReleasePartition(testPart1)
+	err = partOps.ReleasePartition(testCtx, testPart1)
 	assert.Nil(t, err)
 	mockPH.AssertCalled(t, "DeletePartition", testPart1.Device, testPart1.Num)
 }
@@ -248,7 +248,7 @@ func TestDriveProvisioner_ReleasePartition_Fail(t *testing.T) {
 	mockPH.On("IsPartitionExists", testPart1.Device, testPart1.Num).
 		Return(false, expectedErr).Once()
 
-	err = partOps.ReleasePartition(testPart1)
+	err = partOps.ReleasePartition(testCtx, testPart1)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "unable to determine partition existence")
 
@@ -258,7 +258,7 @@ func TestDriveProvisioner_ReleasePartition_Fail(t *testing.T) {
 	mockPH.On("DeletePartition", testPart1.Device, testPart1.Num).
 		Return(expectedErr).Once()
 
-	err = partOps.ReleasePartition(testPart1)
+	err = partOps.ReleasePartition(testCtx, testPart1)
 	assert.Error(t, err)
 	assert.Equal(t, expectedErr, err)
 }
@@ -0,0 +1,248 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	coreV1 "k8s.io/api/core/v1"
+	k8sCl "sigs.k8s.io/controller-runtime/pkg/client"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	"github.com/dell/csi-baremetal/api/v1/drivecrd"
+	"github.com/dell/csi-baremetal/api/v1/lvgcrd"
+	"github.com/dell/csi-baremetal/api/v1/volumecrd"
+	"github.com/dell/csi-baremetal/pkg/base"
+	"github.com/dell/csi-baremetal/pkg/base/util"
+	csibmnodeconst "github.com/dell/csi-baremetal/pkg/crcontrollers/operator/common"
+	"github.com/dell/csi-baremetal/pkg/eventing"
+	p "github.com/dell/csi-baremetal/pkg/node/provisioners"
+)
+
+// adoptDrive searches Drive CR of another node which represents the same physical drive as discovered one.
+// If such Drive CR is OFFLINE (node was replaced and its ID was changed) and data on the drive matches
+// Volume and LogicalVolumeGroup CRs, then ownership of Drive, LVG, Volume and AC CRs is moved to the current node.
+// PVs aren't changed, warning event is sent for each volume which PV refers to the replaced node.
+// Receives golang context and drive reported by drive manager
+// Returns adopted Drive CR together with its previous state or nils if drive wasn't adopted
+func (m *VolumeManager) adoptDrive(ctx context.Context, drive *api.Drive) (*drivecrd.Drive, *drivecrd.Drive) {
+	ll := m.log.WithFields(logrus.Fields{
+		"method":   "adoptDrive",
+		"driveSN":  drive.SerialNumber,
+		"nodeID":   m.nodeID,
+		"drivePID": drive.PID,
+	})
+
+	driveCRs, err := m.crHelper.GetDriveCRs()
+	if err != nil {
+		ll.Errorf("Unable to read Drive CRs: %v", err)
+		return nil, nil
+	}
+
+	var driveCR *drivecrd.Drive
+	for i := range driveCRs {
+		if driveCRs[i].Spec.NodeId != m.nodeID && m.drivesAreTheSame(drive, &driveCRs[i].Spec) {
+			driveCR = &driveCRs[i]
+			break
+		}
+	}
+	if driveCR == nil {
+		return nil, nil
+	}
+	// drive of the live node might be moved physically, it is handled as a new drive
+	if driveCR.Spec.Status != apiV1.DriveStatusOffline {
+		ll.Infof("Drive CR %s of node %s is %s, skip adoption", driveCR.Name, driveCR.Spec.NodeId, driveCR.Spec.Status)
+		return nil, nil
+	}
+
+	oldNodeID := driveCR.Spec.NodeId
+	ll.Infof("Drive CR %s of node %s matches discovered drive", driveCR.Name, oldNodeID)
+
+	lvg, err := m.crHelper.GetLVGByDrive(ctx, driveCR.Spec.UUID)
+	if err != nil {
+		ll.Errorf("Unable to read LogicalVolumeGroup CR for drive %s: %v", driveCR.Name, err)
+		return nil, nil
+	}
+	location := driveCR.Spec.UUID
+	if lvg != nil {
+		location = lvg.Name
+	}
+	volumes, err := m.crHelper.GetVolumesByLocation(ctx, location)
+	if err != nil {
+		ll.Errorf("Unable to read Volume CRs for location %s: %v", location, err)
+		return nil, nil
+	}
+
+	if err = m.verifyDataOnDrive(ctx, drive.Path, lvg, volumes); err != nil {
+		ll.Errorf("Data on the drive doesn't match custom resources: %v", err)
+		m.sendEventForDrive(driveCR, eventing.WarningType, eventing.DriveAdoptionFailed,
+			"Drive SN: %s of node %s wasn't adopted by node %s: %v",
+			drive.SerialNumber, oldNodeID, m.nodeID, err)
+		return nil, nil
+	}
+
+	previousState := driveCR.DeepCopy()
+	// copy fields which aren't reported by drive manager
+	drive.UUID = driveCR.Spec.UUID
+	drive.Usage = driveCR.Spec.Usage
+	drive.IsSystem = driveCR.Spec.IsSystem
+	drive.IsClean = driveCR.Spec.IsClean
+	drive.NodeId = m.nodeID
+	driveCR.Spec = *drive
+	ctxWithID := context.WithValue(ctx, base.RequestUUID, driveCR.Name)
+	if err = m.k8sClient.UpdateCR(ctxWithID, driveCR); err != nil {
+		ll.Errorf("Unable to update Drive CR %s: %v", driveCR.Name, err)
+		return nil, nil
+	}
+
+	if lvg != nil {
+		m.adoptLVG(ctxWithID, lvg)
+	}
+	for _, vol := range volumes {
+		m.adoptVolume(ctxWithID, vol, oldNodeID)
+	}
+	m.adoptACs(ctxWithID, location)
+
+	m.sendEventForDrive(driveCR, eventing.NormalType, eventing.DriveAdopted,
+		"Drive SN: %s was adopted from node %s by node %s with %d volume(s).",
+		drive.SerialNumber, oldNodeID, m.nodeID, len(volumes))
+	return previousState, driveCR
+}
+
+// verifyDataOnDrive checks that VG of LogicalVolumeGroup with LVs of volumes or partitions of volumes exist on the device
+// Receives device path, LogicalVolumeGroup CR (might be nil) and volumes which are located on the drive or LVG
+// Returns error if data doesn't match
+func (m *VolumeManager) verifyDataOnDrive(ctx context.Context, device string, lvg *lvgcrd.LogicalVolumeGroup, volumes []*volumecrd.Volume) error {
+	if lvg != nil {
+		vgName, err := m.lvmOps.GetVGNameByPVName(ctx, device)
+		if err != nil {
+			return fmt.Errorf("unable to read VG name for PV %s: %v", device, err)
+		}
+		if vgName != lvg.Spec.Name {
+			return fmt.Errorf("PV %s belongs to VG %s, expected %s", device, vgName, lvg.Spec.Name)
+		}
+		lvs, err := m.lvmOps.GetLVsInVG(ctx, vgName)
+		if err != nil {
+			return fmt.Errorf("unable to read LVs of VG %s: %v", vgName, err)
+		}
+		for _, vol := range volumes {
+			if !util.ContainsString(lvs, vol.Spec.Id) {
+				return fmt.Errorf("LV of volume %s doesn't exist in VG %s", vol.Spec.Id, vgName)
+			}
+		}
+		return nil
+	}
+
+	for _, vol := range volumes {
+		expectedUUID, err := util.GetVolumeUUID(vol.Spec.Id)
+		if err != nil {
+			return err
+		}
+		partUUID, err := m.partOps.GetPartitionUUID(ctx, device, p.DefaultPartitionNumber)
+		if err != nil {
+			return fmt.Errorf("unable to read partition UUID of device %s: %v", device, err)
+		}
+		if partUUID != expectedUUID {
+			return fmt.Errorf("partition UUID of device %s is %s, expected %s for volume %s",
+				device, partUUID, expectedUUID, vol.Spec.Id)
+		}
+	}
+	return nil
+}
+
+// adoptLVG moves LogicalVolumeGroup CR to the current node
+func (m *VolumeManager) adoptLVG(ctx context.Context, lvg *lvgcrd.LogicalVolumeGroup) {
+	if lvg.Spec.Node == m.nodeID {
+		return
+	}
+	lvg.Spec.Node = m.nodeID
+	if err := m.k8sClient.UpdateCR(ctx, lvg); err != nil {
+		m.log.WithField("method", "adoptLVG").
+			Errorf("Unable to update LogicalVolumeGroup CR %s: %v", lvg.Name, err)
+	}
+}
+
+// adoptVolume moves Volume CR to the current node and returns it to OPERATIVE state
+func (m *VolumeManager) adoptVolume(ctx context.Context, vol *volumecrd.Volume, oldNodeID string) {
+	ll := m.log.WithFields(logrus.Fields{
+		"method":   "adoptVolume",
+		"volumeID": vol.Spec.Id,
+	})
+
+	vol.Spec.NodeId = m.nodeID
+	if vol.Spec.OperationalStatus == apiV1.OperationalStatusMissing {
+		vol.Spec.OperationalStatus = apiV1.OperationalStatusOperative
+	}
+	if err := m.k8sClient.UpdateCR(ctx, vol); err != nil {
+		ll.Errorf("Unable to update Volume CR: %v", err)
+		return
+	}
+	m.checkPVTopology(ctx, vol, oldNodeID)
+}
+
+// checkPVTopology sends warning event if node affinity of PV refers to the replaced node.
+// Node affinity of PV is immutable and PV must be re-created by administrator, see docs
+func (m *VolumeManager) checkPVTopology(ctx context.Context, vol *volumecrd.Volume, oldNodeID string) {
+	pv := &coreV1.PersistentVolume{}
+	// PV name is the same as volume ID
+	if err := m.k8sClient.Get(ctx, k8sCl.ObjectKey{Name: vol.Spec.Id}, pv); err != nil {
+		m.log.WithField("method", "checkPVTopology").Errorf("Unable to read PV %s: %v", vol.Spec.Id, err)
+		return
+	}
+	if affinityRefersNode(pv.Spec.NodeAffinity, oldNodeID) {
+		m.recorder.Eventf(vol, eventing.WarningType, eventing.VolumeTopologyOutdated,
+			"Node affinity of PV %s refers to replaced node %s, PV must be re-created with node ID %s",
+			pv.Name, oldNodeID, m.nodeID)
+	}
+}
+
+// adoptACs moves AvailableCapacity CRs with provided location to the current node
+func (m *VolumeManager) adoptACs(ctx context.Context, location string) {
+	acs, err := m.crHelper.GetACCRs()
+	if err != nil {
+		m.log.WithField("method", "adoptACs").Errorf("Unable to read AvailableCapacity CRs: %v", err)
+		return
+	}
+	for _, ac := range acs {
+		ac := ac
+		if ac.Spec.Location != location || ac.Spec.NodeId == m.nodeID {
+			continue
+		}
+		ac.Spec.NodeId = m.nodeID
+		if err := m.k8sClient.UpdateCR(ctx, &ac); err != nil {
+			m.log.WithField("method", "adoptACs").Errorf("Unable to update AvailableCapacity CR %s: %v", ac.Name, err)
+		}
+	}
+}
+
+// affinityRefersNode checks whether csi-baremetal node ID topology key in node affinity terms has provided value
+func affinityRefersNode(affinity *coreV1.VolumeNodeAffinity, nodeID string) bool {
+	if affinity == nil || affinity.Required == nil {
+		return false
+	}
+	for _, term := range affinity.Required.NodeSelectorTerms {
+		for _, expr := range term.MatchExpressions {
+			if expr.Key == csibmnodeconst.NodeIDTopologyLabelKey && util.ContainsString(expr.Values, nodeID) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,172 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	coreV1 "k8s.io/api/core/v1"
+	k8smetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sCl "sigs.k8s.io/controller-runtime/pkg/client"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	"github.com/dell/csi-baremetal/api/v1/drivecrd"
+	vcrd "github.com/dell/csi-baremetal/api/v1/volumecrd"
+	csibmnodeconst "github.com/dell/csi-baremetal/pkg/crcontrollers/operator/common"
+	"github.com/dell/csi-baremetal/pkg/eventing"
+	"github.com/dell/csi-baremetal/pkg/mocks"
+	mocklu "github.com/dell/csi-baremetal/pkg/mocks/linuxutils"
+	p "github.com/dell/csi-baremetal/pkg/node/provisioners"
+)
+
+const (
+	oldNodeID       = "replaced-node"
+	replacedVolID   = "pvc-1f7b3c2e-8a55-4b8e-9a1c-3f0e7d5a9b11"
+	replacedVolUUID = "1f7b3c2e-8a55-4b8e-9a1c-3f0e7d5a9b11"
+)
+
+func prepareReplacedNodeResources(t *testing.T, vm *VolumeManager, driveStatus string) (*api.Drive, *coreV1.PersistentVolume) {
+	oldDrive := drive2
+	oldDrive.NodeId = oldNodeID
+	oldDrive.Status = driveStatus
+	oldDrive.Health = apiV1.HealthUnknown
+	oldDrive.IsClean = false
+	addDriveCRs(vm.k8sClient, vm.k8sClient.ConstructDriveCR(oldDrive.UUID, oldDrive))
+
+	vol := vm.k8sClient.ConstructVolumeCR(replacedVolID, testNs, api.Volume{
+		Id:                replacedVolID,
+		NodeId:            oldNodeID,
+		Location:          oldDrive.UUID,
+		StorageClass:      apiV1.StorageClassHDD,
+		CSIStatus:         apiV1.Published,
+		OperationalStatus: apiV1.OperationalStatusMissing,
+	})
+	assert.Nil(t, vm.k8sClient.CreateCR(testCtx, vol.Name, vol))
+
+	pv := &coreV1.PersistentVolume{
+		ObjectMeta: k8smetav1.ObjectMeta{Name: replacedVolID},
+		Spec: coreV1.PersistentVolumeSpec{
+			PersistentVolumeReclaimPolicy: coreV1.PersistentVolumeReclaimDelete,
+			ClaimRef:                      &coreV1.ObjectReference{Name: "pvc-1", Namespace: testNs},
+			NodeAffinity: &coreV1.VolumeNodeAffinity{Required: &coreV1.NodeSelector{
+				NodeSelectorTerms: []coreV1.NodeSelectorTerm{{MatchExpressions: []coreV1.NodeSelectorRequirement{{
+					Key:      csibmnodeconst.NodeIDTopologyLabelKey,
+					Operator: coreV1.NodeSelectorOpIn,
+					Values:   []string{oldNodeID},
+				}}}},
+			}},
+		},
+	}
+	assert.Nil(t, vm.k8sClient.Create(testCtx, pv))
+
+	discovered := oldDrive
+	discovered.UUID = ""
+	discovered.NodeId = nodeID
+	discovered.Status = apiV1.DriveStatusOnline
+	discovered.Health = apiV1.HealthGood
+	return &discovered, pv
+}
+
+func TestVolumeManager_updateDrivesCRs_AdoptDrive(t *testing.T) {
+	vm := prepareSuccessVolumeManager(t)
+	vm.nodeReplacement = true
+	discovered, _ := prepareReplacedNodeResources(t, vm, apiV1.DriveStatusOffline)
+	partOps := &mocklu.MockWrapPartition{}
+	partOps.On("GetPartitionUUID", discovered.Path, p.DefaultPartitionNumber).Return(replacedVolUUID, nil)
+	vm.partOps = partOps
+
+	updates, err := vm.updateDrivesCRs(testCtx, []*api.Drive{discovered})
+	assert.Nil(t, err)
+	assert.Len(t, updates.Created, 0)
+	assert.Len(t, updates.Updated, 1)
+
+	driveCRs, err := vm.crHelper.GetDriveCRs(nodeID)
+	assert.Nil(t, err)
+	assert.Len(t, driveCRs, 1)
+	assert.Equal(t, drive2.UUID, driveCRs[0].Spec.UUID)
+	assert.Equal(t, apiV1.DriveStatusOnline, driveCRs[0].Spec.Status)
+	assert.False(t, driveCRs[0].Spec.IsClean)
+
+	vol := &vcrd.Volume{}
+	assert.Nil(t, vm.k8sClient.ReadCR(testCtx, replacedVolID, testNs, vol))
+	assert.Equal(t, nodeID, vol.Spec.NodeId)
+	assert.Equal(t, apiV1.OperationalStatusOperative, vol.Spec.OperationalStatus)
+
+	// PV isn't changed, warning is sent
+	pv := &coreV1.PersistentVolume{}
+	assert.Nil(t, vm.k8sClient.Get(testCtx, k8sCl.ObjectKey{Name: replacedVolID}, pv))
+	assert.Equal(t, []string{oldNodeID},
+		pv.Spec.NodeAffinity.Required.NodeSelectorTerms[0].MatchExpressions[0].Values)
+	rec := vm.recorder.(*mocks.NoOpRecorder)
+	assert.Equal(t, eventing.VolumeTopologyOutdated, rec.Calls[len(rec.Calls)-2].Reason)
+	assert.Equal(t, eventing.DriveAdopted, rec.Calls[len(rec.Calls)-1].Reason)
+}
+
+func TestVolumeManager_updateDrivesCRs_AdoptDriveFail(t *testing.T) {
+	// partition UUID doesn't match volume
+	vm := prepareSuccessVolumeManager(t)
+	vm.nodeReplacement = true
+	discovered, _ := prepareReplacedNodeResources(t, vm, apiV1.DriveStatusOffline)
+	partOps := &mocklu.MockWrapPartition{}
+	partOps.On("GetPartitionUUID", discovered.Path, p.DefaultPartitionNumber).Return("another-uuid", nil)
+	vm.partOps = partOps
+
+	updates, err := vm.updateDrivesCRs(testCtx, []*api.Drive{discovered})
+	assert.Nil(t, err)
+	assert.Len(t, updates.Created, 1)
+	oldDrive := &drivecrd.Drive{}
+	assert.Nil(t, vm.k8sClient.ReadCR(testCtx, drive2.UUID, "", oldDrive))
+	assert.Equal(t, oldNodeID, oldDrive.Spec.NodeId)
+
+	// drive of the live node isn't adopted
+	vm = prepareSuccessVolumeManager(t)
+	vm.nodeReplacement = true
+	discovered, _ = prepareReplacedNodeResources(t, vm, apiV1.DriveStatusOnline)
+	updates, err = vm.updateDrivesCRs(testCtx, []*api.Drive{discovered})
+	assert.Nil(t, err)
+	assert.Len(t, updates.Created, 1)
+	vol := &vcrd.Volume{}
+	assert.Nil(t, vm.k8sClient.ReadCR(testCtx, replacedVolID, testNs, vol))
+	assert.Equal(t, oldNodeID, vol.Spec.NodeId)
+
+	// feature is disabled
+	vm = prepareSuccessVolumeManager(t)
+	discovered, _ = prepareReplacedNodeResources(t, vm, apiV1.DriveStatusOffline)
+	updates, err = vm.updateDrivesCRs(testCtx, []*api.Drive{discovered})
+	assert.Nil(t, err)
+	assert.Len(t, updates.Created, 1)
+}
+
+func TestVolumeManager_verifyDataOnDrive_LVG(t *testing.T) {
+	vm := prepareSuccessVolumeManager(t)
+	lvmOps := &mocklu.MockWrapLVM{}
+	lvmOps.On("GetVGNameByPVName", "/dev/sdb").Return(testLVGName, nil).Times(2)
+	lvmOps.On("GetVGNameByPVName", "/dev/sdb").Return("another-vg", nil).Once()
+	lvmOps.On("GetLVsInVG", testLVGName).Return([]string{replacedVolID}, nil)
+	vm.lvmOps = lvmOps
+	lvg := vm.k8sClient.ConstructLVGCR(testLVGName, api.LogicalVolumeGroup{Name: testLVGName})
+	volumes := []*vcrd.Volume{vm.k8sClient.ConstructVolumeCR(replacedVolID, testNs, api.Volume{Id: replacedVolID})}
+
+	assert.Nil(t, vm.verifyDataOnDrive(testCtx, "/dev/sdb", lvg, volumes))
+	// LV of volume doesn't exist
+	volumes = append(volumes, vm.k8sClient.ConstructVolumeCR("pvc-another", testNs, api.Volume{Id: "pvc-another"}))
+	assert.NotNil(t, vm.verifyDataOnDrive(testCtx, "/dev/sdb", lvg, volumes))
+	// PV belongs to another VG
+	assert.NotNil(t, vm.verifyDataOnDrive(testCtx, "/dev/sdb", lvg, nil))
+}
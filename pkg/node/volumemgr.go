@@ -27,6 +27,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/api/trace"
 	k8sError "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/utils/keymutex"
@@ -42,6 +43,7 @@ import (
 	"github.com/dell/csi-baremetal/api/v1/lvgcrd"
 	"github.com/dell/csi-baremetal/api/v1/volumecrd"
 	"github.com/dell/csi-baremetal/pkg/base"
+	"github.com/dell/csi-baremetal/pkg/base/audit"
 	"github.com/dell/csi-baremetal/pkg/base/command"
 	"github.com/dell/csi-baremetal/pkg/base/k8s"
 	"github.com/dell/csi-baremetal/pkg/base/linuxutils/datadiscover"
@@ -49,6 +51,7 @@ import (
 	"github.com/dell/csi-baremetal/pkg/base/linuxutils/lsblk"
 	"github.com/dell/csi-baremetal/pkg/base/linuxutils/lvm"
 	ph "github.com/dell/csi-baremetal/pkg/base/linuxutils/partitionhelper"
+	"github.com/dell/csi-baremetal/pkg/base/tracing"
 	"github.com/dell/csi-baremetal/pkg/base/util"
 	"github.com/dell/csi-baremetal/pkg/common"
 	"github.com/dell/csi-baremetal/pkg/eventing"
@@ -118,6 +121,11 @@ type VolumeManager struct {
 
 	// discover data on drive
 	dataDiscover types.WrapDataDiscover
+
+	// whether volumes of LVG on SUSPECT drive are kept because drive is evacuated by drive controller
+	lvgEvacuation bool
+	// whether drives, volumes and LVGs of OFFLINE node are adopted when their drives are discovered on the current node
+	nodeReplacement bool
 }
 
 // driveStates internal struct, holds info about drive updates
@@ -255,6 +263,8 @@ func (m *VolumeManager) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 	if err != nil {
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
+	ctx, span := m.startReconcileSpan(ctx, volume)
+	defer span.End()
 	if volume.DeletionTimestamp.IsZero() {
 		if !util.ContainsString(volume.ObjectMeta.Finalizers, volumeFinalizer) && volume.Spec.CSIStatus != apiV1.Empty {
 			ll.Debug("Appending finalizer for volume")
@@ -334,6 +344,17 @@ func (m *VolumeManager) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 	return ctrl.Result{}, nil
 }
 
+// startReconcileSpan continues the trace of the operation requested by controller for the volume in progress.
+// Span isn't recorded for other statuses or if Volume CR doesn't hold trace context
+func (m *VolumeManager) startReconcileSpan(ctx context.Context, volume *volumecrd.Volume) (context.Context, trace.Span) {
+	switch volume.Spec.CSIStatus {
+	case apiV1.Creating, apiV1.Removing, apiV1.Resizing:
+		return tracing.StartChildSpan(tracing.Extract(ctx, volume), "VolumeManager.Reconcile",
+			tracing.VolumeIDKey.String(volume.Spec.Id), tracing.CSIStatusKey.String(volume.Spec.CSIStatus))
+	}
+	return ctx, trace.NoopSpan{}
+}
+
 func (m *VolumeManager) updateVolumeAndDriveUsageStatus(ctx context.Context, volume *volumecrd.Volume,
 	volumeStatus, driveStatus string) (ctrl.Result, error) {
 	ll := m.log.WithFields(logrus.Fields{
@@ -435,10 +456,11 @@ func (m *VolumeManager) prepareVolume(ctx context.Context, volume *volumecrd.Vol
 
 	newStatus := apiV1.Created
 
-	err := m.getProvisionerForVolume(&volume.Spec).PrepareVolume(volume.Spec)
+	err := m.getProvisionerForVolume(&volume.Spec).PrepareVolume(ctx, volume.Spec)
 	if err != nil {
 		ll.Errorf("Unable to create volume size of %d bytes: %v. Set volume status to Failed", volume.Spec.Size, err)
 		newStatus = apiV1.Failed
+		apiV1.SetFailedCondition(&volume.Status.Conditions, apiV1.ConditionReady, err)
 	}
 
 	volume.Spec.CSIStatus = newStatus
@@ -463,9 +485,10 @@ func (m *VolumeManager) handleRemovingStatus(ctx context.Context, volume *volume
 		err       error
 		newStatus string
 	)
-	if err = m.getProvisionerForVolume(&volume.Spec).ReleaseVolume(volume.Spec); err != nil {
+	if err = m.getProvisionerForVolume(&volume.Spec).ReleaseVolume(ctx, volume.Spec); err != nil {
 		ll.Errorf("Failed to remove volume - %s. Error: %v. Set status to Failed", volume.Spec.Id, err)
 		newStatus = apiV1.Failed
+		apiV1.SetFailedCondition(&volume.Status.Conditions, apiV1.ConditionReady, err)
 		drive := m.crHelper.GetDriveCRByUUID(volume.Spec.Location)
 		if drive != nil {
 			drive.Spec.Usage = apiV1.DriveUsageFailed
@@ -547,13 +570,13 @@ func (m *VolumeManager) Discover() error {
 	m.handleDriveUpdates(ctx, updates)
 
 	if m.discoverSystemLVG {
-		if err = m.discoverLVGOnSystemDrive(); err != nil {
+		if err = m.discoverLVGOnSystemDrive(ctx); err != nil {
 			m.log.WithField("method", "Discover").
 				Errorf("unable to inspect system LogicalVolumeGroup: %v", err)
 		}
 	}
 
-	if err = m.discoverDataOnDrives(); err != nil {
+	if err = m.discoverDataOnDrives(ctx); err != nil {
 		return fmt.Errorf("discoverDataOnDrives return error: %v", err)
 	}
 
@@ -625,6 +648,17 @@ func (m *VolumeManager) updateDrivesCRs(ctx context.Context, drivesFromMgr []*ap
 			if drivePtr.Status == apiV1.DriveStatusOffline {
 				continue
 			}
+			// drive might belong to the replaced node, try to adopt it with volumes
+			if m.nodeReplacement {
+				if previousState, adopted := m.adoptDrive(ctx, drivePtr); adopted != nil {
+					if searchSystemDrives && adopted.Spec.IsSystem {
+						m.systemDrivesUUIDs = append(m.systemDrivesUUIDs, adopted.Spec.UUID)
+					}
+					updates.AddUpdated(previousState, adopted)
+					driveCRs = append(driveCRs, *adopted)
+					continue
+				}
+			}
 			// drive CR does not exist, try to create it
 			toCreateSpec := *drivePtr
 			toCreateSpec.NodeId = m.nodeID
@@ -632,7 +666,7 @@ func (m *VolumeManager) updateDrivesCRs(ctx context.Context, drivesFromMgr []*ap
 			// TODO: what operational status should be if drivemgr reported drive with not a good health
 			toCreateSpec.Usage = apiV1.DriveUsageInUse
 			toCreateSpec.IsClean = true
-			isSystem, err := m.isDriveSystem(drivePtr.Path)
+			isSystem, err := m.isDriveSystem(ctx, drivePtr.Path)
 			if err != nil {
 				ll.Errorf("Failed to determine if drive %v is system, error: %v", drivePtr, err)
 			}
@@ -715,7 +749,7 @@ func (m *VolumeManager) isDriveInLVG(d api.Drive) bool {
 // discoverVolumeCRs matches system block devices with driveCRs
 // searches drives in driveCRs that are not have volume and if there are some partitions on them - try to read
 // partition uuid and create volume CR object
-func (m *VolumeManager) discoverDataOnDrives() error {
+func (m *VolumeManager) discoverDataOnDrives(ctx context.Context) error {
 	ll := m.log.WithFields(logrus.Fields{
 		"method": "discoverVolumeCRs",
 	})
@@ -747,7 +781,7 @@ func (m *VolumeManager) discoverDataOnDrives() error {
 			}
 			continue
 		}
-		if discoverResult, err = m.dataDiscover.DiscoverData(drive.Spec.Path, drive.Spec.SerialNumber); err != nil {
+		if discoverResult, err = m.dataDiscover.DiscoverData(ctx, drive.Spec.Path, drive.Spec.SerialNumber); err != nil {
 			ll.Errorf("Failed to discover data on drive %s, err: %v", drive.Spec.SerialNumber, err)
 			continue
 		}
@@ -772,7 +806,7 @@ func (m *VolumeManager) discoverDataOnDrives() error {
 // return nil in case of success. If system drive is not SSD or LogicalVolumeGroup CR that points in system VG is exists - return nil.
 // If system VG free space is less then threshold - AC CR will not be created but LogicalVolumeGroup will.
 // Returns error in case of error on any step
-func (m *VolumeManager) discoverLVGOnSystemDrive() error {
+func (m *VolumeManager) discoverLVGOnSystemDrive(ctx context.Context) error {
 	ll := m.log.WithField("method", "discoverLVGOnSystemDrive")
 
 	if len(m.systemDrivesUUIDs) == 0 {
@@ -797,7 +831,7 @@ func (m *VolumeManager) discoverLVGOnSystemDrive() error {
 	for _, lvg := range lvgs {
 		lvg := lvg
 		if lvg.Spec.Node == m.nodeID && len(lvg.Spec.Locations) > 0 && util.ContainsString(m.systemDrivesUUIDs, lvg.Spec.Locations[0]) {
-			if vgFreeSpace, err = m.lvmOps.GetVgFreeSpace(lvg.Spec.Name); err != nil {
+			if vgFreeSpace, err = m.lvmOps.GetVgFreeSpace(ctx, lvg.Spec.Name); err != nil {
 				return err
 			}
 			ll.Infof("LogicalVolumeGroup CR that points on system VG is exists: %v", lvg)
@@ -817,7 +851,7 @@ func (m *VolumeManager) discoverLVGOnSystemDrive() error {
 		return err
 	}
 
-	pvs, err := m.lvmOps.GetAllPVs()
+	pvs, err := m.lvmOps.GetAllPVs(ctx)
 	if err != nil {
 		return fmt.Errorf("unable to list PVs on the system: %v", err)
 	}
@@ -840,15 +874,15 @@ func (m *VolumeManager) discoverLVGOnSystemDrive() error {
 
 	// 4. search VG info
 	var vgName string
-	vgName, err = m.lvmOps.GetVGNameByPVName(systemPVName)
+	vgName, err = m.lvmOps.GetVGNameByPVName(ctx, systemPVName)
 	if err != nil {
 		return fmt.Errorf("unable to detect system VG name: %v", err)
 	}
 
-	if vgFreeSpace, err = m.lvmOps.GetVgFreeSpace(vgName); err != nil {
+	if vgFreeSpace, err = m.lvmOps.GetVgFreeSpace(ctx, vgName); err != nil {
 		return fmt.Errorf("unable to determine VG %s free space: %v", vgName, err)
 	}
-	lvs, err := m.lvmOps.GetLVsInVG(vgName)
+	lvs, err := m.lvmOps.GetLVsInVG(ctx, vgName)
 	if err != nil {
 		return fmt.Errorf("unable to determine LVs in system VG %s: %v", vgName, err)
 	}
@@ -865,15 +899,15 @@ func (m *VolumeManager) discoverLVGOnSystemDrive() error {
 			VolumeRefs: lvs,
 			Health:     apiV1.HealthGood,
 		}
-		vgCR = m.k8sClient.ConstructLVGCR(vgCRName, vg)
-		ctx  = context.WithValue(context.Background(), base.RequestUUID, vg.Name)
+		vgCR      = m.k8sClient.ConstructLVGCR(vgCRName, vg)
+		ctxWithID = context.WithValue(ctx, base.RequestUUID, vg.Name)
 	)
 	m.updateLVGAnnotation(vgCR, vgFreeSpace)
-	if err = m.k8sClient.CreateCR(ctx, vg.Name, vgCR); err != nil {
+	if err = m.k8sClient.CreateCR(ctxWithID, vg.Name, vgCR); err != nil {
 		return fmt.Errorf("unable to create LogicalVolumeGroup CR %v, error: %v", vgCR, err)
 	}
 	driveCR.Spec.IsClean = false
-	if err = m.k8sClient.UpdateCR(ctx, driveCR); err != nil {
+	if err = m.k8sClient.UpdateCR(ctxWithID, driveCR); err != nil {
 		return fmt.Errorf("unable to update Drive CR %v, error: %v", driveCR, err)
 	}
 	return nil
@@ -917,6 +951,10 @@ func (m *VolumeManager) handleDriveStatusChange(ctx context.Context, drive *api.
 		if err := m.k8sClient.UpdateCR(ctx, lvg); err != nil {
 			ll.Errorf("Failed to update lvg CR's %s health status: %v", lvg.Name, err)
 		}
+		if m.isEvacuated(ctx, drive, lvg) {
+			ll.Infof("Data of LVG %s is moved from the drive, volumes are handled by drive controller", lvg.Name)
+			return
+		}
 	} else {
 		errMsg := "Failed get LogicalVolumeGroup CR"
 		if err != nil {
@@ -954,6 +992,23 @@ func (m *VolumeManager) handleDriveStatusChange(ctx context.Context, drive *api.
 	// TODO: Handle disk health which are used by LVGs - https://github.com/dell/csi-baremetal/issues/88
 }
 
+// isEvacuated returns true if data of SUSPECT drive is moved to another PVs of LVG by drive controller.
+// Evacuation is started for SUSPECT drive of LVG with several PVs. If drive becomes BAD during evacuation,
+// drive controller aborts it and releases volumes itself, so volumes are kept while evacuation is in progress
+func (m *VolumeManager) isEvacuated(ctx context.Context, drive *api.Drive, lvg *lvgcrd.LogicalVolumeGroup) bool {
+	if !m.lvgEvacuation || drive.IsSystem || len(lvg.Spec.Locations) < 2 {
+		return false
+	}
+	if drive.Health == apiV1.HealthSuspect {
+		return true
+	}
+	driveCR := &drivecrd.Drive{}
+	if err := m.k8sClient.ReadCR(ctx, drive.UUID, "", driveCR); err != nil {
+		return false
+	}
+	return driveCR.Annotations[apiV1.DriveAnnotationEvacuation] == apiV1.EvacuationInProgress
+}
+
 // drivesAreTheSame check whether two drive represent same node drive or no
 // method is rely on that each drive could be uniquely identified by it VID/PID/Serial Number
 func (m *VolumeManager) drivesAreTheSame(drive1, drive2 *api.Drive) bool {
@@ -1024,6 +1079,32 @@ func (m *VolumeManager) createEventForDriveStatusChange(
 		statusMsgTemplate, currentStatus, prevStatus)
 }
 
+// SendAuditEvent mirrors entry of the audit log of destructive operations as event of the affected Drive CRs,
+// entries without drive serial number are skipped. It is registered as audit.Sink
+func (m *VolumeManager) SendAuditEvent(entry audit.Entry) {
+	serials := entry.DriveSerials()
+	if len(serials) == 0 {
+		return
+	}
+	drives, err := m.cachedCrHelper.GetDriveCRs(m.nodeID)
+	if err != nil {
+		m.log.WithField("method", "SendAuditEvent").Errorf("Unable to read Drive CRs: %v", err)
+		return
+	}
+	for i := range drives {
+		if !util.ContainsString(serials, drives[i].Spec.SerialNumber) {
+			continue
+		}
+		eventType := eventing.NormalType
+		if entry.Result == audit.ResultFailure {
+			eventType = eventing.WarningType
+		}
+		m.sendEventForDrive(&drives[i], eventType, eventing.DriveDestructiveOperation,
+			"Command '%s' finished with result %s, volume ID '%s', request '%s'. ",
+			entry.Command, entry.Result, entry.VolumeID, entry.RequestUUID)
+	}
+}
+
 func (m *VolumeManager) sendEventForDrive(drive *drivecrd.Drive, eventtype, reason, messageFmt string,
 	args ...interface{}) {
 	messageFmt += drive.GetDriveDescription()
@@ -1033,8 +1114,8 @@ func (m *VolumeManager) sendEventForDrive(drive *drivecrd.Drive, eventtype, reas
 // isDriveSystem check whether drive is system
 // Parameters: path string - drive path
 // Returns true if drive is system, false in opposite; error
-func (m *VolumeManager) isDriveSystem(path string) (bool, error) {
-	devices, err := m.listBlk.GetBlockDevices(path)
+func (m *VolumeManager) isDriveSystem(ctx context.Context, path string) (bool, error) {
+	devices, err := m.listBlk.GetBlockDevices(ctx, path)
 	if err != nil {
 		return false, err
 	}
@@ -1075,12 +1156,12 @@ func (m *VolumeManager) handleExpandingStatus(ctx context.Context, volume *volum
 	ll := m.log.WithFields(logrus.Fields{
 		"method": "handleExpandingStatus",
 	})
-	volumePath, err := m.provisioners[p.LVMBasedVolumeType].GetVolumePath(volume.Spec)
+	volumePath, err := m.provisioners[p.LVMBasedVolumeType].GetVolumePath(ctx, volume.Spec)
 	if err != nil {
 		ll.Errorf("Failed to get volume path, err: %v", err)
 		return ctrl.Result{Requeue: true}, err
 	}
-	if err = m.lvmOps.ExpandLV(volumePath, volume.Spec.Size); err != nil {
+	if err = m.lvmOps.ExpandLV(ctx, volumePath, volume.Spec.Size); err != nil {
 		volume.Spec.CSIStatus = apiV1.Failed
 	} else {
 		volume.Spec.CSIStatus = apiV1.Resized
@@ -1098,7 +1179,7 @@ func (m *VolumeManager) changeDriveIsCleanField(drive *drivecrd.Drive, clean boo
 	})
 	drive.Spec.IsClean = clean
 	ctxWithID := context.WithValue(context.Background(), base.RequestUUID, drive.Name)
-	if err := m.k8sClient.Update(ctxWithID, drive); err != nil {
+	if err := m.k8sClient.UpdateCR(ctxWithID, drive); err != nil {
 		ll.Errorf("Unable to update drive CR %s: %v", drive.Name, err)
 	}
 }
@@ -40,6 +40,7 @@ import (
 	"github.com/dell/csi-baremetal/api/v1/lvgcrd"
 	vcrd "github.com/dell/csi-baremetal/api/v1/volumecrd"
 	"github.com/dell/csi-baremetal/pkg/base"
+	"github.com/dell/csi-baremetal/pkg/base/audit"
 	"github.com/dell/csi-baremetal/pkg/base/k8s"
 	dataDiscover "github.com/dell/csi-baremetal/pkg/base/linuxutils/datadiscover/types"
 	"github.com/dell/csi-baremetal/pkg/base/linuxutils/fs"
@@ -813,6 +814,38 @@ func TestVolumeManager_handleDriveStatusChange(t *testing.T) {
 	err = vm.k8sClient.ReadCR(testCtx, testLVGName, "", updatedLVG)
 	assert.Nil(t, err)
 	assert.Equal(t, apiV1.HealthBad, updatedLVG.Spec.Health)
+
+	// volumes of SUSPECT drive of LVG with several PVs aren't released if drive is evacuated
+	vm.lvgEvacuation = true
+	updatedLVG.Spec.Locations = []string{driveUUID, "another-drive"}
+	assert.Nil(t, vm.k8sClient.UpdateCR(testCtx, updatedLVG))
+	vol.Spec.Location = lvg.Name
+	vol.Spec.Health = apiV1.HealthGood
+	vol.Spec.Usage = apiV1.VolumeUsageInUse
+	assert.Nil(t, vm.k8sClient.UpdateCR(testCtx, &vol))
+	drive.Health = apiV1.HealthSuspect
+	drive.IsSystem = false
+	vm.handleDriveStatusChange(testCtx, &drive)
+	assert.Nil(t, vm.k8sClient.ReadCR(testCtx, testID, volCR.Namespace, rVolume))
+	assert.Equal(t, apiV1.VolumeUsageInUse, rVolume.Spec.Usage)
+	assert.Nil(t, vm.k8sClient.ReadCR(testCtx, testLVGName, "", updatedLVG))
+	assert.Equal(t, apiV1.HealthSuspect, updatedLVG.Spec.Health)
+
+	// drive becomes BAD during evacuation, volumes are released by drive controller
+	driveCR := vm.k8sClient.ConstructDriveCR(driveUUID, drive)
+	driveCR.Annotations = map[string]string{apiV1.DriveAnnotationEvacuation: apiV1.EvacuationInProgress}
+	assert.Nil(t, vm.k8sClient.CreateCR(testCtx, driveCR.Name, driveCR))
+	drive.Health = apiV1.HealthBad
+	vm.handleDriveStatusChange(testCtx, &drive)
+	assert.Nil(t, vm.k8sClient.ReadCR(testCtx, testID, volCR.Namespace, rVolume))
+	assert.Equal(t, apiV1.VolumeUsageInUse, rVolume.Spec.Usage)
+
+	// evacuation isn't in progress, volumes are released
+	driveCR.Annotations = nil
+	assert.Nil(t, vm.k8sClient.UpdateCR(testCtx, driveCR))
+	vm.handleDriveStatusChange(testCtx, &drive)
+	assert.Nil(t, vm.k8sClient.ReadCR(testCtx, testID, volCR.Namespace, rVolume))
+	assert.Equal(t, apiV1.VolumeUsageReleasing, rVolume.Spec.Usage)
 }
 
 func Test_discoverLVGOnSystemDrive_LVGAlreadyExists(t *testing.T) {
@@ -834,7 +867,7 @@ func Test_discoverLVGOnSystemDrive_LVGAlreadyExists(t *testing.T) {
 	err = m.k8sClient.CreateCR(testCtx, lvgCR.Name, lvgCR)
 	assert.Nil(t, err)
 
-	err = m.discoverLVGOnSystemDrive()
+	err = m.discoverLVGOnSystemDrive(testCtx)
 	assert.Nil(t, err)
 
 	err = m.k8sClient.ReadList(testCtx, &lvgList)
@@ -850,7 +883,7 @@ func Test_discoverLVGOnSystemDrive_LVGAlreadyExists(t *testing.T) {
 	err = m.k8sClient.CreateCR(testCtx, lvgCR.Name, lvgCR)
 	assert.Nil(t, err)
 
-	err = m.discoverLVGOnSystemDrive()
+	err = m.discoverLVGOnSystemDrive(testCtx)
 	assert.Nil(t, err)
 
 	err = m.k8sClient.ReadList(testCtx, &lvgList)
@@ -886,7 +919,7 @@ func Test_discoverLVGOnSystemDrive_LVGCreatedACNo(t *testing.T) {
 
 	// expect success, LogicalVolumeGroup CR and AC CR was created
 	m.systemDrivesUUIDs = append(m.systemDrivesUUIDs, systemDriveCR.Spec.UUID)
-	err = m.discoverLVGOnSystemDrive()
+	err = m.discoverLVGOnSystemDrive(testCtx)
 	assert.Nil(t, err)
 
 	err = m.k8sClient.ReadList(testCtx, &lvgList)
@@ -910,7 +943,7 @@ func Test_discoverLVGOnSystemDrive_LVGCreatedACNo(t *testing.T) {
 	assert.Nil(t, m.k8sClient.CreateCR(testCtx, systemDriveCR.Name, &systemDriveCR))
 	m.systemDrivesUUIDs = append(m.systemDrivesUUIDs, systemDriveCR.Spec.UUID)
 
-	err = m.discoverLVGOnSystemDrive()
+	err = m.discoverLVGOnSystemDrive(testCtx)
 	assert.NotNil(t, err)
 	assert.Contains(t, err.Error(), "unable to determine LVs in system VG")
 
@@ -1106,7 +1139,7 @@ func TestVolumeManager_discoverDataOnDrives(t *testing.T) {
 		err := vm.k8sClient.CreateCR(testCtx, testDrive.Name, &testDrive)
 		assert.Nil(t, err)
 
-		err = vm.discoverDataOnDrives()
+		err = vm.discoverDataOnDrives(testCtx)
 		assert.Nil(t, err)
 
 		newDrive := &drivecrd.Drive{}
@@ -1134,7 +1167,7 @@ func TestVolumeManager_discoverDataOnDrives(t *testing.T) {
 		err := vm.k8sClient.CreateCR(testCtx, testDrive.Name, &testDrive)
 		assert.Nil(t, err)
 
-		err = vm.discoverDataOnDrives()
+		err = vm.discoverDataOnDrives(testCtx)
 		assert.Nil(t, err)
 
 		newDrive := &drivecrd.Drive{}
@@ -1157,7 +1190,7 @@ func TestVolumeManager_discoverDataOnDrives(t *testing.T) {
 		err := vm.k8sClient.CreateCR(testCtx, testDrive.Name, &testDrive)
 		assert.Nil(t, err)
 
-		err = vm.discoverDataOnDrives()
+		err = vm.discoverDataOnDrives(testCtx)
 		assert.Nil(t, err)
 		newDrive := &drivecrd.Drive{}
 		err = vm.k8sClient.ReadCR(testCtx, testDriveCR.Name, "", newDrive)
@@ -1181,7 +1214,7 @@ func TestVolumeManager_discoverDataOnDrives(t *testing.T) {
 		err := vm.k8sClient.CreateCR(testCtx, testDrive.Name, &testDrive)
 		assert.Nil(t, err)
 
-		err = vm.discoverDataOnDrives()
+		err = vm.discoverDataOnDrives(testCtx)
 		assert.Nil(t, err)
 		newDrive := &drivecrd.Drive{}
 		err = vm.k8sClient.ReadCR(testCtx, testDriveCR.Name, "", newDrive)
@@ -1247,27 +1280,55 @@ func TestVolumeManager_isDriveSystem(t *testing.T) {
 	vm := NewVolumeManager(hwMgrClient, nil, testLogger, kubeClient, kubeClient, new(mocks.NoOpRecorder), nodeID)
 	listBlk.On("GetBlockDevices", drive2.Path).Return([]lsblk.BlockDevice{bdev1}, nil).Once()
 	vm.listBlk = listBlk
-	isSystem, err := vm.isDriveSystem("/dev/sdb")
+	isSystem, err := vm.isDriveSystem(testCtx, "/dev/sdb")
 	assert.Nil(t, err)
 	assert.Equal(t, false, isSystem)
 
 	bdev1.MountPoint = base.KubeletRootPath
 	listBlk.On("GetBlockDevices", drive2.Path).Return([]lsblk.BlockDevice{bdev1}, nil).Once()
 	vm.listBlk = listBlk
-	isSystem, err = vm.isDriveSystem("/dev/sdb")
+	isSystem, err = vm.isDriveSystem(testCtx, "/dev/sdb")
 	assert.Nil(t, err)
 	assert.Equal(t, isSystem, true)
 
 	bdev1.MountPoint = base.HostRootPath
 	listBlk.On("GetBlockDevices", drive2.Path).Return([]lsblk.BlockDevice{bdev1}, nil).Once()
 	vm.listBlk = listBlk
-	isSystem, err = vm.isDriveSystem("/dev/sdb")
+	isSystem, err = vm.isDriveSystem(testCtx, "/dev/sdb")
 	assert.Nil(t, err)
 	assert.Equal(t, isSystem, true)
 
 	listBlk.On("GetBlockDevices", drive2.Path).Return([]lsblk.BlockDevice{bdev1}, testErr).Once()
 	vm.listBlk = listBlk
-	isSystem, err = vm.isDriveSystem("/dev/sdb")
+	isSystem, err = vm.isDriveSystem(testCtx, "/dev/sdb")
 	assert.NotNil(t, err)
 	assert.Equal(t, isSystem, false)
 }
+
+func TestVolumeManager_SendAuditEvent(t *testing.T) {
+	kubeClient, err := k8s.GetFakeKubeClient(testNs, testLogger)
+	assert.Nil(t, err)
+	rec := new(mocks.NoOpRecorder)
+	vm := NewVolumeManager(nil, nil, testLogger, kubeClient, kubeClient, rec, nodeID)
+
+	driveCR := kubeClient.ConstructDriveCR(drive1UUID, *getTestDrive(drive1UUID, "SN1"))
+	assert.Nil(t, kubeClient.CreateCR(testCtx, driveCR.Name, driveCR))
+
+	// entries without drive or with unknown drive are skipped
+	vm.SendAuditEvent(audit.Entry{Command: "lvremove -fy vg/lv", Result: audit.ResultSuccess})
+	vm.SendAuditEvent(audit.Entry{DriveSerial: "SN2", Command: "wipefs -af /dev/sdb", Result: audit.ResultSuccess})
+	assert.Empty(t, rec.Calls)
+
+	vm.SendAuditEvent(audit.Entry{DriveSerial: "SN1", Command: "wipefs -af /dev/sda", Result: audit.ResultFailure})
+	assert.Equal(t, 1, len(rec.Calls))
+	assert.Equal(t, driveCR.Name, rec.Calls[0].Object.(*drivecrd.Drive).Name)
+	assert.Equal(t, eventing.WarningType, rec.Calls[0].Eventtype)
+	assert.Equal(t, eventing.DriveDestructiveOperation, rec.Calls[0].Reason)
+
+	// command on VG is sent to each drive of the VG
+	driveCR2 := kubeClient.ConstructDriveCR(drive2UUID, *getTestDrive(drive2UUID, "SN2"))
+	assert.Nil(t, kubeClient.CreateCR(testCtx, driveCR2.Name, driveCR2))
+	vm.SendAuditEvent(audit.Entry{DriveSerial: audit.DrivesTarget("SN1", "SN2").DriveSerial,
+		Command: "vgremove -y vg", Result: audit.ResultSuccess})
+	assert.Equal(t, 3, len(rec.Calls))
+}
@@ -42,8 +42,10 @@ import (
 	"github.com/dell/csi-baremetal/pkg/base/capacityplanner"
 	fc "github.com/dell/csi-baremetal/pkg/base/featureconfig"
 	"github.com/dell/csi-baremetal/pkg/base/k8s"
+	"github.com/dell/csi-baremetal/pkg/base/quota"
 	"github.com/dell/csi-baremetal/pkg/base/util"
 	annotations "github.com/dell/csi-baremetal/pkg/crcontrollers/operator/common"
+	"github.com/dell/csi-baremetal/pkg/metrics/common"
 )
 
 // Extender holds http handlers for scheduler extender endpoints and implements logic for nodes filtering
@@ -59,6 +61,7 @@ type Extender struct {
 	sync.Mutex
 	logger                 *logrus.Entry
 	capacityManagerBuilder capacityplanner.CapacityManagerBuilder
+	scoringStrategy        ScoringStrategy
 }
 
 // NewExtender returns new instance of Extender struct
@@ -72,9 +75,15 @@ func NewExtender(logger *logrus.Logger, kubeClient *k8s.KubeClient,
 		annotationKey:          annotationKey,
 		logger:                 logger.WithField("component", "Extender"),
 		capacityManagerBuilder: &capacityplanner.DefaultCapacityManagerBuilder{},
+		scoringStrategy:        VolumeCountStrategy,
 	}, nil
 }
 
+// SetScoringStrategy sets strategy which is used for nodes ranking on prioritize stage
+func (e *Extender) SetScoringStrategy(strategy ScoringStrategy) {
+	e.scoringStrategy = strategy
+}
+
 // FilterHandler extracts ExtenderArgs struct from req and writes ExtenderFilterResult to the w
 func (e *Extender) FilterHandler(w http.ResponseWriter, req *http.Request) {
 	sessionUUID := uuid.New().String()
@@ -83,6 +92,7 @@ func (e *Extender) FilterHandler(w http.ResponseWriter, req *http.Request) {
 		"method":      "FilterHandler",
 	})
 	ll.Infof("Processing request: %v", req)
+	defer common.SchedulingDuration.EvaluateDurationForMethod("extender_filter")()
 
 	w.Header().Set("Content-Type", "application/json")
 	resp := json.NewEncoder(w)
@@ -104,7 +114,7 @@ func (e *Extender) FilterHandler(w http.ResponseWriter, req *http.Request) {
 	ll.Info("Filtering")
 	ctxWithVal := context.WithValue(req.Context(), base.RequestUUID, sessionUUID)
 	pod := extenderArgs.Pod
-	requests, err := e.gatherCapacityRequestsByProvisioner(ctxWithVal, pod)
+	requests, err := e.GatherCapacityRequestsByProvisioner(ctxWithVal, pod)
 	if err != nil {
 		extenderRes.Error = err.Error()
 		if err := resp.Encode(extenderRes); err != nil {
@@ -145,6 +155,7 @@ func (e *Extender) PrioritizeHandler(w http.ResponseWriter, req *http.Request) {
 		"method":      "PrioritizeHandler",
 	})
 	ll.Infof("Processing request: %v", req)
+	defer common.SchedulingDuration.EvaluateDurationForMethod("extender_prioritize")()
 
 	w.Header().Set("Content-Type", "application/json")
 	resp := json.NewEncoder(w)
@@ -163,7 +174,8 @@ func (e *Extender) PrioritizeHandler(w http.ResponseWriter, req *http.Request) {
 	e.Lock()
 	defer e.Unlock()
 
-	hostPriority, err := e.score(extenderArgs.Nodes.Items)
+	ctxWithVal := context.WithValue(req.Context(), base.RequestUUID, sessionUUID)
+	hostPriority, err := e.score(ctxWithVal, extenderArgs.Pod, extenderArgs.Nodes.Items)
 	if err != nil {
 		ll.Errorf("Unable to score %v", err)
 		return
@@ -209,12 +221,12 @@ func (e *Extender) BindHandler(w http.ResponseWriter, req *http.Request) {
 	}
 }
 
-// gatherCapacityRequestsByProvisioner search all volumes in pod' spec that should be provisioned
+// GatherCapacityRequestsByProvisioner search all volumes in pod' spec that should be provisioned
 // by provisioner e.provisioner and construct genV1.Volume struct for each of such volume
-func (e *Extender) gatherCapacityRequestsByProvisioner(ctx context.Context, pod *coreV1.Pod) ([]*genV1.CapacityRequest, error) {
+func (e *Extender) GatherCapacityRequestsByProvisioner(ctx context.Context, pod *coreV1.Pod) ([]*genV1.CapacityRequest, error) {
 	ll := e.logger.WithFields(logrus.Fields{
 		"sessionUUID": ctx.Value(base.RequestUUID),
-		"method":      "gatherCapacityRequestsByProvisioner",
+		"method":      "GatherCapacityRequestsByProvisioner",
 		"pod":         pod.Name,
 	})
 
@@ -324,15 +336,27 @@ func (e *Extender) filter(ctx context.Context, pod *coreV1.Pod, nodes []coreV1.N
 		return nodes, nil, nil
 	}
 
+	// reject all nodes when requested storage exceeds StorageQuota of the namespace
+	if err = e.CheckStorageQuota(ctx, pod, capacities); err != nil {
+		if _, ok := err.(*quota.ExceededError); !ok {
+			return nil, nil, err
+		}
+		filteredNodes = schedulerapi.FailedNodesMap{}
+		for _, node := range nodes {
+			filteredNodes[node.Name] = err.Error()
+		}
+		return nil, filteredNodes, nil
+	}
+
 	// construct ACR name
-	reservationName := getReservationName(pod)
+	reservationName := GetReservationName(pod)
 	// read reservation
 	reservation := &acrcrd.AvailableCapacityReservation{}
 	err = e.k8sClient.ReadCR(ctx, reservationName, "", reservation)
 	if err != nil {
 		if k8serrors.IsNotFound(err) {
 			// create new reservation
-			if err := e.createReservation(ctx, pod.Namespace, reservationName, nodes, capacities); err != nil {
+			if err := e.createReservation(ctx, pod, reservationName, nodes, capacities); err != nil {
 				// cannot create reservation
 				return nil, nil, err
 			}
@@ -347,7 +371,18 @@ func (e *Extender) filter(ctx context.Context, pod *coreV1.Pod, nodes []coreV1.N
 	return e.handleReservation(ctx, reservation, nodes)
 }
 
-func getReservationName(pod *coreV1.Pod) string {
+// CheckStorageQuota returns *quota.ExceededError if capacity requests of the pod exceed StorageQuota of its namespace
+// Requested size is used for the check, volume on the whole drive is checked with its real size by CreateVolume
+func (e *Extender) CheckStorageQuota(ctx context.Context, pod *coreV1.Pod, capacities []*genV1.CapacityRequest) error {
+	requests := make([]quota.Request, len(capacities))
+	for i, capacity := range capacities {
+		requests[i] = quota.Request{StorageClass: capacity.GetStorageClass(), Bytes: capacity.GetSize(), Volumes: 1}
+	}
+	return quota.NewChecker(e.k8sCache, e.logger.WithField("pod", pod.Name)).Check(ctx, pod.Namespace, requests)
+}
+
+// GetReservationName returns name of ACR which holds reservation for the pod volumes
+func GetReservationName(pod *coreV1.Pod) string {
 	namespace := pod.Namespace
 	if namespace == "" {
 		namespace = "default"
@@ -356,11 +391,31 @@ func getReservationName(pod *coreV1.Pod) string {
 	return namespace + "-" + pod.Name
 }
 
-func (e *Extender) createReservation(ctx context.Context, namespace string, name string, nodes []coreV1.Node,
+// GetReservationAnnotations returns annotations which link ACR to the pod, they are used by reservation GC,
+// volume group of the pod is passed to CreateVolume through them as well
+func GetReservationAnnotations(pod *coreV1.Pod) map[string]string {
+	namespace := pod.Namespace
+	if namespace == "" {
+		namespace = "default"
+	}
+
+	result := map[string]string{
+		v1.ReservationAnnotationPodName:      pod.Name,
+		v1.ReservationAnnotationPodNamespace: namespace,
+		v1.ReservationAnnotationPodUID:       string(pod.UID),
+	}
+	// volume group of the pod is passed to CreateVolume through reservation
+	if group, ok := pod.Labels[v1.VolumeGroupKey]; ok {
+		result[v1.VolumeGroupKey] = group
+	}
+	return result
+}
+
+func (e *Extender) createReservation(ctx context.Context, pod *coreV1.Pod, name string, nodes []coreV1.Node,
 	capacities []*genV1.CapacityRequest) error {
 	// ACR CRD
 	reservation := genV1.AvailableCapacityReservation{
-		Namespace: namespace,
+		Namespace: pod.Namespace,
 		Status:    v1.ReservationRequested,
 	}
 
@@ -384,7 +439,7 @@ func (e *Extender) createReservation(ctx context.Context, namespace string, name
 			Kind:       v1.AvailableCapacityReservationKind,
 			APIVersion: v1.APIV1Version,
 		},
-		ObjectMeta: metav1.ObjectMeta{Name: name},
+		ObjectMeta: metav1.ObjectMeta{Name: name, Annotations: GetReservationAnnotations(pod)},
 		Spec:       reservation,
 	}
 
@@ -481,13 +536,27 @@ func (e *Extender) resendReservationRequest(ctx context.Context, reservation *ac
 	return nil
 }
 
-func (e *Extender) score(nodes []coreV1.Node) ([]schedulerapi.HostPriority, error) {
+func (e *Extender) score(ctx context.Context, pod *coreV1.Pod, nodes []coreV1.Node) ([]schedulerapi.HostPriority, error) {
 	ll := e.logger.WithFields(logrus.Fields{
-		"method": "score",
+		"sessionUUID": ctx.Value(base.RequestUUID),
+		"method":      "score",
+		"strategy":    e.scoringStrategy,
 	})
 
+	if e.scoringStrategy != VolumeCountStrategy && pod != nil {
+		hostPriority, err := e.scoreByCapacity(ctx, pod, nodes)
+		switch {
+		case err != nil:
+			ll.Warningf("Unable to rank nodes by capacity, fallback to %s strategy: %v", VolumeCountStrategy, err)
+		case hostPriority != nil:
+			return hostPriority, nil
+		default:
+			ll.Debugf("Pod %s doesn't have confirmed reservation, fallback to %s strategy", pod.Name, VolumeCountStrategy)
+		}
+	}
+
 	var volumeList = &volcrd.VolumeList{}
-	if err := e.k8sCache.ReadList(context.Background(), volumeList); err != nil {
+	if err := e.k8sCache.ReadList(ctx, volumeList); err != nil {
 		err = fmt.Errorf("unable to read volumes list: %v", err)
 		return nil, err
 	}
@@ -534,6 +603,11 @@ func nodeVolumeCountMapping(vollist *volcrd.VolumeList) map[string][]volcrd.Volu
 	return nodeMapping
 }
 
+// NodeVolumePriorities ranks nodes by count of volumes on them, see nodePrioritize for details
+func NodeVolumePriorities(volumeList *volcrd.VolumeList) (map[string]int, int) {
+	return nodePrioritize(nodeVolumeCountMapping(volumeList))
+}
+
 // nodePrioritize will set priority for nodes and also return the maximum priority
 func nodePrioritize(nodeMapping map[string][]volcrd.Volume) (map[string]int, int) {
 	var maxCount int
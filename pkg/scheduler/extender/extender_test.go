@@ -34,6 +34,7 @@ import (
 	genV1 "github.com/dell/csi-baremetal/api/generated/v1"
 	v1 "github.com/dell/csi-baremetal/api/v1"
 	acrcrd "github.com/dell/csi-baremetal/api/v1/acreservationcrd"
+	sqcrd "github.com/dell/csi-baremetal/api/v1/storagequotacrd"
 	volcrd "github.com/dell/csi-baremetal/api/v1/volumecrd"
 	"github.com/dell/csi-baremetal/pkg/base"
 	"github.com/dell/csi-baremetal/pkg/base/capacityplanner"
@@ -144,7 +145,7 @@ func TestExtender_gatherVolumesByProvisioner_Success(t *testing.T) {
 	// create PVCs and SC
 	applyObjs(t, e.k8sClient, &testPVC1, &testPVC2, &testSC1)
 
-	volumes, err := e.gatherCapacityRequestsByProvisioner(testCtx, &pod)
+	volumes, err := e.GatherCapacityRequestsByProvisioner(testCtx, &pod)
 	assert.Nil(t, err)
 	assert.Equal(t, 2, len(volumes))
 }
@@ -154,7 +155,7 @@ func TestExtender_gatherVolumesByProvisioner_Fail(t *testing.T) {
 
 	// sc mapping empty
 	pod := testPod
-	volumes, err := e.gatherCapacityRequestsByProvisioner(testCtx, &pod)
+	volumes, err := e.GatherCapacityRequestsByProvisioner(testCtx, &pod)
 	assert.Nil(t, volumes)
 	assert.NotNil(t, err)
 
@@ -169,7 +170,7 @@ func TestExtender_gatherVolumesByProvisioner_Fail(t *testing.T) {
 	// create SC
 	applyObjs(t, e.k8sClient, &testSC1)
 
-	volumes, err = e.gatherCapacityRequestsByProvisioner(testCtx, &pod)
+	volumes, err = e.GatherCapacityRequestsByProvisioner(testCtx, &pod)
 	assert.Nil(t, err)
 	assert.Equal(t, 1, len(volumes))
 	//assert.True(t, volumes[0].Ephemeral)
@@ -186,7 +187,7 @@ func TestExtender_gatherVolumesByProvisioner_Fail(t *testing.T) {
 			},
 		},
 	})
-	volumes, err = e.gatherCapacityRequestsByProvisioner(testCtx, &pod)
+	volumes, err = e.GatherCapacityRequestsByProvisioner(testCtx, &pod)
 	assert.Nil(t, volumes)
 	assert.NotNil(t, err)
 
@@ -204,7 +205,7 @@ func TestExtender_gatherVolumesByProvisioner_Fail(t *testing.T) {
 		},
 	}}
 
-	volumes, err = e.gatherCapacityRequestsByProvisioner(testCtx, &pod)
+	volumes, err = e.GatherCapacityRequestsByProvisioner(testCtx, &pod)
 	assert.Nil(t, err)
 	assert.NotNil(t, volumes)
 	assert.Equal(t, 1, len(volumes))
@@ -299,7 +300,7 @@ func TestExtender_filterSuccess(t *testing.T) {
 	capacities := make([]*genV1.CapacityRequest, 1)
 
 	// reservation requested
-	reservation := *e.k8sClient.ConstructACRCR(getReservationName(pod), genV1.AvailableCapacityReservation{
+	reservation := *e.k8sClient.ConstructACRCR(GetReservationName(pod), genV1.AvailableCapacityReservation{
 		Status: v1.ReservationRequested})
 	assert.Nil(t, e.k8sClient.Create(testCtx, &reservation))
 
@@ -436,6 +437,34 @@ func TestExtender_filterSuccess(t *testing.T) {
 	}*/
 }
 
+func TestExtender_filterStorageQuota(t *testing.T) {
+	e := setup(t)
+	nodes := []coreV1.Node{
+		{ObjectMeta: metaV1.ObjectMeta{UID: "node-1-uid", Name: "node-1"}},
+		{ObjectMeta: metaV1.ObjectMeta{UID: "node-2-uid", Name: "node-2"}},
+	}
+	pod := &coreV1.Pod{ObjectMeta: metaV1.ObjectMeta{Name: "pod", Namespace: testNs}}
+	capacities := []*genV1.CapacityRequest{{Name: "pvc", StorageClass: v1.StorageClassHDD, Size: 2048}}
+
+	maxBytes := resource.MustParse("1Ki")
+	storageQuota := &sqcrd.StorageQuota{
+		TypeMeta:   metaV1.TypeMeta{Kind: v1.StorageQuotaKind, APIVersion: v1.APIV1Version},
+		ObjectMeta: metaV1.ObjectMeta{Name: "quota"},
+		Spec:       sqcrd.StorageQuotaSpec{Namespace: testNs, MaxBytes: &maxBytes},
+	}
+	assert.Nil(t, e.k8sClient.Create(testCtx, storageQuota))
+
+	matched, failed, err := e.filter(testCtx, pod, nodes, capacities)
+	assert.Nil(t, err)
+	assert.Nil(t, matched)
+	assert.Equal(t, len(nodes), len(failed))
+	assert.Contains(t, failed["node-1"], "storage quota quota")
+
+	// reservation isn't created for rejected pod
+	reservation := &acrcrd.AvailableCapacityReservation{}
+	assert.NotNil(t, e.k8sCache.ReadCR(testCtx, GetReservationName(pod), "", reservation))
+}
+
 func TestExtender_getSCNameStorageType_Success(t *testing.T) {
 	e := setup(t)
 	// create 2 storage classes
@@ -589,11 +618,11 @@ func Test_reservationName(t *testing.T) {
 	podName := "mypod-0"
 	namespace := "mynamespace"
 	pod := &coreV1.Pod{ObjectMeta: metaV1.ObjectMeta{Name: podName, Namespace: namespace}}
-	name := getReservationName(pod)
+	name := GetReservationName(pod)
 	assert.Equal(t, namespace+"-"+podName, name)
 
 	pod = &coreV1.Pod{ObjectMeta: metaV1.ObjectMeta{Name: podName, Namespace: ""}}
-	name = getReservationName(pod)
+	name = GetReservationName(pod)
 	assert.Equal(t, "default-"+podName, name)
 
 }
@@ -603,14 +632,14 @@ func Test_createReservation(t *testing.T) {
 	namespace := "test"
 	podName := "mypod-0"
 	pod := &coreV1.Pod{ObjectMeta: metaV1.ObjectMeta{Name: podName, Namespace: namespace}}
-	name := getReservationName(pod)
+	name := GetReservationName(pod)
 	// volumes
 	capacityRequests := []*genV1.CapacityRequest{{Name: "pvc-1", Size: 100, StorageClass: "HDD"}}
 	// nodes
 	nodes := []coreV1.Node{{ObjectMeta: metaV1.ObjectMeta{Name: "node-1", UID: "uuid-1"}}}
 
 	e := setup(t)
-	err := e.createReservation(testCtx, namespace, name, nodes, capacityRequests)
+	err := e.createReservation(testCtx, pod, name, nodes, capacityRequests)
 	assert.Nil(t, err)
 
 	// read back and check fields
@@ -621,12 +650,14 @@ func Test_createReservation(t *testing.T) {
 	assert.Equal(t, namespace, reservationResource.Spec.Namespace)
 	assert.Equal(t, len(nodes), len(reservationResource.Spec.NodeRequests.Requested))
 	assert.Equal(t, len(capacityRequests), len(reservationResource.Spec.ReservationRequests))
+	assert.Equal(t, podName, reservationResource.Annotations[v1.ReservationAnnotationPodName])
+	assert.Equal(t, namespace, reservationResource.Annotations[v1.ReservationAnnotationPodNamespace])
 
 	// empty namespace
 	namespace = ""
 	pod = &coreV1.Pod{ObjectMeta: metaV1.ObjectMeta{Name: podName, Namespace: namespace}}
-	name = getReservationName(pod)
-	err = e.createReservation(testCtx, namespace, name, nodes, capacityRequests)
+	name = GetReservationName(pod)
+	err = e.createReservation(testCtx, pod, name, nodes, capacityRequests)
 	assert.Nil(t, err)
 
 	reservationResource = &acrcrd.AvailableCapacityReservation{}
@@ -0,0 +1,326 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package extender
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	coreV1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	schedulerapi "k8s.io/kubernetes/pkg/scheduler/api/v1"
+	k8sCl "sigs.k8s.io/controller-runtime/pkg/client"
+
+	genV1 "github.com/dell/csi-baremetal/api/generated/v1"
+	v1 "github.com/dell/csi-baremetal/api/v1"
+	acrcrd "github.com/dell/csi-baremetal/api/v1/acreservationcrd"
+	accrd "github.com/dell/csi-baremetal/api/v1/availablecapacitycrd"
+	"github.com/dell/csi-baremetal/api/v1/drivecrd"
+	volcrd "github.com/dell/csi-baremetal/api/v1/volumecrd"
+	"github.com/dell/csi-baremetal/pkg/base"
+	"github.com/dell/csi-baremetal/pkg/base/capacityplanner"
+	annotations "github.com/dell/csi-baremetal/pkg/crcontrollers/operator/common"
+	"github.com/dell/csi-baremetal/pkg/metrics/common"
+)
+
+// podNodeNameField is a field selector of pods by node name
+const podNodeNameField = "spec.nodeName"
+
+// PreemptHandler extracts ExtenderPreemptionArgs struct from req and writes ExtenderPreemptionResult to the w
+// Only nodes on which pod volumes fit after victims removal are returned
+func (e *Extender) PreemptHandler(w http.ResponseWriter, req *http.Request) {
+	sessionUUID := uuid.New().String()
+	ll := e.logger.WithFields(logrus.Fields{
+		"sessionUUID": sessionUUID,
+		"method":      "PreemptHandler",
+	})
+	ll.Infof("Processing request: %v", req)
+	defer common.SchedulingDuration.EvaluateDurationForMethod("extender_preempt")()
+
+	var extenderArgs schedulerapi.ExtenderPreemptionArgs
+	if err := json.NewDecoder(req.Body).Decode(&extenderArgs); err != nil {
+		ll.Errorf("Unable to decode request body: %v", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ctxWithVal := context.WithValue(req.Context(), base.RequestUUID, sessionUUID)
+	victims, err := e.preempt(ctxWithVal, &extenderArgs)
+	if err != nil {
+		// scheduler ignores extender which fails and preempts victims which it selected by itself
+		ll.Errorf("preempt finished with error: %v", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	ll.Infof("Construct response. Get %d nodes in request. Among them suitable nodes count is %d",
+		len(extenderArgs.NodeNameToVictims)+len(extenderArgs.NodeNameToMetaVictims), len(victims))
+
+	w.Header().Set("Content-Type", "application/json")
+	extenderRes := &schedulerapi.ExtenderPreemptionResult{NodeNameToMetaVictims: victims}
+	if err := json.NewEncoder(w).Encode(extenderRes); err != nil {
+		ll.Errorf("Unable to write response %v: %v", extenderRes, err)
+	}
+}
+
+// preempt returns victims of the nodes on which capacity requests of the pod are satisfied after victims removal
+func (e *Extender) preempt(ctx context.Context,
+	args *schedulerapi.ExtenderPreemptionArgs) (map[string]*schedulerapi.MetaVictims, error) {
+	ll := e.logger.WithFields(logrus.Fields{
+		"sessionUUID": ctx.Value(base.RequestUUID),
+		"method":      "preempt",
+		"pod":         args.Pod.Name,
+	})
+
+	nodeVictims, err := e.getNodeVictims(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+
+	requests, err := e.GatherCapacityRequestsByProvisioner(ctx, args.Pod)
+	if err != nil {
+		return nil, err
+	}
+	if len(requests) == 0 {
+		return toMetaVictims(nodeVictims), nil
+	}
+
+	selectors, err := capacityplanner.ResolveACSelectors(ctx, e.k8sCache, args.Pod.Namespace, requests)
+	if err != nil {
+		return nil, err
+	}
+	ctx = capacityplanner.WithACSelectors(ctx, selectors)
+	volumes := make([]*genV1.Volume, len(requests))
+	for i, request := range requests {
+		volumes[i] = &genV1.Volume{Id: request.Name, Size: request.Size, StorageClass: request.StorageClass}
+	}
+
+	freeACs, err := e.readFreeCapacity(ctx, GetReservationName(args.Pod))
+	if err != nil {
+		return nil, err
+	}
+	volumeList := &volcrd.VolumeList{}
+	if err := e.k8sCache.ReadList(ctx, volumeList); err != nil {
+		return nil, fmt.Errorf("unable to read volumes list: %v", err)
+	}
+	driveList := &drivecrd.DriveList{}
+	if err := e.k8sCache.ReadList(ctx, driveList); err != nil {
+		return nil, fmt.Errorf("unable to read drives list: %v", err)
+	}
+
+	result := map[string]*schedulerapi.Victims{}
+	for nodeName, victims := range nodeVictims {
+		node := &coreV1.Node{}
+		if err := e.k8sClient.Get(ctx, k8sCl.ObjectKey{Name: nodeName}, node); err != nil {
+			if k8serrors.IsNotFound(err) {
+				ll.Infof("Node %s is removed", nodeName)
+				continue
+			}
+			// node which can't be read isn't treated as node without candidates
+			return nil, fmt.Errorf("unable to read node %s: %v", nodeName, err)
+		}
+		nodeID, err := annotations.GetNodeID(node, e.annotationKey, e.featureChecker)
+		if err != nil {
+			ll.Errorf("failed to get NodeID: %s", err)
+			continue
+		}
+
+		released := e.releasedVolumes(ctx, victims.Pods, nodeID, volumeList.Items)
+		capacity := releaseCapacity(capacityOfNode(freeACs, nodeID), released, driveList.Items)
+		capManager := e.capacityManagerBuilder.GetCapacityManager(ll, acListReader(capacity))
+		plan, err := capManager.PlanVolumesPlacing(ctx, volumes, []string{nodeID})
+		if err != nil {
+			return nil, err
+		}
+		if plan == nil || plan.GetVolumesToACMapping(nodeID) == nil {
+			ll.Infof("Volumes don't fit node %s after removal of %d victims", nodeName, len(victims.Pods))
+			continue
+		}
+		ll.Debugf("Volumes fit node %s, %d volumes of victims are released", nodeName, len(released))
+		result[nodeName] = victims
+	}
+	return toMetaVictims(result), nil
+}
+
+// getNodeVictims returns victims with pods, pods are read from API if scheduler sends only UIDs
+func (e *Extender) getNodeVictims(ctx context.Context,
+	args *schedulerapi.ExtenderPreemptionArgs) (map[string]*schedulerapi.Victims, error) {
+	if len(args.NodeNameToMetaVictims) == 0 {
+		return args.NodeNameToVictims, nil
+	}
+
+	result := make(map[string]*schedulerapi.Victims, len(args.NodeNameToMetaVictims))
+	for nodeName, metaVictims := range args.NodeNameToMetaVictims {
+		// only pods of the node are read
+		pods := &coreV1.PodList{}
+		if err := e.k8sClient.List(ctx, pods, k8sCl.MatchingFields{podNodeNameField: nodeName}); err != nil {
+			return nil, fmt.Errorf("unable to read pods of node %s: %v", nodeName, err)
+		}
+		podByUID := make(map[string]*coreV1.Pod, len(pods.Items))
+		for i := range pods.Items {
+			podByUID[string(pods.Items[i].UID)] = &pods.Items[i]
+		}
+
+		victims := &schedulerapi.Victims{NumPDBViolations: metaVictims.NumPDBViolations}
+		for _, metaPod := range metaVictims.Pods {
+			pod, ok := podByUID[metaPod.UID]
+			if !ok {
+				// pod is already removed
+				continue
+			}
+			victims.Pods = append(victims.Pods, pod)
+		}
+		result[nodeName] = victims
+	}
+	return result, nil
+}
+
+// readFreeCapacity returns ACs which aren't reserved by requested or confirmed reservations, ACs reserved by the preemptor are free for it
+func (e *Extender) readFreeCapacity(ctx context.Context, reservationName string) ([]accrd.AvailableCapacity, error) {
+	acList := &accrd.AvailableCapacityList{}
+	if err := e.k8sClient.ReadList(ctx, acList); err != nil {
+		return nil, fmt.Errorf("unable to read AC list: %v", err)
+	}
+	acrList := &acrcrd.AvailableCapacityReservationList{}
+	if err := e.k8sClient.ReadList(ctx, acrList); err != nil {
+		return nil, fmt.Errorf("unable to read ACR list: %v", err)
+	}
+	// only requested and confirmed reservations hold capacity
+	acrs := capacityplanner.FilterACRList(acrList.Items, func(acr acrcrd.AvailableCapacityReservation) bool {
+		return acr.Name != reservationName &&
+			(acr.Spec.Status == v1.ReservationRequested || acr.Spec.Status == v1.ReservationConfirmed)
+	})
+	return capacityplanner.NewReservationFilter().FilterByReservation(false, acList.Items, acrs), nil
+}
+
+// releasedVolumes returns volumes on the node which are removed together with victims:
+// inline volumes which are used only by the victim and volumes of PVCs controlled by the victim,
+// other PVCs are kept after pod removal and their capacity isn't released
+func (e *Extender) releasedVolumes(ctx context.Context, victims []*coreV1.Pod, nodeID string,
+	volumes []volcrd.Volume) []volcrd.Volume {
+	var result []volcrd.Volume
+	for _, pod := range victims {
+		ownedPVs := map[string]struct{}{}
+		for _, volume := range pod.Spec.Volumes {
+			if volume.PersistentVolumeClaim == nil {
+				continue
+			}
+			pvc := &coreV1.PersistentVolumeClaim{}
+			if err := e.k8sCache.ReadCR(ctx, volume.PersistentVolumeClaim.ClaimName, pod.Namespace, pvc); err != nil {
+				e.logger.Debugf("Unable to read PVC %s: %v", volume.PersistentVolumeClaim.ClaimName, err)
+				continue
+			}
+			if metav1.IsControlledBy(pvc, pod) {
+				ownedPVs[pvc.Spec.VolumeName] = struct{}{}
+			}
+		}
+
+		for _, volume := range volumes {
+			if volume.Namespace != pod.Namespace || volume.Spec.NodeId != nodeID {
+				continue
+			}
+			_, owned := ownedPVs[volume.Name]
+			if owned || (volume.Spec.Ephemeral && isOwnedOnlyBy(volume.Spec.Owners, pod.Name)) {
+				result = append(result, volume)
+			}
+		}
+	}
+	return result
+}
+
+// isOwnedOnlyBy checks that volume is used by the pod only
+func isOwnedOnlyBy(owners []string, podName string) bool {
+	for _, owner := range owners {
+		if owner != podName {
+			return false
+		}
+	}
+	return len(owners) > 0
+}
+
+// capacityOfNode returns copy of ACs of the node
+func capacityOfNode(acs []accrd.AvailableCapacity, nodeID string) []accrd.AvailableCapacity {
+	var result []accrd.AvailableCapacity
+	for _, ac := range acs {
+		if ac.Spec.NodeId == nodeID {
+			result = append(result, *ac.DeepCopy())
+		}
+	}
+	return result
+}
+
+// releaseCapacity returns ACs which exist after volumes removal:
+// drive of the full drive volume gets AC back with the drive attributes, LogicalVolumeGroup AC is increased by the volume size
+func releaseCapacity(acs []accrd.AvailableCapacity, volumes []volcrd.Volume,
+	drives []drivecrd.Drive) []accrd.AvailableCapacity {
+	for _, volume := range volumes {
+		if volume.Spec.LocationType == v1.LocationTypeLVM {
+			found := false
+			for i := range acs {
+				if acs[i].Spec.Location == volume.Spec.Location {
+					acs[i].Spec.Size += volume.Spec.Size
+					found = true
+					break
+				}
+			}
+			if found {
+				continue
+			}
+		}
+		ac := accrd.AvailableCapacity{Spec: genV1.AvailableCapacity{
+			Location:     volume.Spec.Location,
+			NodeId:       volume.Spec.NodeId,
+			StorageClass: volume.Spec.StorageClass,
+			Size:         volume.Spec.Size,
+		}}
+		ac.Name = volume.Spec.Location
+		// attributes are used by ACSelectors of the storage class, the same as in AC of capacity controller
+		for i := range drives {
+			if drives[i].Spec.UUID == volume.Spec.Location {
+				capacityplanner.SetDriveAttributes(&ac, &drives[i])
+				break
+			}
+		}
+		acs = append(acs, ac)
+	}
+	return acs
+}
+
+// toMetaVictims converts victims to the identifiers of the pods
+func toMetaVictims(nodeVictims map[string]*schedulerapi.Victims) map[string]*schedulerapi.MetaVictims {
+	result := make(map[string]*schedulerapi.MetaVictims, len(nodeVictims))
+	for nodeName, victims := range nodeVictims {
+		metaVictims := &schedulerapi.MetaVictims{NumPDBViolations: victims.NumPDBViolations}
+		for _, pod := range victims.Pods {
+			metaVictims.Pods = append(metaVictims.Pods, &schedulerapi.MetaPod{UID: string(pod.UID)})
+		}
+		result[nodeName] = metaVictims
+	}
+	return result
+}
+
+// acListReader implements capacityplanner.CapacityReader for the list of ACs
+type acListReader []accrd.AvailableCapacity
+
+// ReadCapacity returns ACs of the list
+func (r acListReader) ReadCapacity(_ context.Context) ([]accrd.AvailableCapacity, error) {
+	return r, nil
+}
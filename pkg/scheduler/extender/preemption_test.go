@@ -0,0 +1,208 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package extender
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	schedulerapi "k8s.io/kubernetes/pkg/scheduler/api/v1"
+	k8sCl "sigs.k8s.io/controller-runtime/pkg/client"
+
+	genV1 "github.com/dell/csi-baremetal/api/generated/v1"
+	v1 "github.com/dell/csi-baremetal/api/v1"
+	accrd "github.com/dell/csi-baremetal/api/v1/availablecapacitycrd"
+	"github.com/dell/csi-baremetal/api/v1/drivecrd"
+	volcrd "github.com/dell/csi-baremetal/api/v1/volumecrd"
+	"github.com/dell/csi-baremetal/pkg/base/k8s"
+)
+
+// failingClient fails reading of nodes and pods, e.g. when access is forbidden
+type failingClient struct {
+	k8sCl.Client
+}
+
+var errForbidden = errors.New("forbidden")
+
+func (c failingClient) Get(ctx context.Context, key k8sCl.ObjectKey, obj runtime.Object) error {
+	if _, ok := obj.(*coreV1.Node); ok {
+		return errForbidden
+	}
+	return c.Client.Get(ctx, key, obj)
+}
+
+func (c failingClient) List(ctx context.Context, list runtime.Object, opts ...k8sCl.ListOption) error {
+	if _, ok := list.(*coreV1.PodList); ok {
+		return errForbidden
+	}
+	return c.Client.List(ctx, list, opts...)
+}
+
+func TestExtender_preempt(t *testing.T) {
+	var (
+		e  = setup(t)
+		gb = int64(1024 * 1024 * 1024)
+	)
+	preemptor := testPod.DeepCopy()
+	preemptor.Spec.Volumes = []coreV1.Volume{{Name: "inline", VolumeSource: coreV1.VolumeSource{CSI: &testCSIVolumeSrc}}}
+
+	newVictim := func(name string, pvc string) *coreV1.Pod {
+		pod := &coreV1.Pod{ObjectMeta: metaV1.ObjectMeta{Name: name, Namespace: testNs, UID: types.UID(name + "-uid")}}
+		if pvc != "" {
+			pod.Spec.Volumes = []coreV1.Volume{{Name: "data", VolumeSource: coreV1.VolumeSource{
+				PersistentVolumeClaim: &coreV1.PersistentVolumeClaimVolumeSource{ClaimName: pvc}}}}
+		}
+		return pod
+	}
+	newVolume := func(name, nodeID, sc, locationType string, ephemeral bool, owner string) *volcrd.Volume {
+		return e.k8sClient.ConstructVolumeCR(name, testNs, genV1.Volume{Id: name, NodeId: nodeID, Location: name + "-location",
+			LocationType: locationType, StorageClass: sc, Size: 20 * gb, Ephemeral: ephemeral, Owners: []string{owner}})
+	}
+
+	// node-1: victim has inline volume which is released
+	// node-2: victim has PVC which is kept after pod removal
+	// node-3: victim has PVC controlled by the pod and LVM volume which are released
+	// node-4: no victims, has enough capacity
+	victim1 := newVictim("victim-1", "")
+	victim2 := newVictim("victim-2", "pvc-2")
+	victim3 := newVictim("victim-3", "pvc-3")
+	applyObjs(t, e.k8sClient, testSC1.DeepCopy(), victim1, victim2, victim3,
+		newVolume("volume-1", "uid-1", v1.StorageClassHDD, v1.LocationTypeDrive, true, victim1.Name),
+		newVolume("volume-2", "uid-2", v1.StorageClassHDD, v1.LocationTypeDrive, false, victim2.Name),
+		newVolume("volume-3", "uid-3", v1.StorageClassHDD, v1.LocationTypeDrive, false, victim3.Name),
+		newVolume("volume-4", "uid-3", v1.StorageClassHDDLVG, v1.LocationTypeLVM, true, victim3.Name),
+		&coreV1.PersistentVolumeClaim{
+			ObjectMeta: metaV1.ObjectMeta{Name: "pvc-2", Namespace: testNs},
+			Spec:       coreV1.PersistentVolumeClaimSpec{VolumeName: "volume-2"},
+		},
+		&coreV1.PersistentVolumeClaim{
+			ObjectMeta: metaV1.ObjectMeta{Name: "pvc-3", Namespace: testNs, OwnerReferences: []metaV1.OwnerReference{
+				*metaV1.NewControllerRef(victim3, coreV1.SchemeGroupVersion.WithKind("Pod"))}},
+			Spec: coreV1.PersistentVolumeClaimSpec{VolumeName: "volume-3"},
+		})
+
+	nodeVictims := map[string]*schedulerapi.Victims{}
+	for i, victim := range []*coreV1.Pod{victim1, victim2, victim3, nil} {
+		name, uid := "node-"+string(rune('1'+i)), "uid-"+string(rune('1'+i))
+		applyObjs(t, e.k8sClient, &coreV1.Node{ObjectMeta: metaV1.ObjectMeta{Name: name, UID: types.UID(uid)}})
+		// small drive on each node
+		ac := e.k8sClient.ConstructACCR(name, genV1.AvailableCapacity{NodeId: uid, StorageClass: v1.StorageClassHDD, Size: gb})
+		applyObjs(t, e.k8sClient, ac)
+		nodeVictims[name] = &schedulerapi.Victims{NumPDBViolations: i}
+		if victim != nil {
+			nodeVictims[name].Pods = []*coreV1.Pod{victim}
+		}
+	}
+	applyObjs(t, e.k8sClient, e.k8sClient.ConstructACCR("big",
+		genV1.AvailableCapacity{NodeId: "uid-4", StorageClass: v1.StorageClassHDD, Size: 20 * gb}))
+	// rejected reservation doesn't hold capacity
+	applyObjs(t, e.k8sClient, e.k8sClient.ConstructACRCR("rejected", genV1.AvailableCapacityReservation{
+		Namespace: testNs,
+		Status:    v1.ReservationRejected,
+		ReservationRequests: []*genV1.ReservationRequest{
+			{CapacityRequest: &genV1.CapacityRequest{}, Reservations: []string{"big"}},
+		},
+	}))
+
+	victims, err := e.preempt(testCtx, &schedulerapi.ExtenderPreemptionArgs{Pod: preemptor, NodeNameToVictims: nodeVictims})
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(victims))
+	assert.Nil(t, victims["node-2"])
+	assert.Equal(t, []*schedulerapi.MetaPod{{UID: string(victim1.UID)}}, victims["node-1"].Pods)
+	assert.Equal(t, []*schedulerapi.MetaPod{{UID: string(victim3.UID)}}, victims["node-3"].Pods)
+	assert.Equal(t, 2, victims["node-3"].NumPDBViolations)
+	assert.Empty(t, victims["node-4"].Pods)
+
+	// scheduler sends only UIDs of the victims
+	metaVictims := map[string]*schedulerapi.MetaVictims{
+		"node-1": {Pods: []*schedulerapi.MetaPod{{UID: string(victim1.UID)}}},
+		"node-2": {Pods: []*schedulerapi.MetaPod{{UID: string(victim2.UID)}}},
+	}
+	victims, err = e.preempt(testCtx, &schedulerapi.ExtenderPreemptionArgs{Pod: preemptor, NodeNameToMetaVictims: metaVictims})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(victims))
+	assert.NotNil(t, victims["node-1"])
+
+	// pod without local volumes, victims aren't changed
+	victims, err = e.preempt(testCtx, &schedulerapi.ExtenderPreemptionArgs{Pod: testPod.DeepCopy(), NodeNameToVictims: nodeVictims})
+	assert.Nil(t, err)
+	assert.Equal(t, 4, len(victims))
+
+	// failed reads of nodes and pods aren't treated as nodes without candidates
+	e.k8sClient = k8s.NewKubeClient(failingClient{Client: e.k8sClient.Client}, testLogger, testNs)
+	_, err = e.preempt(testCtx, &schedulerapi.ExtenderPreemptionArgs{Pod: preemptor, NodeNameToVictims: nodeVictims})
+	assert.NotNil(t, err)
+	_, err = e.preempt(testCtx, &schedulerapi.ExtenderPreemptionArgs{Pod: preemptor, NodeNameToMetaVictims: metaVictims})
+	assert.NotNil(t, err)
+
+	// removed node is skipped
+	e = setup(t)
+	applyObjs(t, e.k8sClient, testSC1.DeepCopy())
+	victims, err = e.preempt(testCtx, &schedulerapi.ExtenderPreemptionArgs{Pod: preemptor,
+		NodeNameToVictims: map[string]*schedulerapi.Victims{"removed": {}}})
+	assert.Nil(t, err)
+	assert.Empty(t, victims)
+}
+
+func TestExtender_PreemptHandler(t *testing.T) {
+	e := setup(t)
+	applyObjs(t, e.k8sClient, testSC1.DeepCopy())
+
+	body, err := json.Marshal(&schedulerapi.ExtenderPreemptionArgs{Pod: testPod.DeepCopy(),
+		NodeNameToVictims: map[string]*schedulerapi.Victims{"node-1": {}}})
+	assert.Nil(t, err)
+	recorder := httptest.NewRecorder()
+	e.PreemptHandler(recorder, httptest.NewRequest(http.MethodPost, "/preempt", bytes.NewReader(body)))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	result := &schedulerapi.ExtenderPreemptionResult{}
+	assert.Nil(t, json.NewDecoder(recorder.Body).Decode(result))
+	assert.NotNil(t, result.NodeNameToMetaVictims["node-1"])
+
+	recorder = httptest.NewRecorder()
+	e.PreemptHandler(recorder, httptest.NewRequest(http.MethodPost, "/preempt", bytes.NewReader([]byte("{"))))
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func TestReleaseCapacity(t *testing.T) {
+	acs := []accrd.AvailableCapacity{{Spec: genV1.AvailableCapacity{Location: "lvg", StorageClass: v1.StorageClassHDDLVG, Size: 10}}}
+	volumes := []volcrd.Volume{
+		{Spec: genV1.Volume{Location: "lvg", LocationType: v1.LocationTypeLVM, StorageClass: v1.StorageClassHDDLVG, Size: 5}},
+		{Spec: genV1.Volume{Location: "drive", LocationType: v1.LocationTypeDrive, StorageClass: v1.StorageClassSSD, Size: 100}},
+	}
+	drives := []drivecrd.Drive{{
+		ObjectMeta: metaV1.ObjectMeta{Name: "drive", Labels: map[string]string{"tier": "fast"}},
+		Spec:       genV1.Drive{UUID: "drive", VID: "vendor", Type: v1.DriveTypeSSD, Size: 100},
+	}}
+	acs = releaseCapacity(acs, volumes, drives)
+	assert.Equal(t, 2, len(acs))
+	assert.Equal(t, int64(15), acs[0].Spec.Size)
+	assert.Equal(t, v1.StorageClassSSD, acs[1].Spec.StorageClass)
+	assert.Equal(t, int64(100), acs[1].Spec.Size)
+	// released AC matches selectors of the storage class as AC of the drive
+	assert.Equal(t, "vendor", acs[1].Annotations[v1.ACAnnotationDriveVID])
+	assert.Equal(t, "fast", acs[1].Labels["tier"])
+}
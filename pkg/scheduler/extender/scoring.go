@@ -0,0 +1,243 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package extender
+
+import (
+	"context"
+	"fmt"
+
+	coreV1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	schedulerapi "k8s.io/kubernetes/pkg/scheduler/api"
+	schedulerapiv1 "k8s.io/kubernetes/pkg/scheduler/api/v1"
+
+	genV1 "github.com/dell/csi-baremetal/api/generated/v1"
+	v1 "github.com/dell/csi-baremetal/api/v1"
+	acrcrd "github.com/dell/csi-baremetal/api/v1/acreservationcrd"
+	accrd "github.com/dell/csi-baremetal/api/v1/availablecapacitycrd"
+	volcrd "github.com/dell/csi-baremetal/api/v1/volumecrd"
+	"github.com/dell/csi-baremetal/pkg/base/util"
+	annotations "github.com/dell/csi-baremetal/pkg/crcontrollers/operator/common"
+)
+
+// ScoringStrategy defines how nodes are ranked on prioritize stage
+type ScoringStrategy string
+
+const (
+	// VolumeCountStrategy ranks nodes by count of volumes, nodes with less volumes have higher score
+	VolumeCountStrategy ScoringStrategy = "volume-count"
+	// LeastAllocatedStrategy ranks nodes by free bytes of requested storage classes left after placement
+	LeastAllocatedStrategy ScoringStrategy = "least-allocated"
+	// MostAllocatedStrategy ranks nodes by allocated bytes of requested storage classes after placement (bin-packing)
+	MostAllocatedStrategy ScoringStrategy = "most-allocated"
+	// BestFitStrategy ranks nodes by how close reserved ACs sizes are to the requested sizes
+	BestFitStrategy ScoringStrategy = "best-fit"
+)
+
+// ParseScoringStrategy converts string to ScoringStrategy, empty string means VolumeCountStrategy
+func ParseScoringStrategy(strategy string) (ScoringStrategy, error) {
+	switch s := ScoringStrategy(strategy); s {
+	case "":
+		return VolumeCountStrategy, nil
+	case VolumeCountStrategy, LeastAllocatedStrategy, MostAllocatedStrategy, BestFitStrategy:
+		return s, nil
+	default:
+		return "", fmt.Errorf("unknown scoring strategy %s, supported: %s, %s, %s, %s", strategy,
+			VolumeCountStrategy, LeastAllocatedStrategy, MostAllocatedStrategy, BestFitStrategy)
+	}
+}
+
+// nodeCapacityStat holds capacity of requested storage classes on the node
+type nodeCapacityStat struct {
+	// requested storage class to free bytes in ACs which could be used for it and aren't reserved by other pods
+	free map[string]int64
+	// requested storage class to bytes allocated by volumes which could be placed on it
+	allocated map[string]int64
+	// sum of sizes of ACs reserved for the pod on the node
+	reserved int64
+}
+
+// scoreByCapacity ranks nodes according to capacity aware scoring strategy
+// Placing plan is taken from the pod ACR which was confirmed on filter stage
+// Returns nil if pod doesn't request capacity or reservation isn't confirmed
+func (e *Extender) scoreByCapacity(ctx context.Context, pod *coreV1.Pod,
+	nodes []coreV1.Node) ([]schedulerapiv1.HostPriority, error) {
+	requests, err := e.GatherCapacityRequestsByProvisioner(ctx, pod)
+	if err != nil {
+		return nil, err
+	}
+	if len(requests) == 0 {
+		return nil, nil
+	}
+
+	reservation := &acrcrd.AvailableCapacityReservation{}
+	if err := e.k8sClient.ReadCR(ctx, GetReservationName(pod), "", reservation); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if reservation.Spec.Status != v1.ReservationConfirmed {
+		return nil, nil
+	}
+
+	acList := &accrd.AvailableCapacityList{}
+	if err := e.k8sClient.ReadList(ctx, acList); err != nil {
+		return nil, fmt.Errorf("unable to read AC list: %v", err)
+	}
+	acrList := &acrcrd.AvailableCapacityReservationList{}
+	if err := e.k8sClient.ReadList(ctx, acrList); err != nil {
+		return nil, fmt.Errorf("unable to read ACR list: %v", err)
+	}
+	volumeList := &volcrd.VolumeList{}
+	if err := e.k8sCache.ReadList(ctx, volumeList); err != nil {
+		return nil, fmt.Errorf("unable to read volumes list: %v", err)
+	}
+
+	stats := buildNodeCapacityStats(requests, reservation, acrList.Items, acList.Items, volumeList.Items)
+	requested := map[string]int64{}
+	for _, request := range requests {
+		requested[request.StorageClass] += request.Size
+	}
+
+	hostPriority := make([]schedulerapiv1.HostPriority, 0, len(nodes))
+	for _, node := range nodes {
+		node := node
+		nodeID, err := annotations.GetNodeID(&node, e.annotationKey, e.featureChecker)
+		if err != nil {
+			e.logger.Errorf("failed to get NodeID: %s", err)
+		}
+		hostPriority = append(hostPriority, schedulerapiv1.HostPriority{
+			Host:  node.GetName(),
+			Score: calculateScore(e.scoringStrategy, stats[nodeID], requested),
+		})
+	}
+	return hostPriority, nil
+}
+
+// buildNodeCapacityStats collects capacity of requested storage classes for each node
+// ACs reserved by confirmed reservations of other pods aren't counted as free
+func buildNodeCapacityStats(requests []*genV1.CapacityRequest, reservation *acrcrd.AvailableCapacityReservation,
+	acrs []acrcrd.AvailableCapacityReservation, acs []accrd.AvailableCapacity,
+	volumes []volcrd.Volume) map[string]*nodeCapacityStat {
+	stats := map[string]*nodeCapacityStat{}
+	getStat := func(nodeID string) *nodeCapacityStat {
+		if _, ok := stats[nodeID]; !ok {
+			stats[nodeID] = &nodeCapacityStat{free: map[string]int64{}, allocated: map[string]int64{}}
+		}
+		return stats[nodeID]
+	}
+
+	reservedByOthers := map[string]struct{}{}
+	for _, acr := range acrs {
+		if acr.Name == reservation.Name || acr.Spec.Status != v1.ReservationConfirmed {
+			continue
+		}
+		for _, request := range acr.Spec.ReservationRequests {
+			for _, acName := range request.Reservations {
+				reservedByOthers[acName] = struct{}{}
+			}
+		}
+	}
+
+	acByName := make(map[string]*accrd.AvailableCapacity, len(acs))
+	for i, ac := range acs {
+		acByName[ac.Name] = &acs[i]
+		if _, ok := reservedByOthers[ac.Name]; ok {
+			continue
+		}
+		for _, request := range requests {
+			if matchStorageClass(ac.Spec.StorageClass, request.StorageClass) {
+				getStat(ac.Spec.NodeId).free[request.StorageClass] += ac.Spec.Size
+			}
+		}
+	}
+	for _, volume := range volumes {
+		for _, request := range requests {
+			if matchStorageClass(volume.Spec.StorageClass, request.StorageClass) {
+				getStat(volume.Spec.NodeId).allocated[request.StorageClass] += volume.Spec.Size
+			}
+		}
+	}
+	for _, request := range reservation.Spec.ReservationRequests {
+		for _, acName := range request.Reservations {
+			if ac, ok := acByName[acName]; ok {
+				getStat(ac.Spec.NodeId).reserved += ac.Spec.Size
+			}
+		}
+	}
+	return stats
+}
+
+// calculateScore returns score of the node in range [0, MaxPriority] according to the strategy
+// requested maps storage class to requested bytes, allocation strategies score each storage class separately
+// and return the average score
+func calculateScore(strategy ScoringStrategy, stat *nodeCapacityStat, requested map[string]int64) int {
+	if stat == nil || len(requested) == 0 {
+		return 0
+	}
+	var score int64
+	switch strategy {
+	case LeastAllocatedStrategy, MostAllocatedStrategy:
+		for sc, size := range requested {
+			score += calculateAllocationScore(strategy, stat.free[sc], stat.allocated[sc], size)
+		}
+		score /= int64(len(requested))
+	case BestFitStrategy:
+		if stat.reserved == 0 {
+			return 0
+		}
+		var total int64
+		for _, size := range requested {
+			total += size
+		}
+		if total >= stat.reserved {
+			return schedulerapi.MaxPriority
+		}
+		score = total * schedulerapi.MaxPriority / stat.reserved
+	}
+	return int(score)
+}
+
+// calculateAllocationScore returns score of one storage class according to least or most allocated strategy
+func calculateAllocationScore(strategy ScoringStrategy, free, allocated, requested int64) int64 {
+	total := free + allocated
+	if total == 0 {
+		return 0
+	}
+	free -= requested
+	if free < 0 {
+		free = 0
+	}
+	if strategy == LeastAllocatedStrategy {
+		return free * schedulerapi.MaxPriority / total
+	}
+	return (total - free) * schedulerapi.MaxPriority / total
+}
+
+// matchStorageClass checks whether capacity with storage class sc could be used for request with storage class reqSC
+func matchStorageClass(sc, reqSC string) bool {
+	switch {
+	case sc == reqSC:
+		return true
+	case reqSC == v1.StorageClassAny:
+		return !util.IsStorageClassLVG(sc)
+	case util.IsStorageClassLVG(reqSC):
+		return sc == util.GetSubStorageClass(reqSC)
+	}
+	return false
+}
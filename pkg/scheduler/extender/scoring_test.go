@@ -0,0 +1,161 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package extender
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	coreV1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	genV1 "github.com/dell/csi-baremetal/api/generated/v1"
+	v1 "github.com/dell/csi-baremetal/api/v1"
+)
+
+func TestParseScoringStrategy(t *testing.T) {
+	s, err := ParseScoringStrategy("")
+	assert.Nil(t, err)
+	assert.Equal(t, VolumeCountStrategy, s)
+
+	s, err = ParseScoringStrategy("best-fit")
+	assert.Nil(t, err)
+	assert.Equal(t, BestFitStrategy, s)
+
+	_, err = ParseScoringStrategy("random")
+	assert.NotNil(t, err)
+}
+
+func TestMatchStorageClass(t *testing.T) {
+	assert.True(t, matchStorageClass(v1.StorageClassHDD, v1.StorageClassHDD))
+	assert.True(t, matchStorageClass(v1.StorageClassSSD, v1.StorageClassAny))
+	assert.False(t, matchStorageClass(v1.StorageClassHDDLVG, v1.StorageClassAny))
+	assert.True(t, matchStorageClass(v1.StorageClassHDD, v1.StorageClassHDDLVG))
+	assert.False(t, matchStorageClass(v1.StorageClassSSD, v1.StorageClassHDD))
+}
+
+func TestCalculateScore(t *testing.T) {
+	stat := &nodeCapacityStat{
+		free:      map[string]int64{v1.StorageClassHDD: 60, v1.StorageClassSSD: 100},
+		allocated: map[string]int64{v1.StorageClassHDD: 40},
+		reserved:  20,
+	}
+	hdd := func(size int64) map[string]int64 { return map[string]int64{v1.StorageClassHDD: size} }
+
+	assert.Equal(t, 5, calculateScore(LeastAllocatedStrategy, stat, hdd(10)))
+	assert.Equal(t, 5, calculateScore(MostAllocatedStrategy, stat, hdd(10)))
+	assert.Equal(t, 5, calculateScore(BestFitStrategy, stat, hdd(10)))
+	assert.Equal(t, 0, calculateScore(LeastAllocatedStrategy, nil, hdd(10)))
+	assert.Equal(t, 0, calculateScore(MostAllocatedStrategy, &nodeCapacityStat{}, hdd(10)))
+	assert.Equal(t, 0, calculateScore(BestFitStrategy, &nodeCapacityStat{}, hdd(10)))
+	// requested more than free
+	assert.Equal(t, 0, calculateScore(LeastAllocatedStrategy, stat, hdd(100)))
+	assert.Equal(t, 10, calculateScore(MostAllocatedStrategy, stat, hdd(100)))
+	// storage classes are scored separately: HDD is half allocated, SSD is free
+	requested := map[string]int64{v1.StorageClassHDD: 10, v1.StorageClassSSD: 10}
+	assert.Equal(t, 7, calculateScore(LeastAllocatedStrategy, stat, requested))
+	assert.Equal(t, 3, calculateScore(MostAllocatedStrategy, stat, requested))
+	// storage class without capacity
+	assert.Equal(t, 0, calculateScore(LeastAllocatedStrategy, stat, map[string]int64{v1.StorageClassNVMe: 10}))
+}
+
+func TestExtender_scoreByCapacity(t *testing.T) {
+	var (
+		e       = setup(t)
+		gb      = int64(1024 * 1024 * 1024)
+		node1   = coreV1.Node{ObjectMeta: metaV1.ObjectMeta{Name: "node-1", UID: types.UID("uid-1")}}
+		node2   = coreV1.Node{ObjectMeta: metaV1.ObjectMeta{Name: "node-2", UID: types.UID("uid-2")}}
+		nodes   = []coreV1.Node{node1, node2}
+		pod     = testPod.DeepCopy()
+		request = &genV1.CapacityRequest{Name: testPVC1Name, StorageClass: v1.StorageClassHDD, Size: testSizeGb * 1024}
+	)
+	pod.Spec.Volumes = []coreV1.Volume{{VolumeSource: coreV1.VolumeSource{
+		PersistentVolumeClaim: &coreV1.PersistentVolumeClaimVolumeSource{ClaimName: testPVC1Name}}}}
+	// testPVC1 requests might be modified by other tests
+	pvc := testPVC1.DeepCopy()
+	pvc.Spec.Resources.Requests = coreV1.ResourceList{
+		coreV1.ResourceStorage: *resource.NewQuantity(request.Size, resource.DecimalSI)}
+	applyObjs(t, e.k8sClient, testSC1.DeepCopy(), pvc)
+
+	// node-1 has a single big drive, node-2 has small drive which fits request and one more big drive
+	acs := map[string]genV1.AvailableCapacity{
+		"ac-1": {NodeId: "uid-1", StorageClass: v1.StorageClassHDD, Size: 100 * gb},
+		"ac-2": {NodeId: "uid-2", StorageClass: v1.StorageClassHDD, Size: 20 * 1024},
+		"ac-3": {NodeId: "uid-2", StorageClass: v1.StorageClassHDD, Size: 10 * gb},
+	}
+	for name, ac := range acs {
+		assert.Nil(t, e.k8sClient.CreateCR(testCtx, name, e.k8sClient.ConstructACCR(name, ac)))
+	}
+
+	// node-2 already has volume
+	volume := e.k8sClient.ConstructVolumeCR("vol-1", "another-ns",
+		genV1.Volume{Id: "vol-1", NodeId: "uid-2", StorageClass: v1.StorageClassHDD, Size: 10 * gb})
+	assert.Nil(t, e.k8sClient.CreateCR(testCtx, "vol-1", volume))
+
+	// no reservation, fallback to volume count
+	hp, err := e.scoreByCapacity(testCtx, pod, nodes)
+	assert.Nil(t, err)
+	assert.Nil(t, hp)
+
+	reservation := e.k8sClient.ConstructACRCR(GetReservationName(pod), genV1.AvailableCapacityReservation{
+		Namespace:    testNs,
+		Status:       v1.ReservationConfirmed,
+		NodeRequests: &genV1.NodeRequests{Requested: []string{"uid-1", "uid-2"}, Reserved: []string{"uid-1", "uid-2"}},
+		ReservationRequests: []*genV1.ReservationRequest{
+			{CapacityRequest: request, Reservations: []string{"ac-1", "ac-2"}},
+		},
+	})
+	assert.Nil(t, e.k8sClient.Create(testCtx, reservation))
+
+	e.SetScoringStrategy(LeastAllocatedStrategy)
+	hp, err = e.scoreByCapacity(testCtx, pod, nodes)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(hp))
+	assert.Equal(t, 9, hp[0].Score)
+	assert.Equal(t, 5, hp[1].Score)
+
+	e.SetScoringStrategy(MostAllocatedStrategy)
+	hp, err = e.scoreByCapacity(testCtx, pod, nodes)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, hp[0].Score)
+	assert.Equal(t, 5, hp[1].Score)
+
+	e.SetScoringStrategy(BestFitStrategy)
+	hp, err = e.score(testCtx, pod, nodes)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, hp[0].Score)
+	assert.Equal(t, 5, hp[1].Score)
+
+	// big drive of node-2 is reserved by another pod and isn't free anymore
+	another := e.k8sClient.ConstructACRCR("another-pod", genV1.AvailableCapacityReservation{
+		Namespace:    testNs,
+		Status:       v1.ReservationConfirmed,
+		NodeRequests: &genV1.NodeRequests{Requested: []string{"uid-2"}, Reserved: []string{"uid-2"}},
+		ReservationRequests: []*genV1.ReservationRequest{
+			{CapacityRequest: &genV1.CapacityRequest{Name: "pvc", StorageClass: v1.StorageClassHDD, Size: gb},
+				Reservations: []string{"ac-3"}},
+		},
+	})
+	assert.Nil(t, e.k8sClient.Create(testCtx, another))
+	e.SetScoringStrategy(LeastAllocatedStrategy)
+	hp, err = e.scoreByCapacity(testCtx, pod, nodes)
+	assert.Nil(t, err)
+	assert.Equal(t, 9, hp[0].Score)
+	assert.Equal(t, 0, hp[1].Score)
+}
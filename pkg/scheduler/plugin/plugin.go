@@ -17,21 +17,89 @@ limitations under the License.
 package plugin
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
 	v1 "k8s.io/api/core/v1"
+	storageV1 "k8s.io/api/storage/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/component-base/metrics/legacyregistry"
 	framework "k8s.io/kubernetes/pkg/scheduler/framework/v1alpha1"
+
+	genV1 "github.com/dell/csi-baremetal/api/generated/v1"
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	acrcrd "github.com/dell/csi-baremetal/api/v1/acreservationcrd"
+	sqcrd "github.com/dell/csi-baremetal/api/v1/storagequotacrd"
+	volcrd "github.com/dell/csi-baremetal/api/v1/volumecrd"
+	"github.com/dell/csi-baremetal/pkg/base"
+	"github.com/dell/csi-baremetal/pkg/base/capacityplanner"
+	fc "github.com/dell/csi-baremetal/pkg/base/featureconfig"
+	"github.com/dell/csi-baremetal/pkg/base/k8s"
+	"github.com/dell/csi-baremetal/pkg/base/quota"
+	annotations "github.com/dell/csi-baremetal/pkg/crcontrollers/operator/common"
+	"github.com/dell/csi-baremetal/pkg/metrics/common"
+	"github.com/dell/csi-baremetal/pkg/scheduler/extender"
 )
 
 // CSISchedulerPlugin is a plugin that does placement decision based on information in AC CRD
+// Capacity is planned in-process with capacityplanner, reservation is created on Reserve stage
+// in confirmed state, so there are no round trips to the reservation controller
 type CSISchedulerPlugin struct {
 	frameworkHandle framework.FrameworkHandle
+	k8sClient       *k8s.KubeClient
+	k8sCache        *k8s.KubeCache
+	// extender is used for gathering of capacity requests, plugin shares the same logic
+	extender       *extender.Extender
+	featureChecker fc.FeatureChecker
+	annotationKey  string
+
+	capacityManagerBuilder capacityplanner.CapacityManagerBuilder
+	logger                 *logrus.Entry
+}
+
+// Args holds plugin configuration which is passed in pluginConfig section of the scheduler configuration
+type Args struct {
+	// Namespace in which plugin will search for resources
+	Namespace string `json:"namespace"`
+	// Provisioner name which storage classes plugin will be observing
+	Provisioner string `json:"provisioner"`
+	// LogLevel of the plugin logger
+	LogLevel string `json:"logLevel"`
+	// UseNodeAnnotation whether plugin should read node ID from node annotation
+	UseNodeAnnotation bool `json:"useNodeAnnotation"`
+	// UseExternalAnnotation whether plugin should read node ID from external annotation
+	UseExternalAnnotation bool `json:"useExternalAnnotation"`
+	// NodeIDAnnotation custom node annotation name
+	NodeIDAnnotation string `json:"nodeIDAnnotation"`
 }
 
 const (
 	// Name is the name of the plugin used in Registry and configurations.
 	Name = "CSISchedulerPlugin"
+	// stateKey is a key under which podState is stored in PluginContext
+	stateKey framework.ContextKey = Name
 )
 
+// podState holds data which is calculated once per scheduling cycle and shared between extension points
+type podState struct {
+	requests []*genV1.CapacityRequest
+	// node name to node ID mapping
+	nodeIDs map[string]string
+	// capacity placing plan for all nodes from snapshot, nil when capacity isn't found
+	plan *capacityplanner.VolumesPlacingPlan
+	// constraints of capacity planning for the pod volumes: selectors, spreading and cordoned nodes
+	constraints *capacityplanner.PlanningConstraints
+	// storage quota of the namespace which is exceeded by the pod volumes, nil if requests fit quotas
+	quotaErr error
+	// node ID to rank mapping, calculated lazily on Score stage
+	priorities map[string]int
+	maxRank    int
+}
+
 // please refer to https://kubernetes.io/docs/concepts/scheduling-eviction/scheduling-framework/ for details
 // Filter plugin
 var _ framework.FilterPlugin = &CSISchedulerPlugin{}
@@ -52,26 +120,277 @@ func (c CSISchedulerPlugin) Name() string {
 
 // New initializes a new plugin and returns it.
 func New(configuration *runtime.Unknown, handle framework.FrameworkHandle) (framework.Plugin, error) {
-	sp := &CSISchedulerPlugin{frameworkHandle: handle}
-	return sp, nil
+	args := Args{LogLevel: base.InfoLevel}
+	if configuration != nil && len(configuration.Raw) > 0 {
+		if err := json.Unmarshal(configuration.Raw, &args); err != nil {
+			return nil, fmt.Errorf("unable to decode %s args: %v", Name, err)
+		}
+	}
+
+	logger, err := base.InitLogger("", args.LogLevel)
+	if err != nil {
+		return nil, err
+	}
+
+	featureConf := fc.NewFeatureConfig()
+	featureConf.Update(fc.FeatureNodeIDFromAnnotation, args.UseNodeAnnotation)
+	featureConf.Update(fc.FeatureExternalAnnotationForNode, args.UseExternalAnnotation)
+
+	k8sClient, err := k8s.GetK8SClient()
+	if err != nil {
+		return nil, err
+	}
+	kubeClient := k8s.NewKubeClient(k8sClient, logger, args.Namespace)
+
+	// plugin lives as long as scheduler process, cache is never stopped
+	kubeCache, err := k8s.InitKubeCache(logger, make(chan struct{}),
+		&v1.Node{},
+		&v1.PersistentVolumeClaim{},
+		&storageV1.StorageClass{},
+		&volcrd.Volume{},
+		&acrcrd.AvailableCapacityReservation{},
+		&sqcrd.StorageQuota{})
+	if err != nil {
+		return nil, fmt.Errorf("fail to init kubeCache: %v", err)
+	}
+
+	// kube-scheduler exposes metrics from its own registry
+	legacyregistry.RawMustRegister(common.SchedulingDuration.Collect())
+
+	return newPlugin(handle, logger, kubeClient, kubeCache, args.Provisioner, featureConf, args.NodeIDAnnotation)
+}
+
+func newPlugin(handle framework.FrameworkHandle, logger *logrus.Logger, kubeClient *k8s.KubeClient,
+	kubeCache *k8s.KubeCache, provisioner string, featureConf fc.FeatureChecker,
+	annotationKey string) (*CSISchedulerPlugin, error) {
+	e, err := extender.NewExtender(logger, kubeClient, kubeCache, provisioner, featureConf, annotationKey)
+	if err != nil {
+		return nil, err
+	}
+	return &CSISchedulerPlugin{
+		frameworkHandle:        handle,
+		k8sClient:              kubeClient,
+		k8sCache:               kubeCache,
+		extender:               e,
+		featureChecker:         featureConf,
+		annotationKey:          annotationKey,
+		capacityManagerBuilder: &capacityplanner.DefaultCapacityManagerBuilder{},
+		logger:                 logger.WithField("component", Name),
+	}, nil
 }
 
 // Filter filters out nodes which don't have ACs match to PVCs
 func (c CSISchedulerPlugin) Filter(pc *framework.PluginContext, pod *v1.Pod, nodeName string) *framework.Status {
-	panic("implement me")
+	defer common.SchedulingDuration.EvaluateDurationForMethod("plugin_filter")()
+
+	state, err := c.getState(pc, pod)
+	if err != nil {
+		return framework.NewStatus(framework.Error, err.Error())
+	}
+	if len(state.requests) == 0 {
+		return nil
+	}
+	if state.quotaErr != nil {
+		return framework.NewStatus(framework.Unschedulable, state.quotaErr.Error())
+	}
+
+	nodeID, ok := state.nodeIDs[nodeName]
+	if !ok || state.plan == nil || state.plan.GetVolumesToACMapping(nodeID) == nil {
+		return framework.NewStatus(framework.Unschedulable,
+			fmt.Sprintf("No available capacity found on the node %s", nodeName))
+	}
+	return nil
 }
 
 // Score does balancing across the nodes for better performance. Nodes with more ACs should have highest scores
+// Rank of the node is calculated in the same way as in extender and normalized to [0, MaxNodeScore]
 func (c CSISchedulerPlugin) Score(pc *framework.PluginContext, p *v1.Pod, nodeName string) (int, *framework.Status) {
-	panic("implement me")
+	defer common.SchedulingDuration.EvaluateDurationForMethod("plugin_score")()
+
+	state, err := c.getState(pc, p)
+	if err != nil {
+		return 0, framework.NewStatus(framework.Error, err.Error())
+	}
+
+	pc.Lock()
+	defer pc.Unlock()
+	if state.priorities == nil {
+		volumeList := &volcrd.VolumeList{}
+		if err := c.k8sCache.ReadList(context.Background(), volumeList); err != nil {
+			return 0, framework.NewStatus(framework.Error, fmt.Sprintf("unable to read volumes list: %v", err))
+		}
+		state.priorities, state.maxRank = extender.NodeVolumePriorities(volumeList)
+	}
+
+	if state.maxRank == 0 {
+		return framework.MaxNodeScore, nil
+	}
+	// set the highest priority if node doesn't have any volumes
+	rank := state.maxRank
+	if r, ok := state.priorities[state.nodeIDs[nodeName]]; ok {
+		rank = r
+	}
+	return rank * framework.MaxNodeScore / state.maxRank, nil
 }
 
 // Reserve does reservation of ACs
+// Capacity is planned again for the selected node since ACs might be reserved for another pod after Filter stage
 func (c CSISchedulerPlugin) Reserve(pc *framework.PluginContext, p *v1.Pod, nodeName string) *framework.Status {
-	panic("implement me")
+	defer common.SchedulingDuration.EvaluateDurationForMethod("plugin_reserve")()
+
+	state, err := c.getState(pc, p)
+	if err != nil {
+		return framework.NewStatus(framework.Error, err.Error())
+	}
+	if len(state.requests) == 0 {
+		return nil
+	}
+
+	ctx := context.WithValue(context.Background(), base.RequestUUID, uuid.New().String())
+	ll := c.logger.WithFields(logrus.Fields{"method": "Reserve", "pod": p.Name, "node": nodeName})
+
+	nodeID, ok := state.nodeIDs[nodeName]
+	if !ok {
+		return framework.NewStatus(framework.Error, fmt.Sprintf("unable to detect ID of node %s", nodeName))
+	}
+
+	reservationName := extender.GetReservationName(p)
+	// reservation from the previous scheduling attempt must not hide ACs from the planner
+	if err := c.removeReservation(ctx, reservationName); err != nil {
+		return framework.NewStatus(framework.Error, err.Error())
+	}
+
+	volumes := convertToVolumes(state.requests)
+	// the same constraints as on Filter stage are applied
+	ctx = capacityplanner.WithPlanningConstraints(ctx, state.constraints)
+	plan, err := c.planVolumesPlacing(ctx, volumes, []string{nodeID})
+	if err != nil {
+		return framework.NewStatus(framework.Error, err.Error())
+	}
+	if plan == nil || plan.GetVolumesToACMapping(nodeID) == nil {
+		return framework.NewStatus(framework.Unschedulable,
+			fmt.Sprintf("No available capacity found on the node %s", nodeName))
+	}
+
+	reservation := genV1.AvailableCapacityReservation{
+		Namespace:           p.Namespace,
+		Status:              apiV1.ReservationConfirmed,
+		NodeRequests:        &genV1.NodeRequests{Requested: []string{nodeID}, Reserved: []string{nodeID}},
+		ReservationRequests: make([]*genV1.ReservationRequest, len(state.requests)),
+	}
+	for i, request := range state.requests {
+		reservation.ReservationRequests[i] = &genV1.ReservationRequest{
+			CapacityRequest: request,
+			Reservations:    []string{plan.GetACForVolume(nodeID, volumes[i]).Name},
+		}
+	}
+
+	acr := c.k8sClient.ConstructACRCR(reservationName, reservation)
+	acr.Annotations = extender.GetReservationAnnotations(p)
+	if err := c.k8sClient.CreateCR(ctx, reservationName, acr); err != nil {
+		ll.Errorf("Unable to create reservation %s: %v", reservationName, err)
+		return framework.NewStatus(framework.Error, err.Error())
+	}
+	ll.Infof("Capacity for pod was reserved in %s", reservationName)
+	return nil
 }
 
 // Unreserve un-reserver ACs
+// Called when pod was rejected after Reserve stage (binding failed for example), reservation is removed
 func (c CSISchedulerPlugin) Unreserve(pc *framework.PluginContext, p *v1.Pod, nodeName string) {
-	panic("implement me")
+	defer common.SchedulingDuration.EvaluateDurationForMethod("plugin_unreserve")()
+
+	reservationName := extender.GetReservationName(p)
+	if err := c.removeReservation(context.Background(), reservationName); err != nil {
+		c.logger.WithField("method", "Unreserve").
+			Errorf("Unable to roll back reservation %s for node %s: %v", reservationName, nodeName, err)
+	}
+}
+
+// getState returns podState from PluginContext, state is calculated on the first call in the scheduling cycle
+func (c CSISchedulerPlugin) getState(pc *framework.PluginContext, pod *v1.Pod) (*podState, error) {
+	pc.Lock()
+	defer pc.Unlock()
+
+	if data, err := pc.Read(stateKey); err == nil {
+		return data.(*podState), nil
+	}
+
+	ctx := context.WithValue(context.Background(), base.RequestUUID, uuid.New().String())
+	requests, err := c.extender.GatherCapacityRequestsByProvisioner(ctx, pod)
+	if err != nil {
+		return nil, err
+	}
+	state := &podState{requests: requests, nodeIDs: map[string]string{}}
+	if len(requests) != 0 {
+		state.constraints, err = capacityplanner.ResolvePlanningConstraints(ctx, c.k8sCache, pod.Namespace, pod, requests)
+		if err != nil {
+			return nil, err
+		}
+		if err = c.extender.CheckStorageQuota(ctx, pod, requests); err != nil {
+			if _, ok := err.(*quota.ExceededError); !ok {
+				return nil, err
+			}
+			state.quotaErr = err
+		}
+	}
+
+	nodeIDs := make([]string, 0)
+	for name, info := range c.frameworkHandle.NodeInfoSnapshot().NodeInfoMap {
+		node := info.Node()
+		if node == nil {
+			continue
+		}
+		nodeID, err := annotations.GetNodeID(node, c.annotationKey, c.featureChecker)
+		if err != nil {
+			c.logger.Errorf("failed to get NodeID: %s", err)
+			continue
+		}
+		state.nodeIDs[name] = nodeID
+		nodeIDs = append(nodeIDs, nodeID)
+	}
+
+	if len(requests) != 0 {
+		ctx = capacityplanner.WithPlanningConstraints(ctx, state.constraints)
+		if state.plan, err = c.planVolumesPlacing(ctx, convertToVolumes(requests), nodeIDs); err != nil {
+			return nil, err
+		}
+	}
+
+	pc.Write(stateKey, state)
+	return state, nil
+}
+
+// planVolumesPlacing plans volumes placing on the nodes using capacity which isn't reserved yet
+func (c CSISchedulerPlugin) planVolumesPlacing(ctx context.Context, volumes []*genV1.Volume,
+	nodeIDs []string) (*capacityplanner.VolumesPlacingPlan, error) {
+	acReader := capacityplanner.NewACReader(c.k8sClient, c.logger, true)
+	acrReader := capacityplanner.NewACRReader(c.k8sClient, c.logger, true)
+	unreservedCapReader := capacityplanner.NewUnreservedACReader(c.logger, acReader, acrReader)
+	capManager := c.capacityManagerBuilder.GetCapacityManager(c.logger, unreservedCapReader)
+	return capManager.PlanVolumesPlacing(ctx, volumes, nodeIDs)
+}
+
+// removeReservation removes ACR, not found error is ignored
+func (c CSISchedulerPlugin) removeReservation(ctx context.Context, name string) error {
+	reservation := &acrcrd.AvailableCapacityReservation{}
+	if err := c.k8sClient.ReadCR(ctx, name, "", reservation); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if err := c.k8sClient.DeleteCR(ctx, reservation); err != nil && !k8serrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// convertToVolumes converts capacity requests to volumes which are used by capacity planner
+func convertToVolumes(requests []*genV1.CapacityRequest) []*genV1.Volume {
+	volumes := make([]*genV1.Volume, len(requests))
+	for i, request := range requests {
+		volumes[i] = &genV1.Volume{Id: request.Name, Size: request.Size, StorageClass: request.StorageClass}
+	}
+	return volumes
 }
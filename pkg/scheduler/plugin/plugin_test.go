@@ -0,0 +1,308 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	coreV1 "k8s.io/api/core/v1"
+	storageV1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	schedulerapi "k8s.io/kubernetes/pkg/scheduler/api/v1"
+	framework "k8s.io/kubernetes/pkg/scheduler/framework/v1alpha1"
+	schedulernodeinfo "k8s.io/kubernetes/pkg/scheduler/nodeinfo"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	genV1 "github.com/dell/csi-baremetal/api/generated/v1"
+	v1 "github.com/dell/csi-baremetal/api/v1"
+	acrcrd "github.com/dell/csi-baremetal/api/v1/acreservationcrd"
+	"github.com/dell/csi-baremetal/api/v1/nodecrd"
+	"github.com/dell/csi-baremetal/pkg/base"
+	fc "github.com/dell/csi-baremetal/pkg/base/featureconfig"
+	"github.com/dell/csi-baremetal/pkg/base/k8s"
+	"github.com/dell/csi-baremetal/pkg/base/util"
+	"github.com/dell/csi-baremetal/pkg/crcontrollers/reservation"
+	"github.com/dell/csi-baremetal/pkg/scheduler/extender"
+)
+
+var (
+	testLogger      = logrus.New()
+	testCtx         = context.Background()
+	testNs          = "default"
+	testProvisioner = "baremetal-csi"
+	testSCName      = "csi-baremetal-sc-hdd"
+	testPVCName     = "pvc-1"
+
+	testNode1 = coreV1.Node{ObjectMeta: metaV1.ObjectMeta{Name: "node-1", UID: types.UID("node-1-uid")}}
+	testNode2 = coreV1.Node{ObjectMeta: metaV1.ObjectMeta{Name: "node-2", UID: types.UID("node-2-uid")}}
+
+	testSC = storageV1.StorageClass{
+		ObjectMeta:  metaV1.ObjectMeta{Name: testSCName},
+		Provisioner: testProvisioner,
+		Parameters:  map[string]string{base.StorageTypeKey: v1.StorageClassHDD},
+	}
+
+	testPVC = coreV1.PersistentVolumeClaim{
+		TypeMeta:   metaV1.TypeMeta{Kind: "PersistentVolumeClaim", APIVersion: "v1"},
+		ObjectMeta: metaV1.ObjectMeta{Name: testPVCName, Namespace: testNs},
+		Spec: coreV1.PersistentVolumeClaimSpec{
+			StorageClassName: &testSCName,
+			Resources: coreV1.ResourceRequirements{
+				Requests: coreV1.ResourceList{
+					coreV1.ResourceStorage: *resource.NewQuantity(int64(util.GBYTE), resource.DecimalSI),
+				},
+			},
+		},
+	}
+
+	testPod = coreV1.Pod{
+		ObjectMeta: metaV1.ObjectMeta{Name: "pod-1", Namespace: testNs},
+		Spec: coreV1.PodSpec{Volumes: []coreV1.Volume{{
+			Name: "vol",
+			VolumeSource: coreV1.VolumeSource{
+				PersistentVolumeClaim: &coreV1.PersistentVolumeClaimVolumeSource{ClaimName: testPVCName},
+			},
+		}}},
+	}
+)
+
+// fakeHandle is a framework.FrameworkHandle which returns snapshot with predefined nodes
+type fakeHandle struct {
+	framework.FrameworkHandle
+	snapshot *schedulernodeinfo.Snapshot
+}
+
+func (h *fakeHandle) NodeInfoSnapshot() *schedulernodeinfo.Snapshot {
+	return h.snapshot
+}
+
+func setup(t testing.TB, nodes ...coreV1.Node) *CSISchedulerPlugin {
+	k, err := k8s.GetFakeKubeClient(testNs, testLogger)
+	assert.Nil(t, err)
+	kubeClient := k8s.NewKubeClient(k, testLogger, testNs)
+	kubeCache := k8s.NewKubeCache(k, testLogger)
+
+	snapshot := schedulernodeinfo.NewSnapshot()
+	for _, node := range nodes {
+		node := node
+		info := schedulernodeinfo.NewNodeInfo()
+		assert.Nil(t, info.SetNode(&node))
+		snapshot.NodeInfoMap[node.Name] = info
+	}
+
+	sc := testSC
+	pvc := testPVC
+	assert.Nil(t, kubeClient.Create(testCtx, &sc))
+	assert.Nil(t, kubeClient.Create(testCtx, &pvc))
+
+	p, err := newPlugin(&fakeHandle{snapshot: snapshot}, testLogger, kubeClient, kubeCache,
+		testProvisioner, fc.NewFeatureConfig(), "")
+	assert.Nil(t, err)
+	return p
+}
+
+func createAC(t testing.TB, p *CSISchedulerPlugin, name string, node coreV1.Node, size int64) {
+	ac := p.k8sClient.ConstructACCR(name, genV1.AvailableCapacity{
+		Location: name, NodeId: string(node.UID), StorageClass: v1.StorageClassHDD, Size: size})
+	assert.Nil(t, p.k8sClient.CreateCR(testCtx, name, ac))
+}
+
+func readACR(t *testing.T, p *CSISchedulerPlugin, pod *coreV1.Pod) (*acrcrd.AvailableCapacityReservation, error) {
+	acr := &acrcrd.AvailableCapacityReservation{}
+	err := p.k8sClient.ReadCR(testCtx, testNs+"-"+pod.Name, "", acr)
+	return acr, err
+}
+
+func TestCSISchedulerPlugin_FilterReserveUnreserve(t *testing.T) {
+	p := setup(t, testNode1, testNode2)
+	createAC(t, p, "ac-1", testNode1, int64(util.GBYTE)*2)
+
+	pc := framework.NewPluginContext()
+	pod := testPod.DeepCopy()
+
+	assert.True(t, p.Filter(pc, pod, testNode1.Name).IsSuccess())
+	status := p.Filter(pc, pod, testNode2.Name)
+	assert.Equal(t, framework.Unschedulable, status.Code())
+
+	// reserve
+	assert.True(t, p.Reserve(pc, pod, testNode1.Name).IsSuccess())
+	acr, err := readACR(t, p, pod)
+	assert.Nil(t, err)
+	assert.Equal(t, v1.ReservationConfirmed, acr.Spec.Status)
+	assert.Equal(t, []string{string(testNode1.UID)}, acr.Spec.NodeRequests.Reserved)
+	assert.Equal(t, 1, len(acr.Spec.ReservationRequests))
+	assert.Equal(t, testPVCName, acr.Spec.ReservationRequests[0].CapacityRequest.Name)
+	assert.Equal(t, []string{"ac-1"}, acr.Spec.ReservationRequests[0].Reservations)
+
+	// AC is reserved, another pod can't use it
+	anotherPod := testPod.DeepCopy()
+	anotherPod.Name = "pod-2"
+	assert.Equal(t, framework.Unschedulable, p.Filter(framework.NewPluginContext(), anotherPod, testNode1.Name).Code())
+
+	// repeated reserve for the same pod replaces previous reservation
+	assert.True(t, p.Reserve(framework.NewPluginContext(), pod, testNode1.Name).IsSuccess())
+
+	// binding failed, reservation is rolled back
+	p.Unreserve(pc, pod, testNode1.Name)
+	_, err = readACR(t, p, pod)
+	assert.NotNil(t, err)
+	// unreserve is idempotent
+	p.Unreserve(pc, pod, testNode1.Name)
+
+	assert.True(t, p.Filter(framework.NewPluginContext(), anotherPod, testNode1.Name).IsSuccess())
+}
+
+func TestCSISchedulerPlugin_ReserveNoCapacity(t *testing.T) {
+	p := setup(t, testNode1)
+	pod := testPod.DeepCopy()
+
+	status := p.Reserve(framework.NewPluginContext(), pod, testNode1.Name)
+	assert.Equal(t, framework.Unschedulable, status.Code())
+	_, err := readACR(t, p, pod)
+	assert.NotNil(t, err)
+}
+
+func TestCSISchedulerPlugin_ReserveCordonedNode(t *testing.T) {
+	p := setup(t, testNode1)
+	createAC(t, p, "ac-1", testNode1, int64(util.GBYTE)*2)
+	pod := testPod.DeepCopy()
+
+	// node is decommissioned, its capacity isn't reserved on Reserve stage as well as on Filter stage
+	node := &nodecrd.Node{
+		ObjectMeta: metaV1.ObjectMeta{Name: "csibmnode-1",
+			Annotations: map[string]string{v1.NodeAnnotationDecommission: v1.DecommissionPolicyWait}},
+		Spec: genV1.Node{UUID: string(testNode1.UID)},
+	}
+	assert.Nil(t, p.k8sClient.Create(testCtx, node))
+
+	status := p.Reserve(framework.NewPluginContext(), pod, testNode1.Name)
+	assert.Equal(t, framework.Unschedulable, status.Code())
+	_, err := readACR(t, p, pod)
+	assert.NotNil(t, err)
+}
+
+func TestCSISchedulerPlugin_NoVolumes(t *testing.T) {
+	p := setup(t, testNode1)
+	pod := testPod.DeepCopy()
+	pod.Spec.Volumes = nil
+	pc := framework.NewPluginContext()
+
+	assert.True(t, p.Filter(pc, pod, testNode1.Name).IsSuccess())
+	assert.True(t, p.Reserve(pc, pod, testNode1.Name).IsSuccess())
+	_, err := readACR(t, p, pod)
+	assert.NotNil(t, err)
+}
+
+func TestCSISchedulerPlugin_Score(t *testing.T) {
+	p := setup(t, testNode1, testNode2)
+	pod := testPod.DeepCopy()
+	pod.Spec.Volumes = nil
+
+	// no volumes, all nodes have max score
+	score, status := p.Score(framework.NewPluginContext(), pod, testNode1.Name)
+	assert.True(t, status.IsSuccess())
+	assert.Equal(t, framework.MaxNodeScore, score)
+
+	for _, name := range []string{"vol-1", "vol-2"} {
+		vol := p.k8sClient.ConstructVolumeCR(name, testNs, genV1.Volume{Id: name, NodeId: string(testNode1.UID)})
+		assert.Nil(t, p.k8sClient.CreateCR(testCtx, name, vol))
+	}
+
+	pc := framework.NewPluginContext()
+	score, status = p.Score(pc, pod, testNode1.Name)
+	assert.True(t, status.IsSuccess())
+	assert.Equal(t, 0, score)
+	score, status = p.Score(pc, pod, testNode2.Name)
+	assert.True(t, status.IsSuccess())
+	assert.Equal(t, framework.MaxNodeScore, score)
+}
+
+// BenchmarkScheduling compares latency of capacity reservation by the plugin, which plans capacity in
+// the scheduler process, and by the extender, which waits for reservation controller to confirm reservation
+func BenchmarkScheduling(b *testing.B) {
+	level := testLogger.GetLevel()
+	testLogger.SetLevel(logrus.ErrorLevel)
+	defer testLogger.SetLevel(level)
+
+	const nodeNum = 20
+	nodes := make([]coreV1.Node, nodeNum)
+	for i := range nodes {
+		name := fmt.Sprintf("node-%d", i)
+		nodes[i] = coreV1.Node{ObjectMeta: metaV1.ObjectMeta{Name: name, UID: types.UID(name + "-uid")}}
+	}
+	p := setup(b, nodes...)
+	for i, node := range nodes {
+		createAC(b, p, fmt.Sprintf("ac-%d", i), node, int64(util.GBYTE)*2)
+	}
+	pod := testPod.DeepCopy()
+	reservationName := extender.GetReservationName(pod)
+
+	b.Run("plugin", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			pc := framework.NewPluginContext()
+			for _, node := range nodes {
+				p.Filter(pc, pod, node.Name)
+			}
+			if status := p.Reserve(pc, pod, nodes[0].Name); !status.IsSuccess() {
+				b.Fatal(status.Message())
+			}
+			p.Unreserve(pc, pod, nodes[0].Name)
+		}
+	})
+
+	// fake KubeClient used by controller reads cluster scoped objects from its namespace
+	sc := testSC
+	sc.Namespace = testNs
+	assert.Nil(b, p.k8sClient.Create(testCtx, &sc))
+	controller := reservation.NewController(p.k8sClient, nil, testLogger)
+	args, err := json.Marshal(schedulerapi.ExtenderArgs{Pod: pod, Nodes: &coreV1.NodeList{Items: nodes}})
+	assert.Nil(b, err)
+	filter := func() *schedulerapi.ExtenderFilterResult {
+		w := httptest.NewRecorder()
+		p.extender.FilterHandler(w, httptest.NewRequest("POST", "/filter", bytes.NewReader(args)))
+		result := &schedulerapi.ExtenderFilterResult{}
+		if err := json.NewDecoder(w.Body).Decode(result); err != nil {
+			b.Fatal(err)
+		}
+		return result
+	}
+
+	b.Run("extender", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			// the first filter request creates reservation, controller confirms it and the second one gets nodes
+			filter()
+			if _, err := controller.Reconcile(ctrl.Request{NamespacedName: types.NamespacedName{Name: reservationName}}); err != nil {
+				b.Fatal(err)
+			}
+			if result := filter(); result.Nodes == nil || len(result.Nodes.Items) == 0 {
+				b.Fatalf("nodes aren't matched: %s", result.Error)
+			}
+			if err := p.removeReservation(testCtx, reservationName); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
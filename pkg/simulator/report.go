@@ -0,0 +1,126 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulator
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	accrd "github.com/dell/csi-baremetal/api/v1/availablecapacitycrd"
+)
+
+// Report holds placement plan of the volumes and capacity which is left on the nodes
+type Report struct {
+	Placements []Placement `json:"placements"`
+	// Unplaced holds IDs of volumes for which capacity wasn't found
+	Unplaced []string `json:"unplaced,omitempty"`
+	// Capacity holds unreserved capacity per node and storage class before and after placement
+	Capacity []NodeCapacity `json:"capacity"`
+}
+
+// Placement describes the capacity selected for the volume
+type Placement struct {
+	Volume       string `json:"volume"`
+	Node         string `json:"node"`
+	StorageClass string `json:"storageClass"`
+	// Size is an allocated size, volume on the whole drive allocates the size of the drive
+	Size     int64  `json:"size"`
+	AC       string `json:"ac"`
+	Location string `json:"location"`
+}
+
+// NodeCapacity describes unreserved capacity of the storage class on the node
+type NodeCapacity struct {
+	Node         string `json:"node"`
+	StorageClass string `json:"storageClass"`
+	ACsBefore    int    `json:"acsBefore"`
+	BytesBefore  int64  `json:"bytesBefore"`
+	ACsAfter     int    `json:"acsAfter"`
+	BytesAfter   int64  `json:"bytesAfter"`
+}
+
+// WriteJSON writes report in JSON format
+func (r *Report) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+// WriteTable writes report as human readable tables
+func (r *Report) WriteTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "VOLUME\tNODE\tSTORAGE CLASS\tSIZE\tAC\tLOCATION")
+	for _, p := range r.Placements {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", p.Volume, p.Node, p.StorageClass, formatSize(p.Size), p.AC, p.Location)
+	}
+	for _, volume := range r.Unplaced {
+		fmt.Fprintf(tw, "%s\t<none>\t\t\t\t\n", volume)
+	}
+	fmt.Fprintln(tw)
+	fmt.Fprintln(tw, "NODE\tSTORAGE CLASS\tACS BEFORE\tFREE BEFORE\tACS AFTER\tFREE AFTER")
+	for _, c := range r.Capacity {
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%s\t%d\t%s\n", c.Node, c.StorageClass,
+			c.ACsBefore, formatSize(c.BytesBefore), c.ACsAfter, formatSize(c.BytesAfter))
+	}
+	fmt.Fprintln(tw)
+	fmt.Fprintf(tw, "Placed: %d, unplaced: %d\n", len(r.Placements), len(r.Unplaced))
+	return tw.Flush()
+}
+
+// buildCapacityReport groups capacity by node and storage class, result is sorted by node and storage class
+func buildCapacityReport(before, after []accrd.AvailableCapacity) []NodeCapacity {
+	type key struct{ node, sc string }
+	capacity := map[key]*NodeCapacity{}
+	get := func(ac accrd.AvailableCapacity) *NodeCapacity {
+		k := key{node: ac.Spec.NodeId, sc: ac.Spec.StorageClass}
+		if _, ok := capacity[k]; !ok {
+			capacity[k] = &NodeCapacity{Node: k.node, StorageClass: k.sc}
+		}
+		return capacity[k]
+	}
+	for _, ac := range before {
+		c := get(ac)
+		c.ACsBefore++
+		c.BytesBefore += ac.Spec.Size
+	}
+	for _, ac := range after {
+		c := get(ac)
+		c.ACsAfter++
+		c.BytesAfter += ac.Spec.Size
+	}
+
+	result := make([]NodeCapacity, 0, len(capacity))
+	for _, c := range capacity {
+		result = append(result, *c)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Node != result[j].Node {
+			return result[i].Node < result[j].Node
+		}
+		return result[i].StorageClass < result[j].StorageClass
+	})
+	return result
+}
+
+func formatSize(size int64) string {
+	return resource.NewQuantity(size, resource.BinarySI).String()
+}
@@ -0,0 +1,249 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package simulator contains offline placement simulator which runs capacityplanner logic
+// against snapshot of AvailableCapacities and AvailableCapacityReservations
+package simulator
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	genV1 "github.com/dell/csi-baremetal/api/generated/v1"
+	v1 "github.com/dell/csi-baremetal/api/v1"
+	acrcrd "github.com/dell/csi-baremetal/api/v1/acreservationcrd"
+	accrd "github.com/dell/csi-baremetal/api/v1/availablecapacitycrd"
+	"github.com/dell/csi-baremetal/pkg/base/capacityplanner"
+	"github.com/dell/csi-baremetal/pkg/base/util"
+)
+
+// Simulator places hypothetical volumes on capacity of the snapshot without any changes in the cluster
+type Simulator struct {
+	logger *logrus.Entry
+}
+
+// NewSimulator returns new instance of Simulator
+func NewSimulator(logger *logrus.Entry) *Simulator {
+	return &Simulator{logger: logger}
+}
+
+// Run places volumes of the scenario one by one the same way as reservation controller does:
+// capacity reserved by ACRs of the snapshot is skipped, capacity of placed volume isn't available for the next ones
+func (s *Simulator) Run(ctx context.Context, snapshot *Snapshot, scenario *Scenario) (*Report, error) {
+	acs := make([]accrd.AvailableCapacity, 0, len(snapshot.ACs))
+	for _, ac := range snapshot.ACs {
+		acs = append(acs, *ac.DeepCopy())
+	}
+	newACs, err := buildNodesCapacity(scenario.NewNodes)
+	if err != nil {
+		return nil, err
+	}
+	acs = append(acs, newACs...)
+	volumes, err := buildVolumes(scenario.Volumes)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := scenario.Nodes
+	if len(nodes) == 0 {
+		nodes = nodesOfCapacity(acs)
+	}
+
+	state := &capacityState{acs: acs}
+	resReader := &staticReservationReader{acrs: snapshot.ACRs}
+	report := &Report{}
+	before := state.freeCapacity(ctx, s.logger, resReader)
+
+	for _, volume := range volumes {
+		capReader := capacityplanner.NewUnreservedACReader(s.logger, state, resReader)
+		plan, err := capacityplanner.NewCapacityManager(s.logger, capReader).
+			PlanVolumesPlacing(ctx, []*genV1.Volume{volume}, nodes)
+		if err != nil {
+			return nil, err
+		}
+		var node string
+		if plan != nil {
+			node = plan.SelectNode()
+		}
+		if node == "" {
+			s.logger.Debugf("Capacity for volume %s not found", volume.Id)
+			report.Unplaced = append(report.Unplaced, volume.Id)
+			continue
+		}
+		ac := plan.GetACForVolume(node, volume)
+		allocated := state.consume(ac.Name, volume)
+		s.logger.Debugf("Volume %s is placed on node %s, AC %s", volume.Id, node, ac.Name)
+		report.Placements = append(report.Placements, Placement{
+			Volume:       volume.Id,
+			Node:         node,
+			StorageClass: volume.StorageClass,
+			Size:         allocated,
+			AC:           ac.Name,
+			Location:     ac.Spec.Location,
+		})
+	}
+
+	report.Capacity = buildCapacityReport(before, state.freeCapacity(ctx, s.logger, resReader))
+	return report, nil
+}
+
+// buildNodesCapacity returns ACs for drives of hypothetical nodes
+func buildNodesCapacity(nodes []NodeTemplate) ([]accrd.AvailableCapacity, error) {
+	var result []accrd.AvailableCapacity
+	for _, node := range nodes {
+		if node.Name == "" {
+			return nil, fmt.Errorf("name of the new node is required")
+		}
+		for _, drive := range node.Drives {
+			sc, err := convertStorageClass(drive.StorageClass)
+			if err != nil {
+				return nil, err
+			}
+			if sc == v1.StorageClassAny || util.IsStorageClassLVG(sc) {
+				return nil, fmt.Errorf("drive of node %s has unsupported storage class %s", node.Name, drive.StorageClass)
+			}
+			if drive.Size.Value() <= 0 {
+				return nil, fmt.Errorf("drive of node %s has non positive size", node.Name)
+			}
+			for i := 0; i < countOrOne(drive.Count); i++ {
+				name := fmt.Sprintf("%s-%s-%d", node.Name, strings.ToLower(sc), len(result))
+				ac := accrd.AvailableCapacity{Spec: genV1.AvailableCapacity{
+					Location:     name,
+					NodeId:       node.Name,
+					StorageClass: sc,
+					Size:         drive.Size.Value(),
+				}}
+				ac.Kind = v1.AvailableCapacityKind
+				ac.APIVersion = v1.APIV1Version
+				ac.Name = name
+				result = append(result, ac)
+			}
+		}
+	}
+	return result, nil
+}
+
+// buildVolumes converts volume requests to the volumes, volume ID has index suffix if request count is more than one
+func buildVolumes(requests []VolumeRequest) ([]*genV1.Volume, error) {
+	var result []*genV1.Volume
+	for _, request := range requests {
+		sc, err := convertStorageClass(request.StorageClass)
+		if err != nil {
+			return nil, err
+		}
+		if request.Size.Value() <= 0 {
+			return nil, fmt.Errorf("volume %s has non positive size", request.Name)
+		}
+		count := countOrOne(request.Count)
+		for i := 0; i < count; i++ {
+			id := request.Name
+			if count > 1 {
+				id = fmt.Sprintf("%s-%d", request.Name, i)
+			}
+			result = append(result, &genV1.Volume{Id: id, StorageClass: sc, Size: request.Size.Value()})
+		}
+	}
+	return result, nil
+}
+
+// convertStorageClass converts storage class to CSI storage class and fails on unknown ones
+func convertStorageClass(sc string) (string, error) {
+	result := util.ConvertStorageClass(sc)
+	if result == v1.StorageClassAny && !strings.EqualFold(sc, v1.StorageClassAny) {
+		return "", fmt.Errorf("unknown storage class %s", sc)
+	}
+	return result, nil
+}
+
+func countOrOne(count int) int {
+	if count <= 0 {
+		return 1
+	}
+	return count
+}
+
+func nodesOfCapacity(acs []accrd.AvailableCapacity) []string {
+	nodes := map[string]struct{}{}
+	for _, ac := range acs {
+		nodes[ac.Spec.NodeId] = struct{}{}
+	}
+	result := make([]string, 0, len(nodes))
+	for node := range nodes {
+		result = append(result, node)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// capacityState holds ACs which are modified by placed volumes, implements capacityplanner.CapacityReader
+type capacityState struct {
+	acs []accrd.AvailableCapacity
+}
+
+// ReadCapacity returns copy of the current ACs
+func (cs *capacityState) ReadCapacity(_ context.Context) ([]accrd.AvailableCapacity, error) {
+	result := make([]accrd.AvailableCapacity, 0, len(cs.acs))
+	for _, ac := range cs.acs {
+		result = append(result, *ac.DeepCopy())
+	}
+	return result, nil
+}
+
+// consume modifies AC the same way as CreateVolume does and returns allocated size:
+// AC of full drive volume is removed, AC of LVM volume is converted to LVG AC and decreased by volume size
+func (cs *capacityState) consume(acName string, volume *genV1.Volume) int64 {
+	for i := range cs.acs {
+		ac := &cs.acs[i]
+		if ac.Name != acName {
+			continue
+		}
+		if !util.IsStorageClassLVG(volume.StorageClass) {
+			size := ac.Spec.Size
+			cs.acs = append(cs.acs[:i], cs.acs[i+1:]...)
+			return size
+		}
+		if ac.Spec.StorageClass != volume.StorageClass {
+			ac.Spec.StorageClass = volume.StorageClass
+			ac.Spec.Size = capacityplanner.SubtractLVMMetadataSize(ac.Spec.Size)
+		}
+		size := capacityplanner.AlignSizeByPE(volume.Size)
+		ac.Spec.Size -= size
+		return size
+	}
+	return 0
+}
+
+// freeCapacity returns ACs which aren't reserved by ACRs
+func (cs *capacityState) freeCapacity(ctx context.Context, logger *logrus.Entry,
+	resReader capacityplanner.ReservationReader) []accrd.AvailableCapacity {
+	// static readers never fail
+	acs, _ := capacityplanner.NewUnreservedACReader(logger, cs, resReader).ReadCapacity(ctx)
+	return acs
+}
+
+// staticReservationReader returns ACRs of the snapshot, implements capacityplanner.ReservationReader
+type staticReservationReader struct {
+	acrs []acrcrd.AvailableCapacityReservation
+}
+
+// ReadReservations returns ACRs of the snapshot
+func (sr *staticReservationReader) ReadReservations(_ context.Context) ([]acrcrd.AvailableCapacityReservation, error) {
+	return sr.acrs, nil
+}
@@ -0,0 +1,169 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulator
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	genV1 "github.com/dell/csi-baremetal/api/generated/v1"
+	v1 "github.com/dell/csi-baremetal/api/v1"
+	acrcrd "github.com/dell/csi-baremetal/api/v1/acreservationcrd"
+	accrd "github.com/dell/csi-baremetal/api/v1/availablecapacitycrd"
+	"github.com/dell/csi-baremetal/pkg/base/capacityplanner"
+)
+
+var (
+	testCtx    = context.Background()
+	testLogger = logrus.NewEntry(logrus.New())
+
+	testSize = resource.MustParse("1Ti")
+
+	testDump = `apiVersion: v1
+kind: List
+items:
+- apiVersion: csi-baremetal.dell.com/v1
+  kind: AvailableCapacity
+  metadata:
+    name: ac-1
+  spec:
+    Location: drive-1
+    NodeId: node-1
+    storageClass: SSD
+    Size: 1099511627776
+- apiVersion: csi-baremetal.dell.com/v1
+  kind: Drive
+  metadata:
+    name: drive-1
+---
+apiVersion: csi-baremetal.dell.com/v1
+kind: AvailableCapacityReservation
+metadata:
+  name: default-pod
+spec:
+  ReservationRequests:
+  - Reservations: [ac-1]
+`
+)
+
+func newAC(name, node, sc string, size int64) accrd.AvailableCapacity {
+	ac := accrd.AvailableCapacity{Spec: genV1.AvailableCapacity{Location: name, NodeId: node, StorageClass: sc, Size: size}}
+	ac.Name = name
+	return ac
+}
+
+func TestLoadSnapshot(t *testing.T) {
+	dir, err := ioutil.TempDir("", "simulator")
+	assert.Nil(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	path := filepath.Join(dir, "dump.yaml")
+	assert.Nil(t, ioutil.WriteFile(path, []byte(testDump), 0600))
+
+	snapshot, err := LoadSnapshot([]string{path})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(snapshot.ACs))
+	assert.Equal(t, "node-1", snapshot.ACs[0].Spec.NodeId)
+	assert.Equal(t, testSize.Value(), snapshot.ACs[0].Spec.Size)
+	assert.Equal(t, 1, len(snapshot.ACRs))
+	assert.Equal(t, []string{"ac-1"}, snapshot.ACRs[0].Spec.ReservationRequests[0].Reservations)
+
+	_, err = LoadSnapshot([]string{filepath.Join(dir, "missing.yaml")})
+	assert.NotNil(t, err)
+}
+
+func TestSimulator_Run(t *testing.T) {
+	snapshot := &Snapshot{
+		ACs: []accrd.AvailableCapacity{
+			newAC("ac-1", "node-1", v1.StorageClassSSD, testSize.Value()),
+			newAC("ac-2", "node-2", v1.StorageClassSSD, testSize.Value()),
+			newAC("ac-3", "node-2", v1.StorageClassHDD, 2*testSize.Value()),
+		},
+		ACRs: []acrcrd.AvailableCapacityReservation{{
+			Spec: genV1.AvailableCapacityReservation{ReservationRequests: []*genV1.ReservationRequest{
+				{Reservations: []string{"ac-2"}}}},
+		}},
+	}
+	scenario := &Scenario{
+		NewNodes: []NodeTemplate{{Name: "node-3", Drives: []DriveTemplate{
+			{StorageClass: "ssd", Size: testSize, Count: 1}}}},
+		Volumes: []VolumeRequest{
+			{Name: "data", StorageClass: "SSD", Size: resource.MustParse("500Gi"), Count: 3},
+			{Name: "logs", StorageClass: "HDDLVG", Size: resource.MustParse("100Gi"), Count: 2},
+		},
+	}
+
+	report, err := NewSimulator(testLogger).Run(testCtx, snapshot, scenario)
+	assert.Nil(t, err)
+	// reserved AC is skipped, volume on the whole drive allocates the drive
+	assert.Equal(t, 4, len(report.Placements))
+	assert.Equal(t, []string{"data-2"}, report.Unplaced)
+	for _, p := range report.Placements[:2] {
+		assert.Equal(t, testSize.Value(), p.Size)
+		assert.NotEqual(t, "ac-2", p.AC)
+	}
+	// both LVM volumes use the same drive
+	lvmSize := resource.MustParse("100Gi")
+	for _, p := range report.Placements[2:] {
+		assert.Equal(t, "node-2", p.Node)
+		assert.Equal(t, "ac-3", p.AC)
+		assert.Equal(t, capacityplanner.AlignSizeByPE(lvmSize.Value()), p.Size)
+	}
+	// snapshot isn't modified
+	assert.Equal(t, v1.StorageClassHDD, snapshot.ACs[2].Spec.StorageClass)
+
+	capacity := map[string]NodeCapacity{}
+	for _, c := range report.Capacity {
+		capacity[c.Node+"/"+c.StorageClass] = c
+	}
+	assert.Equal(t, 0, capacity["node-2/"+v1.StorageClassHDD].ACsAfter)
+	assert.Equal(t, 1, capacity["node-2/"+v1.StorageClassHDDLVG].ACsAfter)
+	assert.Equal(t, 1, capacity["node-3/"+v1.StorageClassSSD].ACsBefore)
+	assert.Equal(t, 0, capacity["node-3/"+v1.StorageClassSSD].ACsAfter)
+
+	buf := &bytes.Buffer{}
+	assert.Nil(t, report.WriteTable(buf))
+	assert.Contains(t, buf.String(), "data-2")
+	buf.Reset()
+	assert.Nil(t, report.WriteJSON(buf))
+	assert.Contains(t, buf.String(), `"unplaced": [`)
+
+	// nodes restriction
+	scenario.Nodes = []string{"node-1"}
+	report, err = NewSimulator(testLogger).Run(testCtx, snapshot, scenario)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(report.Placements))
+	assert.Equal(t, 4, len(report.Unplaced))
+}
+
+func TestSimulator_RunInvalidScenario(t *testing.T) {
+	_, err := NewSimulator(testLogger).Run(testCtx, &Snapshot{}, &Scenario{
+		Volumes: []VolumeRequest{{Name: "data", StorageClass: "tape", Size: testSize}}})
+	assert.NotNil(t, err)
+
+	_, err = NewSimulator(testLogger).Run(testCtx, &Snapshot{}, &Scenario{
+		NewNodes: []NodeTemplate{{Name: "node", Drives: []DriveTemplate{{StorageClass: "HDDLVG", Size: testSize}}}}})
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,170 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/yaml"
+
+	v1 "github.com/dell/csi-baremetal/api/v1"
+	acrcrd "github.com/dell/csi-baremetal/api/v1/acreservationcrd"
+	accrd "github.com/dell/csi-baremetal/api/v1/availablecapacitycrd"
+	"github.com/dell/csi-baremetal/pkg/base/k8s"
+)
+
+// Snapshot holds AvailableCapacities and AvailableCapacityReservations of the cluster
+type Snapshot struct {
+	ACs  []accrd.AvailableCapacity
+	ACRs []acrcrd.AvailableCapacityReservation
+}
+
+// Scenario describes hypothetical volumes which should be placed and nodes which could be used for them
+type Scenario struct {
+	// Nodes restricts placement to the node IDs, all nodes of the snapshot and NewNodes are used if empty
+	Nodes []string `json:"nodes,omitempty"`
+	// NewNodes are hypothetical nodes which are added to the snapshot
+	NewNodes []NodeTemplate `json:"newNodes,omitempty"`
+	// Volumes are hypothetical volumes, they are placed one by one in the order of the list
+	Volumes []VolumeRequest `json:"volumes"`
+}
+
+// NodeTemplate describes drives of the hypothetical node
+type NodeTemplate struct {
+	Name   string          `json:"name"`
+	Drives []DriveTemplate `json:"drives"`
+}
+
+// DriveTemplate describes count drives of the same storage class and size
+type DriveTemplate struct {
+	StorageClass string            `json:"storageClass"`
+	Size         resource.Quantity `json:"size"`
+	Count        int               `json:"count,omitempty"`
+}
+
+// VolumeRequest describes count volumes of the same storage class and size, each volume is requested by separate pod
+type VolumeRequest struct {
+	Name         string            `json:"name"`
+	StorageClass string            `json:"storageClass"`
+	Size         resource.Quantity `json:"size"`
+	Count        int               `json:"count,omitempty"`
+}
+
+// LoadScenario reads Scenario from YAML or JSON file
+func LoadScenario(path string) (*Scenario, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	scenario := &Scenario{}
+	if err = yaml.UnmarshalStrict(data, scenario); err != nil {
+		return nil, fmt.Errorf("unable to parse scenario %s: %v", path, err)
+	}
+	if len(scenario.Volumes) == 0 {
+		return nil, fmt.Errorf("scenario %s has no volumes", path)
+	}
+	return scenario, nil
+}
+
+// LoadSnapshot reads AvailableCapacities and AvailableCapacityReservations from YAML dumps,
+// dump could be created with "kubectl get ac,acr -o yaml", multiple documents in one file are supported,
+// objects of other kinds are skipped
+func LoadSnapshot(paths []string) (*Snapshot, error) {
+	snapshot := &Snapshot{}
+	for _, path := range paths {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, doc := range bytes.Split(data, []byte("\n---")) {
+			if len(bytes.TrimSpace(doc)) == 0 {
+				continue
+			}
+			if err = snapshot.addDocument(doc); err != nil {
+				return nil, fmt.Errorf("unable to parse %s: %v", path, err)
+			}
+		}
+	}
+	return snapshot, nil
+}
+
+// ReadSnapshot reads AvailableCapacities and AvailableCapacityReservations from the cluster
+func ReadSnapshot(ctx context.Context, client *k8s.KubeClient) (*Snapshot, error) {
+	acList := &accrd.AvailableCapacityList{}
+	if err := client.ReadList(ctx, acList); err != nil {
+		return nil, fmt.Errorf("unable to read AvailableCapacities: %v", err)
+	}
+	acrList := &acrcrd.AvailableCapacityReservationList{}
+	if err := client.ReadList(ctx, acrList); err != nil {
+		return nil, fmt.Errorf("unable to read AvailableCapacityReservations: %v", err)
+	}
+	return &Snapshot{ACs: acList.Items, ACRs: acrList.Items}, nil
+}
+
+// addDocument adds objects of the YAML document, document could hold single object or list
+func (s *Snapshot) addDocument(doc []byte) error {
+	data, err := yaml.YAMLToJSON(doc)
+	if err != nil {
+		return err
+	}
+	var object struct {
+		Kind  string            `json:"kind"`
+		Items []json.RawMessage `json:"items"`
+	}
+	if err = json.Unmarshal(data, &object); err != nil {
+		return err
+	}
+	if !strings.HasSuffix(object.Kind, "List") {
+		return s.addObject(object.Kind, data)
+	}
+	for _, item := range object.Items {
+		var itemKind struct {
+			Kind string `json:"kind"`
+		}
+		if err = json.Unmarshal(item, &itemKind); err != nil {
+			return err
+		}
+		if err = s.addObject(itemKind.Kind, item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Snapshot) addObject(kind string, data []byte) error {
+	switch kind {
+	case v1.AvailableCapacityKind:
+		ac := accrd.AvailableCapacity{}
+		if err := json.Unmarshal(data, &ac); err != nil {
+			return err
+		}
+		s.ACs = append(s.ACs, ac)
+	case v1.AvailableCapacityReservationKind:
+		acr := acrcrd.AvailableCapacityReservation{}
+		if err := json.Unmarshal(data, &acr); err != nil {
+			return err
+		}
+		s.ACRs = append(s.ACRs, acr)
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package webhook contains webhooks which are served by csi-baremetal controller
+package webhook
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	k8sCl "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/conversion"
+
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+)
+
+// ConversionPath is a path of the conversion webhook, it must be the same as in CRDs
+const ConversionPath = "/convert"
+
+// ConvertibleCRDs are names of CRDs which have v1 and v1alpha2 versions
+var ConvertibleCRDs = []string{
+	"volumes." + apiV1.CSICRsGroupVersion,
+	"drives." + apiV1.CSICRsGroupVersion,
+	"logicalvolumegroups." + apiV1.CSICRsGroupVersion,
+	"availablecapacities." + apiV1.CSICRsGroupVersion,
+	"availablecapacityreservations." + apiV1.CSICRsGroupVersion,
+}
+
+// crdGVK is a GroupVersionKind of CustomResourceDefinition, CRD is read as unstructured object
+// since apiextensions types aren't a dependency of the project
+var crdGVK = schema.GroupVersionKind{Group: "apiextensions.k8s.io", Version: "v1beta1", Kind: "CustomResourceDefinition"}
+
+// RegisterConversion registers conversion webhook for all the types of the manager scheme,
+// it converts objects through the hub version v1
+func RegisterConversion(mgr ctrl.Manager) {
+	mgr.GetWebhookServer().Register(ConversionPath, &conversion.Webhook{})
+}
+
+// InjectConversionConfig sets webhook service and CA bundle in the conversion config of ConvertibleCRDs.
+// CRDs are installed with the placeholder namespace and without CA bundle since they can't be templated
+func InjectConversionConfig(ctx context.Context, client k8sCl.Client, service types.NamespacedName,
+	caBundle []byte, log *logrus.Logger) error {
+	ll := log.WithField("method", "InjectConversionConfig")
+
+	for _, name := range ConvertibleCRDs {
+		crd := &unstructured.Unstructured{}
+		crd.SetGroupVersionKind(crdGVK)
+		if err := client.Get(ctx, types.NamespacedName{Name: name}, crd); err != nil {
+			return fmt.Errorf("unable to read CRD %s: %v", name, err)
+		}
+		if _, found, _ := unstructured.NestedMap(crd.Object, "spec", "conversion", "webhookClientConfig"); !found {
+			ll.Warnf("CRD %s has no conversion webhook config, it should be re-applied", name)
+			continue
+		}
+
+		config := map[string]string{
+			"namespace": service.Namespace,
+			"name":      service.Name,
+		}
+		for field, value := range config {
+			if err := unstructured.SetNestedField(crd.Object, value,
+				"spec", "conversion", "webhookClientConfig", "service", field); err != nil {
+				return err
+			}
+		}
+		if err := unstructured.SetNestedField(crd.Object, base64.StdEncoding.EncodeToString(caBundle),
+			"spec", "conversion", "webhookClientConfig", "caBundle"); err != nil {
+			return err
+		}
+		if err := client.Update(ctx, crd); err != nil {
+			return fmt.Errorf("unable to update CRD %s: %v", name, err)
+		}
+		ll.Infof("Conversion webhook config of CRD %s points to service %s", name, service)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var (
+	testCtx    = context.Background()
+	testLogger = logrus.New()
+	testSvc    = types.NamespacedName{Namespace: "csi", Name: "csi-baremetal-controller-webhook"}
+)
+
+func newCRD(name string, withConversion bool) *unstructured.Unstructured {
+	crd := &unstructured.Unstructured{Object: map[string]interface{}{"spec": map[string]interface{}{}}}
+	crd.SetGroupVersionKind(crdGVK)
+	crd.SetName(name)
+	if withConversion {
+		crd.Object["spec"] = map[string]interface{}{
+			"conversion": map[string]interface{}{
+				"strategy": "Webhook",
+				"webhookClientConfig": map[string]interface{}{
+					"service": map[string]interface{}{
+						"namespace": "default",
+						"name":      testSvc.Name,
+						"path":      ConversionPath,
+					},
+				},
+			},
+		}
+	}
+	return crd
+}
+
+func TestInjectConversionConfig(t *testing.T) {
+	objs := make([]runtime.Object, 0, len(ConvertibleCRDs))
+	for i, name := range ConvertibleCRDs {
+		// the first CRD is installed without conversion
+		objs = append(objs, newCRD(name, i != 0))
+	}
+	client := fake.NewFakeClientWithScheme(runtime.NewScheme(), objs...)
+
+	assert.Nil(t, InjectConversionConfig(testCtx, client, testSvc, []byte("ca"), testLogger))
+
+	for i, name := range ConvertibleCRDs {
+		crd := newCRD("", false)
+		assert.Nil(t, client.Get(testCtx, types.NamespacedName{Name: name}, crd))
+		config, found, err := unstructured.NestedMap(crd.Object, "spec", "conversion", "webhookClientConfig")
+		assert.Nil(t, err)
+		if i == 0 {
+			assert.False(t, found)
+			continue
+		}
+		assert.Equal(t, map[string]interface{}{
+			"service": map[string]interface{}{
+				"namespace": testSvc.Namespace,
+				"name":      testSvc.Name,
+				"path":      ConversionPath,
+			},
+			"caBundle": "Y2E=",
+		}, config)
+	}
+}
+
+func TestInjectConversionConfig_Fail(t *testing.T) {
+	client := fake.NewFakeClientWithScheme(runtime.NewScheme())
+	assert.NotNil(t, InjectConversionConfig(testCtx, client, testSvc, []byte("ca"), testLogger))
+}
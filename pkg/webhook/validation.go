@@ -0,0 +1,332 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	admissionV1beta1 "k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	"github.com/dell/csi-baremetal/api/v1/drivecrd"
+	"github.com/dell/csi-baremetal/api/v1/lvgcrd"
+	"github.com/dell/csi-baremetal/api/v1/volumecrd"
+	"github.com/dell/csi-baremetal/pkg/base/k8s"
+)
+
+// ValidationPath is a path of the validating webhook, it must be the same as in ValidatingWebhookConfiguration
+const ValidationPath = "/validate"
+
+// driveUsageTransitions are the usage changes of Drive which are done by drive controller and VolumeManager,
+// usage can be changed to FAILED from any state
+var driveUsageTransitions = map[string][]string{
+	apiV1.DriveUsageInUse:     {apiV1.DriveUsageReleasing},
+	apiV1.DriveUsageReleasing: {apiV1.DriveUsageReleased},
+	// volume of the drive might be released after the evacuation
+	apiV1.DriveUsageReleased: {apiV1.DriveUsageReleasing, apiV1.DriveUsageRemoving},
+	apiV1.DriveUsageRemoving: {apiV1.DriveUsageRemoved},
+	apiV1.DriveUsageRemoved:  {},
+	// FAILED drive might be returned to operation or removal might be retried
+	apiV1.DriveUsageFailed: {apiV1.DriveUsageInUse, apiV1.DriveUsageReleasing, apiV1.DriveUsageRemoving},
+}
+
+// volumeUsageTransitions are the usage changes of Volume which are done by VolumeManager and drive controller
+var volumeUsageTransitions = map[string][]string{
+	apiV1.VolumeUsageInUse:     {apiV1.VolumeUsageReleasing},
+	apiV1.VolumeUsageReleasing: {apiV1.VolumeUsageReleased, apiV1.VolumeUsageFailed},
+	apiV1.VolumeUsageReleased:  {},
+	apiV1.VolumeUsageFailed:    {apiV1.VolumeUsageReleasing},
+}
+
+// Validator is a handler of the validating webhook for Drive, Volume and LogicalVolumeGroup custom resources.
+// It rejects illegal usage transitions, changes of immutable fields and removal of the resources with volumes
+// made by users, requests of CSI components are allowed
+type Validator struct {
+	crHelper *k8s.CRHelper
+	decoder  *admission.Decoder
+	// names of the users (service accounts of CSI components) which requests aren't validated
+	exemptUsers map[string]struct{}
+	log         *logrus.Entry
+}
+
+// NewValidator is a constructor for Validator
+// Receives KubeClient, names of the users which requests are always allowed and logrus logger
+func NewValidator(client *k8s.KubeClient, exemptUsers []string, log *logrus.Logger) *Validator {
+	users := make(map[string]struct{}, len(exemptUsers))
+	for _, user := range exemptUsers {
+		users[user] = struct{}{}
+	}
+	return &Validator{
+		crHelper:    k8s.NewCRHelper(client, log),
+		exemptUsers: users,
+		log:         log.WithField("component", "Validator"),
+	}
+}
+
+// RegisterValidation registers validating webhook in the webhook server of the manager
+func RegisterValidation(mgr ctrl.Manager, validator *Validator) {
+	mgr.GetWebhookServer().Register(ValidationPath, &admission.Webhook{Handler: validator})
+}
+
+// InjectDecoder is used by webhook to set decoder of the manager scheme
+func (v *Validator) InjectDecoder(decoder *admission.Decoder) error {
+	v.decoder = decoder
+	return nil
+}
+
+// Handle validates update and delete requests of Drive, Volume and LogicalVolumeGroup,
+// other requests and requests of the exempt users are allowed
+func (v *Validator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	ll := v.log.WithFields(logrus.Fields{
+		"method":    "Handle",
+		"kind":      req.Kind.Kind,
+		"name":      req.Name,
+		"operation": req.Operation,
+	})
+	if _, ok := v.exemptUsers[req.UserInfo.Username]; ok {
+		return admission.Allowed("")
+	}
+
+	var (
+		denyReason string
+		err        error
+	)
+	switch req.Kind.Kind {
+	case "Drive":
+		denyReason, err = v.validateDrive(ctx, req)
+	case "Volume":
+		denyReason, err = v.validateVolume(ctx, req)
+	case "LogicalVolumeGroup":
+		denyReason, err = v.validateLVG(ctx, req)
+	}
+
+	if err != nil {
+		ll.Errorf("Unable to validate request: %v", err)
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	if denyReason != "" {
+		ll.Warnf("Request is denied: %s", denyReason)
+		return denied(denyReason)
+	}
+	return admission.Allowed("")
+}
+
+// denied returns response with Forbidden reason and message which is shown to the user
+func denied(message string) admission.Response {
+	resp := admission.Denied(string(metav1.StatusReasonForbidden))
+	resp.Result.Message = message
+	return resp
+}
+
+// validateDrive returns reason of denial of Drive request or empty string if request is allowed
+func (v *Validator) validateDrive(ctx context.Context, req admission.Request) (string, error) {
+	old := &drivecrd.Drive{}
+	if err := v.decoder.DecodeRaw(req.OldObject, old); err != nil {
+		return "", err
+	}
+
+	if req.Operation == admissionV1beta1.Delete {
+		// finalizer of the drive is removed by drive controller without waiting for the volumes
+		if old.Annotations[apiV1.AnnotationForceDelete] == apiV1.ForceDeleteOn {
+			v.log.WithField("method", "validateDrive").Warnf("Drive %s is force deleted", old.Name)
+			return "", nil
+		}
+		return v.checkVolumesRemoved(ctx, "Drive", old.Name, old.Spec.UUID)
+	}
+	if req.Operation != admissionV1beta1.Update {
+		return "", nil
+	}
+
+	drive := &drivecrd.Drive{}
+	if err := v.decoder.DecodeRaw(req.Object, drive); err != nil {
+		return "", err
+	}
+	for field, values := range map[string][2]string{
+		"uuid":         {old.Spec.UUID, drive.Spec.UUID},
+		"serialNumber": {old.Spec.SerialNumber, drive.Spec.SerialNumber},
+	} {
+		if reason := checkImmutable("Drive", drive.Name, field, values[0], values[1]); reason != "" {
+			return reason, nil
+		}
+	}
+	// drive of the replaced node is adopted by the new node only in OFFLINE state
+	if old.Spec.NodeId != "" && old.Spec.NodeId != drive.Spec.NodeId && old.Spec.Status != apiV1.DriveStatusOffline {
+		return fmt.Sprintf("node of Drive %s can't be changed from %s to %s, drive is %s",
+			drive.Name, old.Spec.NodeId, drive.Spec.NodeId, old.Spec.Status), nil
+	}
+	return checkUsageTransition("Drive", drive.Name, old.Spec.Usage, drive.Spec.Usage,
+		driveUsageTransitions, apiV1.DriveUsageFailed), nil
+}
+
+// validateVolume returns reason of denial of Volume request or empty string if request is allowed
+func (v *Validator) validateVolume(ctx context.Context, req admission.Request) (string, error) {
+	old := &volumecrd.Volume{}
+	if err := v.decoder.DecodeRaw(req.OldObject, old); err != nil {
+		return "", err
+	}
+
+	if req.Operation == admissionV1beta1.Delete {
+		return checkVolumeRemoved(old), nil
+	}
+	if req.Operation != admissionV1beta1.Update {
+		return "", nil
+	}
+
+	volume := &volumecrd.Volume{}
+	if err := v.decoder.DecodeRaw(req.Object, volume); err != nil {
+		return "", err
+	}
+
+	for field, values := range map[string][2]string{
+		"id":           {old.Spec.Id, volume.Spec.Id},
+		"location":     {old.Spec.Location, volume.Spec.Location},
+		"locationType": {old.Spec.LocationType, volume.Spec.LocationType},
+		"storageClass": {old.Spec.StorageClass, volume.Spec.StorageClass},
+		"mode":         {old.Spec.Mode, volume.Spec.Mode},
+		"type":         {old.Spec.Type, volume.Spec.Type},
+	} {
+		if reason := checkImmutable("Volume", volume.Name, field, values[0], values[1]); reason != "" {
+			return reason, nil
+		}
+	}
+	if old.Spec.Ephemeral != volume.Spec.Ephemeral {
+		return fmt.Sprintf("ephemeral of Volume %s is immutable", volume.Name), nil
+	}
+	// volume is moved to the new node along with its drive
+	if old.Spec.NodeId != "" && old.Spec.NodeId != volume.Spec.NodeId {
+		drive, err := v.crHelper.GetDriveCRByVolume(volume)
+		if err != nil {
+			return "", err
+		}
+		if drive == nil || drive.Spec.NodeId != volume.Spec.NodeId {
+			return fmt.Sprintf("node of Volume %s can't be changed to %s, it isn't the node of location %s",
+				volume.Name, volume.Spec.NodeId, volume.Spec.Location), nil
+		}
+	}
+	return checkUsageTransition("Volume", volume.Name, old.Spec.Usage, volume.Spec.Usage,
+		volumeUsageTransitions, ""), nil
+}
+
+// validateLVG returns reason of denial of LogicalVolumeGroup request or empty string if request is allowed
+func (v *Validator) validateLVG(ctx context.Context, req admission.Request) (string, error) {
+	old := &lvgcrd.LogicalVolumeGroup{}
+	if err := v.decoder.DecodeRaw(req.OldObject, old); err != nil {
+		return "", err
+	}
+
+	if req.Operation == admissionV1beta1.Delete {
+		// LVG of the decommissioned node is force deleted by operator along with its volumes
+		if old.Annotations[apiV1.AnnotationForceDelete] == apiV1.ForceDeleteOn {
+			v.log.WithField("method", "validateLVG").Warnf("LogicalVolumeGroup %s is force deleted", old.Name)
+			return "", nil
+		}
+		return v.checkVolumesRemoved(ctx, "LogicalVolumeGroup", old.Name, old.Name)
+	}
+	if req.Operation != admissionV1beta1.Update {
+		return "", nil
+	}
+
+	lvg := &lvgcrd.LogicalVolumeGroup{}
+	if err := v.decoder.DecodeRaw(req.Object, lvg); err != nil {
+		return "", err
+	}
+	if reason := checkImmutable("LogicalVolumeGroup", lvg.Name, "name", old.Spec.Name, lvg.Spec.Name); reason != "" {
+		return reason, nil
+	}
+	// LVG is moved to the new node along with its drives
+	if old.Spec.Node != "" && old.Spec.Node != lvg.Spec.Node {
+		var drive *drivecrd.Drive
+		if len(lvg.Spec.Locations) > 0 {
+			drive = v.crHelper.GetDriveCRByUUID(lvg.Spec.Locations[0])
+		}
+		if drive == nil || drive.Spec.NodeId != lvg.Spec.Node {
+			return fmt.Sprintf("node of LogicalVolumeGroup %s can't be changed to %s, it isn't the node of its drives",
+				lvg.Name, lvg.Spec.Node), nil
+		}
+	}
+	return "", nil
+}
+
+// checkVolumesRemoved returns reason of denial if there are volumes on the location which aren't REMOVED
+func (v *Validator) checkVolumesRemoved(ctx context.Context, kind, name, location string) (string, error) {
+	volumes, err := v.crHelper.GetVolumesByLocation(ctx, location)
+	if err != nil {
+		return "", err
+	}
+	inUse := make([]string, 0)
+	for _, volume := range volumes {
+		if volume.Spec.CSIStatus != apiV1.Removed {
+			inUse = append(inUse, volume.Name)
+		}
+	}
+	if len(inUse) > 0 {
+		return fmt.Sprintf("%s %s can't be deleted, it has volumes: %s", kind, name, strings.Join(inUse, ", ")), nil
+	}
+	return "", nil
+}
+
+// checkVolumeRemoved returns reason of denial if Volume is still in use, volume CR is deleted by CSI after the volume
+// is REMOVED, FAILED volume or volume with force delete annotation can be deleted by user
+func checkVolumeRemoved(volume *volumecrd.Volume) string {
+	switch {
+	case volume.Spec.CSIStatus == apiV1.Removed, volume.Spec.CSIStatus == apiV1.Failed:
+		return ""
+	case volume.Annotations[apiV1.AnnotationForceDelete] == apiV1.ForceDeleteOn:
+		return ""
+	}
+	return fmt.Sprintf("Volume %s can't be deleted in %s status, delete its PVC or set %s=%s annotation",
+		volume.Name, volume.Spec.CSIStatus, apiV1.AnnotationForceDelete, apiV1.ForceDeleteOn)
+}
+
+// checkImmutable returns reason of denial if the field which was already set is changed
+func checkImmutable(kind, name, field, oldValue, newValue string) string {
+	if oldValue == "" || oldValue == newValue {
+		return ""
+	}
+	return fmt.Sprintf("%s of %s %s is immutable, it can't be changed from %s to %s",
+		field, kind, name, oldValue, newValue)
+}
+
+// checkUsageTransition returns reason of denial if usage can't be changed from oldUsage to newUsage,
+// usage can be changed from any state to anyStateTarget if it isn't empty
+func checkUsageTransition(kind, name, oldUsage, newUsage string, transitions map[string][]string,
+	anyStateTarget string) string {
+	if oldUsage == "" || oldUsage == newUsage || (anyStateTarget != "" && newUsage == anyStateTarget) {
+		return ""
+	}
+	allowed, known := transitions[oldUsage]
+	if !known {
+		return ""
+	}
+	for _, usage := range allowed {
+		if usage == newUsage {
+			return ""
+		}
+	}
+	if anyStateTarget != "" {
+		allowed = append(allowed, anyStateTarget)
+	}
+	return fmt.Sprintf("usage of %s %s can't be changed from %s to %s, allowed: %s",
+		kind, name, oldUsage, newUsage, strings.Join(allowed, ", "))
+}
@@ -0,0 +1,272 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	admissionV1beta1 "k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	"github.com/dell/csi-baremetal/api/v1/drivecrd"
+	"github.com/dell/csi-baremetal/pkg/base/k8s"
+)
+
+const (
+	testNs      = "default"
+	testNode    = "node-1"
+	testDriveID = "drive-1"
+	testLVGName = "lvg-1"
+	testVolume  = "pvc-1"
+
+	testExemptUser = "system:serviceaccount:default:csi-controller-sa"
+)
+
+func prepareValidator(t *testing.T) (*Validator, *k8s.KubeClient) {
+	kubeClient, err := k8s.GetFakeKubeClient(testNs, testLogger)
+	assert.Nil(t, err)
+	scheme, err := k8s.PrepareScheme()
+	assert.Nil(t, err)
+	decoder, err := admission.NewDecoder(scheme)
+	assert.Nil(t, err)
+
+	validator := NewValidator(kubeClient, []string{testExemptUser}, testLogger)
+	assert.Nil(t, validator.InjectDecoder(decoder))
+	return validator, kubeClient
+}
+
+func newRequest(t *testing.T, kind string, operation admissionV1beta1.Operation, old, obj runtime.Object) admission.Request {
+	req := admission.Request{AdmissionRequest: admissionV1beta1.AdmissionRequest{
+		Kind:      metav1.GroupVersionKind{Group: apiV1.CSICRsGroupVersion, Version: apiV1.Version, Kind: kind},
+		Operation: operation,
+	}}
+	if old != nil {
+		raw, err := json.Marshal(old)
+		assert.Nil(t, err)
+		req.OldObject = runtime.RawExtension{Raw: raw}
+	}
+	if obj != nil {
+		raw, err := json.Marshal(obj)
+		assert.Nil(t, err)
+		req.Object = runtime.RawExtension{Raw: raw}
+	}
+	return req
+}
+
+func newDrive(kubeClient *k8s.KubeClient, usage string) *drivecrd.Drive {
+	return kubeClient.ConstructDriveCR(testDriveID, api.Drive{
+		UUID:         testDriveID,
+		SerialNumber: "sn-1",
+		NodeId:       testNode,
+		Status:       apiV1.DriveStatusOnline,
+		Usage:        usage,
+	})
+}
+
+func TestValidator_Drive(t *testing.T) {
+	validator, kubeClient := prepareValidator(t)
+
+	t.Run("Usage transitions", func(t *testing.T) {
+		for _, tc := range []struct {
+			from, to string
+			allowed  bool
+		}{
+			{apiV1.DriveUsageInUse, apiV1.DriveUsageReleasing, true},
+			{apiV1.DriveUsageInUse, apiV1.DriveUsageInUse, true},
+			{apiV1.DriveUsageInUse, apiV1.DriveUsageFailed, true},
+			{apiV1.DriveUsageInUse, apiV1.DriveUsageRemoved, false},
+			{apiV1.DriveUsageReleasing, apiV1.DriveUsageReleased, true},
+			{apiV1.DriveUsageReleasing, apiV1.DriveUsageInUse, false},
+			{apiV1.DriveUsageReleased, apiV1.DriveUsageRemoving, true},
+			{apiV1.DriveUsageRemoving, apiV1.DriveUsageRemoved, true},
+			{apiV1.DriveUsageRemoved, apiV1.DriveUsageInUse, false},
+			{apiV1.DriveUsageFailed, apiV1.DriveUsageRemoving, true},
+			{"", apiV1.DriveUsageInUse, true},
+		} {
+			req := newRequest(t, "Drive", admissionV1beta1.Update,
+				newDrive(kubeClient, tc.from), newDrive(kubeClient, tc.to))
+			resp := validator.Handle(testCtx, req)
+			assert.Equal(t, tc.allowed, resp.Allowed, "%s -> %s", tc.from, tc.to)
+		}
+
+		resp := validator.Handle(testCtx, newRequest(t, "Drive", admissionV1beta1.Update,
+			newDrive(kubeClient, apiV1.DriveUsageInUse), newDrive(kubeClient, apiV1.DriveUsageRemoved)))
+		assert.Contains(t, resp.Result.Message, "can't be changed from IN_USE to REMOVED")
+	})
+
+	t.Run("Immutable fields", func(t *testing.T) {
+		old := newDrive(kubeClient, apiV1.DriveUsageInUse)
+		drive := old.DeepCopy()
+		drive.Spec.SerialNumber = "sn-2"
+		resp := validator.Handle(testCtx, newRequest(t, "Drive", admissionV1beta1.Update, old, drive))
+		assert.False(t, resp.Allowed)
+		assert.Contains(t, resp.Result.Message, "serialNumber of Drive")
+
+		drive = old.DeepCopy()
+		drive.Spec.NodeId = "node-2"
+		resp = validator.Handle(testCtx, newRequest(t, "Drive", admissionV1beta1.Update, old, drive))
+		assert.False(t, resp.Allowed)
+
+		// drive is adopted by the new node
+		old.Spec.Status = apiV1.DriveStatusOffline
+		resp = validator.Handle(testCtx, newRequest(t, "Drive", admissionV1beta1.Update, old, drive))
+		assert.True(t, resp.Allowed)
+	})
+
+	t.Run("Delete drive with volume", func(t *testing.T) {
+		drive := newDrive(kubeClient, apiV1.DriveUsageInUse)
+		volume := kubeClient.ConstructVolumeCR(testVolume, testNs, api.Volume{
+			Id: testVolume, Location: testDriveID, NodeId: testNode, CSIStatus: apiV1.Published,
+		})
+		assert.Nil(t, kubeClient.CreateCR(testCtx, volume.Name, volume))
+
+		resp := validator.Handle(testCtx, newRequest(t, "Drive", admissionV1beta1.Delete, drive, nil))
+		assert.False(t, resp.Allowed)
+		assert.Contains(t, resp.Result.Message, testVolume)
+
+		drive.Annotations = map[string]string{apiV1.AnnotationForceDelete: apiV1.ForceDeleteOn}
+		resp = validator.Handle(testCtx, newRequest(t, "Drive", admissionV1beta1.Delete, drive, nil))
+		assert.True(t, resp.Allowed)
+		drive.Annotations = nil
+
+		volume.Spec.CSIStatus = apiV1.Removed
+		assert.Nil(t, kubeClient.UpdateCR(testCtx, volume))
+		resp = validator.Handle(testCtx, newRequest(t, "Drive", admissionV1beta1.Delete, drive, nil))
+		assert.True(t, resp.Allowed)
+		assert.Nil(t, kubeClient.DeleteCR(testCtx, volume))
+	})
+}
+
+func TestValidator_Volume(t *testing.T) {
+	validator, kubeClient := prepareValidator(t)
+	drive := newDrive(kubeClient, apiV1.DriveUsageInUse)
+	assert.Nil(t, kubeClient.CreateCR(testCtx, drive.Name, drive))
+	old := kubeClient.ConstructVolumeCR(testVolume, testNs, api.Volume{
+		Id:           testVolume,
+		Location:     testDriveID,
+		LocationType: apiV1.LocationTypeDrive,
+		StorageClass: apiV1.StorageClassHDD,
+		NodeId:       testNode,
+		Usage:        apiV1.VolumeUsageInUse,
+	})
+
+	volume := old.DeepCopy()
+	volume.Spec.Location = "drive-2"
+	resp := validator.Handle(testCtx, newRequest(t, "Volume", admissionV1beta1.Update, old, volume))
+	assert.False(t, resp.Allowed)
+	assert.Contains(t, resp.Result.Message, "location of Volume")
+
+	volume = old.DeepCopy()
+	volume.Spec.Usage = apiV1.VolumeUsageReleased
+	resp = validator.Handle(testCtx, newRequest(t, "Volume", admissionV1beta1.Update, old, volume))
+	assert.False(t, resp.Allowed)
+
+	volume.Spec.Usage = apiV1.VolumeUsageReleasing
+	resp = validator.Handle(testCtx, newRequest(t, "Volume", admissionV1beta1.Update, old, volume))
+	assert.True(t, resp.Allowed)
+
+	// node of volume follows node of the drive
+	volume = old.DeepCopy()
+	volume.Spec.NodeId = "node-2"
+	resp = validator.Handle(testCtx, newRequest(t, "Volume", admissionV1beta1.Update, old, volume))
+	assert.False(t, resp.Allowed)
+
+	drive.Spec.NodeId = "node-2"
+	assert.Nil(t, kubeClient.UpdateCR(testCtx, drive))
+	resp = validator.Handle(testCtx, newRequest(t, "Volume", admissionV1beta1.Update, old, volume))
+	assert.True(t, resp.Allowed)
+
+	// volume which isn't removed can't be deleted
+	old.Spec.CSIStatus = apiV1.Published
+	resp = validator.Handle(testCtx, newRequest(t, "Volume", admissionV1beta1.Delete, old, nil))
+	assert.False(t, resp.Allowed)
+	assert.Contains(t, resp.Result.Message, "Volume "+testVolume+" can't be deleted")
+
+	old.Annotations = map[string]string{apiV1.AnnotationForceDelete: apiV1.ForceDeleteOn}
+	resp = validator.Handle(testCtx, newRequest(t, "Volume", admissionV1beta1.Delete, old, nil))
+	assert.True(t, resp.Allowed)
+
+	old.Annotations = nil
+	old.Spec.CSIStatus = apiV1.Removed
+	resp = validator.Handle(testCtx, newRequest(t, "Volume", admissionV1beta1.Delete, old, nil))
+	assert.True(t, resp.Allowed)
+}
+
+func TestValidator_ExemptUser(t *testing.T) {
+	validator, kubeClient := prepareValidator(t)
+	old := newDrive(kubeClient, apiV1.DriveUsageInUse)
+	drive := old.DeepCopy()
+	drive.Spec.Usage = apiV1.DriveUsageRemoved
+
+	req := newRequest(t, "Drive", admissionV1beta1.Update, old, drive)
+	resp := validator.Handle(testCtx, req)
+	assert.False(t, resp.Allowed)
+
+	req.UserInfo.Username = testExemptUser
+	resp = validator.Handle(testCtx, req)
+	assert.True(t, resp.Allowed)
+}
+
+func TestValidator_LVG(t *testing.T) {
+	validator, kubeClient := prepareValidator(t)
+	lvg := kubeClient.ConstructLVGCR(testLVGName, api.LogicalVolumeGroup{
+		Name:      testLVGName,
+		Node:      testNode,
+		Locations: []string{testDriveID},
+	})
+	assert.Nil(t, kubeClient.CreateCR(testCtx, lvg.Name, lvg))
+
+	updated := lvg.DeepCopy()
+	updated.Spec.Name = "vg"
+	resp := validator.Handle(testCtx, newRequest(t, "LogicalVolumeGroup", admissionV1beta1.Update, lvg, updated))
+	assert.False(t, resp.Allowed)
+
+	volume := kubeClient.ConstructVolumeCR(testVolume, testNs, api.Volume{
+		Id: testVolume, Location: testLVGName, NodeId: testNode, CSIStatus: apiV1.Created,
+	})
+	assert.Nil(t, kubeClient.CreateCR(testCtx, volume.Name, volume))
+	resp = validator.Handle(testCtx, newRequest(t, "LogicalVolumeGroup", admissionV1beta1.Delete, lvg, nil))
+	assert.False(t, resp.Allowed)
+	assert.Contains(t, resp.Result.Message, "LogicalVolumeGroup "+testLVGName+" can't be deleted")
+
+	// LVG is force deleted by operator
+	forced := lvg.DeepCopy()
+	forced.Annotations = map[string]string{apiV1.AnnotationForceDelete: apiV1.ForceDeleteOn}
+	resp = validator.Handle(testCtx, newRequest(t, "LogicalVolumeGroup", admissionV1beta1.Delete, forced, nil))
+	assert.True(t, resp.Allowed)
+
+	// drive of LVG can't be deleted as well
+	drive := newDrive(kubeClient, apiV1.DriveUsageInUse)
+	resp = validator.Handle(testCtx, newRequest(t, "Drive", admissionV1beta1.Delete, drive, nil))
+	assert.False(t, resp.Allowed)
+
+	assert.Nil(t, kubeClient.DeleteCR(testCtx, volume))
+	resp = validator.Handle(testCtx, newRequest(t, "LogicalVolumeGroup", admissionV1beta1.Delete, lvg, nil))
+	assert.True(t, resp.Allowed)
+}
+
+func TestValidator_OtherKinds(t *testing.T) {
+	validator, _ := prepareValidator(t)
+	resp := validator.Handle(testCtx, newRequest(t, "AvailableCapacity", admissionV1beta1.Delete, nil, nil))
+	assert.True(t, resp.Allowed)
+}
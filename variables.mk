@@ -44,6 +44,7 @@ EXTENDER         := extender
 EXTENDER_PATCHER := scheduler-patcher
 OPERATOR      	 := operator
 PLUGIN           := plugin
+SIMULATOR        := simulator
 
 BASE_DRIVE_MGR     := basemgr
 LOOPBACK_DRIVE_MGR := loopbackmgr